@@ -33,6 +33,13 @@ func alphaCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service)
 	cmd.AddCommand(
 		vizCommand(p, dockerCli, backend),
 		publishCommand(p, dockerCli, backend),
+		checkpointCommand(p, dockerCli, backend),
+		restoreCommand(p, dockerCli, backend),
+		stackDeployCommand(p, dockerCli, backend),
+		exportCommand(p, dockerCli, backend),
+		importCommand(p, dockerCli, backend),
+		alphaVolumesCommand(p, dockerCli, backend),
+		lintCommand(p, dockerCli),
 	)
 	return cmd
 }
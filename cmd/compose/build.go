@@ -27,6 +27,7 @@ import (
 	"github.com/docker/cli/cli/command"
 	cliopts "github.com/docker/cli/opts"
 	ui "github.com/docker/compose/v2/pkg/progress"
+	"github.com/docker/go-units"
 	buildkit "github.com/moby/buildkit/util/progress/progressui"
 	"github.com/spf13/cobra"
 
@@ -35,17 +36,24 @@ import (
 
 type buildOptions struct {
 	*ProjectOptions
-	quiet   bool
-	pull    bool
-	push    bool
-	args    []string
-	noCache bool
-	memory  cliopts.MemBytes
-	ssh     string
-	builder string
-	deps    bool
+	quiet      bool
+	pull       bool
+	push       bool
+	args       []string
+	noCache    bool
+	memory     cliopts.MemBytes
+	ssh        string
+	builder    string
+	deps       bool
+	print      bool
+	sbom       string
+	provenance string
+	// contextSizeWarning is the build context size above which a warning is emitted, as a human readable size
+	contextSizeWarning string
 }
 
+const defaultContextSizeWarning = "100MB"
+
 func (opts buildOptions) toAPIBuildOptions(services []string) (api.BuildOptions, error) {
 	var SSHKeys []types.SSHKey
 	var err error
@@ -66,18 +74,31 @@ func (opts buildOptions) toAPIBuildOptions(services []string) (api.BuildOptions,
 	if builderName == "" {
 		builderName = os.Getenv("BUILDX_BUILDER")
 	}
+	contextSizeWarning := opts.contextSizeWarning
+	if contextSizeWarning == "" {
+		contextSizeWarning = defaultContextSizeWarning
+	}
+	contextSizeThreshold, err := units.FromHumanSize(contextSizeWarning)
+	if err != nil {
+		return api.BuildOptions{}, fmt.Errorf("invalid --context-size-warning: %w", err)
+	}
 
 	return api.BuildOptions{
-		Pull:     opts.pull,
-		Push:     opts.push,
-		Progress: ui.Mode,
-		Args:     types.NewMappingWithEquals(opts.args),
-		NoCache:  opts.noCache,
-		Quiet:    opts.quiet,
-		Services: services,
-		Deps:     opts.deps,
-		SSHs:     SSHKeys,
-		Builder:  builderName,
+		Pull:       opts.pull,
+		Push:       opts.push,
+		Progress:   ui.Mode,
+		Args:       types.NewMappingWithEquals(opts.args),
+		NoCache:    opts.noCache,
+		Quiet:      opts.quiet,
+		Services:   services,
+		Deps:       opts.deps,
+		SSHs:       SSHKeys,
+		Builder:    builderName,
+		Print:      opts.print,
+		SBOM:       opts.sbom,
+		Provenance: opts.provenance,
+
+		ContextSizeWarning: contextSizeThreshold,
 	}, nil
 }
 
@@ -118,6 +139,12 @@ func buildCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service)
 	flags.StringVar(&opts.ssh, "ssh", "", "Set SSH authentications used when building service images. (use 'default' for using your default SSH Agent)")
 	flags.StringVar(&opts.builder, "builder", "", "Set builder to use")
 	flags.BoolVar(&opts.deps, "with-dependencies", false, "Also build dependencies (transitively)")
+	flags.BoolVar(&opts.print, "print", false, "Print the resolved build configuration of services as JSON, without building")
+	flags.StringVar(&opts.sbom, "sbom", "", `Attach a SBOM attestation to built images (format: "true" or "generator=image")`)
+	flags.Lookup("sbom").NoOptDefVal = "true"
+	flags.StringVar(&opts.provenance, "provenance", "", `Attach a provenance attestation to built images (format: "true" or "mode=max")`)
+	flags.Lookup("provenance").NoOptDefVal = "true"
+	flags.StringVar(&opts.contextSizeWarning, "context-size-warning", defaultContextSizeWarning, "Warn when a build context is larger than this size, once .dockerignore is applied. 0 disables the warning")
 
 	flags.Bool("parallel", true, "Build images in parallel. DEPRECATED")
 	flags.MarkHidden("parallel") //nolint:errcheck
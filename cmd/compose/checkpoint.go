@@ -0,0 +1,86 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+type checkpointOptions struct {
+	*ProjectOptions
+	leaveRunning bool
+}
+
+func checkpointCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
+	opts := checkpointOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "checkpoint [OPTIONS] CHECKPOINT [SERVICE...]",
+		Short: "Checkpoint running service containers, using CRIU",
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runCheckpoint(ctx, dockerCli, backend, opts, args[0], args[1:])
+		}),
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeServiceNames(dockerCli, p),
+	}
+	cmd.Flags().BoolVar(&opts.leaveRunning, "leave-running", false, "Leave containers running after checkpoint")
+	return cmd
+}
+
+func runCheckpoint(ctx context.Context, dockerCli command.Cli, backend api.Service, opts checkpointOptions, name string, services []string) error {
+	project, _, err := opts.ToProject(ctx, dockerCli, services)
+	if err != nil {
+		return err
+	}
+
+	return backend.Checkpoint(ctx, project, api.CheckpointOptions{
+		Name:         name,
+		Services:     services,
+		LeaveRunning: opts.leaveRunning,
+	})
+}
+
+func restoreCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore CHECKPOINT [SERVICE...]",
+		Short: "Restore stopped service containers from a checkpoint, in dependency order",
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runRestore(ctx, dockerCli, backend, p, args[0], args[1:])
+		}),
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeServiceNames(dockerCli, p),
+	}
+	return cmd
+}
+
+func runRestore(ctx context.Context, dockerCli command.Cli, backend api.Service, p *ProjectOptions, name string, services []string) error {
+	project, _, err := p.ToProject(ctx, dockerCli, services)
+	if err != nil {
+		return err
+	}
+
+	return backend.Restore(ctx, project, api.CheckpointOptions{
+		Name:     name,
+		Services: services,
+	})
+}
@@ -23,9 +23,11 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/compose-spec/compose-go/v2/cli"
 	"github.com/compose-spec/compose-go/v2/dotenv"
@@ -67,6 +69,11 @@ const (
 	ComposeEnvFiles = "COMPOSE_ENV_FILES"
 	// ComposeMenu defines if the navigation menu should be rendered. Can be also set via --menu
 	ComposeMenu = "COMPOSE_MENU"
+	// ComposeSecretsExec allows secrets to run the host command set by x-exec. Not read from project .env file, so a
+	// project can't opt in on its own
+	ComposeSecretsExec = "COMPOSE_SECRETS_EXEC"
+	// ComposeHooks allows x-hooks host commands to run. Can be also set via --hooks, not read from project .env file
+	ComposeHooks = "COMPOSE_HOOKS"
 )
 
 type Backend interface {
@@ -75,6 +82,14 @@ type Backend interface {
 	SetDesktopClient(cli *desktop.Client)
 
 	SetExperiments(experiments *experimental.State)
+
+	SetLockTimeout(timeout time.Duration)
+
+	SetSecretsExec(enabled bool)
+
+	SetHooks(enabled bool)
+
+	SetContainerRuntime(name string) error
 }
 
 // Command defines a compose CLI command as a func with args
@@ -98,8 +113,15 @@ func AdaptCmd(fn CobraCommand) func(cmd *cobra.Command, args []string) error {
 		}()
 
 		err := fn(ctx, cmd, args)
+		printWarnings(ctx, cmd.ErrOrStderr())
 		var composeErr compose.Error
-		if api.IsErrCanceled(err) || errors.Is(ctx.Err(), context.Canceled) {
+		switch {
+		case errors.Is(err, api.ErrRolledBack):
+			err = dockercli.StatusError{
+				StatusCode: 130,
+				Status:     compose.RolledBackStatus,
+			}
+		case api.IsErrCanceled(err) || errors.Is(ctx.Err(), context.Canceled):
 			err = dockercli.StatusError{
 				StatusCode: 130,
 				Status:     compose.CanceledStatus,
@@ -181,12 +203,18 @@ func (o *ProjectOptions) addProjectFlags(f *pflag.FlagSet) {
 }
 
 func (o *ProjectOptions) projectOrName(ctx context.Context, dockerCli command.Cli, services ...string) (*types.Project, string, error) {
-	name := o.ProjectName
+	name, err := projectNameFrom(o.ProjectName, "--project-name")
+	if err != nil {
+		return nil, "", err
+	}
 	var project *types.Project
 	if len(o.ConfigPaths) > 0 || o.ProjectName == "" {
 		p, _, err := o.ToProject(ctx, dockerCli, services, cli.WithDiscardEnvFile)
 		if err != nil {
-			envProjectName := os.Getenv(ComposeProjectName)
+			envProjectName, envErr := projectNameFrom(os.Getenv(ComposeProjectName), ComposeProjectName)
+			if envErr != nil {
+				return nil, "", envErr
+			}
 			if envProjectName != "" {
 				return nil, envProjectName, nil
 			}
@@ -199,13 +227,9 @@ func (o *ProjectOptions) projectOrName(ctx context.Context, dockerCli command.Cl
 }
 
 func (o *ProjectOptions) toProjectName(ctx context.Context, dockerCli command.Cli) (string, error) {
-	if o.ProjectName != "" {
-		return o.ProjectName, nil
-	}
-
-	envProjectName := os.Getenv(ComposeProjectName)
-	if envProjectName != "" {
-		return envProjectName, nil
+	name, err := o.explicitProjectName()
+	if err != nil || name != "" {
+		return name, err
 	}
 
 	project, _, err := o.ToProject(ctx, dockerCli, nil)
@@ -215,11 +239,29 @@ func (o *ProjectOptions) toProjectName(ctx context.Context, dockerCli command.Cl
 	return project.Name, nil
 }
 
+// explicitProjectName returns the project name set by --project-name, or else by COMPOSE_PROJECT_NAME.
+// Top-level `name` and project directory are only considered by the loader, as they require to parse compose files
+func (o *ProjectOptions) explicitProjectName() (string, error) {
+	if o.ProjectName != "" {
+		return projectNameFrom(o.ProjectName, "--project-name")
+	}
+	return projectNameFrom(os.Getenv(ComposeProjectName), ComposeProjectName)
+}
+
+// projectNameFrom checks name set by source is a valid project name
+func projectNameFrom(name string, source string) (string, error) {
+	if name != loader.NormalizeProjectName(name) {
+		return "", api.InvalidProjectNameError{Name: name, Source: source}
+	}
+	return name, nil
+}
+
 func (o *ProjectOptions) ToModel(ctx context.Context, dockerCli command.Cli, services []string, po ...cli.ProjectOptionsFn) (map[string]any, error) {
 	remotes := o.remoteLoaders(dockerCli)
 	for _, r := range remotes {
 		po = append(po, cli.WithResourceLoader(r))
 	}
+	po = append(po, withRemoteConfigPaths(ctx, remotes))
 
 	options, err := o.toProjectOptions(po...)
 	if err != nil {
@@ -230,7 +272,7 @@ func (o *ProjectOptions) ToModel(ctx context.Context, dockerCli command.Cli, ser
 		api.Separator = "_"
 	}
 
-	return options.LoadModel(ctx)
+	return withLoaderWarnings(ctx, options.LoadModel)
 }
 
 func (o *ProjectOptions) ToProject(ctx context.Context, dockerCli command.Cli, services []string, po ...cli.ProjectOptionsFn) (*types.Project, tracing.Metrics, error) { //nolint:gocyclo
@@ -239,6 +281,7 @@ func (o *ProjectOptions) ToProject(ctx context.Context, dockerCli command.Cli, s
 	for _, r := range remotes {
 		po = append(po, cli.WithResourceLoader(r))
 	}
+	po = append(po, withRemoteConfigPaths(ctx, remotes))
 
 	options, err := o.toProjectOptions(po...)
 	if err != nil {
@@ -272,7 +315,30 @@ func (o *ProjectOptions) ToProject(ctx context.Context, dockerCli command.Cli, s
 		api.Separator = "_"
 	}
 
-	project, err := options.LoadProject(ctx)
+	project, err := withLoaderWarnings(ctx, options.LoadProject)
+	if err != nil {
+		return nil, metrics, compose.WrapComposeError(err)
+	}
+
+	project, disabled, err := compose.WithoutDisabledServices(project)
+	if err != nil {
+		return nil, metrics, compose.WrapComposeError(err)
+	}
+	for _, name := range services {
+		if slices.Contains(disabled, name) {
+			return nil, metrics, api.DisabledServiceError{Service: name}
+		}
+	}
+
+	if err := checkProviderServices(project); err != nil {
+		return nil, metrics, compose.WrapComposeError(err)
+	}
+
+	if err := compose.CheckServiceReferences(project); err != nil {
+		return nil, metrics, compose.WrapComposeError(err)
+	}
+
+	project, err = compose.WithInitContainers(project)
 	if err != nil {
 		return nil, metrics, compose.WrapComposeError(err)
 	}
@@ -318,7 +384,36 @@ func (o *ProjectOptions) remoteLoaders(dockerCli command.Cli) []loader.ResourceL
 	return []loader.ResourceLoader{git, oci}
 }
 
+// withRemoteConfigPaths downloads compose files set by -f as a git or OCI remote resource, and uses the directory of
+// the first one as project working directory, so relative paths are resolved within the downloaded resource
+func withRemoteConfigPaths(ctx context.Context, remotes []loader.ResourceLoader) cli.ProjectOptionsFn {
+	return func(o *cli.ProjectOptions) error {
+		o.ConfigPaths = append([]string{}, o.ConfigPaths...)
+		for i, path := range o.ConfigPaths {
+			for _, r := range remotes {
+				if !r.Accept(path) {
+					continue
+				}
+				local, err := r.Load(ctx, path)
+				if err != nil {
+					return err
+				}
+				if local == "" {
+					return fmt.Errorf("remote compose file %s is not available in offline mode", path)
+				}
+				o.ConfigPaths[i] = local
+				if i == 0 && o.WorkingDir == "" {
+					o.WorkingDir = filepath.Dir(local)
+				}
+				break
+			}
+		}
+		return nil
+	}
+}
+
 func (o *ProjectOptions) toProjectOptions(po ...cli.ProjectOptionsFn) (*cli.ProjectOptions, error) {
+	model := &rawModel{}
 	return cli.NewProjectOptions(o.ConfigPaths,
 		append(po,
 			cli.WithWorkingDirectory(o.ProjectDir),
@@ -326,11 +421,67 @@ func (o *ProjectOptions) toProjectOptions(po ...cli.ProjectOptionsFn) (*cli.Proj
 			cli.WithConfigFileEnv,
 			cli.WithDefaultConfigPath,
 			cli.WithEnvFiles(o.EnvFiles...),
+			withProjectEnvFiles(model),
+			withProviderServices(model),
 			cli.WithDotEnv,
+			withProjectVariables(model),
 			cli.WithDefaultProfiles(o.Profiles...),
 			cli.WithName(o.ProjectName))...)
 }
 
+// extEnvFile declares, at compose file top-level, env files to load before the ones set by --env-file
+const extEnvFile = "x-env_file"
+
+// withProjectEnvFiles prepends env files declared by top-level x-env_file to the ones set by command line, so that
+// later files override values set by earlier ones
+func withProjectEnvFiles(model *rawModel) cli.ProjectOptionsFn {
+	return func(o *cli.ProjectOptions) error {
+		m := model.get(o)
+		if m == nil {
+			return nil
+		}
+		workingDir, err := o.GetWorkingDir()
+		if err != nil {
+			return err
+		}
+		envFiles, err := projectEnvFiles(m[extEnvFile], workingDir)
+		if err != nil {
+			return err
+		}
+		if len(envFiles) > 0 {
+			o.EnvFiles = append(envFiles, o.EnvFiles...)
+		}
+		return nil
+	}
+}
+
+// projectEnvFiles returns env files declared by x-env_file, resolved relative to the project directory
+func projectEnvFiles(envFile any, dir string) ([]string, error) {
+	var files []string
+	switch envFile := envFile.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		files = []string{envFile}
+	case []any:
+		for _, f := range envFile {
+			file, ok := f.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s must be a string or a list of strings", extEnvFile)
+			}
+			files = append(files, file)
+		}
+	default:
+		return nil, fmt.Errorf("%s must be a string or a list of strings", extEnvFile)
+	}
+	for i, file := range files {
+		if !filepath.IsAbs(file) {
+			files[i] = filepath.Join(dir, file)
+		}
+	}
+	return files, nil
+}
+
 // PluginName is the name of the plugin
 const PluginName = "compose"
 
@@ -356,10 +507,14 @@ func RootCommand(dockerCli command.Cli, backend Backend) *cobra.Command { //noli
 	var (
 		ansi     string
 		noAnsi   bool
+		theme    string
 		verbose  bool
 		version  bool
 		parallel int
 		dryRun   bool
+		waitLock time.Duration
+		hooks    bool
+		warnings string
 	)
 	c := &cobra.Command{
 		Short:            "Docker Compose",
@@ -372,7 +527,7 @@ func RootCommand(dockerCli command.Cli, backend Backend) *cobra.Command { //noli
 				return cmd.Help()
 			}
 			if version {
-				return versionCommand(dockerCli).Execute()
+				return versionCommand(dockerCli, experiments).Execute()
 			}
 			_ = cmd.Help()
 			return dockercli.StatusError{
@@ -382,6 +537,9 @@ func RootCommand(dockerCli command.Cli, backend Backend) *cobra.Command { //noli
 		},
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
+			// host commands opt-in is read before project .env file gets loaded
+			backend.SetSecretsExec(utils.StringToBool(os.Getenv(ComposeSecretsExec)))
+			backend.SetHooks(hooks || utils.StringToBool(os.Getenv(ComposeHooks)))
 
 			// (1) process env vars
 			err := setEnvWithDotEnv(&opts)
@@ -423,6 +581,13 @@ func RootCommand(dockerCli command.Cli, backend Backend) *cobra.Command { //noli
 				formatter.SetANSIMode(dockerCli, formatter.Never)
 			}
 
+			if v, ok := os.LookupEnv("COMPOSE_THEME"); ok && !cmd.Flags().Changed("theme") {
+				theme = v
+			}
+			if err := formatter.SetTheme(dockerCli, theme); err != nil {
+				return err
+			}
+
 			switch ansi {
 			case "never":
 				ui.Mode = ui.ModePlain
@@ -492,12 +657,17 @@ func RootCommand(dockerCli command.Cli, backend Backend) *cobra.Command { //noli
 				logrus.Debugf("Limiting max concurrency to %d jobs", parallel)
 				backend.MaxConcurrency(parallel)
 			}
+			backend.SetLockTimeout(waitLock)
 
 			// (5) dry run detection
 			ctx, err = backend.DryRunMode(ctx, dryRun)
 			if err != nil {
 				return err
 			}
+			ctx, err = withWarningsFormat(ctx, warnings)
+			if err != nil {
+				return err
+			}
 			cmd.SetContext(ctx)
 
 			// (6) Desktop integration
@@ -515,7 +685,18 @@ func RootCommand(dockerCli command.Cli, backend Backend) *cobra.Command { //noli
 				}
 			}
 
-			// (7) experimental features
+			// (7) container runtime, when Docker Engine API is served by another runtime than dockerd
+			containerRuntime, err := compose.ContainerRuntimeFromContext(dockerCli.ContextStore(), dockerCli.CurrentContext())
+			if err != nil {
+				logrus.Debugf("failed to read container runtime from docker context metadata: %v", err)
+			} else if containerRuntime != "" {
+				if err := backend.SetContainerRuntime(containerRuntime); err != nil {
+					return err
+				}
+			}
+
+			// (8) experimental features
+			experiments.LoadConfig(dockerCli.ConfigFile())
 			if err := experiments.Load(ctx, desktopCli); err != nil {
 				logrus.Debugf("Failed to query feature flags from Desktop: %v", err)
 			}
@@ -546,7 +727,7 @@ func RootCommand(dockerCli command.Cli, backend Backend) *cobra.Command { //noli
 		eventsCommand(&opts, dockerCli, backend),
 		portCommand(&opts, dockerCli, backend),
 		imagesCommand(&opts, dockerCli, backend),
-		versionCommand(dockerCli),
+		versionCommand(dockerCli, experiments),
 		buildCommand(&opts, dockerCli, backend),
 		pushCommand(&opts, dockerCli, backend),
 		pullCommand(&opts, dockerCli, backend),
@@ -554,8 +735,11 @@ func RootCommand(dockerCli command.Cli, backend Backend) *cobra.Command { //noli
 		copyCommand(&opts, dockerCli, backend),
 		waitCommand(&opts, dockerCli, backend),
 		scaleCommand(&opts, dockerCli, backend),
-		statsCommand(&opts, dockerCli),
+		statsCommand(&opts, dockerCli, backend),
 		watchCommand(&opts, dockerCli, backend),
+		volumesCommand(&opts, dockerCli, backend),
+		networksCommand(&opts, dockerCli, backend),
+		secretsCommand(&opts, dockerCli),
 		alphaCommand(&opts, dockerCli, backend),
 	)
 
@@ -583,7 +767,11 @@ func RootCommand(dockerCli command.Cli, backend Backend) *cobra.Command { //noli
 	)
 
 	c.Flags().StringVar(&ansi, "ansi", "auto", `Control when to print ANSI control characters ("never"|"always"|"auto")`)
+	c.Flags().StringVar(&theme, "theme", "default", `Comma-separated themes for terminal output ("default"|"ascii"|"high-contrast"|"monochrome")`)
 	c.Flags().IntVar(&parallel, "parallel", -1, `Control max parallelism, -1 for unlimited`)
+	c.Flags().DurationVar(&waitLock, "wait-lock", 0, `Maximum duration to wait for another compose process to release the project lock`)
+	c.Flags().BoolVar(&hooks, "hooks", false, `Run host commands set by the "x-hooks" extension`)
+	c.Flags().StringVar(&warnings, "warnings-format", warningsFormatText, `Format of warnings printed on standard error. Values: [text | json]`)
 	c.Flags().BoolVarP(&version, "version", "v", false, "Show the Docker Compose version information")
 	c.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Execute command in dry run mode")
 	c.Flags().MarkHidden("version") //nolint:errcheck
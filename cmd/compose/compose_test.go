@@ -17,10 +17,18 @@
 package compose
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/compose-spec/compose-go/v2/loader"
 	"github.com/compose-spec/compose-go/v2/types"
 	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/api"
 )
 
 func TestFilterServices(t *testing.T) {
@@ -53,3 +61,187 @@ func TestFilterServices(t *testing.T) {
 	_, err = p.GetService("zot")
 	assert.NilError(t, err)
 }
+
+func TestProjectEnvFilesLayering(t *testing.T) {
+	dir := t.TempDir()
+	composeFile := filepath.Join(dir, "compose.yaml")
+	assert.NilError(t, os.WriteFile(composeFile, []byte(`
+x-env_file:
+  - base.env
+  - local.env
+services:
+  app:
+    image: alpine:${TAG}
+`), 0o600))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "base.env"), []byte("TAG=base\nNAME=base\n"), 0o600))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "local.env"), []byte("TAG=local\n"), 0o600))
+	override := filepath.Join(dir, "override.env")
+	assert.NilError(t, os.WriteFile(override, []byte("NAME=override\n"), 0o600))
+
+	opts := ProjectOptions{ConfigPaths: []string{composeFile}, EnvFiles: []string{override}}
+	options, err := opts.toProjectOptions()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, options.EnvFiles, []string{
+		filepath.Join(dir, "base.env"),
+		filepath.Join(dir, "local.env"),
+		override,
+	})
+	assert.Equal(t, options.Environment["TAG"], "local")
+	assert.Equal(t, options.Environment["NAME"], "override")
+}
+
+func TestProjectEnvFilesLoaded(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "compose.yaml"), []byte(`
+services:
+  app:
+    image: alpine:${TAG}
+`), 0o600))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "compose.override.yaml"), []byte(`
+x-env_file: local.env
+`), 0o600))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "local.env"), []byte("TAG=local\n"), 0o600))
+
+	// x-env_file declared by an override file
+	opts := ProjectOptions{ConfigPaths: []string{filepath.Join(dir, "compose.yaml"), filepath.Join(dir, "compose.override.yaml")}}
+	options, err := opts.toProjectOptions()
+	assert.NilError(t, err)
+	project, err := options.LoadProject(context.Background())
+	assert.NilError(t, err)
+	assert.Equal(t, project.Services["app"].Image, "alpine:local")
+
+	// x-env_file declared by compose file read from stdin
+	stdin := os.Stdin
+	defer func() { os.Stdin = stdin }()
+	assert.NilError(t, replayStdin([]byte("x-env_file: local.env\nservices:\n  app:\n    image: alpine:${TAG}\n")))
+	opts = ProjectOptions{ConfigPaths: []string{"-"}, ProjectDir: dir}
+	options, err = opts.toProjectOptions()
+	assert.NilError(t, err)
+	project, err = options.LoadProject(context.Background())
+	assert.NilError(t, err)
+	assert.Equal(t, project.Services["app"].Image, "alpine:local")
+}
+
+func TestProjectEnvFilesInvalid(t *testing.T) {
+	_, err := projectEnvFiles(42, "/tmp")
+	assert.ErrorContains(t, err, "x-env_file must be a string or a list of strings")
+
+	files, err := projectEnvFiles(".env.local", "/project")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, files, []string{"/project/.env.local"})
+}
+
+type fakeRemoteLoader struct {
+	local string
+}
+
+func (f fakeRemoteLoader) Accept(path string) bool {
+	return strings.HasPrefix(path, "fake://")
+}
+
+func (f fakeRemoteLoader) Load(_ context.Context, _ string) (string, error) {
+	return f.local, nil
+}
+
+func (f fakeRemoteLoader) Dir(_ string) string {
+	return filepath.Dir(f.local)
+}
+
+func TestRemoteConfigPaths(t *testing.T) {
+	dir := t.TempDir()
+	composeFile := filepath.Join(dir, "app", "compose.yaml")
+	assert.NilError(t, os.MkdirAll(filepath.Dir(composeFile), 0o700))
+	assert.NilError(t, os.WriteFile(composeFile, []byte(`
+services:
+  app:
+    build: .
+`), 0o600))
+
+	opts := ProjectOptions{ConfigPaths: []string{"fake://example.com/app"}}
+	options, err := opts.toProjectOptions(withRemoteConfigPaths(context.Background(), []loader.ResourceLoader{
+		fakeRemoteLoader{local: composeFile},
+	}))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, options.ConfigPaths, []string{composeFile})
+	assert.Equal(t, options.WorkingDir, filepath.Dir(composeFile))
+	assert.DeepEqual(t, opts.ConfigPaths, []string{"fake://example.com/app"})
+
+	project, err := options.LoadProject(context.Background())
+	assert.NilError(t, err)
+	assert.Equal(t, project.Services["app"].Build.Context, filepath.Dir(composeFile))
+}
+
+func TestIncludeCycle(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.MkdirAll(filepath.Join(dir, "db"), 0o700))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "compose.yaml"), []byte(`
+include:
+  - db/compose.yaml
+services:
+  app:
+    image: alpine
+`), 0o600))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "db", "compose.yaml"), []byte(`
+include:
+  - ../compose.yaml
+services:
+  db:
+    image: postgres
+`), 0o600))
+
+	opts := ProjectOptions{ConfigPaths: []string{filepath.Join(dir, "compose.yaml")}}
+	options, err := opts.toProjectOptions()
+	assert.NilError(t, err)
+	_, err = options.LoadProject(context.Background())
+	assert.ErrorContains(t, err, "include cycle detected")
+}
+
+func TestIncludeEnvFile(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.MkdirAll(filepath.Join(dir, "db"), 0o700))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "compose.yaml"), []byte(`
+include:
+  - path: db/compose.yaml
+    env_file: db/db.env
+services:
+  app:
+    image: alpine
+`), 0o600))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "db", "compose.yaml"), []byte(`
+services:
+  db:
+    image: postgres:${PG_VERSION}
+`), 0o600))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "db", "db.env"), []byte("PG_VERSION=16\n"), 0o600))
+
+	opts := ProjectOptions{ConfigPaths: []string{filepath.Join(dir, "compose.yaml")}}
+	options, err := opts.toProjectOptions()
+	assert.NilError(t, err)
+	project, err := options.LoadProject(context.Background())
+	assert.NilError(t, err)
+	assert.Equal(t, project.Services["db"].Image, "postgres:16")
+}
+
+func TestExplicitProjectName(t *testing.T) {
+	t.Setenv(ComposeProjectName, "from-env")
+
+	opts := ProjectOptions{ProjectName: "from_flag"}
+	name, err := opts.explicitProjectName()
+	assert.NilError(t, err)
+	assert.Equal(t, name, "from_flag")
+
+	opts = ProjectOptions{}
+	name, err = opts.explicitProjectName()
+	assert.NilError(t, err)
+	assert.Equal(t, name, "from-env")
+
+	t.Setenv(ComposeProjectName, "My App")
+	_, err = opts.explicitProjectName()
+	var invalid api.InvalidProjectNameError
+	assert.Assert(t, errors.As(err, &invalid))
+	assert.Equal(t, invalid.Source, ComposeProjectName)
+
+	opts = ProjectOptions{ProjectName: "-app"}
+	_, err = opts.explicitProjectName()
+	assert.ErrorContains(t, err, `invalid project name "-app" set by --project-name`)
+}
@@ -36,6 +36,7 @@ import (
 
 	"github.com/docker/compose/v2/pkg/api"
 	"github.com/docker/compose/v2/pkg/compose"
+	"github.com/docker/compose/v2/pkg/kube"
 )
 
 type configOptions struct {
@@ -52,8 +53,14 @@ type configOptions struct {
 	profiles            bool
 	images              bool
 	hash                string
+	networkOrder        bool
 	noConsistency       bool
 	variables           bool
+	environment         bool
+	expansionReport     bool
+	validateOnly        bool
+	interpolationTrace  []string
+	mergeTrace          bool
 }
 
 func (o *configOptions) ToProject(ctx context.Context, dockerCli command.Cli, services []string, po ...cli.ProjectOptionsFn) (*types.Project, error) {
@@ -100,6 +107,9 @@ func configCommand(p *ProjectOptions, dockerCli command.Cli) *cobra.Command {
 			return nil
 		}),
 		RunE: Adapt(func(ctx context.Context, args []string) error {
+			if opts.environment {
+				return runEnvironment(ctx, dockerCli, opts, args)
+			}
 			if opts.services {
 				return runServices(ctx, dockerCli, opts)
 			}
@@ -109,6 +119,9 @@ func configCommand(p *ProjectOptions, dockerCli command.Cli) *cobra.Command {
 			if opts.hash != "" {
 				return runHash(ctx, dockerCli, opts)
 			}
+			if opts.networkOrder {
+				return runNetworkOrder(ctx, dockerCli, opts, args)
+			}
 			if opts.profiles {
 				return runProfiles(ctx, dockerCli, opts, args)
 			}
@@ -118,13 +131,25 @@ func configCommand(p *ProjectOptions, dockerCli command.Cli) *cobra.Command {
 			if opts.variables {
 				return runVariables(ctx, dockerCli, opts, args)
 			}
+			if len(opts.interpolationTrace) > 0 {
+				return runInterpolationTrace(ctx, dockerCli, opts)
+			}
+			if opts.mergeTrace {
+				return runMergeTrace(ctx, dockerCli, opts, args)
+			}
+			if opts.expansionReport {
+				return runExpansionReport(ctx, dockerCli, opts, args)
+			}
+			if opts.validateOnly {
+				return runValidateOnly(ctx, dockerCli, opts, args)
+			}
 
 			return runConfig(ctx, dockerCli, opts, args)
 		}),
 		ValidArgsFunction: completeServiceNames(dockerCli, p),
 	}
 	flags := cmd.Flags()
-	flags.StringVar(&opts.Format, "format", "yaml", "Format the output. Values: [yaml | json]")
+	flags.StringVar(&opts.Format, "format", "yaml", "Format the output. Values: [yaml | json | k8s], or [dotenv | shell] with --environment")
 	flags.BoolVar(&opts.resolveImageDigests, "resolve-image-digests", false, "Pin image tags to digests")
 	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only validate the configuration, don't print anything")
 	flags.BoolVar(&opts.noInterpolate, "no-interpolate", false, "Don't interpolate environment variables")
@@ -137,7 +162,13 @@ func configCommand(p *ProjectOptions, dockerCli command.Cli) *cobra.Command {
 	flags.BoolVar(&opts.profiles, "profiles", false, "Print the profile names, one per line.")
 	flags.BoolVar(&opts.images, "images", false, "Print the image names, one per line.")
 	flags.StringVar(&opts.hash, "hash", "", "Print the service config hash, one per line.")
+	flags.BoolVar(&opts.networkOrder, "network-order", false, "Print the networks of each service by priority and the one used as default route, one service per line.")
 	flags.BoolVar(&opts.variables, "variables", false, "Print model variables and default values.")
+	flags.BoolVar(&opts.environment, "environment", false, "Print environment used for interpolation, or the environment of a service.")
+	flags.BoolVar(&opts.expansionReport, "expansion-report", false, "Print which anchor, merge key or extends source contributed each service key.")
+	flags.BoolVar(&opts.mergeTrace, "merge-trace", false, "Print which compose file contributed each field of the merged model.")
+	flags.StringArrayVar(&opts.interpolationTrace, "interpolation-trace", nil, "Explain where the value of a variable used for interpolation comes from.")
+	flags.BoolVar(&opts.validateOnly, "validate-only", false, "Report all schema violations with their location, don't print anything else")
 	flags.StringVarP(&opts.Output, "output", "o", "", "Save to file (default to stdout)")
 
 	return cmd
@@ -181,6 +212,8 @@ func runConfig(ctx context.Context, dockerCli command.Cli, opts configOptions, s
 			content, err = project.MarshalJSON()
 		case "yaml":
 			content, err = project.MarshalYAML()
+		case "k8s":
+			content, err = kube.Marshal(ctx, project)
 		default:
 			return fmt.Errorf("unsupported format %q", opts.Format)
 		}
@@ -189,6 +222,14 @@ func runConfig(ctx context.Context, dockerCli command.Cli, opts configOptions, s
 		}
 	}
 
+	if opts.Format == "json" {
+		var err error
+		content, err = canonicalJSON(content)
+		if err != nil {
+			return err
+		}
+	}
+
 	if !opts.noInterpolate {
 		content = escapeDollarSign(content)
 	}
@@ -253,6 +294,25 @@ func formatModel(model map[string]any, format string) (content []byte, err error
 	return
 }
 
+// canonicalJSON re-encodes JSON content with keys sorted at all levels and without HTML escaping,
+// so that output is stable for diffing, whatever the model was rendered from
+func canonicalJSON(content []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	var model any
+	if err := decoder.Decode(&model); err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBuffer([]byte{})
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(model); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 func runServices(ctx context.Context, dockerCli command.Cli, opts configOptions) error {
 	project, err := opts.ToProject(ctx, dockerCli, nil, cli.WithoutEnvironmentResolution)
 	if err != nil {
@@ -315,6 +375,26 @@ func runHash(ctx context.Context, dockerCli command.Cli, opts configOptions) err
 	return nil
 }
 
+func runNetworkOrder(ctx context.Context, dockerCli command.Cli, opts configOptions, services []string) error {
+	project, err := opts.ToProject(ctx, dockerCli, services, cli.WithoutEnvironmentResolution)
+	if err != nil {
+		return err
+	}
+	for _, name := range project.ServiceNames() {
+		s := project.Services[name]
+		order, route := compose.NetworkOrder(project, s)
+		if len(order) == 0 {
+			fmt.Fprintf(dockerCli.Out(), "%s network_mode:%s\n", name, s.NetworkMode)
+			continue
+		}
+		if route == "" {
+			route = "-"
+		}
+		fmt.Fprintf(dockerCli.Out(), "%s %s %s\n", name, strings.Join(order, ","), route)
+	}
+	return nil
+}
+
 func runProfiles(ctx context.Context, dockerCli command.Cli, opts configOptions, services []string) error {
 	set := map[string]struct{}{}
 	project, err := opts.ToProject(ctx, dockerCli, services, cli.WithoutEnvironmentResolution)
@@ -355,13 +435,121 @@ func runVariables(ctx context.Context, dockerCli command.Cli, opts configOptions
 		return err
 	}
 
+	declared, err := decodeVariables(model[extVariables])
+	if err != nil {
+		return err
+	}
+	delete(model, extVariables)
 	variables := template.ExtractVariables(model, template.DefaultPattern)
+	for name, d := range declared {
+		variable := variables[name]
+		variable.Name = name
+		variable.Required = variable.Required || d.Required
+		if variable.DefaultValue == "" {
+			variable.DefaultValue = d.defaultValue()
+		}
+		variables[name] = variable
+	}
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
 	return formatter.Print(variables, "", dockerCli.Out(), func(w io.Writer) {
-		for name, variable := range variables {
-			_, _ = fmt.Fprintf(w, "%s\t%t\t%s\t%s\n", name, variable.Required, variable.DefaultValue, variable.PresenceValue)
+		for _, name := range names {
+			variable := variables[name]
+			_, _ = fmt.Fprintf(w, "%s\t%t\t%s\t%s\t%s\t%s\n", name, variable.Required, variable.DefaultValue,
+				variable.PresenceValue, declared[name].Type, declared[name].Description)
+		}
+	}, "NAME", "REQUIRED", "DEFAULT VALUE", "ALTERNATE VALUE", "TYPE", "DESCRIPTION")
+}
+
+func runEnvironment(ctx context.Context, dockerCli command.Cli, opts configOptions, services []string) error {
+	if len(services) > 1 {
+		return fmt.Errorf("--environment accepts a single service, got %d", len(services))
+	}
+	project, err := opts.ToProject(ctx, dockerCli, services)
+	if err != nil {
+		return err
+	}
+	environment := project.Environment
+	if len(services) == 1 {
+		service, err := project.GetService(services[0])
+		if err != nil {
+			return err
+		}
+		environment = types.Mapping{}
+		for name, value := range service.Environment {
+			// variables declared without a value, and not set by the host, are not passed to the container
+			if value != nil {
+				environment[name] = *value
+			}
+		}
+	}
+	return formatEnvironment(dockerCli.Out(), environment, opts.Format)
+}
+
+// formatEnvironment writes environment sorted by variable name, as plain `NAME=value` lines by default, as a dotenv
+// file with values quoted as required, or as shell `export` statements to be sourced by a script
+func formatEnvironment(w io.Writer, environment types.Mapping, format string) error {
+	names := make([]string, 0, len(environment))
+	for name := range environment {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var line func(name, value string) string
+	switch format {
+	case "", "yaml":
+		line = func(name, value string) string {
+			return name + "=" + value
+		}
+	case "dotenv":
+		line = func(name, value string) string {
+			return name + "=" + dotenvQuote(value)
 		}
-	}, "NAME", "REQUIRED", "DEFAULT VALUE", "ALTERNATE VALUE")
+	case "shell":
+		line = func(name, value string) string {
+			return "export " + name + "=" + shellQuote(value)
+		}
+	default:
+		return fmt.Errorf("unsupported format %q for --environment, use dotenv or shell", format)
+	}
+	for _, name := range names {
+		if _, err := fmt.Fprintln(w, line(name, environment[name])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dotenvQuote returns value as is if it doesn't need quotes, otherwise double-quoted with escape sequences, and `$`
+// escaped so it isn't interpolated when the file is loaded as an env_file
+func dotenvQuote(value string) string {
+	if value != "" && strings.IndexFunc(value, isUnsafeEnvRune) < 0 {
+		return value
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(value) + `"`
+}
+
+// shellQuote returns value single-quoted for a POSIX shell, so it is never expanded
+func shellQuote(value string) string {
+	if value != "" && strings.IndexFunc(value, isUnsafeEnvRune) < 0 {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func isUnsafeEnvRune(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return false
+	case strings.ContainsRune("_-.,/:@+%", r):
+		return false
+	}
+	return true
 }
 
 func escapeDollarSign(marshal []byte) []byte {
@@ -0,0 +1,278 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/compose-spec/compose-go/v2/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/compose/v2/cmd/formatter"
+	"gopkg.in/yaml.v3"
+)
+
+// expansionEntry tells which source contributed a top-level key of a service definition
+type expansionEntry struct {
+	Service string
+	Key     string
+	Source  string
+}
+
+func runExpansionReport(ctx context.Context, dockerCli command.Cli, opts configOptions, services []string) error {
+	project, err := opts.ToProject(ctx, dockerCli, services, cli.WithoutEnvironmentResolution)
+	if err != nil {
+		return err
+	}
+
+	entries, err := expansionReport(project.ComposeFiles)
+	if err != nil {
+		return err
+	}
+	if len(services) > 0 {
+		selected := map[string]bool{}
+		for _, s := range services {
+			selected[s] = true
+		}
+		filtered := entries[:0]
+		for _, e := range entries {
+			if selected[e.Service] {
+				filtered = append(filtered, e)
+			}
+		}
+		entries = filtered
+	}
+
+	format := ""
+	if opts.Format == "json" {
+		format = formatter.JSON
+	}
+	return formatter.Print(entries, format, dockerCli.Out(), func(w io.Writer) {
+		for _, e := range entries {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", e.Service, e.Key, e.Source)
+		}
+	}, "SERVICE", "KEY", "SOURCE")
+}
+
+// expansionReport parses compose files as raw yaml, before anchors, merge keys and `extends` get resolved by the
+// loader, to collect for each service key the source it comes from. When multiple files are used, each file
+// defining a key for a service is reported, in loading order
+func expansionReport(files []string) ([]expansionEntry, error) {
+	var entries []expansionEntry
+	for _, file := range files {
+		root, err := parseYamlFile(file)
+		if err != nil {
+			return nil, err
+		}
+		services := mappingValue(root, "services")
+		if services == nil {
+			continue
+		}
+		for i := 0; i+1 < len(services.Content); i += 2 {
+			name := services.Content[i].Value
+			sources, err := serviceKeySources(file, name, services.Content[i+1], 0)
+			if err != nil {
+				return nil, err
+			}
+			keys := make([]string, 0, len(sources))
+			for k := range sources {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				entries = append(entries, expansionEntry{Service: name, Key: k, Source: sources[k]})
+			}
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Service < entries[j].Service
+	})
+	return entries, nil
+}
+
+// maxExtendsDepth protects against `extends` cycles, which are reported by the loader anyway
+const maxExtendsDepth = 10
+
+func serviceKeySources(file string, service string, node *yaml.Node, depth int) (map[string]string, error) {
+	if depth > maxExtendsDepth {
+		return nil, fmt.Errorf("service %q: too many levels of extends", service)
+	}
+	sources := map[string]string{}
+	if node.Kind == yaml.AliasNode {
+		for k, source := range anchorKeySources(file, node) {
+			sources[k] = source
+		}
+		return sources, nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return sources, nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Value != "<<" {
+			continue
+		}
+		// with a sequence of aliases, first ones take precedence
+		aliases := []*yaml.Node{value}
+		if value.Kind == yaml.SequenceNode {
+			aliases = value.Content
+		}
+		for j := len(aliases) - 1; j >= 0; j-- {
+			for k, source := range anchorKeySources(file, aliases[j]) {
+				sources[k] = source
+			}
+		}
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		switch {
+		case key.Value == "<<":
+		case key.Value == "extends":
+			extended, err := extendsKeySources(file, value, depth)
+			if err != nil {
+				return nil, fmt.Errorf("service %q: %w", service, err)
+			}
+			// keys set by the service itself or by merged anchors override inherited ones
+			for k, source := range extended {
+				if _, ok := sources[k]; !ok {
+					sources[k] = source
+				}
+			}
+		case value.Kind == yaml.AliasNode:
+			sources[key.Value] = anchorSource(file, value)
+		default:
+			sources[key.Value] = fmt.Sprintf("%s:%d", file, key.Line)
+		}
+	}
+	return sources, nil
+}
+
+func anchorKeySources(file string, alias *yaml.Node) map[string]string {
+	sources := map[string]string{}
+	if alias.Kind != yaml.AliasNode || alias.Alias == nil || alias.Alias.Kind != yaml.MappingNode {
+		return sources
+	}
+	source := anchorSource(file, alias)
+	for i := 0; i+1 < len(alias.Alias.Content); i += 2 {
+		sources[alias.Alias.Content[i].Value] = source
+	}
+	return sources
+}
+
+func anchorSource(file string, alias *yaml.Node) string {
+	line := alias.Line
+	if alias.Alias != nil {
+		line = alias.Alias.Line
+	}
+	return fmt.Sprintf("anchor &%s (%s:%d)", alias.Value, file, line)
+}
+
+func extendsKeySources(file string, extends *yaml.Node, depth int) (map[string]string, error) {
+	targetFile, target, node, err := resolveExtends(file, extends)
+	if err != nil {
+		return nil, err
+	}
+	inherited, err := serviceKeySources(targetFile, target, node, depth+1)
+	if err != nil {
+		return nil, err
+	}
+	for k, source := range inherited {
+		inherited[k] = fmt.Sprintf("extends %s < %s", target, source)
+	}
+	return inherited, nil
+}
+
+// resolveExtends returns the file and the name of the service extended by a service declared in file, and its node
+func resolveExtends(file string, extends *yaml.Node) (string, string, *yaml.Node, error) {
+	var target, targetFile string
+	switch extends.Kind {
+	case yaml.ScalarNode:
+		target = extends.Value
+	case yaml.MappingNode:
+		if s := mappingValue(extends, "service"); s != nil {
+			target = s.Value
+		}
+		if f := mappingValue(extends, "file"); f != nil {
+			targetFile = f.Value
+		}
+	}
+	if target == "" {
+		return "", "", nil, fmt.Errorf("extends requires a service name")
+	}
+
+	if targetFile == "" {
+		targetFile = file
+	} else if !filepath.IsAbs(targetFile) {
+		targetFile = filepath.Join(filepath.Dir(file), targetFile)
+	}
+	root, err := parseYamlFile(targetFile)
+	if err != nil {
+		return "", "", nil, err
+	}
+	node := mappingValue(mappingValue(root, "services"), target)
+	if node == nil {
+		return "", "", nil, fmt.Errorf("cannot extend service %q: not found in %s", target, targetFile)
+	}
+	return targetFile, target, node, nil
+}
+
+// parseYamlFile parses a compose file, which is read from stdin for `-`
+func parseYamlFile(file string) (*yaml.Node, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+		if err == nil {
+			// stdin is read again by the next parse
+			err = replayStdin(data)
+		}
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	if len(document.Content) == 0 {
+		return nil, nil
+	}
+	return document.Content[0], nil
+}
+
+// mappingValue returns the value set for key in a yaml mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
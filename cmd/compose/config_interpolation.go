@@ -0,0 +1,210 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+
+	"github.com/compose-spec/compose-go/v2/cli"
+	"github.com/compose-spec/compose-go/v2/dotenv"
+	"github.com/compose-spec/compose-go/v2/template"
+	"github.com/compose-spec/compose-go/v2/utils"
+	"github.com/docker/cli/cli/command"
+
+	"github.com/docker/compose/v2/cmd/formatter"
+)
+
+// interpolationSource is a place a variable used for interpolation gets a value from
+type interpolationSource struct {
+	Variable string
+	Source   string
+	Value    string
+	// Used tells the value is the one interpolated in compose files
+	Used bool
+}
+
+func runInterpolationTrace(ctx context.Context, dockerCli command.Cli, opts configOptions) error {
+	po, err := opts.ProjectOptions.toProjectOptions(opts.ToProjectOptions()...)
+	if err != nil {
+		return err
+	}
+	osEnv := utils.GetAsEqualsMap(os.Environ())
+	var sources []interpolationSource
+	for _, name := range opts.interpolationTrace {
+		s, err := interpolationTrace(po, osEnv, name)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, s...)
+	}
+
+	format := ""
+	if opts.Format == "json" {
+		format = formatter.JSON
+	}
+	return formatter.Print(sources, format, dockerCli.Out(), func(w io.Writer) {
+		for _, s := range sources {
+			used := ""
+			if s.Used {
+				used = "*"
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.Variable, s.Source, s.Value, used)
+		}
+	}, "VARIABLE", "SOURCE", "VALUE", "USED")
+}
+
+// interpolationTrace lists the sources a variable gets a value from, by precedence: shell environment, env files
+// (the last one defining the variable wins), top-level `x-variables` defaults, then the `${VAR:-default}` and
+// `${VAR:+alternate}` values set by each compose file, override files included
+func interpolationTrace(po *cli.ProjectOptions, osEnv map[string]string, name string) ([]interpolationSource, error) {
+	var sources []interpolationSource
+	resolved := false
+	if value, ok := osEnv[name]; ok {
+		sources = append(sources, interpolationSource{Variable: name, Source: "shell", Value: value, Used: true})
+		resolved = true
+	}
+
+	last := -1
+	for _, file := range po.EnvFiles {
+		env, err := dotenv.GetEnvFromFile(po.Environment, []string{file})
+		if err != nil {
+			return nil, err
+		}
+		if value, ok := env[name]; ok {
+			sources = append(sources, interpolationSource{Variable: name, Source: "env_file " + file, Value: value})
+			last = len(sources) - 1
+		}
+	}
+	if last >= 0 && !resolved {
+		sources[last].Used = true
+		resolved = true
+	}
+
+	// models of each compose file, with the files they include or extend, and the merged one, which tells the values
+	// actually used, as overridden by later files
+	merged, err := loadRawModel(po)
+	if err != nil {
+		return nil, err
+	}
+	workingDir, err := po.GetWorkingDir()
+	if err != nil {
+		return nil, err
+	}
+	models := make([]map[string]any, len(po.ConfigPaths))
+	for i, file := range po.ConfigPaths {
+		single := *po
+		single.ConfigPaths = []string{file}
+		single.WorkingDir = workingDir
+		if models[i], err = loadRawModel(&single); err != nil {
+			return nil, err
+		}
+	}
+
+	declaring := -1
+	for i, model := range models {
+		variables, err := decodeVariables(model[extVariables])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", po.ConfigPaths[i], err)
+		}
+		if variable, ok := variables[name]; ok && variable.Default != nil {
+			sources = append(sources, interpolationSource{
+				Variable: name,
+				Source:   extVariables + " in " + po.ConfigPaths[i],
+				Value:    variable.defaultValue(),
+			})
+			declaring = len(sources) - 1
+		}
+	}
+	if declaring >= 0 && !resolved {
+		// later files override the default set by earlier ones
+		sources[declaring].Used = true
+	}
+
+	value := po.Environment[name]
+	delete(merged, extVariables)
+	usedDefaults, usedAlternates := variableValues(merged, name)
+	for i, model := range models {
+		delete(model, extVariables)
+		defaults, alternates := variableValues(model, name)
+		for _, d := range defaults {
+			sources = append(sources, interpolationSource{
+				Variable: name,
+				Source:   "default in " + po.ConfigPaths[i],
+				Value:    d,
+				Used:     value == "" && slices.Contains(usedDefaults, d),
+			})
+		}
+		for _, a := range alternates {
+			sources = append(sources, interpolationSource{
+				Variable: name,
+				Source:   "alternate value in " + po.ConfigPaths[i],
+				Value:    a,
+				Used:     value != "" && slices.Contains(usedAlternates, a),
+			})
+		}
+	}
+
+	if len(sources) == 0 {
+		sources = append(sources, interpolationSource{Variable: name, Source: "not set"})
+	}
+	return sources, nil
+}
+
+// variableValues returns the distinct `${VAR:-default}` and `${VAR:+alternate}` values set for variable name by model
+func variableValues(model any, name string) (defaults []string, alternates []string) {
+	switch v := model.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			d, a := variableValues(v[key], name)
+			defaults, alternates = appendDistinct(defaults, d...), appendDistinct(alternates, a...)
+		}
+	case []any:
+		for _, item := range v {
+			d, a := variableValues(item, name)
+			defaults, alternates = appendDistinct(defaults, d...), appendDistinct(alternates, a...)
+		}
+	case string:
+		if variable, ok := template.ExtractVariables(map[string]any{"": v}, template.DefaultPattern)[name]; ok {
+			if variable.DefaultValue != "" {
+				defaults = append(defaults, variable.DefaultValue)
+			}
+			if variable.PresenceValue != "" {
+				alternates = append(alternates, variable.PresenceValue)
+			}
+		}
+	}
+	return defaults, alternates
+}
+
+func appendDistinct(values []string, add ...string) []string {
+	for _, v := range add {
+		if !slices.Contains(values, v) {
+			values = append(values, v)
+		}
+	}
+	return values
+}
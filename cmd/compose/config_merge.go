@@ -0,0 +1,344 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/format"
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli/command"
+	"gopkg.in/yaml.v3"
+
+	"github.com/docker/compose/v2/cmd/formatter"
+)
+
+// mergeEntry tells which compose file contributed the value of a field, once all files are merged
+type mergeEntry struct {
+	Field  string
+	Source string
+	// Overrides are the sources of values replaced by Source, in loading order
+	Overrides []string `json:",omitempty"`
+}
+
+func runMergeTrace(ctx context.Context, dockerCli command.Cli, opts configOptions, services []string) error {
+	po, err := opts.ProjectOptions.toProjectOptions()
+	if err != nil {
+		return err
+	}
+	entries, err := mergeTrace(po.ConfigPaths)
+	if err != nil {
+		return err
+	}
+	if len(services) > 0 {
+		filtered := entries[:0]
+		for _, e := range entries {
+			for _, s := range services {
+				if strings.HasPrefix(e.Field, "services."+s+".") {
+					filtered = append(filtered, e)
+					break
+				}
+			}
+		}
+		entries = filtered
+	}
+
+	format := ""
+	if opts.Format == "json" {
+		format = formatter.JSON
+	}
+	return formatter.Print(entries, format, dockerCli.Out(), func(w io.Writer) {
+		for _, e := range entries {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", e.Field, e.Source, strings.Join(e.Overrides, ", "))
+		}
+	}, "FIELD", "SOURCE", "OVERRIDES")
+}
+
+// mergeOverridden are the service fields an override file replaces as a whole, rather than being merged
+var mergeOverridden = map[string]bool{
+	"command":    true,
+	"entrypoint": true,
+	"test":       true,
+}
+
+// mergeByKey are the service sequences which can also be set as a mapping, so their items get merged by key
+var mergeByKey = map[string]bool{
+	"environment":         true,
+	"labels":              true,
+	"annotations":         true,
+	"args":                true,
+	"sysctls":             true,
+	"extra_hosts":         true,
+	"networks":            true,
+	"additional_contexts": true,
+}
+
+type mergeTracer struct {
+	entries map[string]*mergeEntry
+	order   []string
+}
+
+// mergeTrace parses compose files as raw yaml, and applies the merge rules used by the loader for multiple compose
+// files, included files and extended services, to tell the file and line each field of the merged model comes from.
+// Lists like ports, volumes or environment are merged by item, identified as the loader does, so each item is
+// reported as a field
+func mergeTrace(files []string) ([]mergeEntry, error) {
+	t := mergeTracer{entries: map[string]*mergeEntry{}}
+	for _, file := range files {
+		if err := t.traceFile(file, 0); err != nil {
+			return nil, err
+		}
+	}
+	entries := make([]mergeEntry, 0, len(t.order))
+	for _, field := range t.order {
+		entries = append(entries, *t.entries[field])
+	}
+	return entries, nil
+}
+
+// traceFile traces the fields set by a compose file, after the ones set by the files it includes
+func (t *mergeTracer) traceFile(file string, depth int) error {
+	if depth > maxExtendsDepth {
+		return fmt.Errorf("%s: too many levels of include", file)
+	}
+	root, err := parseYamlFile(file)
+	if err != nil {
+		return err
+	}
+	if root == nil {
+		return nil
+	}
+	root = resolveAlias(root)
+	for _, included := range includedFiles(file, mappingValue(root, "include")) {
+		if err := t.traceFile(included, depth+1); err != nil {
+			return err
+		}
+	}
+	return t.walk(file, "", "", root, 0)
+}
+
+// includedFiles returns the compose files declared by a top-level include, relative to the including file
+func includedFiles(file string, include *yaml.Node) []string {
+	if include == nil || include.Kind != yaml.SequenceNode {
+		return nil
+	}
+	var paths []*yaml.Node
+	for _, item := range include.Content {
+		item = resolveAlias(item)
+		if item.Kind == yaml.MappingNode {
+			item = mappingValue(item, "path")
+			if item == nil {
+				continue
+			}
+			item = resolveAlias(item)
+		}
+		if item.Kind == yaml.SequenceNode {
+			paths = append(paths, item.Content...)
+		} else {
+			paths = append(paths, item)
+		}
+	}
+	var files []string
+	for _, path := range paths {
+		f := resolveAlias(path).Value
+		if f == "" {
+			continue
+		}
+		if !filepath.IsAbs(f) {
+			f = filepath.Join(filepath.Dir(file), f)
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+func (t *mergeTracer) set(field string, source string) {
+	if e, ok := t.entries[field]; ok {
+		e.Overrides = append(e.Overrides, e.Source)
+		e.Source = source
+		return
+	}
+	t.entries[field] = &mergeEntry{Field: field, Source: source}
+	t.order = append(t.order, field)
+}
+
+func (t *mergeTracer) walk(file string, field string, key string, node *yaml.Node, depth int) error {
+	source := fmt.Sprintf("%s:%d", file, node.Line)
+	switch {
+	case node.Kind == yaml.MappingNode && !mergeOverridden[key]:
+		if extends := mappingValue(node, "extends"); extends != nil && isServiceField(field) {
+			// the extended service is merged first, then overridden by the service definition
+			if depth > maxExtendsDepth {
+				return fmt.Errorf("%s: too many levels of extends", field)
+			}
+			extendedFile, _, extended, err := resolveExtends(file, resolveAlias(extends))
+			if err != nil {
+				return fmt.Errorf("%s: %w", field, err)
+			}
+			if err := t.walk(extendedFile, field, key, resolveAlias(extended), depth+1); err != nil {
+				return err
+			}
+		}
+		// merged anchors contribute their keys as if set inline, but keys set by the mapping itself take precedence
+		if merged := mappingValue(node, "<<"); merged != nil {
+			for _, alias := range mergedMappings(resolveAlias(merged)) {
+				if err := t.walk(file, field, key, alias, depth); err != nil {
+					return err
+				}
+			}
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			k, v := node.Content[i], resolveAlias(node.Content[i+1])
+			switch {
+			case k.Value == "<<":
+			case field == "" && (strings.HasPrefix(k.Value, "x-") || k.Value == "include"):
+				// top-level extensions are only used to declare anchors, included files are traced first
+			case k.Value == "extends" && isServiceField(field):
+			default:
+				if err := t.walk(file, joinField(field, k.Value), k.Value, v, depth); err != nil {
+					return err
+				}
+			}
+		}
+	case node.Kind == yaml.SequenceNode && !mergeOverridden[key]:
+		for _, item := range node.Content {
+			item = resolveAlias(item)
+			if mergeByKey[key] && item.Kind == yaml.ScalarNode {
+				k, _, _ := strings.Cut(item.Value, "=")
+				t.set(joinField(field, k), fmt.Sprintf("%s:%d", file, item.Line))
+				continue
+			}
+			t.set(fmt.Sprintf("%s[%s]", field, sequenceItemKey(key, item)), fmt.Sprintf("%s:%d", file, item.Line))
+		}
+	default:
+		t.set(field, source)
+	}
+	return nil
+}
+
+// isServiceField tells if field is a service definition, as services.web
+func isServiceField(field string) bool {
+	name, ok := strings.CutPrefix(field, "services.")
+	return ok && !strings.Contains(name, ".")
+}
+
+func joinField(field string, key string) string {
+	if field == "" {
+		return key
+	}
+	return field + "." + key
+}
+
+// sequenceItemKey identifies an item of a sequence the way the loader does to detect redefinitions. Ports set with
+// short and long syntax get the same key, so an override file can redefine a port declared with the other syntax
+func sequenceItemKey(key string, item *yaml.Node) string {
+	switch key {
+	case "ports":
+		if k := portKey(item); k != "" {
+			return k
+		}
+	case "volumes", "devices":
+		if item.Kind == yaml.ScalarNode {
+			if volume, err := format.ParseVolume(item.Value); err == nil {
+				return volume.Target
+			}
+		}
+		if target := mappingValue(item, "target"); target != nil {
+			return target.Value
+		}
+	case "secrets", "configs":
+		defaultPath := ""
+		if key == "secrets" {
+			defaultPath = "/run/secrets"
+		}
+		if target := mappingValue(item, "target"); target != nil {
+			return target.Value
+		}
+		if src := mappingValue(item, "source"); src != nil {
+			return defaultPath + "/" + src.Value
+		}
+		if item.Kind == yaml.ScalarNode {
+			return defaultPath + "/" + item.Value
+		}
+	case "env_file":
+		if path := mappingValue(item, "path"); path != nil {
+			return path.Value
+		}
+	}
+	if item.Kind == yaml.ScalarNode {
+		return item.Value
+	}
+	b, err := yaml.Marshal(item)
+	if err != nil {
+		return fmt.Sprintf("%d", item.Line)
+	}
+	return strings.TrimSpace(string(b))
+}
+
+// portKey returns a port as [host_ip:][published:]target/protocol, whatever the syntax it is declared with, or an empty
+// string if it can't be parsed, as a port range
+func portKey(item *yaml.Node) string {
+	var port types.ServicePortConfig
+	switch item.Kind {
+	case yaml.ScalarNode:
+		ports, err := types.ParsePortConfig(item.Value)
+		if err != nil || len(ports) != 1 {
+			return ""
+		}
+		port = ports[0]
+	case yaml.MappingNode:
+		if err := item.Decode(&port); err != nil {
+			return ""
+		}
+	default:
+		return ""
+	}
+	if port.Protocol == "" {
+		port.Protocol = "tcp"
+	}
+	key := fmt.Sprintf("%d/%s", port.Target, port.Protocol)
+	if port.Published != "" {
+		key = port.Published + ":" + key
+	}
+	if port.HostIP != "" && port.HostIP != "0.0.0.0" {
+		key = port.HostIP + ":" + key
+	}
+	return key
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+// mergedMappings returns the mappings merged by a `<<` key, first ones taking precedence
+func mergedMappings(node *yaml.Node) []*yaml.Node {
+	if node.Kind != yaml.SequenceNode {
+		return []*yaml.Node{node}
+	}
+	var mappings []*yaml.Node
+	for i := len(node.Content) - 1; i >= 0; i-- {
+		mappings = append(mappings, resolveAlias(node.Content[i]))
+	}
+	return mappings
+}
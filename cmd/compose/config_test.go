@@ -0,0 +1,304 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	content, err := canonicalJSON([]byte(`{"services":{"web":{"image":"nginx","command":["a<b"],"mem_limit":1073741824}},"name":"test"}`))
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), `{
+  "name": "test",
+  "services": {
+    "web": {
+      "command": [
+        "a<b"
+      ],
+      "image": "nginx",
+      "mem_limit": 1073741824
+    }
+  }
+}
+`)
+}
+
+func TestFormatEnvironment(t *testing.T) {
+	environment := types.Mapping{
+		"PLAIN":   "postgres://db:5432/app",
+		"EMPTY":   "",
+		"SPACES":  "hello world",
+		"QUOTES":  `it's "quoted"`,
+		"DOLLAR":  "$HOME",
+		"NEWLINE": "a\nb",
+	}
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{
+			format: "dotenv",
+			expected: `DOLLAR="\$HOME"
+EMPTY=""
+NEWLINE="a\nb"
+PLAIN=postgres://db:5432/app
+QUOTES="it's \"quoted\""
+SPACES="hello world"
+`,
+		},
+		{
+			format: "shell",
+			expected: `export DOLLAR='$HOME'
+export EMPTY=''
+export NEWLINE='a
+b'
+export PLAIN=postgres://db:5432/app
+export QUOTES='it'\''s "quoted"'
+export SPACES='hello world'
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NilError(t, formatEnvironment(&buf, environment, tt.format))
+			assert.Equal(t, buf.String(), tt.expected)
+		})
+	}
+
+	err := formatEnvironment(&bytes.Buffer{}, environment, "json")
+	assert.Error(t, err, `unsupported format "json" for --environment, use dotenv or shell`)
+}
+
+func TestExpansionReport(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	assert.NilError(t, os.WriteFile(base, []byte(`services:
+  common:
+    restart: always
+`), 0o600))
+	file := filepath.Join(dir, "compose.yaml")
+	assert.NilError(t, os.WriteFile(file, []byte(`x-defaults: &defaults
+  image: nginx
+  environment:
+    FOO: bar
+services:
+  web:
+    <<: *defaults
+    extends:
+      file: base.yaml
+      service: common
+    image: httpd
+`), 0o600))
+
+	entries, err := expansionReport([]string{file})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, entries, []expansionEntry{
+		{Service: "web", Key: "environment", Source: "anchor &defaults (" + file + ":1)"},
+		{Service: "web", Key: "image", Source: file + ":11"},
+		{Service: "web", Key: "restart", Source: "extends common < " + base + ":3"},
+	})
+}
+
+func TestMergeTrace(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "compose.yaml")
+	assert.NilError(t, os.WriteFile(file, []byte(`services:
+  web:
+    image: nginx
+    command: ["nginx", "-g", "daemon off;"]
+    ports:
+      - 8080:80
+    environment:
+      DEBUG: "false"
+      PORT: "80"
+    volumes:
+      - ./html:/usr/share/nginx/html
+`), 0o600))
+	override := filepath.Join(dir, "compose.override.yaml")
+	assert.NilError(t, os.WriteFile(override, []byte(`services:
+  web:
+    command: ["nginx-debug"]
+    ports:
+      - 8443:443
+    environment:
+      - DEBUG=true
+    volumes:
+      - type: bind
+        source: ./dev
+        target: /usr/share/nginx/html
+`), 0o600))
+
+	entries, err := mergeTrace([]string{file, override})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, entries, []mergeEntry{
+		{Field: "services.web.image", Source: file + ":3"},
+		{Field: "services.web.command", Source: override + ":3", Overrides: []string{file + ":4"}},
+		{Field: "services.web.ports[8080:80/tcp]", Source: file + ":6"},
+		{Field: "services.web.environment.DEBUG", Source: override + ":7", Overrides: []string{file + ":8"}},
+		{Field: "services.web.environment.PORT", Source: file + ":9"},
+		{Field: "services.web.volumes[/usr/share/nginx/html]", Source: override + ":9", Overrides: []string{file + ":11"}},
+		{Field: "services.web.ports[8443:443/tcp]", Source: override + ":5"},
+	})
+}
+
+func TestMergeTraceIncludeExtends(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	assert.NilError(t, os.WriteFile(base, []byte(`services:
+  common:
+    restart: always
+    ports:
+      - 8080:80
+`), 0o600))
+	db := filepath.Join(dir, "db.yaml")
+	assert.NilError(t, os.WriteFile(db, []byte(`services:
+  db:
+    image: postgres
+`), 0o600))
+	file := filepath.Join(dir, "compose.yaml")
+	assert.NilError(t, os.WriteFile(file, []byte(`include:
+  - db.yaml
+services:
+  web:
+    extends:
+      file: base.yaml
+      service: common
+    image: nginx
+    ports:
+      - target: 80
+        published: "8080"
+        protocol: tcp
+`), 0o600))
+
+	entries, err := mergeTrace([]string{file})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, entries, []mergeEntry{
+		{Field: "services.db.image", Source: db + ":3"},
+		{Field: "services.web.restart", Source: base + ":3"},
+		{Field: "services.web.ports[8080:80/tcp]", Source: file + ":10", Overrides: []string{base + ":5"}},
+		{Field: "services.web.image", Source: file + ":8"},
+	})
+}
+
+func TestInterpolationTrace(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "compose.yaml")
+	assert.NilError(t, os.WriteFile(file, []byte(`x-variables:
+  TRACE_PORT:
+    default: 8080
+services:
+  web:
+    image: nginx:${TRACE_TAG:-latest}
+    user: ${TRACE_USER:-root}
+    ports:
+      - ${TRACE_PORT}:80
+`), 0o600))
+	override := filepath.Join(dir, "compose.override.yaml")
+	assert.NilError(t, os.WriteFile(override, []byte(`services:
+  web:
+    image: nginx:${TRACE_TAG:-dev}
+    user: ${TRACE_USER:-app}
+    labels:
+      debug: ${TRACE_DEBUG:+enabled}
+`), 0o600))
+	dotEnv := filepath.Join(dir, ".env")
+	assert.NilError(t, os.WriteFile(dotEnv, []byte("TRACE_TAG=1.0\nTRACE_DEBUG=1\n"), 0o600))
+	localEnv := filepath.Join(dir, "local.env")
+	assert.NilError(t, os.WriteFile(localEnv, []byte("TRACE_TAG=2.0\n"), 0o600))
+
+	opts := ProjectOptions{ConfigPaths: []string{file, override}, EnvFiles: []string{dotEnv, localEnv}}
+	po, err := opts.toProjectOptions()
+	assert.NilError(t, err)
+
+	sources, err := interpolationTrace(po, map[string]string{}, "TRACE_TAG")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, sources, []interpolationSource{
+		{Variable: "TRACE_TAG", Source: "env_file " + dotEnv, Value: "1.0"},
+		{Variable: "TRACE_TAG", Source: "env_file " + localEnv, Value: "2.0", Used: true},
+		{Variable: "TRACE_TAG", Source: "default in " + file, Value: "latest"},
+		{Variable: "TRACE_TAG", Source: "default in " + override, Value: "dev"},
+	})
+
+	sources, err = interpolationTrace(po, map[string]string{"TRACE_TAG": "3.0"}, "TRACE_TAG")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, sources[0], interpolationSource{Variable: "TRACE_TAG", Source: "shell", Value: "3.0", Used: true})
+	assert.Assert(t, !sources[2].Used)
+
+	sources, err = interpolationTrace(po, map[string]string{}, "TRACE_PORT")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, sources, []interpolationSource{
+		{Variable: "TRACE_PORT", Source: "x-variables in " + file, Value: "8080", Used: true},
+	})
+
+	sources, err = interpolationTrace(po, map[string]string{}, "TRACE_DEBUG")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, sources, []interpolationSource{
+		{Variable: "TRACE_DEBUG", Source: "env_file " + dotEnv, Value: "1", Used: true},
+		{Variable: "TRACE_DEBUG", Source: "alternate value in " + override, Value: "enabled", Used: true},
+	})
+
+	// only the default of the override file is used
+	sources, err = interpolationTrace(po, map[string]string{}, "TRACE_USER")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, sources, []interpolationSource{
+		{Variable: "TRACE_USER", Source: "default in " + file, Value: "root"},
+		{Variable: "TRACE_USER", Source: "default in " + override, Value: "app", Used: true},
+	})
+
+	sources, err = interpolationTrace(po, map[string]string{}, "TRACE_UNKNOWN")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, sources, []interpolationSource{{Variable: "TRACE_UNKNOWN", Source: "not set"}})
+}
+
+func TestValidateComposeFiles(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "compose.yaml")
+	assert.NilError(t, os.WriteFile(file, []byte(`services:
+  web:
+    image: nginx
+    restart: 12
+    unknown: true
+    labels:
+      com.example.x: "1"
+    mem_limit: ${MEM}
+  db:
+    deploy:
+      replicas: "two"
+volumez: {}
+`), 0o600))
+
+	violations, err := validateComposeFiles([]string{file})
+	assert.NilError(t, err)
+	var reported []string
+	for _, v := range violations {
+		reported = append(reported, v.String())
+	}
+	assert.DeepEqual(t, reported, []string{
+		file + ":4:14: services.web.restart Invalid type. Expected: string, given: integer",
+		file + ":5:5: services.web Additional property unknown is not allowed",
+		file + ":11:17: services.db.deploy.replicas Invalid type. Expected: integer, given: string",
+		file + ":12:1: (root) Additional property volumez is not allowed",
+	})
+}
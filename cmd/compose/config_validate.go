@@ -0,0 +1,176 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/schema"
+	"github.com/docker/cli/cli/command"
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+
+	"github.com/docker/compose/v2/pkg/compose"
+)
+
+// schemaViolation locates a compose file content which doesn't match the compose specification schema
+type schemaViolation struct {
+	File        string
+	Line        int
+	Column      int
+	Field       string
+	Description string
+}
+
+func (v schemaViolation) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s %s", v.File, v.Line, v.Column, v.Field, v.Description)
+}
+
+func runValidateOnly(ctx context.Context, dockerCli command.Cli, opts configOptions, services []string) error {
+	options, err := opts.ProjectOptions.toProjectOptions()
+	if err != nil {
+		return compose.WrapComposeError(err)
+	}
+	var files []string
+	for _, f := range options.ConfigPaths {
+		if f != "-" {
+			files = append(files, f)
+		}
+	}
+	violations, err := validateComposeFiles(files)
+	if err != nil {
+		return compose.WrapComposeError(err)
+	}
+	for _, v := range violations {
+		_, _ = fmt.Fprintln(dockerCli.Err(), v.String())
+	}
+	if len(violations) > 0 {
+		return compose.WrapCategorisedComposeError(fmt.Errorf("%d schema violation(s) found", len(violations)), compose.ComposeParseFailure)
+	}
+
+	// schema is valid, let the loader report interpolation or consistency errors
+	_, err = opts.ToProject(ctx, dockerCli, services)
+	return err
+}
+
+// validateComposeFiles checks each compose file against the compose specification schema, and returns all the
+// violations found with their location. Values relying on interpolation are not checked, as those are only
+// validated by the loader once variables are resolved
+func validateComposeFiles(files []string) ([]schemaViolation, error) {
+	schemaLoader := gojsonschema.NewStringLoader(schema.Schema)
+	var violations []schemaViolation
+	for _, file := range files {
+		root, err := parseYamlFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if root == nil {
+			continue
+		}
+		var model map[string]any
+		if err := root.Decode(&model); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		result, err := gojsonschema.Validate(schemaLoader, gojsonschema.NewGoLoader(model))
+		if err != nil {
+			return nil, err
+		}
+		var found []schemaViolation
+		errs := result.Errors()
+		for _, e := range errs {
+			if isInterpolated(e.Value()) || isSummaryError(e, errs) {
+				continue
+			}
+			path := strings.Split(e.Context().String("\x00"), "\x00")[1:]
+			property, isKey := e.Details()["property"].(string)
+			isKey = isKey && e.Type() == "additional_property_not_allowed"
+			if isKey {
+				path = append(path, property)
+			}
+			node := lookupNode(root, path, isKey)
+			found = append(found, schemaViolation{
+				File:        file,
+				Line:        node.Line,
+				Column:      node.Column,
+				Field:       e.Field(),
+				Description: e.Description(),
+			})
+		}
+		sort.SliceStable(found, func(i, j int) bool {
+			if found[i].Line != found[j].Line {
+				return found[i].Line < found[j].Line
+			}
+			return found[i].Column < found[j].Column
+		})
+		violations = append(violations, found...)
+	}
+	return violations, nil
+}
+
+func isInterpolated(value any) bool {
+	s, ok := value.(string)
+	return ok && strings.Contains(s, "$")
+}
+
+// isSummaryError returns true for oneOf/anyOf errors, which are detailed by more specific ones
+func isSummaryError(e gojsonschema.ResultError, errs []gojsonschema.ResultError) bool {
+	if e.Type() != "number_one_of" && e.Type() != "number_any_of" {
+		return false
+	}
+	for _, other := range errs {
+		if other != e && strings.HasPrefix(other.Field(), e.Field()) {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupNode returns the yaml node at path, or the deepest node found along it. The key node is returned rather than
+// its value when isKey is set, as additional properties are reported on their key
+func lookupNode(node *yaml.Node, path []string, isKey bool) *yaml.Node {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	if len(path) == 0 {
+		return node
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		// keys can contain dots, so path segments can't be trusted to match a single key
+		for n := len(path); n > 0; n-- {
+			key := strings.Join(path[:n], ".")
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value != key {
+					continue
+				}
+				if n == len(path) && isKey {
+					return node.Content[i]
+				}
+				return lookupNode(node.Content[i+1], path[n:], isKey)
+			}
+		}
+	case yaml.SequenceNode:
+		if i, err := strconv.Atoi(path[0]); err == nil && i < len(node.Content) {
+			return lookupNode(node.Content[i], path[1:], isKey)
+		}
+	}
+	return node
+}
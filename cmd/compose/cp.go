@@ -36,6 +36,7 @@ type copyOptions struct {
 	all         bool
 	followLink  bool
 	copyUIDGID  bool
+	sync        bool
 }
 
 func copyCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
@@ -71,6 +72,7 @@ func copyCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service)
 	flags.MarkDeprecated("all", "By default all the containers of the service will get the source file/directory to be copied") //nolint:errcheck
 	flags.BoolVarP(&opts.followLink, "follow-link", "L", false, "Always follow symbol link in SRC_PATH")
 	flags.BoolVarP(&opts.copyUIDGID, "archive", "a", false, "Archive mode (copy all uid/gid information)")
+	flags.BoolVar(&opts.sync, "sync", false, "Only copy files of SRC_PATH directory changed since last sync, and delete removed ones")
 
 	return copyCmd
 }
@@ -88,5 +90,6 @@ func runCopy(ctx context.Context, dockerCli command.Cli, backend api.Service, op
 		Index:       opts.index,
 		FollowLink:  opts.followLink,
 		CopyUIDGID:  opts.copyUIDGID,
+		Sync:        opts.sync,
 	})
 }
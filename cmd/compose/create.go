@@ -25,27 +25,47 @@ import (
 	"time"
 
 	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/containerd/containerd/platforms"
 	"github.com/docker/cli/cli/command"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/utils"
 )
 
 type createOptions struct {
-	Build         bool
-	noBuild       bool
-	Pull          string
-	pullChanged   bool
-	removeOrphans bool
-	ignoreOrphans bool
-	forceRecreate bool
-	noRecreate    bool
-	recreateDeps  bool
-	noInherit     bool
-	timeChanged   bool
-	timeout       int
-	quietPull     bool
-	scale         []string
+	Build               bool
+	noBuild             bool
+	Pull                string
+	pullChanged         bool
+	removeOrphans       bool
+	ignoreOrphans       bool
+	forceRecreate       bool
+	noRecreate          bool
+	recreateDeps        bool
+	noInherit           bool
+	timeChanged         bool
+	timeout             int
+	quietPull           bool
+	scale               []string
+	adopt               bool
+	adoptOrphans        bool
+	ignoreResourceCheck bool
+	rollbackOnCancel    bool
+	platform            string
+	healthcheck         healthcheckOptions
+}
+
+// healthcheckOptions override services' healthchecks for a single invocation
+type healthcheckOptions struct {
+	cmd           string
+	interval      time.Duration
+	timeout       time.Duration
+	startPeriod   time.Duration
+	startInterval time.Duration
+	retries       int
+	disable       bool
 }
 
 func createCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
@@ -61,12 +81,16 @@ func createCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service
 			if opts.Build && opts.noBuild {
 				return fmt.Errorf("--build and --no-build are incompatible")
 			}
-			if opts.forceRecreate && opts.noRecreate {
-				return fmt.Errorf("--force-recreate and --no-recreate are incompatible")
+			if err := opts.healthcheck.validate(); err != nil {
+				return err
 			}
-			return nil
+			return opts.validateRecreate()
 		}),
 		RunE: p.WithServices(dockerCli, func(ctx context.Context, project *types.Project, services []string) error {
+			opts.ignoreOrphans = utils.StringToBool(project.Environment[ComposeIgnoreOrphans])
+			if opts.ignoreOrphans && opts.removeOrphans {
+				return fmt.Errorf("cannot combine %s and --remove-orphans", ComposeIgnoreOrphans)
+			}
 			return runCreate(ctx, dockerCli, backend, opts, buildOpts, project, services)
 		}),
 		ValidArgsFunction: completeServiceNames(dockerCli, p),
@@ -78,8 +102,15 @@ func createCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service
 	flags.BoolVar(&opts.quietPull, "quiet-pull", false, "Pull without printing progress information")
 	flags.BoolVar(&opts.forceRecreate, "force-recreate", false, "Recreate containers even if their configuration and image haven't changed")
 	flags.BoolVar(&opts.noRecreate, "no-recreate", false, "If containers already exist, don't recreate them. Incompatible with --force-recreate.")
+	flags.BoolVar(&opts.recreateDeps, "always-recreate-deps", false, "Recreate dependent containers. Incompatible with --no-recreate.")
+	flags.BoolVarP(&opts.noInherit, "renew-anon-volumes", "V", false, "Recreate anonymous volumes instead of retrieving data from the previous containers")
 	flags.BoolVar(&opts.removeOrphans, "remove-orphans", false, "Remove containers for services not defined in the Compose file")
 	flags.StringArrayVar(&opts.scale, "scale", []string{}, "Scale SERVICE to NUM instances. Overrides the `scale` setting in the Compose file if present.")
+	flags.BoolVar(&opts.adopt, "adopt", false, "Rename containers created with a distinct naming scheme (e.g. by docker-compose v1) instead of reporting them")
+	flags.BoolVar(&opts.adoptOrphans, "adopt-orphans", false, "Move containers of another project running a service with the same name and image into this project, keeping their volumes")
+	flags.BoolVar(&opts.ignoreResourceCheck, "ignore-resource-check", false, "Only warn when services require more memory or cpus than the Docker Engine has")
+	flags.BoolVar(&opts.rollbackOnCancel, "rollback-on-cancel", false, "Remove networks, volumes and containers created by the command if it gets canceled")
+	opts.healthcheck.addFlags(flags)
 	return cmd
 }
 
@@ -107,9 +138,28 @@ func runCreate(ctx context.Context, _ command.Cli, backend api.Service, createOp
 		Inherit:              !createOpts.noInherit,
 		Timeout:              createOpts.GetTimeout(),
 		QuietPull:            createOpts.quietPull,
+		Adopt:                createOpts.adopt,
+		AdoptOrphans:         createOpts.adoptOrphans,
+		IgnoreResourceCheck:  createOpts.ignoreResourceCheck,
+		RollbackOnCancel:     createOpts.rollbackOnCancel,
+		HealthCheck:          createOpts.healthcheck.override(),
 	})
 }
 
+// validateRecreate checks the recreate policy flags are consistent
+func (opts createOptions) validateRecreate() error {
+	if opts.forceRecreate && opts.noRecreate {
+		return fmt.Errorf("--force-recreate and --no-recreate are incompatible")
+	}
+	if opts.recreateDeps && opts.noRecreate {
+		return fmt.Errorf("--always-recreate-deps and --no-recreate are incompatible")
+	}
+	if opts.noInherit && opts.noRecreate {
+		return fmt.Errorf("--renew-anon-volumes and --no-recreate are incompatible")
+	}
+	return nil
+}
+
 func (opts createOptions) recreateStrategy() string {
 	if opts.noRecreate {
 		return api.RecreateNever
@@ -161,6 +211,16 @@ func (opts createOptions) Apply(project *types.Project) error {
 		}
 	}
 
+	if opts.platform != "" {
+		if _, err := platforms.Parse(opts.platform); err != nil {
+			return fmt.Errorf("invalid --platform option %q: %w", opts.platform, err)
+		}
+		for i, service := range project.Services {
+			service.Platform = opts.platform
+			project.Services[i] = service
+		}
+	}
+
 	if err := applyPlatforms(project, true); err != nil {
 		return err
 	}
@@ -191,6 +251,68 @@ func applyScaleOpts(project *types.Project, opts []string) error {
 	return nil
 }
 
+func (opts *healthcheckOptions) addFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&opts.cmd, "health-cmd", "", "Override services' healthcheck command, run by the container shell")
+	flags.DurationVar(&opts.interval, "health-interval", 0, "Override services' healthcheck interval (ms|s|m|h)")
+	flags.DurationVar(&opts.timeout, "health-timeout", 0, "Override services' healthcheck timeout (ms|s|m|h)")
+	flags.DurationVar(&opts.startPeriod, "health-start-period", 0, "Override services' healthcheck start period (ms|s|m|h)")
+	flags.DurationVar(&opts.startInterval, "health-start-interval", 0, "Override services' healthcheck interval during the start period (ms|s|m|h)")
+	flags.IntVar(&opts.retries, "health-retries", 0, "Override services' number of consecutive healthcheck failures to report unhealthy")
+	flags.BoolVar(&opts.disable, "no-healthcheck", false, "Disable services' healthchecks")
+}
+
+func (opts healthcheckOptions) validate() error {
+	if opts.retries < 0 {
+		return fmt.Errorf("--health-retries cannot be negative")
+	}
+	if opts.disable && opts.toHealthCheck() != nil {
+		return fmt.Errorf("--no-healthcheck conflicts with --health-* options")
+	}
+	return nil
+}
+
+// toHealthCheck returns the healthcheck attributes set by flags, nil if none is set
+func (opts healthcheckOptions) toHealthCheck() *types.HealthCheckConfig {
+	var healthcheck types.HealthCheckConfig
+	set := false
+	if opts.cmd != "" {
+		healthcheck.Test = types.HealthCheckTest{"CMD-SHELL", opts.cmd}
+		set = true
+	}
+	for _, d := range []struct {
+		value  time.Duration
+		target **types.Duration
+	}{
+		{opts.interval, &healthcheck.Interval},
+		{opts.timeout, &healthcheck.Timeout},
+		{opts.startPeriod, &healthcheck.StartPeriod},
+		{opts.startInterval, &healthcheck.StartInterval},
+	} {
+		if d.value != 0 {
+			value := types.Duration(d.value)
+			*d.target = &value
+			set = true
+		}
+	}
+	if opts.retries > 0 {
+		retries := uint64(opts.retries)
+		healthcheck.Retries = &retries
+		set = true
+	}
+	if !set {
+		return nil
+	}
+	return &healthcheck
+}
+
+// override returns the healthcheck override to be passed to the backend, nil if flags don't set any
+func (opts healthcheckOptions) override() *types.HealthCheckConfig {
+	if opts.disable {
+		return &types.HealthCheckConfig{Disable: true}
+	}
+	return opts.toHealthCheck()
+}
+
 func (opts createOptions) isPullPolicyValid() bool {
 	pullPolicies := []string{types.PullPolicyAlways, types.PullPolicyNever, types.PullPolicyBuild,
 		types.PullPolicyMissing, types.PullPolicyIfNotPresent}
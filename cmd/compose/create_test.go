@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/davecgh/go-spew/spew"
@@ -96,6 +97,71 @@ func sampleProject() *types.Project {
 	}
 }
 
+func TestRecreatePolicies(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		opts         createOptions
+		recreate     string
+		dependencies string
+		err          string
+	}{
+		{name: "default", opts: createOptions{}, recreate: api.RecreateDiverged, dependencies: api.RecreateDiverged},
+		{name: "force", opts: createOptions{forceRecreate: true}, recreate: api.RecreateForce, dependencies: api.RecreateDiverged},
+		{name: "never", opts: createOptions{noRecreate: true}, recreate: api.RecreateNever, dependencies: api.RecreateNever},
+		{name: "deps", opts: createOptions{recreateDeps: true}, recreate: api.RecreateDiverged, dependencies: api.RecreateForce},
+		{name: "force deps", opts: createOptions{forceRecreate: true, recreateDeps: true}, recreate: api.RecreateForce, dependencies: api.RecreateForce},
+		{name: "renew", opts: createOptions{noInherit: true}, recreate: api.RecreateDiverged, dependencies: api.RecreateDiverged},
+		{name: "force renew", opts: createOptions{forceRecreate: true, noInherit: true}, recreate: api.RecreateForce, dependencies: api.RecreateDiverged},
+		{name: "force never", opts: createOptions{forceRecreate: true, noRecreate: true}, err: "--force-recreate and --no-recreate are incompatible"},
+		{name: "deps never", opts: createOptions{recreateDeps: true, noRecreate: true}, err: "--always-recreate-deps and --no-recreate are incompatible"},
+		{name: "renew never", opts: createOptions{noInherit: true, noRecreate: true}, err: "--renew-anon-volumes and --no-recreate are incompatible"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.opts.validateRecreate()
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.recreate, tc.opts.recreateStrategy())
+			require.Equal(t, tc.dependencies, tc.opts.dependenciesRecreateStrategy())
+		})
+	}
+}
+
+func TestHealthcheckOverride(t *testing.T) {
+	require.Nil(t, healthcheckOptions{}.override())
+
+	interval := types.Duration(time.Second)
+	retries := uint64(10)
+	require.Equal(t, &types.HealthCheckConfig{
+		Test:     types.HealthCheckTest{"CMD-SHELL", "curl -f localhost"},
+		Interval: &interval,
+		Retries:  &retries,
+	}, healthcheckOptions{cmd: "curl -f localhost", interval: time.Second, retries: 10}.override())
+
+	require.Equal(t, &types.HealthCheckConfig{Disable: true}, healthcheckOptions{disable: true}.override())
+	require.EqualError(t, healthcheckOptions{disable: true, interval: time.Second}.validate(),
+		"--no-healthcheck conflicts with --health-* options")
+}
+
+func TestPlatformOverride(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			"web": {Name: "web", Image: "nginx"},
+			"db":  {Name: "db", Image: "postgres", Platform: "linux/amd64"},
+			"app": {Name: "app", Build: &types.BuildConfig{Context: "."}},
+		},
+	}
+	require.NoError(t, createOptions{platform: "linux/arm64"}.Apply(project))
+	for _, service := range project.Services {
+		require.Equal(t, "linux/arm64", service.Platform)
+	}
+	require.Equal(t, types.StringList{"linux/arm64"}, project.Services["app"].Build.Platforms)
+
+	require.ErrorContains(t, createOptions{platform: "linux/"}.Apply(project), `invalid --platform option "linux/"`)
+}
+
 func defaultCreateOptions(includeBuild bool) api.CreateOptions {
 	var build *api.BuildOptions
 	if includeBuild {
@@ -117,8 +183,9 @@ func defaultCreateOptions(includeBuild bool) api.CreateOptions {
 
 func defaultBuildOptions() api.BuildOptions {
 	return api.BuildOptions{
-		Args:     make(types.MappingWithEquals),
-		Progress: "auto",
+		Args:               make(types.MappingWithEquals),
+		Progress:           "auto",
+		ContextSizeWarning: 100_000_000,
 	}
 }
 
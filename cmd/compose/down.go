@@ -18,16 +18,18 @@ package compose
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"time"
 
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/compose/v2/pkg/utils"
-	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
+	"github.com/docker/compose/v2/cmd/formatter"
 	"github.com/docker/compose/v2/pkg/api"
 )
 
@@ -38,6 +40,12 @@ type downOptions struct {
 	timeout       int
 	volumes       bool
 	images        string
+	assumeYes     bool
+	services      []string
+	networks      bool
+	format        string
+	// volume is set when the deprecated --volume flag is used
+	volume bool
 }
 
 func downCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
@@ -49,28 +57,41 @@ func downCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service)
 		Short: "Stop and remove containers, networks",
 		PreRunE: AdaptCmd(func(ctx context.Context, cmd *cobra.Command, args []string) error {
 			opts.timeChanged = cmd.Flags().Changed("timeout")
+			if opts.volume {
+				api.Warn(ctx, api.Warning{Code: api.WarningDeprecated, Message: "--volume is deprecated, please use --volumes"})
+			}
 			if opts.images != "" {
 				if opts.images != "all" && opts.images != "local" {
 					return fmt.Errorf("invalid value for --rmi: %q", opts.images)
 				}
 			}
+			if opts.networks && (opts.volumes || opts.images != "") {
+				return errors.New("--networks only removes containers and networks, it can't be combined with --volumes or --rmi")
+			}
+			if opts.format != "" && opts.format != "json" {
+				return fmt.Errorf("unsupported format %q", opts.format)
+			}
 			return nil
 		}),
 		RunE: Adapt(func(ctx context.Context, args []string) error {
-			return runDown(ctx, dockerCli, backend, opts, args)
+			return runDown(ctx, dockerCli, backend, opts, append(args, opts.services...))
 		}),
 		ValidArgsFunction: noCompletion(),
 	}
 	flags := downCmd.Flags()
 	removeOrphans := utils.StringToBool(os.Getenv(ComposeRemoveOrphans))
 	flags.BoolVar(&opts.removeOrphans, "remove-orphans", removeOrphans, "Remove containers for services not defined in the Compose file")
+	flags.BoolVarP(&opts.assumeYes, "yes", "y", false, "Don't ask to confirm removal of orphan containers")
 	flags.IntVarP(&opts.timeout, "timeout", "t", 0, "Specify a shutdown timeout in seconds")
 	flags.BoolVarP(&opts.volumes, "volumes", "v", false, `Remove named volumes declared in the "volumes" section of the Compose file and anonymous volumes attached to containers`)
 	flags.StringVar(&opts.images, "rmi", "", `Remove images used by services. "local" remove only images that don't have a custom tag ("local"|"all")`)
+	flags.StringSliceVar(&opts.services, "services", nil, "Only remove containers of these services, as a comma-separated list")
+	flags.BoolVar(&opts.networks, "networks", false, "Only remove containers and networks, keep volumes and images")
+	flags.StringVar(&opts.format, "format", "", `Print a summary of removed resources. Values: [json]`)
 	flags.SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
 		if name == "volume" {
 			name = "volumes"
-			logrus.Warn("--volume is deprecated, please use --volumes")
+			opts.volume = true
 		}
 		return pflag.NormalizedName(name)
 	})
@@ -88,12 +109,33 @@ func runDown(ctx context.Context, dockerCli command.Cli, backend api.Service, op
 		timeoutValue := time.Duration(opts.timeout) * time.Second
 		timeout = &timeoutValue
 	}
-	return backend.Down(ctx, name, api.DownOptions{
+	downOptions := api.DownOptions{
 		RemoveOrphans: opts.removeOrphans,
 		Project:       project,
 		Timeout:       timeout,
 		Images:        opts.images,
 		Volumes:       opts.volumes,
 		Services:      services,
+		AssumeYes:     opts.assumeYes,
+	}
+	if opts.networks {
+		downOptions.Resources = []string{api.ResourceContainer, api.ResourceNetwork}
+	}
+	removed := []api.RemovedResource{}
+	if opts.format != "" {
+		downOptions.Removed = func(resource api.RemovedResource) {
+			removed = append(removed, resource)
+		}
+	}
+	err = backend.Down(ctx, name, downOptions)
+	if err != nil || opts.format == "" {
+		return err
+	}
+	sort.Slice(removed, func(i, j int) bool {
+		if removed[i].Type != removed[j].Type {
+			return removed[i].Type < removed[j].Type
+		}
+		return removed[i].Name < removed[j].Name
 	})
+	return formatter.Print(removed, formatter.JSON, dockerCli.Out(), nil)
 }
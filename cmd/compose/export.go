@@ -0,0 +1,84 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+type importOptions struct {
+	*ProjectOptions
+	outputDir string
+}
+
+func exportCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export [OPTIONS] ARCHIVE",
+		Short: "Export project images, volumes and configuration into an archive",
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runExport(ctx, dockerCli, backend, p, args[0])
+		}),
+		Args: cobra.ExactArgs(1),
+	}
+	return cmd
+}
+
+func runExport(ctx context.Context, dockerCli command.Cli, backend api.Service, p *ProjectOptions, archive string) error {
+	project, _, err := p.ToProject(ctx, dockerCli, nil)
+	if err != nil {
+		return err
+	}
+
+	return backend.Export(ctx, project, api.ExportOptions{
+		Output: archive,
+	})
+}
+
+func importCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
+	opts := importOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "import [OPTIONS] ARCHIVE",
+		Short: "Import an archive created by export, restoring project images, networks and volumes",
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runImport(ctx, backend, opts, args[0])
+		}),
+		Args: cobra.ExactArgs(1),
+	}
+	cmd.Flags().StringVar(&opts.outputDir, "output-dir", ".", "Directory to write the imported compose.yaml file to")
+	return cmd
+}
+
+func runImport(ctx context.Context, backend api.Service, opts importOptions, archive string) error {
+	dir, err := filepath.Abs(opts.outputDir)
+	if err != nil {
+		return err
+	}
+
+	return backend.Import(ctx, api.ImportOptions{
+		Input:       archive,
+		WorkingDir:  dir,
+		ProjectName: opts.ProjectName,
+	})
+}
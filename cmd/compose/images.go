@@ -35,8 +35,12 @@ import (
 
 type imageOptions struct {
 	*ProjectOptions
-	Quiet  bool
-	Format string
+	Quiet         bool
+	Format        string
+	pruneDangling bool
+	checkUpdates  bool
+	digests       bool
+	sbom          bool
 }
 
 func imagesCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
@@ -53,17 +57,26 @@ func imagesCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service
 	}
 	imgCmd.Flags().StringVar(&opts.Format, "format", "table", "Format the output. Values: [table | json]")
 	imgCmd.Flags().BoolVarP(&opts.Quiet, "quiet", "q", false, "Only display IDs")
+	imgCmd.Flags().BoolVar(&opts.pruneDangling, "prune-dangling", false, "Remove images built for the project which are not used by any service anymore")
+	imgCmd.Flags().BoolVar(&opts.digests, "digests", false, "Show image digests")
+	imgCmd.Flags().BoolVar(&opts.checkUpdates, "check-updates", false, "Query the registry for image tags resolving to a newer digest")
+	imgCmd.Flags().BoolVar(&opts.sbom, "sbom", false, "Retrieve the SBOM attested for images from the registry, as JSON")
 	return imgCmd
 }
 
 func runImages(ctx context.Context, dockerCli command.Cli, backend api.Service, opts imageOptions, services []string) error {
+	if opts.pruneDangling {
+		return runImagesPrune(ctx, dockerCli, backend, opts)
+	}
 	projectName, err := opts.toProjectName(ctx, dockerCli)
 	if err != nil {
 		return err
 	}
 
 	images, err := backend.Images(ctx, projectName, api.ImagesOptions{
-		Services: services,
+		Services:     services,
+		CheckUpdates: opts.checkUpdates,
+		SBOM:         opts.sbom,
 	})
 	if err != nil {
 		return err
@@ -90,6 +103,19 @@ func runImages(ctx context.Context, dockerCli command.Cli, backend api.Service,
 		return images[i].ContainerName < images[j].ContainerName
 	})
 
+	if opts.sbom {
+		// SBOMs don't fit a table
+		opts.Format = formatter.JSON
+	}
+
+	headers := []string{"CONTAINER", "REPOSITORY", "TAG", "IMAGE ID"}
+	if opts.digests {
+		headers = append(headers, "DIGEST")
+	}
+	headers = append(headers, "SIZE")
+	if opts.checkUpdates {
+		headers = append(headers, "UPDATE")
+	}
 	return formatter.Print(images, opts.Format, dockerCli.Out(),
 		func(w io.Writer) {
 			for _, img := range images {
@@ -103,8 +129,43 @@ func runImages(ctx context.Context, dockerCli command.Cli, backend api.Service,
 				if tag == "" {
 					tag = "<none>"
 				}
-				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", img.ContainerName, repo, tag, id, size)
+				line := fmt.Sprintf("%s\t%s\t%s\t%s", img.ContainerName, repo, tag, id)
+				if opts.digests {
+					digest := "<none>"
+					if img.Digest != "" {
+						digest = stringid.TruncateID(img.Digest)
+					}
+					line += "\t" + digest
+				}
+				line += "\t" + size
+				if opts.checkUpdates {
+					update := ""
+					if img.UpdateAvailable {
+						update = "available"
+					}
+					line += "\t" + update
+				}
+				_, _ = fmt.Fprintln(w, line)
 			}
 		},
-		"CONTAINER", "REPOSITORY", "TAG", "IMAGE ID", "SIZE")
+		headers...)
+}
+
+func runImagesPrune(ctx context.Context, dockerCli command.Cli, backend api.Service, opts imageOptions) error {
+	project, _, err := opts.ToProject(ctx, dockerCli, nil)
+	if err != nil {
+		return err
+	}
+	removed, err := backend.ImagesPrune(ctx, project, api.ImagesPruneOptions{
+		Dangling: true,
+	})
+	if err != nil {
+		return err
+	}
+	if opts.Quiet {
+		for _, img := range removed {
+			fmt.Fprintln(dockerCli.Out(), img)
+		}
+	}
+	return nil
 }
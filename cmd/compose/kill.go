@@ -18,7 +18,9 @@ package compose
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/docker/cli/cli/command"
 	"github.com/spf13/cobra"
@@ -30,7 +32,7 @@ import (
 type killOptions struct {
 	*ProjectOptions
 	removeOrphans bool
-	signal        string
+	signals       []string
 }
 
 func killCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
@@ -49,7 +51,7 @@ func killCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service)
 	flags := cmd.Flags()
 	removeOrphans := utils.StringToBool(os.Getenv(ComposeRemoveOrphans))
 	flags.BoolVar(&opts.removeOrphans, "remove-orphans", removeOrphans, "Remove containers for services not defined in the Compose file")
-	flags.StringVarP(&opts.signal, "signal", "s", "SIGKILL", "SIGNAL to send to the container")
+	flags.StringArrayVarP(&opts.signals, "signal", "s", []string{"SIGKILL"}, "SIGNAL to send to the container, or SERVICE=SIGNAL to set the signal sent to a service")
 
 	return cmd
 }
@@ -60,10 +62,36 @@ func runKill(ctx context.Context, dockerCli command.Cli, backend api.Service, op
 		return err
 	}
 
+	signal, signals, err := opts.parseSignals()
+	if err != nil {
+		return err
+	}
+
 	return backend.Kill(ctx, name, api.KillOptions{
 		RemoveOrphans: opts.removeOrphans,
 		Project:       project,
 		Services:      services,
-		Signal:        opts.signal,
+		Signal:        signal,
+		Signals:       signals,
 	})
 }
+
+// parseSignals splits --signal values into the default signal and the signals set per service
+func (opts killOptions) parseSignals() (string, map[string]string, error) {
+	var signal string
+	signals := map[string]string{}
+	for _, s := range opts.signals {
+		service, sig, ok := strings.Cut(s, "=")
+		switch {
+		case !ok && signal != "" && signal != s:
+			return "", nil, fmt.Errorf("conflicting signals %s and %s", signal, s)
+		case !ok:
+			signal = s
+		case service == "" || sig == "":
+			return "", nil, fmt.Errorf("invalid signal %q, expected SERVICE=SIGNAL", s)
+		default:
+			signals[service] = sig
+		}
+	}
+	return signal, signals, nil
+}
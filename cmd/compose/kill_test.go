@@ -0,0 +1,41 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestKillParseSignals(t *testing.T) {
+	signal, signals, err := killOptions{signals: []string{"SIGTERM", "web=SIGHUP", "db=SIGINT"}}.parseSignals()
+	assert.NilError(t, err)
+	assert.Equal(t, signal, "SIGTERM")
+	assert.DeepEqual(t, signals, map[string]string{"web": "SIGHUP", "db": "SIGINT"})
+
+	signal, signals, err = killOptions{signals: []string{"web=SIGHUP"}}.parseSignals()
+	assert.NilError(t, err)
+	assert.Equal(t, signal, "")
+	assert.DeepEqual(t, signals, map[string]string{"web": "SIGHUP"})
+
+	_, _, err = killOptions{signals: []string{"SIGTERM", "SIGINT"}}.parseSignals()
+	assert.Error(t, err, "conflicting signals SIGTERM and SIGINT")
+
+	_, _, err = killOptions{signals: []string{"web="}}.parseSignals()
+	assert.Error(t, err, `invalid signal "web=", expected SERVICE=SIGNAL`)
+}
@@ -0,0 +1,111 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v2/cmd/formatter"
+	"github.com/docker/compose/v2/pkg/lint"
+)
+
+type lintOptions struct {
+	*ProjectOptions
+	format  string
+	disable []string
+	rules   bool
+}
+
+func lintCommand(p *ProjectOptions, dockerCli command.Cli) *cobra.Command {
+	opts := lintOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "lint [OPTIONS] [SERVICE...]",
+		Short: "EXPERIMENTAL - Check services for bad practices",
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runLint(ctx, dockerCli, opts, args)
+		}),
+		ValidArgsFunction: completeServiceNames(dockerCli, p),
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&opts.format, "format", "table", "Format the output. Values: [table | json | sarif]")
+	flags.StringArrayVar(&opts.disable, "disable", nil, "Disable a rule by ID")
+	flags.BoolVar(&opts.rules, "rules", false, "List the available rules")
+	return cmd
+}
+
+func runLint(ctx context.Context, dockerCli command.Cli, opts lintOptions, services []string) error {
+	rules, err := lint.Without(lint.Rules(), opts.disable...)
+	if err != nil {
+		return err
+	}
+	if opts.rules {
+		return formatter.Print(rules, "", dockerCli.Out(), func(w io.Writer) {
+			for _, rule := range rules {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", rule.ID(), rule.Level(), rule.Description())
+			}
+		}, "ID", "LEVEL", "DESCRIPTION")
+	}
+
+	project, _, err := opts.ToProject(ctx, dockerCli, services)
+	if err != nil {
+		return err
+	}
+	findings := lint.Lint(project, rules)
+
+	switch opts.format {
+	case "sarif":
+		file := ""
+		if len(project.ComposeFiles) > 0 {
+			file = project.ComposeFiles[0]
+		}
+		content, err := lint.SARIF(findings, rules, file)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(dockerCli.Out(), string(content))
+	case "json":
+		if err := formatter.Print(findings, formatter.JSON, dockerCli.Out(), nil); err != nil {
+			return err
+		}
+	case "table", "":
+		if len(findings) == 0 {
+			break
+		}
+		err := formatter.Print(findings, "", dockerCli.Out(), func(w io.Writer) {
+			for _, f := range findings {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", f.Service, f.Level, f.Rule, f.Message)
+			}
+		}, "SERVICE", "LEVEL", "RULE", "MESSAGE")
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported format %q", opts.format)
+	}
+
+	if len(findings) > 0 {
+		return fmt.Errorf("%d problem(s) found", len(findings))
+	}
+	return nil
+}
@@ -20,7 +20,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"strings"
 
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/compose/v2/cmd/formatter"
@@ -49,7 +48,7 @@ func listCommand(dockerCli command.Cli, backend api.Service) *cobra.Command {
 		Args:              cobra.NoArgs,
 		ValidArgsFunction: noCompletion(),
 	}
-	lsCmd.Flags().StringVar(&lsOpts.Format, "format", "table", "Format the output. Values: [table | json]")
+	lsCmd.Flags().StringVar(&lsOpts.Format, "format", "table", "Format the output. Values: [table | json | TEMPLATE]")
 	lsCmd.Flags().BoolVarP(&lsOpts.Quiet, "quiet", "q", false, "Only display IDs")
 	lsCmd.Flags().Var(&lsOpts.Filter, "filter", "Filter output based on conditions provided")
 	lsCmd.Flags().BoolVarP(&lsOpts.All, "all", "a", false, "Show all stopped Compose projects")
@@ -68,13 +67,13 @@ func runList(ctx context.Context, dockerCli command.Cli, backend api.Service, ls
 		return err
 	}
 
-	stackList, err := backend.List(ctx, api.ListOptions{All: lsOpts.All})
+	stackList, err := backend.Projects(ctx, api.ListOptions{All: lsOpts.All})
 	if err != nil {
 		return err
 	}
 
 	if filters.Len() > 0 {
-		var filtered []api.Stack
+		var filtered []api.ProjectSummary
 		for _, s := range stackList {
 			if filters.Contains("name") && !filters.Match("name", s.Name) {
 				continue
@@ -103,15 +102,23 @@ type stackView struct {
 	Name        string
 	Status      string
 	ConfigFiles string
+	WorkingDir  string
+	Services    int
+	Containers  int
+	Running     int
 }
 
-func viewFromStackList(stackList []api.Stack) []stackView {
+func viewFromStackList(stackList []api.ProjectSummary) []stackView {
 	retList := make([]stackView, len(stackList))
 	for i, s := range stackList {
 		retList[i] = stackView{
 			Name:        s.Name,
-			Status:      strings.TrimSpace(fmt.Sprintf("%s %s", s.Status, s.Reason)),
+			Status:      s.Status,
 			ConfigFiles: s.ConfigFiles,
+			WorkingDir:  s.WorkingDir,
+			Services:    s.Services,
+			Containers:  s.Containers,
+			Running:     s.Running,
 		}
 	}
 	return retList
@@ -0,0 +1,81 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v2/cmd/formatter"
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+type networksOptions struct {
+	*ProjectOptions
+	Quiet  bool
+	Format string
+}
+
+func networksCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
+	opts := networksOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "networks [OPTIONS]",
+		Short: "List networks created for the project",
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runNetworks(ctx, dockerCli, backend, opts)
+		}),
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: noCompletion(),
+	}
+	cmd.Flags().StringVar(&opts.Format, "format", "table", "Format the output. Values: [table | json]")
+	cmd.Flags().BoolVarP(&opts.Quiet, "quiet", "q", false, "Only display network names")
+	return cmd
+}
+
+func runNetworks(ctx context.Context, dockerCli command.Cli, backend api.Service, opts networksOptions) error {
+	projectName, err := opts.toProjectName(ctx, dockerCli)
+	if err != nil {
+		return err
+	}
+	networks, err := backend.Networks(ctx, projectName)
+	if err != nil {
+		return err
+	}
+
+	if opts.Quiet {
+		for _, n := range networks {
+			_, _ = fmt.Fprintln(dockerCli.Out(), n.Name)
+		}
+		return nil
+	}
+
+	return formatter.Print(networks, opts.Format, dockerCli.Out(),
+		func(w io.Writer) {
+			for _, n := range networks {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", n.Name, n.Network, n.Driver,
+					strings.Join(n.Subnets, ", "), strings.Join(n.Services, ", "))
+			}
+		},
+		"NAME", "NETWORK", "DRIVER", "SUBNET", "SERVICES")
+}
@@ -27,6 +27,7 @@ import (
 
 type pauseOptions struct {
 	*ProjectOptions
+	force bool
 }
 
 func pauseCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
@@ -41,18 +42,28 @@ func pauseCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service)
 		}),
 		ValidArgsFunction: completeServiceNames(dockerCli, p),
 	}
+	cmd.Flags().BoolVar(&opts.force, "force", false, "Pause services even if other services depend on them to be healthy")
 	return cmd
 }
 
 func runPause(ctx context.Context, dockerCli command.Cli, backend api.Service, opts pauseOptions, services []string) error {
-	project, name, err := opts.projectOrName(ctx, dockerCli, services...)
+	// project is not filtered by selected services, so services depending on those can be checked
+	project, name, err := opts.projectOrName(ctx, dockerCli)
 	if err != nil {
 		return err
 	}
 
+	if project != nil && len(services) > 0 {
+		project, err = project.WithServicesEnabled(services...)
+		if err != nil {
+			return err
+		}
+	}
+
 	return backend.Pause(ctx, name, api.PauseOptions{
 		Services: services,
 		Project:  project,
+		Force:    opts.force,
 	})
 }
 
@@ -0,0 +1,57 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/mocks"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestRunPauseKeepsDependents(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "compose.yaml"), []byte(`
+name: app
+services:
+  db:
+    image: postgres
+  web:
+    image: nginx
+    depends_on:
+      db:
+        condition: service_healthy
+`), 0o600))
+
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+	backend := mocks.NewMockService(ctrl)
+	backend.EXPECT().Pause(gomock.Any(), "app", gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, options api.PauseOptions) error {
+			assert.DeepEqual(t, options.Services, []string{"db"})
+			// web depends on db to be healthy, so must be known for Pause to check it
+			assert.DeepEqual(t, options.Project.ServiceNames(), []string{"db", "web"})
+			return nil
+		})
+
+	opts := pauseOptions{ProjectOptions: &ProjectOptions{ConfigPaths: []string{filepath.Join(dir, "compose.yaml")}}}
+	assert.NilError(t, runPause(ctx, nil, backend, opts, []string{"db"}))
+}
@@ -0,0 +1,102 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"io"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/compose-spec/compose-go/v2/cli"
+	"github.com/compose-spec/compose-go/v2/loader"
+	"github.com/sirupsen/logrus"
+)
+
+// rawModel loads, at most once, the compose model without interpolation, so extensions declared by the compose files
+// can configure how the project is loaded. Being loaded by the compose-go loader, the model includes extensions set by
+// override files, included files, and compose files read from stdin or downloaded from a remote resource
+type rawModel struct {
+	loaded bool
+	model  map[string]any
+}
+
+// get returns the raw model for project options, or nil if it can't be loaded, so the loader reports invalid compose
+// files when it loads the project
+func (m *rawModel) get(o *cli.ProjectOptions) map[string]any {
+	if m.loaded {
+		return m.model
+	}
+	m.loaded = true
+	model, err := loadRawModel(o)
+	if err != nil {
+		logrus.Debugf("unable to load compose model before interpolation: %v", err)
+		return nil
+	}
+	m.model = model
+	return model
+}
+
+// loadRawModel loads the compose model for project options without interpolation nor validation
+func loadRawModel(o *cli.ProjectOptions) (map[string]any, error) {
+	raw := *o
+	// the loader sets the project name it guesses in environment, which must not be confused with one set by env files
+	raw.Environment = maps.Clone(o.Environment)
+	for _, fn := range []cli.ProjectOptionsFn{
+		cli.WithInterpolation(false),
+		cli.WithNormalization(false),
+		cli.WithConsistency(false),
+		cli.WithResolvedPaths(false),
+		cli.WithLoadOptions(func(options *loader.Options) {
+			// values can't be validated before interpolation
+			options.SkipValidation = true
+			options.SkipResolveEnvironment = true
+		}),
+	} {
+		if err := fn(&raw); err != nil {
+			return nil, err
+		}
+	}
+
+	if slices.Contains(o.ConfigPaths, "-") {
+		// compose file is read from stdin by each load
+		content, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		if err := replayStdin(content); err != nil {
+			return nil, err
+		}
+		defer replayStdin(content) //nolint:errcheck
+	}
+	return raw.LoadModel(context.Background())
+}
+
+// replayStdin replaces stdin by a pipe reading content
+func replayStdin(content []byte) error {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	go func() {
+		_, _ = w.Write(content)
+		_ = w.Close()
+	}()
+	os.Stdin = r
+	return nil
+}
@@ -0,0 +1,210 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/cli"
+	"github.com/compose-spec/compose-go/v2/errdefs"
+	"github.com/compose-spec/compose-go/v2/graph"
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// withProviderServices disables the loader consistency check when compose files declare provider services, as those
+// have neither image nor build section. checkProviderServices then runs the same checks on the loaded project
+func withProviderServices(model *rawModel) cli.ProjectOptionsFn {
+	return func(o *cli.ProjectOptions) error {
+		if hasProviderServices(model.get(o)) {
+			return cli.WithConsistency(false)(o)
+		}
+		return nil
+	}
+}
+
+// hasProviderServices tells if compose model declares a service with the x-provider extension
+func hasProviderServices(model map[string]any) bool {
+	services, _ := model["services"].(map[string]any)
+	for _, service := range services {
+		if service, ok := service.(map[string]any); ok {
+			if _, ok := service[api.ProviderExtension]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isProviderService(service types.ServiceConfig) bool {
+	_, ok := service.Extensions[api.ProviderExtension]
+	return ok
+}
+
+// checkProviderServices checks a project declaring provider services is consistent, as the loader check was skipped.
+// Checks are the ones the loader runs, but provider services are not required to declare an image or build section
+func checkProviderServices(project *types.Project) error {
+	if !slices.ContainsFunc(project.ServiceNames(), func(name string) bool {
+		return isProviderService(project.Services[name])
+	}) {
+		return nil
+	}
+	for _, name := range project.ServiceNames() {
+		service := project.Services[name]
+		if err := checkServiceConsistency(project, service); err != nil {
+			return err
+		}
+		if service.Scale != nil && service.Deploy != nil {
+			service.Deploy.Replicas = service.Scale
+			project.Services[name] = service
+		}
+	}
+
+	for name, secret := range project.Secrets {
+		if secret.External {
+			continue
+		}
+		if secret.File == "" && secret.Environment == "" {
+			return fmt.Errorf("secret %q must declare either `file` or `environment`: %w", name, errdefs.ErrInvalid)
+		}
+	}
+
+	return graph.CheckCycle(project)
+}
+
+func checkServiceConsistency(project *types.Project, s types.ServiceConfig) error { //nolint:gocyclo
+	if s.Build == nil && s.Image == "" && !isProviderService(s) {
+		return fmt.Errorf("service %q has neither an image nor a build context specified: %w", s.Name, errdefs.ErrInvalid)
+	}
+
+	if s.Build != nil {
+		if s.Build.DockerfileInline != "" && s.Build.Dockerfile != "" {
+			return fmt.Errorf("service %q declares mutualy exclusive dockerfile and dockerfile_inline: %w", s.Name, errdefs.ErrInvalid)
+		}
+		if len(s.Build.Platforms) > 0 && s.Platform != "" && !slices.Contains(s.Build.Platforms, s.Platform) {
+			return fmt.Errorf("service.build.platforms MUST include service.platform %q: %w", s.Platform, errdefs.ErrInvalid)
+		}
+		for _, secret := range s.Build.Secrets {
+			if _, ok := project.Secrets[secret.Source]; !ok {
+				return fmt.Errorf("service %q refers to undefined build secret %s: %w", s.Name, secret.Source, errdefs.ErrInvalid)
+			}
+		}
+	}
+
+	if s.NetworkMode != "" && len(s.Networks) > 0 {
+		return fmt.Errorf("service %s declares mutually exclusive `network_mode` and `networks`: %w", s.Name, errdefs.ErrInvalid)
+	}
+	for network := range s.Networks {
+		if _, ok := project.Networks[network]; !ok {
+			return fmt.Errorf("service %q refers to undefined network %s: %w", s.Name, network, errdefs.ErrInvalid)
+		}
+	}
+	if strings.HasPrefix(s.NetworkMode, types.ServicePrefix) {
+		serviceName := s.NetworkMode[len(types.ServicePrefix):]
+		if _, err := project.GetServices(serviceName); err != nil {
+			return fmt.Errorf("service %q not found for network_mode 'service:%s'", serviceName, serviceName)
+		}
+	}
+
+	if s.HealthCheck != nil && len(s.HealthCheck.Test) > 0 {
+		switch s.HealthCheck.Test[0] {
+		case "CMD", "CMD-SHELL", "NONE":
+		default:
+			return errors.New(`healthcheck.test must start either by "CMD", "CMD-SHELL" or "NONE"`)
+		}
+	}
+
+	for dependency, config := range s.DependsOn {
+		if _, err := project.GetService(dependency); err != nil {
+			if errors.Is(err, errdefs.ErrDisabled) && !config.Required {
+				continue
+			}
+			return fmt.Errorf("service %q depends on undefined service %q: %w", s.Name, dependency, errdefs.ErrInvalid)
+		}
+	}
+
+	for _, volume := range s.Volumes {
+		if volume.Type == types.VolumeTypeVolume && volume.Source != "" {
+			if _, ok := project.Volumes[volume.Source]; !ok {
+				return fmt.Errorf("service %q refers to undefined volume %s: %w", s.Name, volume.Source, errdefs.ErrInvalid)
+			}
+		}
+	}
+	for _, volumesFrom := range s.VolumesFrom {
+		if strings.HasPrefix(volumesFrom, types.ContainerPrefix) {
+			continue
+		}
+		serviceName, _, _ := strings.Cut(volumesFrom, ":")
+		if _, err := project.GetService(serviceName); err != nil {
+			return fmt.Errorf("service %q refers to undefined service %s in volumes_from: %w", s.Name, serviceName, errdefs.ErrInvalid)
+		}
+	}
+	for _, config := range s.Configs {
+		if _, ok := project.Configs[config.Source]; !ok {
+			return fmt.Errorf("service %q refers to undefined config %s: %w", s.Name, config.Source, errdefs.ErrInvalid)
+		}
+	}
+	for _, secret := range s.Secrets {
+		if _, ok := project.Secrets[secret.Source]; !ok {
+			return fmt.Errorf("service %q refers to undefined secret %s: %w", s.Name, secret.Source, errdefs.ErrInvalid)
+		}
+	}
+
+	return checkServiceResources(s)
+}
+
+// checkServiceResources checks service doesn't set distinct values on attributes which are aliases for deploy section
+func checkServiceResources(s types.ServiceConfig) error {
+	if s.Scale != nil && s.Deploy != nil && s.Deploy.Replicas != nil && *s.Scale != *s.Deploy.Replicas {
+		return fmt.Errorf("services.%s: can't set distinct values on 'scale' and 'deploy.replicas': %w", s.Name, errdefs.ErrInvalid)
+	}
+	if s.Deploy != nil && s.Deploy.Resources.Limits != nil {
+		limits := s.Deploy.Resources.Limits
+		if s.CPUS != 0 && limits.NanoCPUs.Value() != s.CPUS {
+			return fmt.Errorf("services.%s: can't set distinct values on 'cpus' and 'deploy.resources.limits.cpus': %w", s.Name, errdefs.ErrInvalid)
+		}
+		if s.MemLimit != 0 && limits.MemoryBytes != s.MemLimit {
+			return fmt.Errorf("services.%s: can't set distinct values on 'mem_limit' and 'deploy.resources.limits.memory': %w", s.Name, errdefs.ErrInvalid)
+		}
+		if s.PidsLimit != 0 && limits.Pids != s.PidsLimit {
+			return fmt.Errorf("services.%s: can't set distinct values on 'pids_limit' and 'deploy.resources.limits.pids': %w", s.Name, errdefs.ErrInvalid)
+		}
+	}
+	if s.MemReservation != 0 && s.Deploy != nil && s.Deploy.Resources.Reservations != nil &&
+		s.Deploy.Resources.Reservations.MemoryBytes != s.MemReservation {
+		return fmt.Errorf("services.%s: can't set distinct values on 'mem_reservation' and 'deploy.resources.reservations.memory': %w",
+			s.Name, errdefs.ErrInvalid)
+	}
+	if s.GetScale() > 1 && s.ContainerName != "" {
+		attr := "scale"
+		if s.Scale == nil {
+			attr = "deploy.replicas"
+		}
+		return fmt.Errorf("services.%s: can't set container_name and %s as container name must be unique: %w", s.Name, attr, errdefs.ErrInvalid)
+	}
+	if s.Develop != nil {
+		for _, watch := range s.Develop.Watch {
+			if watch.Action != types.WatchActionRebuild && watch.Target == "" {
+				return fmt.Errorf("services.%s.develop.watch: target is required for non-rebuild actions: %w", s.Name, errdefs.ErrInvalid)
+			}
+		}
+	}
+	return nil
+}
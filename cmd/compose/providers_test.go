@@ -0,0 +1,69 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestProviderServices(t *testing.T) {
+	dir := t.TempDir()
+	composeFile := filepath.Join(dir, "compose.yaml")
+	assert.NilError(t, os.WriteFile(composeFile, []byte(`
+services:
+  database:
+    x-provider:
+      type: awesomecloud
+  app:
+    image: alpine
+    depends_on:
+      - database
+`), 0o600))
+
+	opts := ProjectOptions{ConfigPaths: []string{composeFile}}
+	options, err := opts.toProjectOptions()
+	assert.NilError(t, err)
+	project, err := options.LoadProject(context.Background())
+	assert.NilError(t, err)
+	assert.NilError(t, checkProviderServices(project))
+
+	app := project.Services["app"]
+	app.Image = ""
+	project.Services["app"] = app
+	assert.ErrorContains(t, checkProviderServices(project), `service "app" has neither an image nor a build context specified`)
+
+	app.Image = "alpine"
+	app.Volumes = []types.ServiceVolumeConfig{{Type: types.VolumeTypeVolume, Source: "data", Target: "/data"}}
+	project.Services["app"] = app
+	assert.ErrorContains(t, checkProviderServices(project), `service "app" refers to undefined volume data`)
+
+	app.Volumes = nil
+	app.VolumesFrom = []string{"storage:ro"}
+	project.Services["app"] = app
+	assert.ErrorContains(t, checkProviderServices(project), `service "app" refers to undefined service storage in volumes_from`)
+
+	app.VolumesFrom = nil
+	app.Secrets = []types.ServiceSecretConfig{{Source: "token"}}
+	project.Services["app"] = app
+	assert.ErrorContains(t, checkProviderServices(project), `service "app" refers to undefined secret token`)
+}
@@ -20,16 +20,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/docker/compose/v2/cmd/formatter"
 	"github.com/docker/compose/v2/pkg/api"
 	"github.com/docker/compose/v2/pkg/utils"
 
+	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/docker/cli/cli/command"
 	cliformatter "github.com/docker/cli/cli/command/formatter"
 	cliflags "github.com/docker/cli/cli/flags"
+	moby "github.com/docker/docker/api/types"
 	"github.com/spf13/cobra"
 )
 
@@ -43,6 +48,7 @@ type psOptions struct {
 	Status   []string
 	noTrunc  bool
 	Orphans  bool
+	Summary  bool
 }
 
 func (p *psOptions) parseFilter() error {
@@ -85,6 +91,7 @@ func psCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *c
 	flags.StringArrayVar(&opts.Status, "status", []string{}, "Filter services by status. Values: [paused | restarting | removing | running | dead | created | exited]")
 	flags.BoolVarP(&opts.Quiet, "quiet", "q", false, "Only display IDs")
 	flags.BoolVar(&opts.Services, "services", false, "Display services")
+	flags.BoolVar(&opts.Summary, "summary", false, "Display one line per service with running and desired replicas, health, ports and exit codes")
 	flags.BoolVar(&opts.Orphans, "orphans", true, "Include orphaned services (not declared by project)")
 	flags.BoolVarP(&opts.All, "all", "a", false, "Show all stopped containers (including those created by the run command)")
 	flags.BoolVar(&opts.noTrunc, "no-trunc", false, "Don't truncate output")
@@ -113,7 +120,7 @@ func runPs(ctx context.Context, dockerCli command.Cli, backend api.Service, serv
 
 	containers, err := backend.Ps(ctx, name, api.PsOptions{
 		Project:  project,
-		All:      opts.All || len(opts.Status) != 0,
+		All:      opts.All || opts.Summary || len(opts.Status) != 0,
 		Services: services,
 	})
 	if err != nil {
@@ -124,6 +131,10 @@ func runPs(ctx context.Context, dockerCli command.Cli, backend api.Service, serv
 		containers = filterByStatus(containers, opts.Status)
 	}
 
+	if opts.Summary {
+		return printServiceSummaries(dockerCli, project, services, containers, opts)
+	}
+
 	sort.Slice(containers, func(i, j int) bool {
 		return containers[i].Name < containers[j].Name
 	})
@@ -177,3 +188,45 @@ func hasStatus(c api.ContainerSummary, statuses []string) bool {
 	}
 	return false
 }
+
+func printServiceSummaries(dockerCli command.Cli, project *types.Project, services []string, containers []api.ContainerSummary, opts psOptions) error {
+	summaries := api.SummarizeServices(project, containers)
+	summaries = utils.Filter(summaries, func(summary api.ServiceSummary) bool {
+		if len(services) > 0 && !utils.StringContains(services, summary.Name) {
+			return false
+		}
+		// with a status filter, only services with a matching container are listed
+		return len(opts.Status) == 0 || slices.ContainsFunc(containers, func(c api.ContainerSummary) bool {
+			return c.Service == summary.Name
+		})
+	})
+	if summaries == nil {
+		summaries = []api.ServiceSummary{}
+	}
+
+	return formatter.Print(summaries, opts.Format, dockerCli.Out(),
+		func(w io.Writer) {
+			for _, summary := range summaries {
+				replicas := strconv.Itoa(summary.Running)
+				if summary.Desired != nil {
+					replicas = fmt.Sprintf("%d/%d", summary.Running, *summary.Desired)
+				}
+				var ports []moby.Port
+				for _, p := range summary.Publishers {
+					ports = append(ports, moby.Port{
+						IP:          p.URL,
+						PrivatePort: uint16(p.TargetPort),
+						PublicPort:  uint16(p.PublishedPort),
+						Type:        p.Protocol,
+					})
+				}
+				exitCodes := make([]string, len(summary.ExitCodes))
+				for i, code := range summary.ExitCodes {
+					exitCodes[i] = strconv.Itoa(code)
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", summary.Name, replicas, summary.Health,
+					cliformatter.DisplayablePorts(ports), strings.Join(exitCodes, ","))
+			}
+		},
+		"SERVICE", "REPLICAS", "HEALTH", "PORTS", "EXIT CODES")
+}
@@ -17,9 +17,11 @@
 package compose
 
 import (
+	"bytes"
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/docker/cli/cli/config/configfile"
@@ -84,3 +86,33 @@ func TestPsTable(t *testing.T) {
 
 	assert.Contains(t, string(output), "8080/tcp, 8443/tcp")
 }
+
+func TestPsSummary(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	backend := mocks.NewMockService(ctrl)
+	backend.EXPECT().
+		Ps(gomock.Eq(ctx), "test", api.PsOptions{All: true}).
+		Return([]api.ContainerSummary{
+			{Service: "web", State: "running", Health: "healthy", Publishers: api.PortPublishers{
+				{URL: "0.0.0.0", TargetPort: 80, PublishedPort: 8080, Protocol: "tcp"},
+			}},
+			{Service: "web", State: "exited", ExitCode: 1},
+			{Service: "db", State: "exited", ExitCode: 0},
+		}, nil)
+
+	opts := psOptions{ProjectOptions: &ProjectOptions{ProjectName: "test"}, Summary: true, Format: "table"}
+	var b bytes.Buffer
+	cli := mocks.NewMockCli(ctrl)
+	cli.EXPECT().Out().Return(streams.NewOut(&b)).AnyTimes()
+	err := runPs(ctx, cli, backend, nil, opts)
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.Equal(t, []string{"SERVICE", "REPLICAS", "HEALTH", "PORTS", "EXIT", "CODES"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"db", "0", "0"}, strings.Fields(lines[1]))
+	assert.Equal(t, []string{"web", "1", "healthy", "0.0.0.0:8080->80/tcp", "1"}, strings.Fields(lines[2]))
+}
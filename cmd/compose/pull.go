@@ -82,6 +82,9 @@ func (opts pullOptions) apply(project *types.Project, services []string) (*types
 	}
 
 	if opts.policy != "" {
+		if opts.policy != types.PullPolicyMissing && opts.policy != types.PullPolicyAlways {
+			return nil, fmt.Errorf("invalid --policy option %q", opts.policy)
+		}
 		for i, service := range project.Services {
 			if service.Image == "" {
 				continue
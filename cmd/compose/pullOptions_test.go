@@ -55,3 +55,13 @@ func TestApplyPullOptions(t *testing.T) {
 	assert.Equal(t, project.Services["has-build"].PullPolicy, types.PullPolicyMissing)
 	assert.Equal(t, project.Services["must-pull"].PullPolicy, types.PullPolicyMissing)
 }
+
+func TestApplyPullOptionsInvalidPolicy(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			"must-pull": {Name: "must-pull", Image: "registry.example.com/another-service"},
+		},
+	}
+	_, err := pullOptions{policy: types.PullPolicyNever}.apply(project, nil)
+	assert.Error(t, err, `invalid --policy option "never"`)
+}
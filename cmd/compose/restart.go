@@ -18,6 +18,7 @@ package compose
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/docker/cli/cli/command"
@@ -31,6 +32,7 @@ type restartOptions struct {
 	timeChanged bool
 	timeout     int
 	noDeps      bool
+	dependents  bool
 }
 
 func restartCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
@@ -51,11 +53,15 @@ func restartCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Servic
 	flags := restartCmd.Flags()
 	flags.IntVarP(&opts.timeout, "timeout", "t", 0, "Specify a shutdown timeout in seconds")
 	flags.BoolVar(&opts.noDeps, "no-deps", false, "Don't restart dependent services")
+	flags.BoolVar(&opts.dependents, "with-dependents", false, "Also restart services depending on selected ones, even without depends_on.restart")
 
 	return restartCmd
 }
 
 func runRestart(ctx context.Context, dockerCli command.Cli, backend api.Service, opts restartOptions, services []string) error {
+	if opts.noDeps && opts.dependents {
+		return errors.New("--no-deps and --with-dependents can't be combined")
+	}
 	project, name, err := opts.projectOrName(ctx, dockerCli)
 	if err != nil {
 		return err
@@ -75,9 +81,10 @@ func runRestart(ctx context.Context, dockerCli command.Cli, backend api.Service,
 	}
 
 	return backend.Restart(ctx, name, api.RestartOptions{
-		Timeout:  timeout,
-		Services: services,
-		Project:  project,
-		NoDeps:   opts.noDeps,
+		Timeout:        timeout,
+		Services:       services,
+		Project:        project,
+		NoDeps:         opts.noDeps,
+		WithDependents: opts.dependents,
 	})
 }
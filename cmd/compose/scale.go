@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/docker/cli/cli/command"
 
@@ -33,7 +34,9 @@ import (
 
 type scaleOptions struct {
 	*ProjectOptions
-	noDeps bool
+	noDeps      bool
+	wait        bool
+	waitTimeout int
 }
 
 func scaleCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
@@ -55,6 +58,8 @@ func scaleCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service)
 	}
 	flags := scaleCmd.Flags()
 	flags.BoolVar(&opts.noDeps, "no-deps", false, "Don't start linked services")
+	flags.BoolVar(&opts.wait, "wait", false, "Wait for services to be running|healthy")
+	flags.IntVar(&opts.waitTimeout, "wait-timeout", 0, "Maximum duration to wait for the project to be running|healthy")
 
 	return scaleCmd
 }
@@ -81,7 +86,11 @@ func runScale(ctx context.Context, dockerCli command.Cli, backend api.Service, o
 		project.Services[key] = service
 	}
 
-	return backend.Scale(ctx, project, api.ScaleOptions{Services: services})
+	return backend.Scale(ctx, project, api.ScaleOptions{
+		Services:    services,
+		Wait:        opts.wait,
+		WaitTimeout: time.Duration(opts.waitTimeout) * time.Second,
+	})
 }
 
 func parseServicesReplicasArgs(args []string) (map[string]int, error) {
@@ -96,6 +105,9 @@ func parseServicesReplicasArgs(args []string) (map[string]int, error) {
 		if err != nil {
 			return nil, fmt.Errorf("invalid scale specifier: can't parse replica value as int: %v", arg)
 		}
+		if intValue < 0 {
+			return nil, fmt.Errorf("invalid scale specifier: replica value can't be negative: %v", arg)
+		}
 		serviceReplicaTuples[key] = intValue
 	}
 	return serviceReplicaTuples, nil
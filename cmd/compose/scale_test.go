@@ -0,0 +1,38 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseServicesReplicasArgs(t *testing.T) {
+	replicas, err := parseServicesReplicasArgs([]string{"web=3", "db=0"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, replicas, map[string]int{"web": 3, "db": 0})
+
+	_, err = parseServicesReplicasArgs([]string{"web"})
+	assert.Error(t, err, "invalid scale specifier: web")
+
+	_, err = parseServicesReplicasArgs([]string{"web=two"})
+	assert.Error(t, err, "invalid scale specifier: can't parse replica value as int: web=two")
+
+	_, err = parseServicesReplicasArgs([]string{"web=-1"})
+	assert.Error(t, err, "invalid scale specifier: replica value can't be negative: web=-1")
+}
@@ -0,0 +1,111 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v2/cmd/formatter"
+	"github.com/docker/compose/v2/pkg/compose"
+)
+
+type secretsOptions struct {
+	*ProjectOptions
+	Format string
+}
+
+// secretEntry describes a secret declared by compose model, and services using it
+type secretEntry struct {
+	Name     string
+	Source   string
+	Services []string
+}
+
+// secretsCommand groups subcommands to inspect secrets declared by project
+func secretsCommand(p *ProjectOptions, dockerCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secrets [COMMAND]",
+		Short: "Inspect project secrets",
+	}
+	cmd.AddCommand(secretsListCommand(p, dockerCli))
+	return cmd
+}
+
+func secretsListCommand(p *ProjectOptions, dockerCli command.Cli) *cobra.Command {
+	opts := secretsOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS]",
+		Aliases: []string{"list"},
+		Short:   "List secrets declared by the project and services using them",
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runSecretsList(ctx, dockerCli, opts)
+		}),
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: noCompletion(),
+	}
+	cmd.Flags().StringVar(&opts.Format, "format", "table", "Format the output. Values: [table | json]")
+	return cmd
+}
+
+func runSecretsList(ctx context.Context, dockerCli command.Cli, opts secretsOptions) error {
+	project, _, err := opts.ToProject(ctx, dockerCli, nil)
+	if err != nil {
+		return err
+	}
+	secrets := listSecrets(project)
+	return formatter.Print(secrets, opts.Format, dockerCli.Out(),
+		func(w io.Writer) {
+			for _, s := range secrets {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", s.Name, s.Source, strings.Join(s.Services, ", "))
+			}
+		},
+		"NAME", "SOURCE", "SERVICES")
+}
+
+func listSecrets(project *types.Project) []secretEntry {
+	secrets := make([]secretEntry, 0, len(project.Secrets))
+	for name, secret := range project.Secrets {
+		entry := secretEntry{
+			Name:   name,
+			Source: compose.SecretSource(secret),
+		}
+		for _, service := range project.Services {
+			for _, s := range service.Secrets {
+				if s.Source == name {
+					entry.Services = append(entry.Services, service.Name)
+					break
+				}
+			}
+		}
+		sort.Strings(entry.Services)
+		secrets = append(secrets, entry)
+	}
+	sort.Slice(secrets, func(i, j int) bool {
+		return secrets[i].Name < secrets[j].Name
+	})
+	return secrets
+}
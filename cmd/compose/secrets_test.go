@@ -0,0 +1,43 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestListSecrets(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			"app": {Name: "app", Secrets: []types.ServiceSecretConfig{{Source: "token"}, {Source: "cert"}}},
+			"web": {Name: "web", Secrets: []types.ServiceSecretConfig{{Source: "token"}}},
+		},
+		Secrets: types.Secrets{
+			"token":  {Name: "token", Environment: "TOKEN"},
+			"cert":   {Name: "cert", File: "/certs/cert.pem"},
+			"unused": {Name: "unused", External: true},
+		},
+	}
+	assert.DeepEqual(t, listSecrets(project), []secretEntry{
+		{Name: "cert", Source: "file:/certs/cert.pem", Services: []string{"app"}},
+		{Name: "token", Source: "environment:TOKEN", Services: []string{"app", "web"}},
+		{Name: "unused", Source: "external"},
+	})
+}
@@ -0,0 +1,61 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+type stackDeployOptions struct {
+	*ProjectOptions
+	prune            bool
+	withRegistryAuth bool
+}
+
+func stackDeployCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
+	opts := stackDeployOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "stack-deploy [OPTIONS] [SERVICE...]",
+		Short: "Deploy services as a swarm stack",
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runStackDeploy(ctx, dockerCli, backend, opts, args)
+		}),
+		ValidArgsFunction: completeServiceNames(dockerCli, p),
+	}
+	cmd.Flags().BoolVar(&opts.prune, "prune", false, "Remove swarm services of the project which are not declared by the compose file")
+	cmd.Flags().BoolVar(&opts.withRegistryAuth, "with-registry-auth", false, "Send registry authentication details to swarm agents")
+	return cmd
+}
+
+func runStackDeploy(ctx context.Context, dockerCli command.Cli, backend api.Service, opts stackDeployOptions, services []string) error {
+	project, _, err := opts.ToProject(ctx, dockerCli, services)
+	if err != nil {
+		return err
+	}
+
+	return backend.StackDeploy(ctx, project, api.StackDeployOptions{
+		Prune:            opts.prune,
+		WithRegistryAuth: opts.withRegistryAuth,
+	})
+}
@@ -18,7 +18,11 @@ package compose
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"time"
 
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/container"
@@ -34,9 +38,13 @@ type statsOptions struct {
 	format         string
 	noStream       bool
 	noTrunc        bool
+	listen         string
 }
 
-func statsCommand(p *ProjectOptions, dockerCli command.Cli) *cobra.Command {
+// prometheusFormat serves metrics over HTTP in Prometheus exposition format
+const prometheusFormat = "prometheus"
+
+func statsCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
 	opts := statsOptions{
 		ProjectOptions: p,
 	}
@@ -45,6 +53,9 @@ func statsCommand(p *ProjectOptions, dockerCli command.Cli) *cobra.Command {
 		Short: "Display a live stream of container(s) resource usage statistics",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: Adapt(func(ctx context.Context, args []string) error {
+			if opts.format == prometheusFormat {
+				return runStatsExporter(ctx, dockerCli, backend, opts, args)
+			}
 			return runStats(ctx, dockerCli, opts, args)
 		}),
 		ValidArgsFunction: completeServiceNames(dockerCli, p),
@@ -55,10 +66,12 @@ func statsCommand(p *ProjectOptions, dockerCli command.Cli) *cobra.Command {
 'table':            Print output in table format with column headers (default)
 'table TEMPLATE':   Print output in table format using the given Go template
 'json':             Print in JSON format
+'prometheus':       Serve metrics in Prometheus exposition format on the --listen address
 'TEMPLATE':         Print output using the given Go template.
 Refer to https://docs.docker.com/go/formatting/ for more information about formatting output with templates`)
 	flags.BoolVar(&opts.noStream, "no-stream", false, "Disable streaming stats and only pull the first result")
 	flags.BoolVar(&opts.noTrunc, "no-trunc", false, "Do not truncate output")
+	flags.StringVar(&opts.listen, "listen", "127.0.0.1:9180", "Address to serve metrics on with --format prometheus")
 	return cmd
 }
 
@@ -82,3 +95,93 @@ func runStats(ctx context.Context, dockerCli command.Cli, opts statsOptions, ser
 		Filters:  &args,
 	})
 }
+
+func runStatsExporter(ctx context.Context, dockerCli command.Cli, backend api.Service, opts statsOptions, service []string) error {
+	name, err := opts.ProjectOptions.toProjectName(ctx, dockerCli)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		stats, err := backend.Stats(r.Context(), name, api.StatsOptions{
+			Services: service,
+			All:      opts.all,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		writePrometheusStats(w, name, stats)
+	})
+	server := &http.Server{
+		Addr:              opts.listen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+	_, _ = fmt.Fprintf(dockerCli.Out(), "Serving metrics for project %s on http://%s/metrics\n", name, opts.listen)
+	err = server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+var healthStatuses = []string{"starting", "healthy", "unhealthy"}
+
+func writePrometheusStats(w io.Writer, project string, stats []api.ContainerStats) {
+	metric := func(name, kind, help string, value func(s api.ContainerStats) (float64, bool)) {
+		_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+		for _, s := range stats {
+			if v, ok := value(s); ok {
+				_, _ = fmt.Fprintf(w, "%s{%s} %g\n", name, prometheusLabels(project, s), v)
+			}
+		}
+	}
+	metric("compose_container_running", "gauge", "Whether the container is running.",
+		func(s api.ContainerStats) (float64, bool) {
+			if s.State == "running" {
+				return 1, true
+			}
+			return 0, true
+		})
+	metric("compose_container_cpu_usage_seconds_total", "counter", "Cumulative CPU time consumed by the container.",
+		func(s api.ContainerStats) (float64, bool) {
+			return s.CPUUsage.Seconds(), s.State == "running"
+		})
+	metric("compose_container_memory_usage_bytes", "gauge", "Memory used by the container.",
+		func(s api.ContainerStats) (float64, bool) {
+			return float64(s.MemoryUsage), s.State == "running"
+		})
+	metric("compose_container_memory_limit_bytes", "gauge", "Memory limit of the container.",
+		func(s api.ContainerStats) (float64, bool) {
+			return float64(s.MemoryLimit), s.State == "running"
+		})
+	metric("compose_container_restarts_total", "counter", "Number of times the engine restarted the container.",
+		func(s api.ContainerStats) (float64, bool) {
+			return float64(s.RestartCount), true
+		})
+
+	name := "compose_container_health_status"
+	_, _ = fmt.Fprintf(w, "# HELP %s Container healthcheck status.\n# TYPE %s gauge\n", name, name)
+	for _, s := range stats {
+		if s.Health == "" {
+			continue
+		}
+		for _, status := range healthStatuses {
+			value := 0
+			if s.Health == status {
+				value = 1
+			}
+			_, _ = fmt.Fprintf(w, "%s{%s,status=%q} %d\n", name, prometheusLabels(project, s), status, value)
+		}
+	}
+}
+
+func prometheusLabels(project string, s api.ContainerStats) string {
+	return fmt.Sprintf("project=%q,service=%q,container=%q,number=\"%d\"", project, s.Service, s.Name, s.Number)
+}
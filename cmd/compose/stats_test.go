@@ -0,0 +1,61 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWritePrometheusStats(t *testing.T) {
+	var b bytes.Buffer
+	writePrometheusStats(&b, "demo", []api.ContainerStats{
+		{Name: "demo-db-1", Service: "db", Number: 1, State: "exited", RestartCount: 3},
+		{
+			Name: "demo-web-1", Service: "web", Number: 1, State: "running", Health: "healthy",
+			CPUUsage: 1500 * time.Millisecond, MemoryUsage: 1024, MemoryLimit: 4096,
+		},
+	})
+	expected := `# HELP compose_container_running Whether the container is running.
+# TYPE compose_container_running gauge
+compose_container_running{project="demo",service="db",container="demo-db-1",number="1"} 0
+compose_container_running{project="demo",service="web",container="demo-web-1",number="1"} 1
+# HELP compose_container_cpu_usage_seconds_total Cumulative CPU time consumed by the container.
+# TYPE compose_container_cpu_usage_seconds_total counter
+compose_container_cpu_usage_seconds_total{project="demo",service="web",container="demo-web-1",number="1"} 1.5
+# HELP compose_container_memory_usage_bytes Memory used by the container.
+# TYPE compose_container_memory_usage_bytes gauge
+compose_container_memory_usage_bytes{project="demo",service="web",container="demo-web-1",number="1"} 1024
+# HELP compose_container_memory_limit_bytes Memory limit of the container.
+# TYPE compose_container_memory_limit_bytes gauge
+compose_container_memory_limit_bytes{project="demo",service="web",container="demo-web-1",number="1"} 4096
+# HELP compose_container_restarts_total Number of times the engine restarted the container.
+# TYPE compose_container_restarts_total counter
+compose_container_restarts_total{project="demo",service="db",container="demo-db-1",number="1"} 3
+compose_container_restarts_total{project="demo",service="web",container="demo-web-1",number="1"} 0
+# HELP compose_container_health_status Container healthcheck status.
+# TYPE compose_container_health_status gauge
+compose_container_health_status{project="demo",service="web",container="demo-web-1",number="1",status="starting"} 0
+compose_container_health_status{project="demo",service="web",container="demo-web-1",number="1",status="healthy"} 1
+compose_container_health_status{project="demo",service="web",container="demo-web-1",number="1",status="unhealthy"} 0
+`
+	assert.Equal(t, expected, b.String())
+}
@@ -30,6 +30,7 @@ type stopOptions struct {
 	*ProjectOptions
 	timeChanged bool
 	timeout     int
+	drain       bool
 }
 
 func stopCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
@@ -49,6 +50,7 @@ func stopCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service)
 	}
 	flags := cmd.Flags()
 	flags.IntVarP(&opts.timeout, "timeout", "t", 0, "Specify a shutdown timeout in seconds")
+	flags.BoolVar(&opts.drain, "wait-for-drain", false, "Wait for services declaring x-drain to report no active connections before stopping")
 
 	return cmd
 }
@@ -65,8 +67,9 @@ func runStop(ctx context.Context, dockerCli command.Cli, backend api.Service, op
 		timeout = &timeoutValue
 	}
 	return backend.Stop(ctx, name, api.StopOptions{
-		Timeout:  timeout,
-		Services: services,
-		Project:  project,
+		Timeout:      timeout,
+		Services:     services,
+		Project:      project,
+		WaitForDrain: opts.drain,
 	})
 }
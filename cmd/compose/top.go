@@ -27,11 +27,13 @@ import (
 	"github.com/docker/cli/cli/command"
 	"github.com/spf13/cobra"
 
+	"github.com/docker/compose/v2/cmd/formatter"
 	"github.com/docker/compose/v2/pkg/api"
 )
 
 type topOptions struct {
 	*ProjectOptions
+	format string
 }
 
 func topCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
@@ -46,9 +48,20 @@ func topCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *
 		}),
 		ValidArgsFunction: completeServiceNames(dockerCli, p),
 	}
+	topCmd.Flags().StringVar(&opts.format, "format", "table", "Format the output. Values: [table | json]")
 	return topCmd
 }
 
+// topProcess is a process running in a project container, as reported by `top --format json`
+type topProcess struct {
+	Service   string
+	Container string
+	PID       string
+	PPID      string
+	CPU       string
+	Command   string
+}
+
 func runTop(ctx context.Context, dockerCli command.Cli, backend api.Service, opts topOptions, services []string) error {
 	projectName, err := opts.toProjectName(ctx, dockerCli)
 	if err != nil {
@@ -60,28 +73,68 @@ func runTop(ctx context.Context, dockerCli command.Cli, backend api.Service, opt
 	}
 
 	sort.Slice(containers, func(i, j int) bool {
+		if containers[i].Service != containers[j].Service {
+			return containers[i].Service < containers[j].Service
+		}
 		return containers[i].Name < containers[j].Name
 	})
 
+	switch opts.format {
+	case "json":
+		return formatter.Print(topProcesses(containers), formatter.JSON, dockerCli.Out(), nil)
+	case "table", "":
+		return printTop(dockerCli.Out(), containers)
+	default:
+		return fmt.Errorf("unsupported format %q", opts.format)
+	}
+}
+
+// printTop renders processes of all containers as a single table grouped by service. Process columns are the ones
+// reported by the Docker Engine, so those depend on the container platform
+func printTop(out io.Writer, containers []api.ContainerProcSummary) error {
+	var titles []string
 	for _, container := range containers {
-		fmt.Fprintf(dockerCli.Out(), "%s\n", container.Name)
-		err := psPrinter(dockerCli.Out(), func(w io.Writer) {
+		if len(container.Titles) > len(titles) {
+			titles = container.Titles
+		}
+	}
+	if len(titles) == 0 {
+		return nil
+	}
+	return psPrinter(out, func(w io.Writer) {
+		for _, container := range containers {
 			for _, proc := range container.Processes {
-				info := []interface{}{}
-				for _, p := range proc {
-					info = append(info, p)
-				}
-				_, _ = fmt.Fprintf(w, strings.Repeat("%s\t", len(info))+"\n", info...)
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", container.Service, container.Name, strings.Join(proc, "\t"))
+			}
+		}
+	}, append([]string{"SERVICE", "CONTAINER"}, titles...)...)
+}
 
+func topProcesses(containers []api.ContainerProcSummary) []topProcess {
+	processes := []topProcess{}
+	for _, container := range containers {
+		column := func(proc []string, titles ...string) string {
+			for i, title := range container.Titles {
+				for _, t := range titles {
+					if strings.EqualFold(title, t) && i < len(proc) {
+						return proc[i]
+					}
+				}
 			}
-			fmt.Fprintln(w)
-		},
-			container.Titles...)
-		if err != nil {
-			return err
+			return ""
+		}
+		for _, proc := range container.Processes {
+			processes = append(processes, topProcess{
+				Service:   container.Service,
+				Container: container.Name,
+				PID:       column(proc, "PID"),
+				PPID:      column(proc, "PPID"),
+				CPU:       column(proc, "C", "%CPU", "CPU"),
+				Command:   column(proc, "CMD", "COMMAND", "Name"),
+			})
 		}
 	}
-	return nil
+	return processes
 }
 
 func psPrinter(out io.Writer, printer func(writer io.Writer), headers ...string) error {
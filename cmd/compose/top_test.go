@@ -0,0 +1,64 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+var topTitles = []string{"UID", "PID", "PPID", "C", "STIME", "TTY", "TIME", "CMD"}
+
+var topContainers = []api.ContainerProcSummary{
+	{
+		Name:    "myproject-db-1",
+		Service: "db",
+		Titles:  topTitles,
+		Processes: [][]string{
+			{"999", "1001", "1000", "0", "15:33", "?", "00:00:01", "postgres"},
+			{"999", "1002", "1001", "3", "15:33", "?", "00:00:00", "postgres: checkpointer"},
+		},
+	},
+	{
+		Name:      "myproject-web-1",
+		Service:   "web",
+		Titles:    topTitles,
+		Processes: [][]string{{"root", "2001", "2000", "2", "15:34", "?", "00:00:00", "nginx -g daemon off;"}},
+	},
+}
+
+func TestPrintTop(t *testing.T) {
+	var out bytes.Buffer
+	assert.NilError(t, printTop(&out, topContainers))
+	assert.Equal(t, out.String(), ""+
+		"SERVICE   CONTAINER         UID    PID    PPID   C    STIME   TTY   TIME       CMD\n"+
+		"db        myproject-db-1    999    1001   1000   0    15:33   ?     00:00:01   postgres\n"+
+		"db        myproject-db-1    999    1002   1001   3    15:33   ?     00:00:00   postgres: checkpointer\n"+
+		"web       myproject-web-1   root   2001   2000   2    15:34   ?     00:00:00   nginx -g daemon off;\n")
+}
+
+func TestTopProcesses(t *testing.T) {
+	assert.DeepEqual(t, topProcesses(topContainers), []topProcess{
+		{Service: "db", Container: "myproject-db-1", PID: "1001", PPID: "1000", CPU: "0", Command: "postgres"},
+		{Service: "db", Container: "myproject-db-1", PID: "1002", PPID: "1001", CPU: "3", Command: "postgres: checkpointer"},
+		{Service: "web", Container: "myproject-web-1", PID: "2001", PPID: "2000", CPU: "2", Command: "nginx -g daemon off;"},
+	})
+}
@@ -18,8 +18,8 @@ package compose
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -60,6 +60,8 @@ type upOptions struct {
 	watch                 bool
 	navigationMenu        bool
 	navigationMenuChanged bool
+	plan                  bool
+	keepProfiles          bool
 }
 
 func (opts upOptions) apply(project *types.Project, services []string) (*types.Project, error) {
@@ -86,9 +88,15 @@ func (opts *upOptions) validateNavigationMenu(dockerCli command.Cli, experimenta
 		opts.navigationMenu = false
 		return
 	}
-	if !opts.navigationMenuChanged {
-		opts.navigationMenu = SetUnchangedOption(ComposeMenu, experimentals.NavBar())
+	if opts.navigationMenuChanged {
+		return
+	}
+	if _, ok := os.LookupEnv(ComposeMenu); ok {
+		opts.navigationMenu = SetUnchangedOption(ComposeMenu, false)
+		return
 	}
+	// only check experimental feature when menu would be displayed, so users are not warned otherwise
+	opts.navigationMenu = !opts.Detach && experimentals.Check(experimental.Menu)
 }
 
 func (opts upOptions) OnExit() api.Cascade {
@@ -123,12 +131,13 @@ func upCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service, ex
 			if create.ignoreOrphans && create.removeOrphans {
 				return fmt.Errorf("cannot combine %s and --remove-orphans", ComposeIgnoreOrphans)
 			}
-			if len(up.attach) != 0 && up.attachDependencies {
-				return errors.New("cannot combine --attach and --attach-dependencies")
-			}
 
 			up.validateNavigationMenu(dockerCli, experiments)
 
+			if err := checkVariables(project); err != nil {
+				return err
+			}
+
 			if !p.All && len(project.Services) == 0 {
 				return fmt.Errorf("no service selected")
 			}
@@ -141,7 +150,7 @@ func upCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service, ex
 	flags.BoolVarP(&up.Detach, "detach", "d", false, "Detached mode: Run containers in the background")
 	flags.BoolVar(&create.Build, "build", false, "Build images before starting containers")
 	flags.BoolVar(&create.noBuild, "no-build", false, "Don't build an image, even if it's policy")
-	flags.StringVar(&create.Pull, "pull", "policy", `Pull image before running ("always"|"missing"|"never")`)
+	flags.StringVar(&create.Pull, "pull", "policy", `Pull image before running ("always"|"missing"|"never"|"build")`)
 	flags.BoolVar(&create.removeOrphans, "remove-orphans", false, "Remove containers for services not defined in the Compose file")
 	flags.StringArrayVar(&create.scale, "scale", []string{}, "Scale SERVICE to NUM instances. Overrides the `scale` setting in the Compose file if present.")
 	flags.BoolVar(&up.noColor, "no-color", false, "Produce monochrome output")
@@ -158,12 +167,20 @@ func upCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service, ex
 	flags.BoolVar(&create.recreateDeps, "always-recreate-deps", false, "Recreate dependent containers. Incompatible with --no-recreate.")
 	flags.BoolVarP(&create.noInherit, "renew-anon-volumes", "V", false, "Recreate anonymous volumes instead of retrieving data from the previous containers")
 	flags.BoolVar(&create.quietPull, "quiet-pull", false, "Pull without printing progress information")
-	flags.StringArrayVar(&up.attach, "attach", []string{}, "Restrict attaching to the specified services. Incompatible with --attach-dependencies.")
+	flags.BoolVar(&create.adopt, "adopt", false, "Rename containers created with a distinct naming scheme (e.g. by docker-compose v1) instead of reporting them")
+	flags.BoolVar(&create.adoptOrphans, "adopt-orphans", false, "Move containers of another project running a service with the same name and image into this project, keeping their volumes")
+	flags.BoolVar(&create.ignoreResourceCheck, "ignore-resource-check", false, "Only warn when services require more memory or cpus than the Docker Engine has")
+	flags.BoolVar(&create.rollbackOnCancel, "rollback-on-cancel", false, "Remove networks, volumes and containers created by the command if it gets canceled")
+	flags.StringVar(&create.platform, "platform", "", "Run all services for platform, as linux/arm64, overriding the platform they declare")
+	create.healthcheck.addFlags(flags)
+	flags.StringArrayVar(&up.attach, "attach", []string{}, "Restrict attaching to the specified services")
 	flags.StringArrayVar(&up.noAttach, "no-attach", []string{}, "Do not attach (stream logs) to the specified services")
-	flags.BoolVar(&up.attachDependencies, "attach-dependencies", false, "Automatically attach to log output of dependent services")
+	flags.BoolVar(&up.keepProfiles, "keep-profiles", false, "Keep profiles enabling services which already have containers active")
+	flags.BoolVar(&up.attachDependencies, "attach-dependencies", false, "Automatically attach to log output of dependent services, or of the services selected with --attach")
 	flags.BoolVar(&up.wait, "wait", false, "Wait for services to be running|healthy. Implies detached mode.")
 	flags.IntVar(&up.waitTimeout, "wait-timeout", 0, "Maximum duration to wait for the project to be running|healthy")
 	flags.BoolVarP(&up.watch, "watch", "w", false, "Watch source code and rebuild/refresh containers when files are updated.")
+	flags.BoolVar(&up.plan, "plan", false, "Show changes to be applied to containers, without applying them")
 	flags.BoolVar(&up.navigationMenu, "menu", false, "Enable interactive shortcuts when running attached. Incompatible with --detach. Can also be enable/disable by setting COMPOSE_MENU environment var.")
 
 	return upCmd
@@ -189,11 +206,11 @@ func validateFlags(up *upOptions, create *createOptions) error {
 	if up.Detach && (up.attachDependencies || up.cascadeStop || up.cascadeFail || len(up.attach) > 0) {
 		return fmt.Errorf("--detach cannot be combined with --abort-on-container-exit, --abort-on-container-failure, --attach or --attach-dependencies")
 	}
-	if create.forceRecreate && create.noRecreate {
-		return fmt.Errorf("--force-recreate and --no-recreate are incompatible")
+	if err := create.validateRecreate(); err != nil {
+		return err
 	}
-	if create.recreateDeps && create.noRecreate {
-		return fmt.Errorf("--always-recreate-deps and --no-recreate are incompatible")
+	if err := create.healthcheck.validate(); err != nil {
+		return err
 	}
 	if create.noBuild && up.watch {
 		return fmt.Errorf("--no-build and --watch are incompatible")
@@ -201,6 +218,37 @@ func validateFlags(up *upOptions, create *createOptions) error {
 	return nil
 }
 
+// attachTo returns the services to stream logs from, the other ones running detached
+func (opts upOptions) attachTo(project *types.Project, services []string) ([]string, error) {
+	attachSet := utils.NewSet[string]()
+	if len(opts.attach) != 0 {
+		// services are passed explicitly with --attach, verify they're valid and then use them as-is
+		attachSet.AddAll(opts.attach...)
+		unexpectedSvcs := attachSet.Diff(utils.NewSet(project.ServiceNames()...))
+		if len(unexpectedSvcs) != 0 {
+			return nil, fmt.Errorf("cannot attach to services not included in up: %s", strings.Join(unexpectedSvcs.Elements(), ", "))
+		}
+		services = opts.attach
+	}
+	// mark services being launched (and potentially their deps) for attach
+	// if they didn't opt-out via Compose YAML
+	var dependencyOpt types.DependencyOption = types.IgnoreDependencies
+	if opts.attachDependencies {
+		dependencyOpt = types.IncludeDependencies
+	}
+	if err := project.ForEachService(services, func(serviceName string, s *types.ServiceConfig) error {
+		if s.Attach == nil || *s.Attach {
+			attachSet.Add(serviceName)
+		}
+		return nil
+	}, dependencyOpt); err != nil {
+		return nil, err
+	}
+	// filter out any services that have been explicitly marked for ignore with `--no-attach`
+	attachSet.RemoveAll(opts.noAttach...)
+	return attachSet.Elements(), nil
+}
+
 func runUp(
 	ctx context.Context,
 	dockerCli command.Cli,
@@ -211,6 +259,20 @@ func runUp(
 	project *types.Project,
 	services []string,
 ) error {
+	if upOptions.keepProfiles && len(services) == 0 {
+		// extend a project already running with other profiles, rather than leaving those services aside
+		active, err := backend.ActiveProfiles(ctx, project)
+		if err != nil {
+			return err
+		}
+		if len(active) > 0 {
+			project, err = project.WithProfiles(append(project.Profiles, active...))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	err := createOptions.Apply(project)
 	if err != nil {
 		return err
@@ -247,6 +309,23 @@ func runUp(
 		Inherit:              !createOptions.noInherit,
 		Timeout:              createOptions.GetTimeout(),
 		QuietPull:            createOptions.quietPull,
+		Adopt:                createOptions.adopt,
+		AdoptOrphans:         createOptions.adoptOrphans,
+		IgnoreResourceCheck:  createOptions.ignoreResourceCheck,
+		RollbackOnCancel:     createOptions.rollbackOnCancel,
+		HealthCheck:          createOptions.healthcheck.override(),
+	}
+
+	if upOptions.plan {
+		changes, err := backend.Plan(ctx, project, create)
+		if err != nil {
+			return err
+		}
+		return formatter.Print(changes, "", dockerCli.Out(), func(w io.Writer) {
+			for _, c := range changes {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.Service, c.Container, c.Action, c.Reason)
+			}
+		}, "SERVICE", "CONTAINER", "ACTION", "REASON")
 	}
 
 	if upOptions.noStart {
@@ -258,34 +337,11 @@ func runUp(
 	if !upOptions.Detach {
 		consumer = formatter.NewLogConsumer(ctx, dockerCli.Out(), dockerCli.Err(), !upOptions.noColor, !upOptions.noPrefix, upOptions.timestamp)
 
-		var attachSet utils.Set[string]
-		if len(upOptions.attach) != 0 {
-			// services are passed explicitly with --attach, verify they're valid and then use them as-is
-			attachSet = utils.NewSet(upOptions.attach...)
-			unexpectedSvcs := attachSet.Diff(utils.NewSet(project.ServiceNames()...))
-			if len(unexpectedSvcs) != 0 {
-				return fmt.Errorf("cannot attach to services not included in up: %s", strings.Join(unexpectedSvcs.Elements(), ", "))
-			}
-		} else {
-			// mark services being launched (and potentially their deps) for attach
-			// if they didn't opt-out via Compose YAML
-			attachSet = utils.NewSet[string]()
-			var dependencyOpt types.DependencyOption = types.IgnoreDependencies
-			if upOptions.attachDependencies {
-				dependencyOpt = types.IncludeDependencies
-			}
-			if err := project.ForEachService(services, func(serviceName string, s *types.ServiceConfig) error {
-				if s.Attach == nil || *s.Attach {
-					attachSet.Add(serviceName)
-				}
-				return nil
-			}, dependencyOpt); err != nil {
-				return err
-			}
+		var err error
+		attach, err = upOptions.attachTo(project, services)
+		if err != nil {
+			return err
 		}
-		// filter out any services that have been explicitly marked for ignore with `--no-attach`
-		attachSet.RemoveAll(upOptions.noAttach...)
-		attach = attachSet.Elements()
 	}
 
 	timeout := time.Duration(upOptions.waitTimeout) * time.Second
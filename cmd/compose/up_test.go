@@ -17,6 +17,7 @@
 package compose
 
 import (
+	"sort"
 	"testing"
 
 	"github.com/compose-spec/compose-go/v2/types"
@@ -49,3 +50,47 @@ func TestApplyScaleOpt(t *testing.T) {
 	assert.Equal(t, *bar.Deploy.Replicas, 3)
 
 }
+
+func TestUpAttachTo(t *testing.T) {
+	noAttach := false
+	p := &types.Project{
+		Services: types.Services{
+			"web": {
+				Name:      "web",
+				DependsOn: types.DependsOnConfig{"api": {Condition: types.ServiceConditionStarted}},
+			},
+			"api": {
+				Name:      "api",
+				DependsOn: types.DependsOnConfig{"db": {Condition: types.ServiceConditionStarted}, "proxy": {Condition: types.ServiceConditionStarted}},
+			},
+			"db":     {Name: "db"},
+			"proxy":  {Name: "proxy", Attach: &noAttach},
+			"worker": {Name: "worker"},
+		},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		opts     upOptions
+		services []string
+		expected []string
+	}{
+		{name: "all", opts: upOptions{}, expected: []string{"api", "db", "web", "worker"}},
+		{name: "selected", opts: upOptions{}, services: []string{"web"}, expected: []string{"web"}},
+		{name: "selected with dependencies", opts: upOptions{attachDependencies: true}, services: []string{"web"}, expected: []string{"api", "db", "web"}},
+		{name: "attach", opts: upOptions{attach: []string{"web", "worker"}}, expected: []string{"web", "worker"}},
+		{name: "attach opted-out service", opts: upOptions{attach: []string{"proxy"}}, expected: []string{"proxy"}},
+		{name: "attach with dependencies", opts: upOptions{attach: []string{"api"}, attachDependencies: true}, expected: []string{"api", "db"}},
+		{name: "no-attach", opts: upOptions{attach: []string{"api"}, attachDependencies: true, noAttach: []string{"db"}}, expected: []string{"api"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			attach, err := tc.opts.attachTo(p, tc.services)
+			assert.NilError(t, err)
+			sort.Strings(attach)
+			assert.DeepEqual(t, attach, tc.expected)
+		})
+	}
+
+	_, err := upOptions{attach: []string{"unknown"}}.attachTo(p, nil)
+	assert.Error(t, err, "cannot attach to services not included in up: unknown")
+}
@@ -0,0 +1,138 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/cli"
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/mitchellh/mapstructure"
+)
+
+// extVariables declares, at compose file top-level, the variables the project relies on
+const extVariables = "x-variables"
+
+// variableDefinition documents a variable declared by x-variables
+type variableDefinition struct {
+	// Type is one of string, integer, number or boolean
+	Type        string `mapstructure:"type"`
+	Default     any    `mapstructure:"default"`
+	Required    bool   `mapstructure:"required"`
+	Description string `mapstructure:"description"`
+}
+
+func decodeVariables(v any) (map[string]variableDefinition, error) {
+	var variables map[string]variableDefinition
+	if err := mapstructure.Decode(v, &variables); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", extVariables, err)
+	}
+	for name, variable := range variables {
+		switch variable.Type {
+		case "", "string", "integer", "number", "boolean":
+		default:
+			return nil, fmt.Errorf("invalid %s: variable %s has unsupported type %q", extVariables, name, variable.Type)
+		}
+		if variable.Default != nil {
+			if err := variable.check(variable.defaultValue()); err != nil {
+				return nil, fmt.Errorf("invalid %s: default value of %s %w", extVariables, name, err)
+			}
+		}
+	}
+	return variables, nil
+}
+
+func (v variableDefinition) defaultValue() string {
+	if v.Default == nil {
+		return ""
+	}
+	return fmt.Sprint(v.Default)
+}
+
+// check validates value according to variable type
+func (v variableDefinition) check(value string) error {
+	var err error
+	switch v.Type {
+	case "integer":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "number":
+		_, err = strconv.ParseFloat(value, 64)
+	case "boolean":
+		_, err = strconv.ParseBool(value)
+	}
+	if err != nil {
+		return fmt.Errorf("must be of type %s, got %q", v.Type, value)
+	}
+	return nil
+}
+
+// withProjectVariables sets defaults of variables declared by top-level x-variables, unless those are set by
+// environment or env files, so they apply to interpolation
+func withProjectVariables(model *rawModel) cli.ProjectOptionsFn {
+	return func(o *cli.ProjectOptions) error {
+		variables, err := decodeVariables(model.get(o)[extVariables])
+		if err != nil {
+			return err
+		}
+		for name, variable := range variables {
+			if _, ok := o.Environment[name]; !ok && variable.Default != nil {
+				o.Environment[name] = variable.defaultValue()
+			}
+		}
+		return nil
+	}
+}
+
+// checkVariables reports all required variables which are missing, and values which don't match declared type
+func checkVariables(project *types.Project) error {
+	variables, err := decodeVariables(project.Extensions[extVariables])
+	if err != nil || len(variables) == 0 {
+		return err
+	}
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []string
+	for _, name := range names {
+		variable := variables[name]
+		value, ok := project.Environment[name]
+		if !ok || value == "" {
+			if variable.Required {
+				problem := fmt.Sprintf("%s is required", name)
+				if variable.Description != "" {
+					problem += ": " + variable.Description
+				}
+				problems = append(problems, problem)
+			}
+			continue
+		}
+		if err := variable.check(value); err != nil {
+			problems = append(problems, fmt.Sprintf("%s %s", name, err))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New("invalid variables:\n  " + strings.Join(problems, "\n  "))
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestProjectVariables(t *testing.T) {
+	dir := t.TempDir()
+	composeFile := filepath.Join(dir, "compose.yaml")
+	assert.NilError(t, os.WriteFile(composeFile, []byte(`
+x-variables:
+  PORT:
+    type: integer
+    default: 8080
+  DEBUG:
+    type: boolean
+  DB_PASSWORD:
+    required: true
+    description: password of the database admin user
+  REPLICAS:
+    type: integer
+services:
+  app:
+    image: alpine
+    ports:
+      - ${PORT}:80
+`), 0o600))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, ".env"), []byte("DEBUG=maybe\nREPLICAS=2\n"), 0o600))
+
+	opts := ProjectOptions{ConfigPaths: []string{composeFile}}
+	options, err := opts.toProjectOptions()
+	assert.NilError(t, err)
+	assert.Equal(t, options.Environment["PORT"], "8080")
+	project, err := options.LoadProject(context.Background())
+	assert.NilError(t, err)
+	assert.Equal(t, project.Services["app"].Ports[0].Published, "8080")
+
+	err = checkVariables(project)
+	assert.Error(t, err, "invalid variables:\n"+
+		"  DB_PASSWORD is required: password of the database admin user\n"+
+		`  DEBUG must be of type boolean, got "maybe"`)
+
+	project.Environment["DB_PASSWORD"] = "secret"
+	project.Environment["DEBUG"] = "true"
+	assert.NilError(t, checkVariables(project))
+}
+
+func TestDecodeVariablesInvalid(t *testing.T) {
+	_, err := decodeVariables(map[string]any{"PORT": map[string]any{"type": "port"}})
+	assert.ErrorContains(t, err, `variable PORT has unsupported type "port"`)
+
+	_, err = decodeVariables(map[string]any{"PORT": map[string]any{"type": "integer", "default": "http"}})
+	assert.ErrorContains(t, err, `default value of PORT must be of type integer, got "http"`)
+}
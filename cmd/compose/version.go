@@ -17,6 +17,7 @@
 package compose
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -26,6 +27,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose/v2/internal"
+	"github.com/docker/compose/v2/internal/experimental"
+	"github.com/docker/compose/v2/pkg/api"
 )
 
 type versionOptions struct {
@@ -33,15 +36,14 @@ type versionOptions struct {
 	short  bool
 }
 
-func versionCommand(dockerCli command.Cli) *cobra.Command {
+func versionCommand(dockerCli command.Cli, experiments *experimental.State) *cobra.Command {
 	opts := versionOptions{}
 	cmd := &cobra.Command{
 		Use:   "version [OPTIONS]",
 		Short: "Show the Docker Compose version information",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			runVersion(opts, dockerCli)
-			return nil
+			return runVersion(opts, dockerCli, experiments)
 		},
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			// overwrite parent PersistentPreRunE to avoid trying to load
@@ -57,14 +59,20 @@ func versionCommand(dockerCli command.Cli) *cobra.Command {
 	return cmd
 }
 
-func runVersion(opts versionOptions, dockerCli command.Cli) {
+func runVersion(opts versionOptions, dockerCli command.Cli, experiments *experimental.State) error {
 	if opts.short {
 		fmt.Fprintln(dockerCli.Out(), strings.TrimPrefix(internal.Version, "v"))
-		return
+		return nil
 	}
 	if opts.format == formatter.JSON {
-		fmt.Fprintf(dockerCli.Out(), "{\"version\":%q}\n", internal.Version)
-		return
+		experiments.LoadConfig(dockerCli.ConfigFile())
+		b, err := json.Marshal(api.GetBuildInfo(experiments.Enabled()...))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(dockerCli.Out(), string(b))
+		return nil
 	}
 	fmt.Fprintln(dockerCli.Out(), "Docker Compose version", internal.Version)
+	return nil
 }
@@ -0,0 +1,270 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v2/cmd/formatter"
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+type volumesOptions struct {
+	*ProjectOptions
+	Quiet  bool
+	Format string
+	force  bool
+}
+
+// volumesCommand groups subcommands to manage volumes created by compose for a project
+func volumesCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "volumes [COMMAND]",
+		Short: "Manage project volumes",
+	}
+	cmd.AddCommand(
+		volumesListCommand(p, dockerCli, backend),
+		volumesInspectCommand(p, dockerCli, backend),
+		volumesPruneCommand(p, dockerCli, backend),
+	)
+	return cmd
+}
+
+func volumesListCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
+	opts := volumesOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS]",
+		Aliases: []string{"list"},
+		Short:   "List volumes created for the project",
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runVolumesList(ctx, dockerCli, backend, opts)
+		}),
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: noCompletion(),
+	}
+	cmd.Flags().StringVar(&opts.Format, "format", "table", "Format the output. Values: [table | json]")
+	cmd.Flags().BoolVarP(&opts.Quiet, "quiet", "q", false, "Only display volume names")
+	return cmd
+}
+
+func runVolumesList(ctx context.Context, dockerCli command.Cli, backend api.Service, opts volumesOptions) error {
+	projectName, err := opts.toProjectName(ctx, dockerCli)
+	if err != nil {
+		return err
+	}
+	volumes, err := backend.Volumes(ctx, projectName, api.VolumesOptions{})
+	if err != nil {
+		return err
+	}
+
+	if opts.Quiet {
+		for _, v := range volumes {
+			_, _ = fmt.Fprintln(dockerCli.Out(), v.Name)
+		}
+		return nil
+	}
+
+	return formatter.Print(volumes, opts.Format, dockerCli.Out(),
+		func(w io.Writer) {
+			for _, v := range volumes {
+				size, links := "N/A", "N/A"
+				if v.Size >= 0 {
+					size = units.HumanSizeWithPrecision(float64(v.Size), 3)
+				}
+				if v.RefCount >= 0 {
+					links = strconv.FormatInt(v.RefCount, 10)
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", v.Name, v.Volume, v.Driver, links, size)
+			}
+		},
+		"NAME", "VOLUME", "DRIVER", "LINKS", "SIZE")
+}
+
+func volumesInspectCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
+	opts := volumesOptions{
+		ProjectOptions: p,
+	}
+	return &cobra.Command{
+		Use:   "inspect VOLUME...",
+		Short: "Display detailed information on project volumes",
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runVolumesInspect(ctx, dockerCli, backend, opts, args)
+		}),
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: noCompletion(),
+	}
+}
+
+func runVolumesInspect(ctx context.Context, dockerCli command.Cli, backend api.Service, opts volumesOptions, names []string) error {
+	projectName, err := opts.toProjectName(ctx, dockerCli)
+	if err != nil {
+		return err
+	}
+	volumes, err := backend.Volumes(ctx, projectName, api.VolumesOptions{
+		Volumes: names,
+	})
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		found := false
+		for _, v := range volumes {
+			if v.Name == name || v.Volume == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("no such volume %q in project %q", name, projectName)
+		}
+	}
+
+	out, err := formatter.ToJSON(volumes, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(dockerCli.Out(), out)
+	return err
+}
+
+func volumesPruneCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
+	opts := volumesOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "prune [OPTIONS]",
+		Short: "Remove project volumes not used by any container",
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runVolumesPrune(ctx, dockerCli, backend, opts)
+		}),
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: noCompletion(),
+	}
+	cmd.Flags().BoolVarP(&opts.force, "force", "f", false, "Don't ask to confirm removal")
+	return cmd
+}
+
+func runVolumesPrune(ctx context.Context, dockerCli command.Cli, backend api.Service, opts volumesOptions) error {
+	projectName, err := opts.toProjectName(ctx, dockerCli)
+	if err != nil {
+		return err
+	}
+	removed, err := backend.VolumesPrune(ctx, projectName, api.VolumesPruneOptions{
+		Force: opts.force,
+	})
+	if err != nil {
+		return err
+	}
+	var reclaimed int64
+	for _, v := range removed {
+		if v.Size > 0 {
+			reclaimed += v.Size
+		}
+	}
+	if len(removed) > 0 {
+		_, _ = fmt.Fprintf(dockerCli.Out(), "Total reclaimed space: %s\n", units.HumanSize(float64(reclaimed)))
+	}
+	return nil
+}
+
+type volumesBackupOptions struct {
+	*ProjectOptions
+	file  string
+	clean bool
+}
+
+// alphaVolumesCommand groups experimental subcommands to manage project volumes
+func alphaVolumesCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "volumes [COMMAND]",
+		Short: "Backup and restore project volumes",
+	}
+	cmd.AddCommand(
+		volumesBackupCommand(p, dockerCli, backend),
+		volumesRestoreCommand(p, dockerCli, backend),
+	)
+	return cmd
+}
+
+func volumesBackupCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
+	opts := volumesBackupOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "backup [OPTIONS] VOLUME",
+		Short: "Save the content of a project volume into a gzip-compressed tar archive",
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runVolumesBackup(ctx, dockerCli, backend, opts, args[0])
+		}),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: noCompletion(),
+	}
+	cmd.Flags().StringVarP(&opts.file, "output", "o", "", "Path of the archive to write")
+	_ = cmd.MarkFlagRequired("output")
+	return cmd
+}
+
+func runVolumesBackup(ctx context.Context, dockerCli command.Cli, backend api.Service, opts volumesBackupOptions, volume string) error {
+	project, _, err := opts.ToProject(ctx, dockerCli, nil)
+	if err != nil {
+		return err
+	}
+	return backend.VolumeBackup(ctx, project, api.VolumeBackupOptions{
+		Volume: volume,
+		File:   opts.file,
+	})
+}
+
+func volumesRestoreCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
+	opts := volumesBackupOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "restore [OPTIONS] VOLUME",
+		Short: "Restore the content of a project volume from an archive created by backup",
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runVolumesRestore(ctx, dockerCli, backend, opts, args[0])
+		}),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: noCompletion(),
+	}
+	cmd.Flags().StringVarP(&opts.file, "input", "i", "", "Path of the archive to read")
+	cmd.Flags().BoolVar(&opts.clean, "clean", false, "Remove the volume content not present in the archive")
+	_ = cmd.MarkFlagRequired("input")
+	return cmd
+}
+
+func runVolumesRestore(ctx context.Context, dockerCli command.Cli, backend api.Service, opts volumesBackupOptions, volume string) error {
+	project, _, err := opts.ToProject(ctx, dockerCli, nil)
+	if err != nil {
+		return err
+	}
+	return backend.VolumeRestore(ctx, project, api.VolumeBackupOptions{
+		Volume: volume,
+		File:   opts.file,
+		Clean:  opts.clean,
+	})
+}
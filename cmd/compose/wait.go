@@ -19,6 +19,7 @@ package compose
 import (
 	"context"
 	"os"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
@@ -32,6 +33,8 @@ type waitOptions struct {
 	services []string
 
 	downProject bool
+	condition   string
+	timeout     int
 }
 
 func waitCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service) *cobra.Command {
@@ -43,7 +46,7 @@ func waitCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service)
 	var err error
 	cmd := &cobra.Command{
 		Use:   "wait SERVICE [SERVICE...] [OPTIONS]",
-		Short: "Block until the first service container stops",
+		Short: "Block until the first service container stops, or services reach a condition",
 		Args:  cli.RequiresMinArgs(1),
 		RunE: Adapt(func(ctx context.Context, services []string) error {
 			opts.services = services
@@ -56,6 +59,8 @@ func waitCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service)
 	}
 
 	cmd.Flags().BoolVar(&opts.downProject, "down-project", false, "Drops project when the first container stops")
+	cmd.Flags().StringVar(&opts.condition, "condition", api.WaitConditionExited, "Condition to wait for. Values: [exited | running | healthy]")
+	cmd.Flags().IntVar(&opts.timeout, "timeout", 0, "Maximum duration in seconds to wait for the condition")
 
 	return cmd
 }
@@ -66,8 +71,14 @@ func runWait(ctx context.Context, dockerCli command.Cli, backend api.Service, op
 		return 0, err
 	}
 
-	return backend.Wait(ctx, name, api.WaitOptions{
+	statusCode, err := backend.Wait(ctx, name, api.WaitOptions{
 		Services:                   opts.services,
 		DownProjectOnContainerExit: opts.downProject,
+		Condition:                  opts.condition,
+		Timeout:                    time.Duration(opts.timeout) * time.Second,
 	})
+	if err != nil && statusCode != 0 && opts.condition != api.WaitConditionExited {
+		return 0, cli.StatusError{StatusCode: int(statusCode), Status: err.Error()}
+	}
+	return statusCode, err
 }
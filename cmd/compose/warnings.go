@@ -0,0 +1,92 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v2/cmd/formatter"
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+const warningsFormatText = "text"
+
+// withWarningsFormat sets up ctx to collect warnings when they have to be printed as JSON, rather than logged as they
+// are reported
+func withWarningsFormat(ctx context.Context, format string) (context.Context, error) {
+	switch format {
+	case warningsFormatText:
+		return ctx, nil
+	case formatter.JSON:
+		return api.WithWarningCollector(ctx, &api.WarningCollector{}), nil
+	default:
+		return ctx, fmt.Errorf("unsupported --warnings-format value %q", format)
+	}
+}
+
+// printWarnings prints the warnings collected by ctx, if any, as JSON lines
+func printWarnings(ctx context.Context, out io.Writer) {
+	collector := api.WarningCollectorFrom(ctx)
+	if collector == nil {
+		return
+	}
+	for _, w := range collector.Drain() {
+		b, err := json.Marshal(w)
+		if err != nil {
+			continue
+		}
+		_, _ = fmt.Fprintln(out, string(b))
+	}
+}
+
+// loaderWarningsMu serializes loads which capture compose-go warnings, as those are logged by the logrus standard logger
+var loaderWarningsMu sync.Mutex
+
+// withLoaderWarnings runs load, reporting warnings compose-go logs while loading the compose model to the collector set
+// by ctx, if any, so they are collected along with other warnings
+func withLoaderWarnings[T any](ctx context.Context, load func(context.Context) (T, error)) (T, error) {
+	if api.WarningCollectorFrom(ctx) == nil {
+		return load(ctx)
+	}
+	loaderWarningsMu.Lock()
+	defer loaderWarningsMu.Unlock()
+	logger := logrus.StandardLogger()
+	previous := logger.Formatter
+	logger.SetFormatter(loaderWarningsFormatter{Formatter: previous, ctx: ctx})
+	defer logger.SetFormatter(previous)
+	return load(ctx)
+}
+
+// loaderWarningsFormatter reports warning entries to the collector set by ctx, and formats other entries as usual
+type loaderWarningsFormatter struct {
+	logrus.Formatter
+	ctx context.Context
+}
+
+func (f loaderWarningsFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if entry.Level != logrus.WarnLevel {
+		return f.Formatter.Format(entry)
+	}
+	api.Warn(f.ctx, api.Warning{Code: api.WarningLoad, Message: entry.Message})
+	return nil, nil
+}
@@ -0,0 +1,64 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/cli"
+	"github.com/sirupsen/logrus"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+func TestLoaderWarningsCollected(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "compose.yaml")
+	err := os.WriteFile(file, []byte("version: '3'\nservices:\n  web:\n    image: nginx\n"), 0o600)
+	assert.NilError(t, err)
+	options, err := cli.NewProjectOptions([]string{file}, cli.WithName("test"))
+	assert.NilError(t, err)
+
+	var out bytes.Buffer
+	previous := logrus.StandardLogger().Out
+	logrus.SetOutput(&out)
+	defer logrus.SetOutput(previous)
+
+	ctx, err := withWarningsFormat(context.Background(), "json")
+	assert.NilError(t, err)
+	_, err = withLoaderWarnings(ctx, options.LoadProject)
+	assert.NilError(t, err)
+	assert.Equal(t, out.String(), "", "warnings are expected to be collected rather than logged")
+
+	var printed bytes.Buffer
+	printWarnings(ctx, &printed)
+	assert.Equal(t, printed.String(), `{"code":"load","message":"`+file+`: `+"`version`"+` is obsolete"}`+"\n")
+	assert.DeepEqual(t, api.WarningCollectorFrom(ctx).Warnings(), []api.Warning(nil))
+}
+
+func TestWarningsFormat(t *testing.T) {
+	ctx, err := withWarningsFormat(context.Background(), "text")
+	assert.NilError(t, err)
+	assert.Check(t, api.WarningCollectorFrom(ctx) == nil)
+
+	_, err = withWarningsFormat(context.Background(), "yaml")
+	assert.Error(t, err, `unsupported --warnings-format value "yaml"`)
+}
@@ -26,7 +26,6 @@ import (
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/compose/v2/internal/locker"
 	"github.com/docker/compose/v2/pkg/api"
-	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
@@ -50,7 +49,7 @@ func watchCommand(p *ProjectOptions, dockerCli command.Cli, backend api.Service)
 		}),
 		RunE: AdaptCmd(func(ctx context.Context, cmd *cobra.Command, args []string) error {
 			if cmd.Parent().Name() == "alpha" {
-				logrus.Warn("watch command is now available as a top level command")
+				api.Warn(ctx, api.Warning{Code: api.WarningDeprecated, Message: "watch command is now available as a top level command"})
 			}
 			return runWatch(ctx, dockerCli, backend, watchOpts, buildOpts, args)
 		}),
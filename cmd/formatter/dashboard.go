@@ -0,0 +1,214 @@
+/*
+   Copyright 2024 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// maxPausedLines is the number of log lines kept while logs are paused, older ones are discarded
+const maxPausedLines = 1000
+
+// serviceStatus is the state of a service as rendered by Dashboard
+type serviceStatus struct {
+	State    string
+	Health   string
+	Restarts int
+}
+
+type pausedLine struct {
+	w    io.Writer
+	line string
+}
+
+// Dashboard tracks services status from container events, and controls which log lines get displayed
+type Dashboard struct {
+	mu         sync.Mutex
+	services   []string
+	status     map[string]*serviceStatus
+	started    map[string]bool   // container ID -> started once
+	containers map[string]string // container name -> service
+	focus      string
+	paused     bool
+	buffer     []pausedLine
+}
+
+// NewDashboard creates a Dashboard for services
+func NewDashboard(services []string) *Dashboard {
+	d := &Dashboard{
+		status:     map[string]*serviceStatus{},
+		started:    map[string]bool{},
+		containers: map[string]string{},
+	}
+	for _, service := range services {
+		d.getStatus(service)
+	}
+	return d
+}
+
+func (d *Dashboard) getStatus(service string) *serviceStatus {
+	status, ok := d.status[service]
+	if !ok {
+		status = &serviceStatus{State: "created"}
+		d.status[service] = status
+		d.services = append(d.services, service)
+		sort.Strings(d.services)
+	}
+	return status
+}
+
+// HandleContainerEvent collects container to service mapping from events sent to the log printer
+func (d *Dashboard) HandleContainerEvent(event api.ContainerEvent) {
+	if event.Service == "" {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	status := d.getStatus(event.Service)
+	switch event.Type {
+	case api.ContainerEventAttach:
+		d.containers[event.Container] = event.Service
+		status.State = "running"
+	case api.ContainerEventExit, api.ContainerEventStopped:
+		status.State = fmt.Sprintf("exited (%d)", event.ExitCode)
+	}
+}
+
+// HandleEvent updates services status from an engine event
+func (d *Dashboard) HandleEvent(event api.Event) {
+	if event.Service == "" {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	status := d.getStatus(event.Service)
+	switch {
+	case event.Status == "start":
+		if d.started[event.Container] {
+			status.Restarts++
+		}
+		d.started[event.Container] = true
+		status.State = "running"
+	case event.Status == "die":
+		status.State = "exited"
+		if code, ok := event.Attributes["exitCode"]; ok {
+			status.State = fmt.Sprintf("exited (%s)", code)
+		}
+	case event.Status == "destroy":
+		delete(d.started, event.Container)
+	case strings.HasPrefix(event.Status, "health_status:"):
+		status.Health = strings.TrimSpace(strings.TrimPrefix(event.Status, "health_status:"))
+	}
+}
+
+// FocusNext restricts logs to the next service, or back to all services after the last one
+func (d *Dashboard) FocusNext() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.services) == 0 {
+		return
+	}
+	i := sort.SearchStrings(d.services, d.focus)
+	switch {
+	case d.focus == "":
+		d.focus = d.services[0]
+	case i+1 < len(d.services):
+		d.focus = d.services[i+1]
+	default:
+		d.focus = ""
+	}
+}
+
+// FocusAll displays logs from all services
+func (d *Dashboard) FocusAll() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.focus = ""
+}
+
+// TogglePause pauses logs, or resumes and prints lines collected while paused
+func (d *Dashboard) TogglePause() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.paused = !d.paused
+	if d.paused {
+		return
+	}
+	for _, l := range d.buffer {
+		fmt.Fprint(l.w, l.line)
+	}
+	d.buffer = nil
+}
+
+// Paused tells if logs are paused
+func (d *Dashboard) Paused() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.paused
+}
+
+// Accept tells if logs from container should be displayed
+func (d *Dashboard) Accept(container string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.focus == "" || d.containers[container] == d.focus
+}
+
+// Hold keeps line to be printed on w once logs are resumed, and returns false if logs are not paused
+func (d *Dashboard) Hold(w io.Writer, line string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.paused {
+		return false
+	}
+	d.buffer = append(d.buffer, pausedLine{w: w, line: line})
+	if len(d.buffer) > maxPausedLines {
+		d.buffer = d.buffer[len(d.buffer)-maxPausedLines:]
+	}
+	return true
+}
+
+// String renders services status as a single line
+func (d *Dashboard) String() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var items []string
+	for _, service := range d.services {
+		status := d.status[service]
+		item := service + " " + status.State
+		if status.Health != "" {
+			item += " (" + status.Health + ")"
+		}
+		if status.Restarts > 0 {
+			item += fmt.Sprintf(" %d restarts", status.Restarts)
+		}
+		if service == d.focus {
+			item = ansiColor(CYAN, item, BOLD)
+		}
+		items = append(items, item)
+	}
+	if d.paused {
+		items = append(items, fmt.Sprintf("logs paused (%d lines)", len(d.buffer)))
+	}
+	return strings.Join(items, " | ")
+}
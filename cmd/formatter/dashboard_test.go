@@ -0,0 +1,70 @@
+/*
+   Copyright 2024 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package formatter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"gotest.tools/v3/assert"
+)
+
+func TestDashboardStatus(t *testing.T) {
+	d := NewDashboard([]string{"web", "db"})
+	d.HandleContainerEvent(api.ContainerEvent{Type: api.ContainerEventAttach, Service: "db", Container: "db-1"})
+	d.HandleEvent(api.Event{Service: "web", Container: "123", Status: "start"})
+	d.HandleEvent(api.Event{Service: "web", Container: "123", Status: "die", Attributes: map[string]string{"exitCode": "1"}})
+	d.HandleEvent(api.Event{Service: "web", Container: "123", Status: "start"})
+	d.HandleEvent(api.Event{Service: "web", Container: "123", Status: "health_status: healthy"})
+
+	assert.Equal(t, d.String(), "db running | web running (healthy) 1 restarts")
+}
+
+func TestDashboardFocus(t *testing.T) {
+	d := NewDashboard([]string{"web", "db"})
+	d.HandleContainerEvent(api.ContainerEvent{Type: api.ContainerEventAttach, Service: "db", Container: "db-1"})
+	d.HandleContainerEvent(api.ContainerEvent{Type: api.ContainerEventAttach, Service: "web", Container: "web-1"})
+	assert.Check(t, d.Accept("db-1"))
+	assert.Check(t, d.Accept("web-1"))
+
+	d.FocusNext()
+	assert.Check(t, d.Accept("db-1"))
+	assert.Check(t, !d.Accept("web-1"))
+
+	d.FocusNext()
+	assert.Check(t, !d.Accept("db-1"))
+	assert.Check(t, d.Accept("web-1"))
+
+	d.FocusNext()
+	assert.Check(t, d.Accept("db-1"))
+	assert.Check(t, d.Accept("web-1"))
+}
+
+func TestDashboardPause(t *testing.T) {
+	d := NewDashboard([]string{"web"})
+	var out bytes.Buffer
+	assert.Check(t, !d.Hold(&out, "first\n"))
+
+	d.TogglePause()
+	assert.Check(t, d.Hold(&out, "second\n"))
+	assert.Check(t, d.Hold(&out, "third\n"))
+	assert.Equal(t, out.String(), "")
+
+	d.TogglePause()
+	assert.Equal(t, out.String(), "second\nthird\n")
+}
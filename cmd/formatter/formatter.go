@@ -22,6 +22,7 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/docker/cli/templates"
 	"github.com/docker/compose/v2/pkg/api"
 )
 
@@ -65,7 +66,34 @@ func Print(toJSON interface{}, format string, outWriter io.Writer, writerFn func
 			_, _ = fmt.Fprintln(outWriter, outJSON)
 		}
 	default:
+		if strings.Contains(format, "{{") {
+			return printTemplate(toJSON, format, outWriter)
+		}
 		return fmt.Errorf("format value %q could not be parsed: %w", format, api.ErrParsingFailed)
 	}
 	return nil
 }
+
+// printTemplate renders a Go template, once per item when toJSON is a slice
+func printTemplate(toJSON interface{}, format string, outWriter io.Writer) error {
+	tmpl, err := templates.Parse(format)
+	if err != nil {
+		return fmt.Errorf("format value %q could not be parsed: %w", format, err)
+	}
+	var items []interface{}
+	if reflect.TypeOf(toJSON).Kind() == reflect.Slice {
+		s := reflect.ValueOf(toJSON)
+		for i := 0; i < s.Len(); i++ {
+			items = append(items, s.Index(i).Interface())
+		}
+	} else {
+		items = append(items, toJSON)
+	}
+	for _, item := range items {
+		if err := tmpl.Execute(outWriter, item); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(outWriter)
+	}
+	return nil
+}
@@ -71,8 +71,21 @@ func TestPrint(t *testing.T) {
 	assert.Equal(t, json, `{"Name":"myName1","Status":"myStatus1"}
 {"Name":"myName2","Status":"myStatus2"}
 `)
+
+	b.Reset()
+	assert.NilError(t, Print(testList, "{{.Name}}: {{.Status}}", b, nil))
+	assert.Equal(t, b.String(), "myName1: myStatus1\nmyName2: myStatus2\n")
+
+	err := Print(testList, "yaml", b, nil)
+	assert.ErrorContains(t, err, "could not be parsed")
 }
 
 func TestColorsGoroutinesLeak(t *testing.T) {
 	goleak.VerifyNone(t)
 }
+
+func TestSetThemeUnsupported(t *testing.T) {
+	assert.NilError(t, SetTheme(nil, "default"))
+	err := SetTheme(nil, "default, fancy")
+	assert.ErrorContains(t, err, `unsupported theme "fancy"`)
+}
@@ -107,19 +107,28 @@ func (l *logConsumer) write(w io.Writer, container, message string) {
 	if l.ctx.Err() != nil {
 		return
 	}
-	if KeyboardManager != nil {
-		KeyboardManager.ClearKeyboardInfo()
+	if KeyboardManager != nil && !KeyboardManager.acceptLog(container) {
+		return
 	}
 
 	p := l.getPresenter(container)
 	timestamp := time.Now().Format(jsonmessage.RFC3339NanoFixed)
+	var out strings.Builder
 	for _, line := range strings.Split(message, "\n") {
 		if l.timestamp {
-			fmt.Fprintf(w, "%s%s%s\n", p.prefix, timestamp, line)
+			fmt.Fprintf(&out, "%s%s%s\n", p.prefix, timestamp, line)
 		} else {
-			fmt.Fprintf(w, "%s%s\n", p.prefix, line)
+			fmt.Fprintf(&out, "%s%s\n", p.prefix, line)
+		}
+	}
+
+	if KeyboardManager != nil {
+		if KeyboardManager.holdLog(w, out.String()) {
+			return
 		}
+		KeyboardManager.ClearKeyboardInfo()
 	}
+	fmt.Fprint(w, out.String())
 
 	if KeyboardManager != nil {
 		KeyboardManager.PrintKeyboardInfo()
@@ -152,7 +161,7 @@ type presenter struct {
 
 func (p *presenter) setPrefix(width int) {
 	if p.name == api.WatchLogger {
-		p.prefix = p.colors(strings.Repeat(" ", width) + " ⦿ ")
+		p.prefix = p.colors(strings.Repeat(" ", width) + " " + watchSymbol + " ")
 		return
 	}
 	p.prefix = p.colors(fmt.Sprintf("%-"+strconv.Itoa(width)+"s | ", p.name))
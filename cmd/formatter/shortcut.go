@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"os"
 	"syscall"
@@ -60,7 +61,7 @@ func (ke *KeyboardError) printError(height int, info string) {
 func (ke *KeyboardError) addError(prefix string, err error) {
 	ke.timeStart = time.Now()
 
-	prefix = ansiColor(CYAN, fmt.Sprintf("%s →", prefix), BOLD)
+	prefix = ansiColor(CYAN, fmt.Sprintf("%s %s", prefix, arrowSymbol), BOLD)
 	errorString := fmt.Sprintf("%s  %s", prefix, err.Error())
 
 	ke.err = errors.New(errorString)
@@ -108,6 +109,7 @@ type LogKeyboard struct {
 	IsWatchConfigured     bool
 	logLevel              KEYBOARD_LOG_LEVEL
 	signalChannel         chan<- os.Signal
+	Dashboard             *Dashboard
 }
 
 var KeyboardManager *LogKeyboard
@@ -115,6 +117,7 @@ var eg multierror.Group
 
 func NewKeyboardManager(ctx context.Context, isDockerDesktopActive, isWatchConfigured bool,
 	sc chan<- os.Signal,
+	dashboard *Dashboard,
 	watchFn func(ctx context.Context,
 		project *types.Project,
 		services []string,
@@ -130,6 +133,7 @@ func NewKeyboardManager(ctx context.Context, isDockerDesktopActive, isWatchConfi
 	km.Watch.WatchFn = watchFn
 
 	km.signalChannel = sc
+	km.Dashboard = dashboard
 
 	KeyboardManager = &km
 }
@@ -201,7 +205,27 @@ func (lk *LogKeyboard) navigationMenu() string {
 		isEnabled = " Disable"
 	}
 	watchInfo = watchInfo + shortcutKeyColor("w") + navColor(isEnabled+" Watch")
-	return openDDInfo + watchInfo
+	if lk.Dashboard == nil {
+		return openDDInfo + watchInfo
+	}
+	var isPaused = " Pause"
+	if lk.Dashboard.Paused() {
+		isPaused = " Resume"
+	}
+	logsInfo := navColor("   ") + shortcutKeyColor("f") + navColor(" Focus next") +
+		navColor("   ") + shortcutKeyColor("a") + navColor(" All logs") +
+		navColor("   ") + shortcutKeyColor("p") + navColor(isPaused+" logs")
+	return openDDInfo + watchInfo + logsInfo + navColor("   ") + lk.Dashboard.String()
+}
+
+// acceptLog tells if a log line from container should be displayed according to dashboard focus
+func (lk *LogKeyboard) acceptLog(container string) bool {
+	return lk.Dashboard == nil || lk.Dashboard.Accept(container)
+}
+
+// holdLog keeps a log line while dashboard is paused
+func (lk *LogKeyboard) holdLog(w io.Writer, line string) bool {
+	return lk.Dashboard != nil && lk.Dashboard.Hold(w, line)
 }
 
 func (lk *LogKeyboard) clearNavigationMenu() {
@@ -284,6 +308,8 @@ func (lk *LogKeyboard) HandleKeyEvents(event keyboard.KeyEvent, ctx context.Cont
 		lk.openDockerDesktop(ctx, project)
 	case 'w':
 		lk.StartWatch(ctx, project, options)
+	case 'f', 'a', 'p':
+		lk.handleDashboardKey(kRune)
 	}
 	switch key := event.Key; key {
 	case keyboard.KeyCtrlC:
@@ -303,6 +329,22 @@ func (lk *LogKeyboard) HandleKeyEvents(event keyboard.KeyEvent, ctx context.Cont
 	}
 }
 
+func (lk *LogKeyboard) handleDashboardKey(key rune) {
+	if lk.Dashboard == nil {
+		return
+	}
+	switch key {
+	case 'f':
+		lk.Dashboard.FocusNext()
+	case 'a':
+		lk.Dashboard.FocusAll()
+	case 'p':
+		lk.clearNavigationMenu()
+		lk.Dashboard.TogglePause()
+	}
+	lk.printNavigationMenu()
+}
+
 func allocateSpace(lines int) {
 	for i := 0; i < lines; i++ {
 		ClearLine()
@@ -0,0 +1,71 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
+)
+
+const (
+	// ThemeDefault renders output with unicode symbols and colors
+	ThemeDefault = "default"
+	// ThemeASCII only uses ASCII characters, for screen readers and terminals lacking unicode support
+	ThemeASCII = "ascii"
+	// ThemeHighContrast only uses bold, bright colors
+	ThemeHighContrast = "high-contrast"
+	// ThemeMonochrome disables colors, like NO_COLOR does
+	ThemeMonochrome = "monochrome"
+)
+
+var (
+	watchSymbol = "⦿"
+	arrowSymbol = "→"
+)
+
+// SetTheme applies a comma-separated list of themes to terminal output: progress, logs prefixes and navigation menu
+func SetTheme(streams api.Streams, theme string) error {
+	for _, t := range strings.Split(theme, ",") {
+		t = strings.TrimSpace(t)
+		switch t {
+		case "", ThemeDefault:
+		case ThemeASCII:
+			watchSymbol = "*"
+			arrowSymbol = "->"
+			progress.ASCII()
+		case ThemeHighContrast:
+			rainbow = []colorFunc{
+				makeColorFunc("36;1"),
+				makeColorFunc("33;1"),
+				makeColorFunc("32;1"),
+				makeColorFunc("35;1"),
+				makeColorFunc("37;1"),
+			}
+			progress.HighContrast()
+		case ThemeMonochrome:
+			progress.NoColor()
+			SetANSIMode(streams, Never)
+		default:
+			return fmt.Errorf("unsupported theme %q, expected a comma-separated list of %q, %q, %q or %q",
+				t, ThemeDefault, ThemeASCII, ThemeHighContrast, ThemeMonochrome)
+		}
+	}
+	return nil
+}
@@ -18,32 +18,131 @@ package experimental
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/sirupsen/logrus"
 
 	"github.com/docker/compose/v2/internal/desktop"
 )
 
 // envComposeExperimentalGlobal can be set to a falsy value (e.g. 0, false) to
-// globally opt-out of any experimental features in Compose.
+// globally opt-out of any experimental features in Compose, or to a comma
+// separated list of experimental features to enable (e.g. `menu`).
 const envComposeExperimentalGlobal = "COMPOSE_EXPERIMENTAL"
 
+// configExperimental is the compose plugin configuration key, in docker CLI
+// config file, listing experimental features to enable
+const configExperimental = "experimental"
+
+// Feature is an experimental feature, disabled unless explicitly enabled.
+type Feature struct {
+	// Name is used to enable the feature with COMPOSE_EXPERIMENTAL or the
+	// `plugins.compose.experimental` docker CLI configuration
+	Name string
+	// Description is a short description of the feature
+	Description string
+	// desktopFlag is the Docker Desktop feature flag which also enables the feature
+	desktopFlag string
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]Feature{}
+)
+
+// Menu is the interactive navigation menu of `compose up`
+var Menu = register(Feature{
+	Name:        "menu",
+	Description: "Interactive navigation menu for attached `up`",
+	desktopFlag: "ComposeNav",
+})
+
+func register(f Feature) Feature {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[f.Name]; ok {
+		panic(fmt.Sprintf("experimental feature %q is already registered", f.Name))
+	}
+	registry[f.Name] = f
+	return f
+}
+
+// Features lists the registered experimental features, sorted by name.
+func Features() []Feature {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	features := make([]Feature, 0, len(registry))
+	for _, f := range registry {
+		features = append(features, f)
+	}
+	sort.Slice(features, func(i, j int) bool {
+		return features[i].Name < features[j].Name
+	})
+	return features
+}
+
 // State of experiments (enabled/disabled) based on environment and local config.
 type State struct {
 	// active is false if experiments have been opted-out of globally.
-	active        bool
+	active bool
+	// enabled are the features explicitly enabled by user, nil if not configured
+	enabled       map[string]bool
 	desktopValues desktop.FeatureFlagResponse
+	warned        sync.Map
 }
 
 func NewState() *State {
 	// experimental features have individual controls, but users can opt out
 	// of ALL experiments easily if desired
-	experimentsActive := true
-	if v := os.Getenv(envComposeExperimentalGlobal); v != "" {
-		experimentsActive, _ = strconv.ParseBool(v)
+	s := &State{active: true}
+	if v, ok := os.LookupEnv(envComposeExperimentalGlobal); ok && v != "" {
+		s.configure(v)
 	}
-	return &State{
-		active: experimentsActive,
+	return s
+}
+
+// configure sets state from a boolean opting in or out of all experiments, or
+// a list of features to enable
+func (s *State) configure(value string) {
+	if active, err := strconv.ParseBool(value); err == nil {
+		s.active = active
+		return
+	}
+	s.enabled = map[string]bool{}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := lookup(name); !ok {
+			logrus.Warnf("unknown experimental feature %q", name)
+			continue
+		}
+		s.enabled[name] = true
+	}
+}
+
+func lookup(name string) (Feature, bool) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	f, ok := registry[name]
+	return f, ok
+}
+
+// LoadConfig reads the experimental features enabled in docker CLI config
+// file, unless COMPOSE_EXPERIMENTAL is set, as environment takes precedence.
+func (s *State) LoadConfig(config *configfile.ConfigFile) {
+	if s == nil || config == nil || s.enabled != nil || os.Getenv(envComposeExperimentalGlobal) != "" {
+		return
+	}
+	if v := config.Plugins["compose"][configExperimental]; v != "" {
+		s.configure(v)
 	}
 }
 
@@ -68,14 +167,47 @@ func (s *State) Load(ctx context.Context, client *desktop.Client) error {
 }
 
 func (s *State) NavBar() bool {
-	return s.determineFeatureState("ComposeNav")
+	return s.IsEnabled(Menu)
+}
+
+// IsEnabled tells if feature is enabled, either by user configuration or by
+// a Docker Desktop feature flag.
+func (s *State) IsEnabled(f Feature) bool {
+	if s == nil || !s.active {
+		return false
+	}
+	if s.enabled[f.Name] {
+		return true
+	}
+	return f.desktopFlag != "" && s.determineFeatureState(f.desktopFlag)
+}
+
+// Check gates a subsystem behind an experimental feature: it tells if feature
+// is enabled, and warns the user the first time an enabled feature is used.
+func (s *State) Check(f Feature) bool {
+	if !s.IsEnabled(f) {
+		return false
+	}
+	if _, warned := s.warned.LoadOrStore(f.Name, true); !warned {
+		logrus.Warnf("%s is an experimental feature, it may change or be removed in future versions", f.Name)
+	}
+	return true
+}
+
+// Enabled lists the names of the experimental features currently enabled
+func (s *State) Enabled() []string {
+	var enabled []string
+	for _, f := range Features() {
+		if s.IsEnabled(f) {
+			enabled = append(enabled, f.Name)
+		}
+	}
+	return enabled
 }
 
 func (s *State) determineFeatureState(name string) bool {
 	if s == nil || !s.active || s.desktopValues == nil {
 		return false
 	}
-	// TODO(milas): we should add individual environment variable overrides
-	// 	per-experiment in a generic way here
 	return s.desktopValues[name].Enabled
 }
@@ -0,0 +1,60 @@
+/*
+   Copyright 2024 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package experimental
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/internal/desktop"
+)
+
+var testFeature = register(Feature{Name: "test-feature", Description: "Feature used by tests"})
+
+func TestStateFromEnvironment(t *testing.T) {
+	t.Setenv(envComposeExperimentalGlobal, "test-feature, unknown")
+	s := NewState()
+	assert.Check(t, s.IsEnabled(testFeature))
+	assert.Check(t, !s.IsEnabled(Menu))
+	assert.DeepEqual(t, s.Enabled(), []string{"test-feature"})
+
+	t.Setenv(envComposeExperimentalGlobal, "false")
+	s = NewState()
+	s.desktopValues = desktop.FeatureFlagResponse{"ComposeNav": {Enabled: true}}
+	assert.Check(t, !s.IsEnabled(Menu), "opting out is expected to disable all experiments")
+}
+
+func TestStateFromConfig(t *testing.T) {
+	t.Setenv(envComposeExperimentalGlobal, "")
+	s := NewState()
+	s.LoadConfig(&configfile.ConfigFile{Plugins: map[string]map[string]string{
+		"compose": {"experimental": "menu"},
+	}})
+	assert.DeepEqual(t, s.Enabled(), []string{"menu"})
+	assert.Check(t, s.NavBar())
+}
+
+func TestDesktopFeatureFlag(t *testing.T) {
+	s := NewState()
+	assert.Check(t, !s.NavBar())
+	s.desktopValues = desktop.FeatureFlagResponse{"ComposeNav": {Enabled: true}}
+	assert.Check(t, s.NavBar())
+	assert.Check(t, s.Check(Menu))
+	assert.Check(t, !s.Check(testFeature))
+}
@@ -0,0 +1,117 @@
+/*
+   Copyright 2023 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package locker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/pkg/pidfile"
+	"github.com/gofrs/flock"
+)
+
+// LockedError is returned when a project lock is held by another process
+type LockedError struct {
+	Project string
+	// PID of the process holding the lock, 0 if unknown
+	PID int
+}
+
+func (e *LockedError) Error() string {
+	if e.PID == 0 {
+		return fmt.Sprintf("project %q is locked by another compose process", e.Project)
+	}
+	return fmt.Sprintf("project %q is locked by another compose process (PID %d)", e.Project, e.PID)
+}
+
+// ProjectLock is an advisory lock preventing concurrent compose processes to run conflicting operations on a project.
+// It relies on an OS file lock, which is released by the OS if the holding process dies
+type ProjectLock struct {
+	project string
+	flock   *flock.Flock
+	// pidPath is a side file recording the PID of the lock holder, only used to report who holds the lock
+	pidPath string
+}
+
+// NewProjectLock creates the lock for a project, which is held by a lock file in the runtime directory
+func NewProjectLock(projectName string) (*ProjectLock, error) {
+	run, err := runDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(run, fmt.Sprintf("%s.lock", projectName))
+	return &ProjectLock{
+		project: projectName,
+		flock:   flock.New(path),
+		pidPath: path + ".pid",
+	}, nil
+}
+
+// TryLock attempts to take the lock, and returns a LockedError if held by another process
+func (l *ProjectLock) TryLock() error {
+	locked, err := l.flock.TryLock()
+	if err != nil {
+		return err
+	}
+	if !locked {
+		// best effort, holder may not have written its PID yet
+		pid, _ := pidfile.Read(l.pidPath)
+		return &LockedError{Project: l.project, PID: pid}
+	}
+	if err := os.WriteFile(l.pidPath, []byte(strconv.Itoa(os.Getpid())), 0o644); err != nil {
+		return errors.Join(err, l.flock.Unlock())
+	}
+	return nil
+}
+
+// Lock takes the lock, waiting up to timeout for it to be released by another process
+func (l *ProjectLock) Lock(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		err := l.TryLock()
+		var locked *LockedError
+		if !errors.As(err, &locked) {
+			return err
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// Unlock releases the lock, if held by the current process. The lock file is kept, as removing it would let another
+// process lock a file which is about to be unlinked
+func (l *ProjectLock) Unlock() error {
+	if !l.flock.Locked() {
+		return nil
+	}
+	err := os.Remove(l.pidPath)
+	if os.IsNotExist(err) {
+		err = nil
+	}
+	return errors.Join(err, l.flock.Unlock())
+}
@@ -0,0 +1,62 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package locker
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestProjectLock(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	l, err := NewProjectLock("test")
+	assert.NilError(t, err)
+	assert.NilError(t, l.TryLock())
+
+	// lock is held by another lock instance
+	other, err := NewProjectLock("test")
+	assert.NilError(t, err)
+	err = other.TryLock()
+	assert.ErrorType(t, err, &LockedError{})
+	assert.Error(t, err, `project "test" is locked by another compose process (PID `+strconv.Itoa(os.Getpid())+`)`)
+
+	assert.NilError(t, l.Unlock())
+	_, err = os.Stat(l.pidPath)
+	assert.Assert(t, os.IsNotExist(err))
+
+	assert.NilError(t, other.TryLock())
+	assert.NilError(t, other.Unlock())
+}
+
+func TestProjectLockStale(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	l, err := NewProjectLock("test")
+	assert.NilError(t, err)
+	assert.NilError(t, l.TryLock())
+	// a lock file left by a dead process isn't locked anymore: simulate by releasing the OS lock only
+	assert.NilError(t, l.flock.Unlock())
+
+	other, err := NewProjectLock("test")
+	assert.NilError(t, err)
+	assert.NilError(t, other.TryLock())
+	assert.NilError(t, other.Unlock())
+}
@@ -41,6 +41,13 @@ const (
 	// ComposeYAMLMediaType is the media type for each layer (Compose file)
 	// in the image manifest.
 	ComposeYAMLMediaType = "application/vnd.docker.compose.file+yaml"
+	// ComposeLocalFileMediaType is the media type for local files the Compose
+	// files refer to, like env files, which are restored relative to the
+	// project directory set by the ComposeLocalFilePathAnnotation.
+	ComposeLocalFileMediaType = "application/vnd.docker.compose.localfile"
+	// ComposeLocalFilePathAnnotation is the path of a local file, relative to
+	// the project directory.
+	ComposeLocalFilePathAnnotation = "com.docker.compose.path"
 	// ComposeEmptyConfigMediaType is a media type used for the config descriptor
 	// when doing OCI 1.0-style pushes.
 	//
@@ -81,6 +88,19 @@ func DescriptorForComposeFile(path string, content []byte) v1.Descriptor {
 	}
 }
 
+// DescriptorForLocalFile creates the descriptor for a local file, with path relative to the project directory
+func DescriptorForLocalFile(path string, content []byte) v1.Descriptor {
+	return v1.Descriptor{
+		MediaType: ComposeLocalFileMediaType,
+		Digest:    digest.FromString(string(content)),
+		Size:      int64(len(content)),
+		Annotations: map[string]string{
+			"com.docker.compose.version":   api.ComposeVersion,
+			ComposeLocalFilePathAnnotation: filepath.ToSlash(path),
+		},
+	}
+}
+
 func PushManifest(
 	ctx context.Context,
 	resolver *imagetools.Resolver,
@@ -0,0 +1,149 @@
+/*
+   Copyright 2023 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package sync
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// FileStat is the state of a file used to detect changes, without reading its content
+type FileStat struct {
+	Size    int64       `json:"size"`
+	ModTime time.Time   `json:"mtime"`
+	Mode    fs.FileMode `json:"mode"`
+}
+
+// Index records the state of files within a host directory, by slash-separated path relative to the directory
+type Index map[string]FileStat
+
+// BuildIndex walks a host directory to record the state of all files it contains
+func BuildIndex(dir string) (Index, error) {
+	index := Index{}
+	err := filepath.Walk(dir, func(p string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		stat := FileStat{Mode: info.Mode()}
+		if !info.IsDir() {
+			stat.Size = info.Size()
+			stat.ModTime = info.ModTime()
+		}
+		index[filepath.ToSlash(rel)] = stat
+		return nil
+	})
+	return index, err
+}
+
+// LoadIndex reads an index saved by Save, an index file which doesn't exist results in an empty index
+func LoadIndex(file string) (Index, error) {
+	index := Index{}
+	b, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(b, &index)
+	return index, err
+}
+
+// Save writes index to file, creating parent directories as required
+func (i Index) Save(file string) error {
+	b, err := json.Marshal(i)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(file, b, 0o600)
+}
+
+// Diff returns paths added or modified in current compared to i, and paths which have been removed. Directories
+// are only reported when added or removed, and paths within such a directory are not reported as the whole
+// directory is to be synced
+func (i Index) Diff(current Index) (changed []string, removed []string) {
+	for p, stat := range current {
+		previous, ok := i[p]
+		switch {
+		case !ok:
+			changed = append(changed, p)
+		case stat.Mode.IsDir() && previous.Mode.IsDir():
+		case !stat.ModTime.Equal(previous.ModTime) || stat.Size != previous.Size || stat.Mode != previous.Mode:
+			changed = append(changed, p)
+		}
+	}
+	for p := range i {
+		if _, ok := current[p]; !ok {
+			removed = append(removed, p)
+		}
+	}
+	return topLevelPaths(changed, current), topLevelPaths(removed, i)
+}
+
+// topLevelPaths removes paths within a directory which is part of the list
+func topLevelPaths(paths []string, index Index) []string {
+	listed := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		listed[p] = true
+	}
+	var result []string
+	for _, p := range paths {
+		covered := false
+		for dir := path.Dir(p); dir != "."; dir = path.Dir(dir) {
+			if listed[dir] && index[dir].Mode.IsDir() {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			result = append(result, p)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Changes computes the PathMapping to sync changes in a host directory since index previous was built, into
+// containerPath. Paths which have been removed on host are also returned, to be deleted in container
+func Changes(hostDir string, containerPath string, previous Index) ([]PathMapping, Index, error) {
+	current, err := BuildIndex(hostDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	changed, removed := previous.Diff(current)
+	var mappings []PathMapping
+	for _, p := range append(changed, removed...) {
+		mappings = append(mappings, PathMapping{
+			HostPath:      filepath.Join(hostDir, filepath.FromSlash(p)),
+			ContainerPath: path.Join(containerPath, p),
+		})
+	}
+	return mappings, current, nil
+}
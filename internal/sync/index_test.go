@@ -0,0 +1,84 @@
+/*
+   Copyright 2023 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestIndexDiff(t *testing.T) {
+	now := time.Now()
+	previous := Index{
+		"main.go":        {Size: 10, ModTime: now},
+		"README.md":      {Size: 5, ModTime: now},
+		"lib":            {Mode: os.ModeDir},
+		"lib/util.go":    {Size: 3, ModTime: now},
+		"old":            {Mode: os.ModeDir},
+		"old/legacy.go":  {Size: 1, ModTime: now},
+		"old/legacy2.go": {Size: 1, ModTime: now},
+	}
+	current := Index{
+		"main.go":         {Size: 12, ModTime: now.Add(time.Second)},
+		"README.md":       {Size: 5, ModTime: now},
+		"lib":             {Mode: os.ModeDir},
+		"lib/util.go":     {Size: 3, ModTime: now},
+		"lib/new.go":      {Size: 3, ModTime: now},
+		"assets":          {Mode: os.ModeDir},
+		"assets/logo.png": {Size: 100, ModTime: now},
+		"assets-v2.txt":   {Size: 1, ModTime: now},
+	}
+	changed, removed := previous.Diff(current)
+	assert.DeepEqual(t, changed, []string{"assets", "assets-v2.txt", "lib/new.go", "main.go"})
+	assert.DeepEqual(t, removed, []string{"old"})
+}
+
+func TestChanges(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o600))
+	assert.NilError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0o700))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "sub", "b.txt"), []byte("b"), 0o600))
+
+	changes, index, err := Changes(dir, "/app", Index{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, changes, []PathMapping{
+		{HostPath: filepath.Join(dir, "a.txt"), ContainerPath: "/app/a.txt"},
+		{HostPath: filepath.Join(dir, "sub"), ContainerPath: "/app/sub"},
+	})
+
+	indexFile := filepath.Join(t.TempDir(), "cache", "index.json")
+	assert.NilError(t, index.Save(indexFile))
+	previous, err := LoadIndex(indexFile)
+	assert.NilError(t, err)
+
+	assert.NilError(t, os.Remove(filepath.Join(dir, "a.txt")))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "sub", "b.txt"), []byte("bb"), 0o600))
+	changes, _, err = Changes(dir, "/app", previous)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, changes, []PathMapping{
+		{HostPath: filepath.Join(dir, "sub", "b.txt"), ContainerPath: "/app/sub/b.txt"},
+		{HostPath: filepath.Join(dir, "a.txt"), ContainerPath: "/app/a.txt"},
+	})
+
+	missing, err := LoadIndex(filepath.Join(t.TempDir(), "missing.json"))
+	assert.NilError(t, err)
+	assert.Equal(t, len(missing), 0)
+}
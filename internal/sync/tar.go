@@ -70,7 +70,15 @@ func (t *Tar) Sync(ctx context.Context, service types.ServiceConfig, paths []Pat
 	if err != nil {
 		return err
 	}
+	ids := make([]string, len(containers))
+	for i, c := range containers {
+		ids[i] = c.ID
+	}
+	return t.SyncContainers(ctx, ids, paths)
+}
 
+// SyncContainers copies paths into the selected containers, and deletes the ones which don't exist on host anymore
+func (t *Tar) SyncContainers(ctx context.Context, containerIDs []string, paths []PathMapping) error {
 	var pathsToCopy []PathMapping
 	var pathsToDelete []string
 	for _, p := range paths {
@@ -86,8 +94,8 @@ func (t *Tar) Sync(ctx context.Context, service types.ServiceConfig, paths []Pat
 		deleteCmd = append([]string{"rm", "-rf"}, pathsToDelete...)
 	}
 	var eg multierror.Group
-	for i := range containers {
-		containerID := containers[i].ID
+	for i := range containerIDs {
+		containerID := containerIDs[i]
 		tarReader := tarArchive(pathsToCopy)
 
 		eg.Go(func() error {
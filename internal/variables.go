@@ -19,4 +19,7 @@ package internal
 var (
 	// Version is the version of the CLI injected in compilation time
 	Version = "dev"
+	// Commit is the git commit the CLI is built from, injected in compilation time. When not set, the VCS revision
+	// embedded by the Go toolchain is used
+	Commit = ""
 )
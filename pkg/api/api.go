@@ -18,7 +18,11 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -52,6 +56,8 @@ type Service interface {
 	Ps(ctx context.Context, projectName string, options PsOptions) ([]ContainerSummary, error)
 	// List executes the equivalent to a `docker stack ls`
 	List(ctx context.Context, options ListOptions) ([]Stack, error)
+	// Projects returns all compose projects known to the engine, with services and containers counts
+	Projects(ctx context.Context, options ListOptions) ([]ProjectSummary, error)
 	// Kill executes the equivalent to a `compose kill`
 	Kill(ctx context.Context, projectName string, options KillOptions) error
 	// RunOneOffContainer creates a service oneoff container and starts its dependencies
@@ -70,6 +76,8 @@ type Service interface {
 	UnPause(ctx context.Context, projectName string, options PauseOptions) error
 	// Top executes the equivalent to a `compose top`
 	Top(ctx context.Context, projectName string, services []string) ([]ContainerProcSummary, error)
+	// Stats collects a single resource usage sample for the project containers
+	Stats(ctx context.Context, projectName string, options StatsOptions) ([]ContainerStats, error)
 	// Events executes the equivalent to a `compose events`
 	Events(ctx context.Context, projectName string, options EventsOptions) error
 	// Port executes the equivalent to a `compose port`
@@ -78,6 +86,14 @@ type Service interface {
 	Publish(ctx context.Context, project *types.Project, repository string, options PublishOptions) error
 	// Images executes the equivalent of a `compose images`
 	Images(ctx context.Context, projectName string, options ImagesOptions) ([]ImageSummary, error)
+	// ImagesPrune removes images used or built by the project, as `compose down --rmi` or `compose images --prune-dangling` do
+	ImagesPrune(ctx context.Context, project *types.Project, options ImagesPruneOptions) ([]string, error)
+	// Volumes executes the equivalent of a `compose volumes ls`
+	Volumes(ctx context.Context, projectName string, options VolumesOptions) ([]VolumeSummary, error)
+	// VolumesPrune executes the equivalent of a `compose volumes prune`
+	VolumesPrune(ctx context.Context, projectName string, options VolumesPruneOptions) ([]VolumeSummary, error)
+	// Networks executes the equivalent of a `compose networks`
+	Networks(ctx context.Context, projectName string) ([]NetworkSummary, error)
 	// MaxConcurrency defines upper limit for concurrent operations against engine API
 	MaxConcurrency(parallel int)
 	// DryRunMode defines if dry run applies to the command
@@ -90,10 +106,79 @@ type Service interface {
 	Wait(ctx context.Context, projectName string, options WaitOptions) (int64, error)
 	// Scale manages numbers of container instances running per service
 	Scale(ctx context.Context, project *types.Project, options ScaleOptions) error
+	// ActiveProfiles returns the profiles enabling services which are disabled in project but have containers
+	ActiveProfiles(ctx context.Context, project *types.Project) ([]string, error)
+	// Plan computes the changes `up` would apply to project containers, without applying them
+	Plan(ctx context.Context, project *types.Project, options CreateOptions) ([]PlannedChange, error)
+	// Checkpoint executes the equivalent of a `compose alpha checkpoint`
+	Checkpoint(ctx context.Context, project *types.Project, options CheckpointOptions) error
+	// Restore executes the equivalent of a `compose alpha restore`
+	Restore(ctx context.Context, project *types.Project, options CheckpointOptions) error
+	// Export executes the equivalent of a `compose alpha export`
+	Export(ctx context.Context, project *types.Project, options ExportOptions) error
+	// Import executes the equivalent of a `compose alpha import`
+	Import(ctx context.Context, options ImportOptions) error
+	// VolumeBackup executes the equivalent of a `compose alpha volumes backup`
+	VolumeBackup(ctx context.Context, project *types.Project, options VolumeBackupOptions) error
+	// VolumeRestore executes the equivalent of a `compose alpha volumes restore`
+	VolumeRestore(ctx context.Context, project *types.Project, options VolumeBackupOptions) error
+	// Orphans returns containers labeled with project name but for services project doesn't declare
+	Orphans(ctx context.Context, project *types.Project) ([]ContainerSummary, error)
+	// StackDeploy executes the equivalent of a `compose alpha stack-deploy`
+	StackDeploy(ctx context.Context, project *types.Project, options StackDeployOptions) error
+	// Warnings returns the parts of the compose model which are ignored by the backend
+	Warnings(ctx context.Context, project *types.Project) ([]Warning, error)
 }
 
 type ScaleOptions struct {
 	Services []string
+	// Wait won't return until scaled services reached the running|healthy state
+	Wait        bool
+	WaitTimeout time.Duration
+}
+
+// CheckpointOptions group options of the Checkpoint and Restore APIs
+type CheckpointOptions struct {
+	// Name of the checkpoint, created or restored for each container of the selected services
+	Name string
+	// Services to checkpoint or restore, all if empty
+	Services []string
+	// LeaveRunning keeps containers running after checkpoint is created
+	LeaveRunning bool
+}
+
+// ExportOptions group options of the Export API
+type ExportOptions struct {
+	// Output is the path of the archive to be written
+	Output string
+}
+
+// ImportOptions group options of the Import API
+type ImportOptions struct {
+	// Input is the path of an archive created by Export
+	Input string
+	// WorkingDir is the directory the imported compose.yaml file is written to
+	WorkingDir string
+	// ProjectName overrides the name of the exported project
+	ProjectName string
+}
+
+// VolumeBackupOptions group options of the VolumeBackup and VolumeRestore APIs
+type VolumeBackupOptions struct {
+	// Volume selects a project volume by name or key in the `volumes` section of the compose model
+	Volume string
+	// File is the path of the gzip-compressed tar archive written by backup or read by restore
+	File string
+	// Clean removes the volume before restore, so files which are not in the archive are not kept
+	Clean bool
+}
+
+// StackDeployOptions group options of the StackDeploy API
+type StackDeployOptions struct {
+	// Prune removes swarm services of the project which are not declared by the compose file anymore
+	Prune bool
+	// WithRegistryAuth sends registry credentials to swarm agents, so they can pull private images
+	WithRegistryAuth bool
 }
 
 type WaitOptions struct {
@@ -101,8 +186,21 @@ type WaitOptions struct {
 	Services []string
 	// Executes a down when a container exits
 	DownProjectOnContainerExit bool
+	// Condition services' containers have to reach, defaults to WaitConditionExited
+	Condition string
+	// Timeout is the maximum duration to wait for, zero means no limit
+	Timeout time.Duration
 }
 
+const (
+	// WaitConditionExited waits for the first container to exit
+	WaitConditionExited = "exited"
+	// WaitConditionRunning waits for all containers to be running
+	WaitConditionRunning = "running"
+	// WaitConditionHealthy waits for all containers to be healthy, or running if they don't declare a healthcheck
+	WaitConditionHealthy = "healthy"
+)
+
 type VizOptions struct {
 	// IncludeNetworks if true, network names a container is attached to should appear in the graph node
 	IncludeNetworks bool
@@ -147,12 +245,26 @@ type BuildOptions struct {
 	Memory int64
 	// Builder name passed in the command line
 	Builder string
+	// Print the resolved build configuration of services as JSON, without building
+	Print bool
+	// SBOM attestation parameters, "true" to attach a SBOM generated with default parameters to built images
+	SBOM string
+	// Provenance attestation parameters, "true" to attach provenance with default parameters to built images
+	Provenance string
+	// ContextSizeWarning is the build context size, in bytes, above which a warning is emitted. 0 disables the check
+	ContextSizeWarning int64
 }
 
+// ProviderExtension declares a service provisioned by an external provider plugin, rather than run as containers
+const ProviderExtension = "x-provider"
+
 // Apply mutates project according to build options
 func (o BuildOptions) Apply(project *types.Project) error {
 	platform := project.Environment["DOCKER_DEFAULT_PLATFORM"]
 	for name, service := range project.Services {
+		if _, ok := service.Extensions[ProviderExtension]; ok {
+			continue
+		}
 		if service.Image == "" && service.Build == nil {
 			return fmt.Errorf("invalid service %q. Must specify either image or build", name)
 		}
@@ -199,6 +311,17 @@ type CreateOptions struct {
 	Timeout *time.Duration
 	// QuietPull makes the pulling process quiet
 	QuietPull bool
+	// Adopt renames containers created with a distinct naming scheme so they match the current one
+	Adopt bool
+	// AdoptOrphans moves containers of another project, running a service with the same name and image, into the
+	// current project, typically after the project has been renamed. Their volumes are reused
+	AdoptOrphans bool
+	// IgnoreResourceCheck only warns when services require more memory or cpus than the Docker Engine has
+	IgnoreResourceCheck bool
+	// RollbackOnCancel removes the networks, volumes and containers created by the command if user cancels it
+	RollbackOnCancel bool
+	// HealthCheck overrides the attributes it sets in all services' healthchecks
+	HealthCheck *types.HealthCheckConfig
 }
 
 // StartOptions group options of the Start API
@@ -240,6 +363,8 @@ type RestartOptions struct {
 	Services []string
 	// NoDeps ignores services dependencies
 	NoDeps bool
+	// WithDependents restarts all dependent services, even if they don't declare depends_on.restart
+	WithDependents bool
 }
 
 // StopOptions group options of the Stop API
@@ -250,15 +375,17 @@ type StopOptions struct {
 	Timeout *time.Duration
 	// Services passed in the command line to be stopped
 	Services []string
+	// WaitForDrain runs services' x-drain probe and waits for connections to be drained before stopping
+	WaitForDrain bool
 }
 
-// UpOptions group options of the Up API
+// UpOptions group options of the Up API. Use NewUpOptions to set options without relying on fields layout
 type UpOptions struct {
 	Create CreateOptions
 	Start  StartOptions
 }
 
-// DownOptions group options of the Down API
+// DownOptions group options of the Down API. Use NewDownOptions to set options without relying on fields layout
 type DownOptions struct {
 	// RemoveOrphans will cleanup containers that are not declared on the compose model but own the same labels
 	RemoveOrphans bool
@@ -272,6 +399,31 @@ type DownOptions struct {
 	Volumes bool
 	// Services passed in the command line to be stopped
 	Services []string
+	// AssumeYes don't ask to confirm removal of orphan containers
+	AssumeYes bool
+	// Resources restricts the kinds of resources to be removed, among ResourceContainer, ResourceNetwork,
+	// ResourceVolume and ResourceImage. All kinds are removed when empty, volumes and images still require Volumes
+	// and Images to be set
+	Resources []string
+	// Removed is called for each resource actually removed
+	Removed func(resource RemovedResource)
+}
+
+const (
+	// ResourceContainer is the kind of resource for containers
+	ResourceContainer = "container"
+	// ResourceNetwork is the kind of resource for networks
+	ResourceNetwork = "network"
+	// ResourceVolume is the kind of resource for volumes
+	ResourceVolume = "volume"
+	// ResourceImage is the kind of resource for images
+	ResourceImage = "image"
+)
+
+// RemovedResource is a resource removed by Down
+type RemovedResource struct {
+	Type string
+	Name string
 }
 
 // ConfigOptions group options of the Config API
@@ -300,6 +452,33 @@ type PullOptions struct {
 // ImagesOptions group options of the Images API
 type ImagesOptions struct {
 	Services []string
+	// CheckUpdates queries the registry for the digest of image tags, to detect images that would change on next pull
+	CheckUpdates bool
+	// SBOM retrieves from the registry the SBOM attested for images
+	SBOM bool
+}
+
+// ImagesPruneOptions group options of the ImagesPrune API
+type ImagesPruneOptions struct {
+	// Mode selects images used by services to be removed: "local" for images built by compose, "all" to also remove
+	// images pulled from a registry
+	Mode string
+	// Dangling removes images built for the project which are not referenced anymore by a service
+	Dangling bool
+	// RemoveOrphans removes images built for services which are not declared by the project anymore
+	RemoveOrphans bool
+}
+
+// VolumesOptions group options of the Volumes API
+type VolumesOptions struct {
+	// Volumes select volumes by name or key in the `volumes` section of the compose model
+	Volumes []string
+}
+
+// VolumesPruneOptions group options of the VolumesPrune API
+type VolumesPruneOptions struct {
+	// Force don't ask to confirm removal
+	Force bool
 }
 
 // KillOptions group options of the Kill API
@@ -312,6 +491,8 @@ type KillOptions struct {
 	Services []string
 	// Signal to send to containers
 	Signal string
+	// Signals overrides Signal for some services, by service name
+	Signals map[string]string
 	// All can be set to true to try to kill all found containers, independently of their state
 	All bool
 }
@@ -409,6 +590,8 @@ const (
 // PublishOptions group options of the Publish API
 type PublishOptions struct {
 	ResolveImageDigests bool
+	// WithEnvironment publishes the .env file and env files, which may hold credentials
+	WithEnvironment bool
 
 	OCIVersion OCIVersion
 }
@@ -435,6 +618,13 @@ type PsOptions struct {
 	Services []string
 }
 
+// StatsOptions group options of the Stats API
+type StatsOptions struct {
+	Services []string
+	// All includes stopped containers, reported without resource usage
+	All bool
+}
+
 // CopyOptions group options of the cp API
 type CopyOptions struct {
 	Source      string
@@ -443,6 +633,8 @@ type CopyOptions struct {
 	Index       int
 	FollowLink  bool
 	CopyUIDGID  bool
+	// Sync only transfers files of the source directory changed since last sync to the same container
+	Sync bool
 }
 
 // PortPublisher hold status about published port
@@ -476,6 +668,84 @@ type ContainerSummary struct {
 	LocalVolumes int
 }
 
+// ServiceSummary aggregates the state of a service's containers
+type ServiceSummary struct {
+	Name string
+	// Desired is the number of replicas declared by the compose file, unset for services the project doesn't declare
+	Desired *int `json:",omitempty"`
+	Running int
+	// Health is the worst healthcheck status among running containers, empty if service has no healthcheck
+	Health     string
+	Publishers PortPublishers
+	// ExitCodes are the exit codes of the stopped containers
+	ExitCodes []int `json:",omitempty"`
+}
+
+// SummarizeServices aggregates containers by service, compared with the replicas project declares if set
+func SummarizeServices(project *types.Project, containers []ContainerSummary) []ServiceSummary {
+	summaries := map[string]*ServiceSummary{}
+	get := func(name string) *ServiceSummary {
+		summary, ok := summaries[name]
+		if !ok {
+			summary = &ServiceSummary{Name: name}
+			summaries[name] = summary
+		}
+		return summary
+	}
+	if project != nil {
+		for name, service := range project.Services {
+			desired := service.GetScale()
+			get(name).Desired = &desired
+		}
+	}
+
+	health := map[string][]string{}
+	for _, c := range containers {
+		summary := get(c.Service)
+		switch c.State {
+		case "running":
+			summary.Running++
+			health[c.Service] = append(health[c.Service], c.Health)
+		case "exited", "dead":
+			summary.ExitCodes = append(summary.ExitCodes, c.ExitCode)
+		}
+		for _, p := range c.Publishers {
+			if p.PublishedPort != 0 && !slices.Contains(summary.Publishers, p) {
+				summary.Publishers = append(summary.Publishers, p)
+			}
+		}
+	}
+
+	result := make([]ServiceSummary, 0, len(summaries))
+	for name, summary := range summaries {
+		summary.Health = aggregateHealth(health[name])
+		sort.Sort(summary.Publishers)
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// aggregateHealth returns the worst health status, empty if containers have no healthcheck
+func aggregateHealth(statuses []string) string {
+	aggregated := ""
+	for _, status := range statuses {
+		switch status {
+		case "unhealthy":
+			return status
+		case "starting":
+			aggregated = status
+		case "healthy":
+			if aggregated == "" {
+				aggregated = status
+			}
+		}
+	}
+	return aggregated
+}
+
 // PortPublishers is a slice of PortPublisher
 type PortPublishers []PortPublisher
 
@@ -509,17 +779,68 @@ func (p PortPublishers) Swap(i, j int) {
 type ContainerProcSummary struct {
 	ID        string
 	Name      string
+	Service   string
 	Processes [][]string
 	Titles    []string
 }
 
+// ContainerStats holds a container resource usage sample
+type ContainerStats struct {
+	ID      string
+	Name    string
+	Service string
+	// Number is the container index within the service scale
+	Number int
+	State  string
+	// Health is the healthcheck status, empty if the container has no healthcheck
+	Health       string
+	RestartCount int
+	// CPUUsage is the cumulative CPU time consumed by the container
+	CPUUsage    time.Duration
+	MemoryUsage uint64
+	MemoryLimit uint64
+}
+
 // ImageSummary holds container image description
 type ImageSummary struct {
 	ID            string
 	ContainerName string
 	Repository    string
 	Tag           string
-	Size          int64
+	// Digest is the registry digest of the image, if pulled or pushed
+	Digest string
+	Size   int64
+	// UpdateAvailable is set when the image tag resolves to another digest in the registry
+	UpdateAvailable bool
+	// SBOM is the SBOM attested for the image, as stored in the registry
+	SBOM json.RawMessage `json:",omitempty"`
+}
+
+// VolumeSummary holds project volume description
+type VolumeSummary struct {
+	Name       string
+	Volume     string
+	Driver     string
+	Mountpoint string
+	Scope      string
+	CreatedAt  string
+	Labels     map[string]string
+	Options    map[string]string
+	// Size and RefCount are reported by engine disk usage, -1 if not available
+	Size     int64
+	RefCount int64
+}
+
+// NetworkSummary holds project network description
+type NetworkSummary struct {
+	ID       string
+	Name     string
+	Network  string
+	Driver   string
+	Scope    string
+	Internal bool
+	Subnets  []string
+	Services []string
 }
 
 // ServiceStatus hold status about a service
@@ -550,6 +871,8 @@ type PauseOptions struct {
 	Services []string
 	// Project is the compose project used to define this app. Might be nil if user ran command just with project name
 	Project *types.Project
+	// Force pausing services other services depend on to be healthy
+	Force bool
 }
 
 const (
@@ -576,6 +899,27 @@ const (
 	RecreateNever = "never"
 )
 
+const (
+	// PlanCreate means a new container will be created
+	PlanCreate = "create"
+	// PlanRecreate means container will be replaced by a new one
+	PlanRecreate = "recreate"
+	// PlanStart means container is up-to-date but will be started
+	PlanStart = "start"
+	// PlanRemove means container will be removed
+	PlanRemove = "remove"
+	// PlanNoop means container is up-to-date and running
+	PlanNoop = "no-op"
+)
+
+// PlannedChange describes the action `up` will apply on a service container, and why
+type PlannedChange struct {
+	Service   string
+	Container string
+	Action    string
+	Reason    string
+}
+
 // Stack holds the name and state of a compose application/stack
 type Stack struct {
 	ID          string
@@ -585,6 +929,17 @@ type Stack struct {
 	Reason      string
 }
 
+// ProjectSummary holds the name, location and state of a compose project known to the engine
+type ProjectSummary struct {
+	Name        string
+	Status      string
+	ConfigFiles string
+	WorkingDir  string
+	Services    int
+	Containers  int
+	Running     int
+}
+
 // LogConsumer is a callback to process log messages from services
 type LogConsumer interface {
 	Log(containerName, message string)
@@ -632,6 +987,25 @@ const (
 // Separator is used for naming components
 var Separator = "-"
 
+// ContainerNamer defines the name of service containers, also set as DNS alias on service networks. Names must be
+// deterministic, so compose finds back the containers it created. Services declaring container_name are not affected
+type ContainerNamer interface {
+	ContainerName(projectName string, serviceName string, number int) string
+}
+
+// ContainerNamerFunc adapts a function as a ContainerNamer
+type ContainerNamerFunc func(projectName string, serviceName string, number int) string
+
+// ContainerName implements ContainerNamer
+func (f ContainerNamerFunc) ContainerName(projectName string, serviceName string, number int) string {
+	return f(projectName, serviceName, number)
+}
+
+// DefaultContainerNamer names containers as {project}-{service}-{number}, using Separator
+var DefaultContainerNamer ContainerNamer = ContainerNamerFunc(func(projectName string, serviceName string, number int) string {
+	return strings.Join([]string{projectName, serviceName, strconv.Itoa(number)}, Separator)
+})
+
 // GetImageNameOrDefault computes the default image name for a service, used to tag built images
 func GetImageNameOrDefault(service types.ServiceConfig, projectName string) string {
 	imageName := service.Image
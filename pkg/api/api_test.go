@@ -36,3 +36,27 @@ func TestRunOptionsEnvironmentMap(t *testing.T) {
 	assert.Equal(t, *env["ZOT"], "")
 	assert.Check(t, env["QIX"] == nil)
 }
+
+func TestSummarizeServices(t *testing.T) {
+	scale := 3
+	project := &types.Project{
+		Services: types.Services{
+			"web":    {Name: "web", Scale: &scale},
+			"worker": {Name: "worker"},
+		},
+	}
+	port := PortPublisher{URL: "0.0.0.0", TargetPort: 80, PublishedPort: 8080, Protocol: "tcp"}
+	summaries := SummarizeServices(project, []ContainerSummary{
+		{Service: "web", State: "running", Health: "healthy", Publishers: PortPublishers{port}},
+		{Service: "web", State: "running", Health: "starting", Publishers: PortPublishers{port}},
+		{Service: "web", State: "exited", ExitCode: 137},
+		{Service: "orphan", State: "running"},
+	})
+
+	desired, one := 3, 1
+	assert.DeepEqual(t, summaries, []ServiceSummary{
+		{Name: "orphan", Running: 1},
+		{Name: "web", Desired: &desired, Running: 2, Health: "starting", Publishers: PortPublishers{port}, ExitCodes: []int{137}},
+		{Name: "worker", Desired: &one},
+	})
+}
@@ -0,0 +1,64 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package api
+
+import (
+	"runtime/debug"
+
+	"github.com/docker/compose/v2/internal"
+)
+
+const composeGoModule = "github.com/compose-spec/compose-go/v2"
+
+// BuildInfo describes the running Compose build, so tooling can gate behavior on feature availability
+type BuildInfo struct {
+	// Version is the Compose version
+	Version string `json:"version"`
+	// Commit is the git commit Compose was built from
+	Commit string `json:"commit,omitempty"`
+	// ComposeSpecVersion is the version of compose-go, the Compose Specification implementation used to load models
+	ComposeSpecVersion string `json:"composeSpecVersion,omitempty"`
+	// Experimental lists the enabled experimental features
+	Experimental []string `json:"experimental"`
+}
+
+// GetBuildInfo returns the running Compose build information, with the given enabled experimental features
+func GetBuildInfo(experimental ...string) BuildInfo {
+	info := BuildInfo{
+		Version:      internal.Version,
+		Commit:       internal.Commit,
+		Experimental: append([]string{}, experimental...),
+	}
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, setting := range build.Settings {
+		if setting.Key == "vcs.revision" && info.Commit == "" {
+			info.Commit = setting.Value
+		}
+	}
+	for _, dep := range build.Deps {
+		if dep.Path == composeGoModule {
+			info.ComposeSpecVersion = dep.Version
+			if dep.Replace != nil {
+				info.ComposeSpecVersion = dep.Replace.Version
+			}
+		}
+	}
+	return info
+}
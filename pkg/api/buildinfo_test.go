@@ -0,0 +1,39 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/internal"
+)
+
+func TestGetBuildInfo(t *testing.T) {
+	info := GetBuildInfo("ComposeNav")
+	assert.Equal(t, info.Version, internal.Version)
+	assert.DeepEqual(t, info.Experimental, []string{"ComposeNav"})
+
+	b, err := json.Marshal(GetBuildInfo())
+	assert.NilError(t, err)
+	var decoded map[string]any
+	assert.NilError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, decoded["version"], internal.Version)
+	assert.DeepEqual(t, decoded["experimental"], []any{})
+}
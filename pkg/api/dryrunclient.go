@@ -184,6 +184,14 @@ func (d *DryRunClient) ContainerStop(ctx context.Context, container string, opti
 	return nil
 }
 
+func (d *DryRunClient) CheckpointCreate(ctx context.Context, container string, options checkpoint.CreateOptions) error {
+	return nil
+}
+
+func (d *DryRunClient) ContainerUpdate(ctx context.Context, container string, updateConfig containerType.UpdateConfig) (containerType.ContainerUpdateOKBody, error) {
+	return containerType.ContainerUpdateOKBody{}, nil
+}
+
 func (d *DryRunClient) ContainerUnpause(ctx context.Context, container string) error {
 	return nil
 }
@@ -395,10 +403,6 @@ func (d *DryRunClient) ContainerTop(ctx context.Context, container string, argum
 	return d.apiClient.ContainerTop(ctx, container, arguments)
 }
 
-func (d *DryRunClient) ContainerUpdate(ctx context.Context, container string, updateConfig containerType.UpdateConfig) (containerType.ContainerUpdateOKBody, error) {
-	return d.apiClient.ContainerUpdate(ctx, container, updateConfig)
-}
-
 func (d *DryRunClient) ContainerWait(ctx context.Context, container string, condition containerType.WaitCondition) (<-chan containerType.WaitResponse, <-chan error) {
 	return d.apiClient.ContainerWait(ctx, container, condition)
 }
@@ -528,7 +532,7 @@ func (d *DryRunClient) PluginCreate(ctx context.Context, createContext io.Reader
 }
 
 func (d *DryRunClient) ServiceCreate(ctx context.Context, service swarm.ServiceSpec, options moby.ServiceCreateOptions) (swarm.ServiceCreateResponse, error) {
-	return d.apiClient.ServiceCreate(ctx, service, options)
+	return swarm.ServiceCreateResponse{ID: service.Name}, nil
 }
 
 func (d *DryRunClient) ServiceInspectWithRaw(ctx context.Context, serviceID string, options moby.ServiceInspectOptions) (swarm.Service, []byte, error) {
@@ -540,11 +544,11 @@ func (d *DryRunClient) ServiceList(ctx context.Context, options moby.ServiceList
 }
 
 func (d *DryRunClient) ServiceRemove(ctx context.Context, serviceID string) error {
-	return d.apiClient.ServiceRemove(ctx, serviceID)
+	return nil
 }
 
 func (d *DryRunClient) ServiceUpdate(ctx context.Context, serviceID string, version swarm.Version, service swarm.ServiceSpec, options moby.ServiceUpdateOptions) (swarm.ServiceUpdateResponse, error) {
-	return d.apiClient.ServiceUpdate(ctx, serviceID, version, service, options)
+	return swarm.ServiceUpdateResponse{}, nil
 }
 
 func (d *DryRunClient) ServiceLogs(ctx context.Context, serviceID string, options containerType.LogsOptions) (io.ReadCloser, error) {
@@ -687,10 +691,6 @@ func (d *DryRunClient) Close() error {
 	return d.apiClient.Close()
 }
 
-func (d *DryRunClient) CheckpointCreate(ctx context.Context, container string, options checkpoint.CreateOptions) error {
-	return d.apiClient.CheckpointCreate(ctx, container, options)
-}
-
 func (d *DryRunClient) CheckpointDelete(ctx context.Context, container string, options checkpoint.DeleteOptions) error {
 	return d.apiClient.CheckpointDelete(ctx, container, options)
 }
@@ -18,6 +18,8 @@ package api
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 )
 
 const (
@@ -46,6 +48,8 @@ var (
 	ErrUnsupportedFlag = errors.New("unsupported flag")
 	// ErrCanceled is returned when the command was canceled by user
 	ErrCanceled = errors.New("canceled")
+	// ErrRolledBack is returned when the command was canceled by user, and resources it created have been removed
+	ErrRolledBack = fmt.Errorf("%w, created resources have been rolled back", ErrCanceled)
 	// ErrParsingFailed is returned when a string cannot be parsed
 	ErrParsingFailed = errors.New("parsing failed")
 	// ErrWrongContextType is returned when the caller tries to get a context
@@ -92,3 +96,111 @@ func IsErrParsingFailed(err error) bool {
 func IsErrCanceled(err error) bool {
 	return errors.Is(err, ErrCanceled)
 }
+
+// MissingNetworksError is returned when networks declared as external can't be found
+type MissingNetworksError struct {
+	Networks []string
+}
+
+func (e MissingNetworksError) Error() string {
+	return fmt.Sprintf("networks declared as external, but could not be found: %s", strings.Join(e.Networks, ", "))
+}
+
+// Is makes MissingNetworksError match ErrNotFound
+func (e MissingNetworksError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// InvalidProjectNameError is returned when a project name doesn't match the normalized form compose expects
+type InvalidProjectNameError struct {
+	Name string
+	// Source tells where the invalid name was set
+	Source string
+}
+
+func (e InvalidProjectNameError) Error() string {
+	return fmt.Sprintf("invalid project name %q set by %s: must consist only of lowercase alphanumeric characters, "+
+		"hyphens, and underscores as well as start with a letter or number", e.Name, e.Source)
+}
+
+// InvalidServiceReferenceError is returned when a service shares a namespace, as set by `network_mode: service:db`,
+// with a service which isn't defined or doesn't run a single container
+type InvalidServiceReferenceError struct {
+	Service string
+	// Attribute is the service attribute set with the reference, as network_mode
+	Attribute string
+	Target    string
+	// Scale is the number of containers target service runs, 0 if it isn't defined
+	Scale int
+}
+
+func (e InvalidServiceReferenceError) Error() string {
+	if e.Scale == 0 {
+		return fmt.Sprintf("service %q %s refers to undefined service %q", e.Service, e.Attribute, e.Target)
+	}
+	return fmt.Sprintf("service %q %s refers to service %q which is scaled to %d containers, "+
+		"a single one is required to share its namespace", e.Service, e.Attribute, e.Target, e.Scale)
+}
+
+// Is makes InvalidServiceReferenceError for an undefined service match ErrNotFound
+func (e InvalidServiceReferenceError) Is(target error) bool {
+	return e.Scale == 0 && target == ErrNotFound
+}
+
+// DisabledServiceError is returned when a service disabled by its `x-enabled` extension is selected, or is required
+// by an enabled service
+type DisabledServiceError struct {
+	Service string
+	// ReferencedBy is the enabled service which refers to the disabled one, if any
+	ReferencedBy string
+	// Attribute is the attribute ReferencedBy refers to the disabled service with, as depends_on
+	Attribute string
+}
+
+func (e DisabledServiceError) Error() string {
+	if e.ReferencedBy == "" {
+		return fmt.Sprintf("service %q is disabled by x-enabled", e.Service)
+	}
+	return fmt.Sprintf("service %q %s refers to service %q which is disabled by x-enabled", e.ReferencedBy, e.Attribute, e.Service)
+}
+
+// InvalidAttributeError is returned when a service attribute has a value the Docker Engine would reject
+type InvalidAttributeError struct {
+	Service string
+	// Attribute is the invalid service attribute, as ulimits.nofile
+	Attribute string
+	Value     string
+	Reason    string
+}
+
+func (e InvalidAttributeError) Error() string {
+	return fmt.Sprintf("service %q: invalid %s %q: %s", e.Service, e.Attribute, e.Value, e.Reason)
+}
+
+// RegistryAuthError is returned when a registry rejects the credentials used to pull or push a service image, or
+// credentials for the registry can't be retrieved
+type RegistryAuthError struct {
+	Service  string
+	Registry string
+	// Helper is the credential helper configured for the registry, if any
+	Helper string
+	Err    error
+}
+
+func (e RegistryAuthError) Error() string {
+	if e.Helper != "" {
+		return fmt.Sprintf("service %q: authentication to registry %s failed using credential helper docker-credential-%s: %v",
+			e.Service, e.Registry, e.Helper, e.Err)
+	}
+	return fmt.Sprintf("service %q: authentication to registry %s failed, run `docker login %s`: %v",
+		e.Service, e.Registry, e.Registry, e.Err)
+}
+
+func (e RegistryAuthError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes RegistryAuthError match ErrLoginRequired
+func (e RegistryAuthError) Is(target error) bool {
+	return target == ErrLoginRequired
+}
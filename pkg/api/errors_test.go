@@ -51,3 +51,13 @@ func TestIsUnknown(t *testing.T) {
 
 	assert.Assert(t, !IsUnknownError(errors.New("another error")))
 }
+
+func TestMissingNetworksError(t *testing.T) {
+	var err error = MissingNetworksError{Networks: []string{"front", "back"}}
+	assert.Assert(t, IsNotFoundError(err))
+	assert.Error(t, err, "networks declared as external, but could not be found: front, back")
+
+	var missing MissingNetworksError
+	assert.Assert(t, errors.As(fmt.Errorf("up: %w", err), &missing))
+	assert.DeepEqual(t, missing.Networks, []string{"front", "back"})
+}
@@ -57,6 +57,8 @@ const (
 	ImageBuilderLabel = "com.docker.compose.image.builder"
 	// ContainerReplaceLabel is set when container is created to replace another container (recreated)
 	ContainerReplaceLabel = "com.docker.compose.replace"
+	// InitContainerLabel stores the service an init container, declared by `x-init`, runs for
+	InitContainerLabel = "com.docker.compose.init-container"
 )
 
 // ComposeVersion is the compose tool version as declared by label VersionLabel
@@ -0,0 +1,252 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package api
+
+import (
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+)
+
+// Stability tells if an option is part of the stable API, or may change or be removed in a future release
+type Stability string
+
+const (
+	// StabilityStable options are kept backward compatible
+	StabilityStable Stability = "stable"
+	// StabilityExperimental options may change or be removed without notice
+	StabilityExperimental Stability = "experimental"
+)
+
+// UpOption sets an option of the Up API. Embedders should build UpOptions with NewUpOptions rather than set fields
+// of the struct, so they don't break when fields are added
+type UpOption struct {
+	name      string
+	stability Stability
+	apply     func(*UpOptions)
+}
+
+// Name returns the name of the func which created the option, as UpWithRecreate
+func (o UpOption) Name() string {
+	return o.name
+}
+
+// Stability returns the stability of the option
+func (o UpOption) Stability() Stability {
+	return o.stability
+}
+
+// NewUpOptions returns UpOptions with the defaults used by `docker compose up`, then set by opts
+func NewUpOptions(opts ...UpOption) UpOptions {
+	options := UpOptions{
+		Create: CreateOptions{
+			Recreate:             RecreateDiverged,
+			RecreateDependencies: RecreateDiverged,
+			Inherit:              true,
+		},
+	}
+	for _, o := range opts {
+		o.apply(&options)
+	}
+	return options
+}
+
+// UpWithServices restricts Up to services and their dependencies
+func UpWithServices(services ...string) UpOption {
+	return UpOption{"UpWithServices", StabilityStable, func(o *UpOptions) {
+		o.Create.Services = services
+		o.Start.Services = services
+	}}
+}
+
+// UpWithRecreate sets the strategy to apply on existing containers, among RecreateDiverged, RecreateForce and
+// RecreateNever, and on containers of dependency services
+func UpWithRecreate(strategy string, dependencies string) UpOption {
+	return UpOption{"UpWithRecreate", StabilityStable, func(o *UpOptions) {
+		o.Create.Recreate = strategy
+		o.Create.RecreateDependencies = dependencies
+	}}
+}
+
+// UpWithBuild builds images before creating containers
+func UpWithBuild(build BuildOptions) UpOption {
+	return UpOption{"UpWithBuild", StabilityStable, func(o *UpOptions) {
+		o.Create.Build = &build
+	}}
+}
+
+// UpWithRemoveOrphans removes containers for services which are not declared by the project
+func UpWithRemoveOrphans() UpOption {
+	return UpOption{"UpWithRemoveOrphans", StabilityStable, func(o *UpOptions) {
+		o.Create.RemoveOrphans = true
+	}}
+}
+
+// UpWithRenewAnonymousVolumes creates new anonymous volumes rather than reuse the ones of previous containers
+func UpWithRenewAnonymousVolumes() UpOption {
+	return UpOption{"UpWithRenewAnonymousVolumes", StabilityStable, func(o *UpOptions) {
+		o.Create.Inherit = false
+	}}
+}
+
+// UpWithTimeout sets the delay to wait for containers to stop before they get killed, when recreated
+func UpWithTimeout(timeout time.Duration) UpOption {
+	return UpOption{"UpWithTimeout", StabilityStable, func(o *UpOptions) {
+		o.Create.Timeout = &timeout
+	}}
+}
+
+// UpWithQuietPull pulls images without reporting progress
+func UpWithQuietPull() UpOption {
+	return UpOption{"UpWithQuietPull", StabilityStable, func(o *UpOptions) {
+		o.Create.QuietPull = true
+	}}
+}
+
+// UpWithWait waits for services to be running or healthy, up to timeout if not zero
+func UpWithWait(timeout time.Duration) UpOption {
+	return UpOption{"UpWithWait", StabilityStable, func(o *UpOptions) {
+		o.Start.Wait = true
+		o.Start.WaitTimeout = timeout
+	}}
+}
+
+// UpWithAttach forwards logs of services, or all services if none is set, to consumer, so Up only returns once
+// containers exit, with onExit behavior when one of them stops
+func UpWithAttach(consumer LogConsumer, onExit Cascade, services ...string) UpOption {
+	return UpOption{"UpWithAttach", StabilityStable, func(o *UpOptions) {
+		o.Start.Attach = consumer
+		o.Start.AttachTo = services
+		o.Start.OnExit = onExit
+	}}
+}
+
+// UpWithProject sets the project Start uses, rather than the one computed from existing containers
+func UpWithProject(project *types.Project) UpOption {
+	return UpOption{"UpWithProject", StabilityStable, func(o *UpOptions) {
+		o.Start.Project = project
+	}}
+}
+
+// UpWithAdoption renames containers created with a distinct naming scheme, and with orphans moves containers of
+// another project running the same services into this one
+func UpWithAdoption(orphans bool) UpOption {
+	return UpOption{"UpWithAdoption", StabilityExperimental, func(o *UpOptions) {
+		o.Create.Adopt = true
+		o.Create.AdoptOrphans = orphans
+	}}
+}
+
+// UpWithRollbackOnCancel removes the resources created by Up if ctx is canceled
+func UpWithRollbackOnCancel() UpOption {
+	return UpOption{"UpWithRollbackOnCancel", StabilityExperimental, func(o *UpOptions) {
+		o.Create.RollbackOnCancel = true
+	}}
+}
+
+// UpWithHealthCheck overrides the attributes healthcheck sets in all services' healthchecks
+func UpWithHealthCheck(healthcheck types.HealthCheckConfig) UpOption {
+	return UpOption{"UpWithHealthCheck", StabilityExperimental, func(o *UpOptions) {
+		o.Create.HealthCheck = &healthcheck
+	}}
+}
+
+// DownOption sets an option of the Down API. Embedders should build DownOptions with NewDownOptions rather than set
+// fields of the struct, so they don't break when fields are added
+type DownOption struct {
+	name      string
+	stability Stability
+	apply     func(*DownOptions)
+}
+
+// Name returns the name of the func which created the option, as DownWithVolumes
+func (o DownOption) Name() string {
+	return o.name
+}
+
+// Stability returns the stability of the option
+func (o DownOption) Stability() Stability {
+	return o.stability
+}
+
+// NewDownOptions returns DownOptions with the defaults used by `docker compose down`, then set by opts
+func NewDownOptions(opts ...DownOption) DownOptions {
+	var options DownOptions
+	for _, o := range opts {
+		o.apply(&options)
+	}
+	return options
+}
+
+// DownWithProject sets the project to remove the resources of, rather than the one computed from existing containers
+func DownWithProject(project *types.Project) DownOption {
+	return DownOption{"DownWithProject", StabilityStable, func(o *DownOptions) {
+		o.Project = project
+	}}
+}
+
+// DownWithServices restricts Down to the containers of services
+func DownWithServices(services ...string) DownOption {
+	return DownOption{"DownWithServices", StabilityStable, func(o *DownOptions) {
+		o.Services = services
+	}}
+}
+
+// DownWithVolumes removes named volumes declared by the project and anonymous volumes attached to containers
+func DownWithVolumes() DownOption {
+	return DownOption{"DownWithVolumes", StabilityStable, func(o *DownOptions) {
+		o.Volumes = true
+	}}
+}
+
+// DownWithImages removes images used by services, "all" of them or only "local" ones which don't have a custom tag
+func DownWithImages(images string) DownOption {
+	return DownOption{"DownWithImages", StabilityStable, func(o *DownOptions) {
+		o.Images = images
+	}}
+}
+
+// DownWithRemoveOrphans removes containers for services which are not declared by the project, without asking for
+// confirmation
+func DownWithRemoveOrphans() DownOption {
+	return DownOption{"DownWithRemoveOrphans", StabilityStable, func(o *DownOptions) {
+		o.RemoveOrphans = true
+		o.AssumeYes = true
+	}}
+}
+
+// DownWithTimeout sets the delay to wait for containers to stop before they get killed
+func DownWithTimeout(timeout time.Duration) DownOption {
+	return DownOption{"DownWithTimeout", StabilityStable, func(o *DownOptions) {
+		o.Timeout = &timeout
+	}}
+}
+
+// DownWithResources restricts the kinds of resources to remove, among ResourceContainer, ResourceNetwork,
+// ResourceVolume and ResourceImage
+func DownWithResources(resources ...string) DownOption {
+	return DownOption{"DownWithResources", StabilityExperimental, func(o *DownOptions) {
+		o.Resources = resources
+	}}
+}
+
+// DownWithRemovedReporter sets the func called with each resource actually removed
+func DownWithRemovedReporter(fn func(resource RemovedResource)) DownOption {
+	return DownOption{"DownWithRemovedReporter", StabilityExperimental, func(o *DownOptions) {
+		o.Removed = fn
+	}}
+}
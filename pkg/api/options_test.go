@@ -0,0 +1,71 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestNewUpOptions(t *testing.T) {
+	assert.DeepEqual(t, NewUpOptions(), UpOptions{
+		Create: CreateOptions{Recreate: RecreateDiverged, RecreateDependencies: RecreateDiverged, Inherit: true},
+	})
+
+	timeout := 5 * time.Second
+	options := NewUpOptions(
+		UpWithServices("web"),
+		UpWithRecreate(RecreateForce, RecreateNever),
+		UpWithRenewAnonymousVolumes(),
+		UpWithTimeout(timeout),
+		UpWithWait(time.Minute),
+	)
+	assert.DeepEqual(t, options, UpOptions{
+		Create: CreateOptions{
+			Services:             []string{"web"},
+			Recreate:             RecreateForce,
+			RecreateDependencies: RecreateNever,
+			Timeout:              &timeout,
+		},
+		Start: StartOptions{
+			Services:    []string{"web"},
+			Wait:        true,
+			WaitTimeout: time.Minute,
+		},
+	})
+}
+
+func TestNewDownOptions(t *testing.T) {
+	options := NewDownOptions(DownWithVolumes(), DownWithImages("local"), DownWithServices("web", "db"), DownWithRemoveOrphans())
+	assert.DeepEqual(t, options, DownOptions{
+		Volumes:       true,
+		Images:        "local",
+		Services:      []string{"web", "db"},
+		RemoveOrphans: true,
+		AssumeYes:     true,
+	})
+}
+
+func TestOptionStability(t *testing.T) {
+	assert.Equal(t, UpWithRecreate(RecreateForce, RecreateForce).Name(), "UpWithRecreate")
+	assert.Equal(t, UpWithRecreate(RecreateForce, RecreateForce).Stability(), StabilityStable)
+	assert.Equal(t, UpWithRollbackOnCancel().Stability(), StabilityExperimental)
+	assert.Equal(t, DownWithVolumes().Stability(), StabilityStable)
+	assert.Equal(t, DownWithResources(ResourceContainer).Stability(), StabilityExperimental)
+}
@@ -0,0 +1,139 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WarningCode identifies the kind of condition a Warning reports, so that embedders can filter or translate them
+type WarningCode string
+
+const (
+	// WarningIgnoredAttribute reports a compose model attribute the backend doesn't apply
+	WarningIgnoredAttribute WarningCode = "ignored-attribute"
+	// WarningUnsupportedOption reports an option which is not supported in the context it is used and is ignored
+	WarningUnsupportedOption WarningCode = "unsupported-option"
+	// WarningOrphanContainers reports containers of the project for services which are not declared anymore
+	WarningOrphanContainers WarningCode = "orphan-containers"
+	// WarningUnmanagedResource reports an existing network or volume which was not created by compose for the project
+	WarningUnmanagedResource WarningCode = "unmanaged-resource"
+	// WarningDependency reports a service dependency which is missing, or optional and not satisfied
+	WarningDependency WarningCode = "dependency"
+	// WarningInvalidLabel reports a container with a missing or invalid compose label
+	WarningInvalidLabel WarningCode = "invalid-label"
+	// WarningEmulation reports a service which platform doesn't match the Docker Engine one, and runs with emulation
+	WarningEmulation WarningCode = "emulation"
+	// WarningHook reports a project hook which failed to run
+	WarningHook WarningCode = "hook"
+	// WarningPublishedFile reports a local file which is or isn't included in a published application
+	WarningPublishedFile WarningCode = "published-file"
+	// WarningLoad reports a condition found while loading the compose model, as an obsolete attribute
+	WarningLoad WarningCode = "load"
+	// WarningDeprecated reports a deprecated attribute, flag or command
+	WarningDeprecated WarningCode = "deprecated"
+	// WarningResources reports services which require more resources than the Docker Engine has
+	WarningResources WarningCode = "resources"
+	// WarningResourceInUse reports a resource used by a container while compose operates on it
+	WarningResourceInUse WarningCode = "resource-in-use"
+	// WarningBuildContext reports a build context larger than the size users are warned about
+	WarningBuildContext WarningCode = "build-context"
+	// WarningEngine reports a warning returned by the Docker Engine
+	WarningEngine WarningCode = "engine"
+	// WarningNoResource reports an operation which found no resource to apply to, as down for a project not running
+	WarningNoResource WarningCode = "no-resource"
+	// WarningOperationFailed reports a failure which doesn't abort the operation, as retrieving logs from a container
+	// with a logging driver which doesn't support reading
+	WarningOperationFailed WarningCode = "operation-failed"
+)
+
+// Warning reports a condition which doesn't prevent an operation to complete, but may not produce the expected result
+type Warning struct {
+	Code WarningCode `json:"code"`
+	// Service is the service the warning relates to, if any
+	Service string `json:"service,omitempty"`
+	// Attribute is the compose model attribute the warning relates to, as deploy.placement.constraints
+	Attribute string `json:"attribute,omitempty"`
+	Message   string `json:"message"`
+}
+
+func (w Warning) String() string {
+	message := w.Message
+	if w.Attribute != "" {
+		message = fmt.Sprintf("%s %s", w.Attribute, message)
+	}
+	if w.Service != "" {
+		message = fmt.Sprintf("service %q: %s", w.Service, message)
+	}
+	return message
+}
+
+// WarningCollector collects warnings reported while running an operation
+type WarningCollector struct {
+	mu       sync.Mutex
+	warnings []Warning
+}
+
+// Warnings returns the warnings collected so far, in the order they were reported
+func (c *WarningCollector) Warnings() []Warning {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Warning(nil), c.warnings...)
+}
+
+// Drain returns the warnings collected so far and clears them, so a long-running operation can report warnings as
+// they come
+func (c *WarningCollector) Drain() []Warning {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	warnings := c.warnings
+	c.warnings = nil
+	return warnings
+}
+
+func (c *WarningCollector) add(w Warning) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.warnings = append(c.warnings, w)
+}
+
+type warningCollectorKey struct{}
+
+// WithWarningCollector returns a context which makes operations report warnings to collector, rather than print them
+// on the standard error stream
+func WithWarningCollector(ctx context.Context, collector *WarningCollector) context.Context {
+	return context.WithValue(ctx, warningCollectorKey{}, collector)
+}
+
+// WarningCollectorFrom returns the collector set by ctx, or nil if warnings are logged
+func WarningCollectorFrom(ctx context.Context) *WarningCollector {
+	collector, _ := ctx.Value(warningCollectorKey{}).(*WarningCollector)
+	return collector
+}
+
+// Warn reports a warning to the collector set by ctx, or logs it if none is set
+func Warn(ctx context.Context, w Warning) {
+	if collector := WarningCollectorFrom(ctx); collector != nil {
+		collector.add(w)
+		return
+	}
+	logrus.Warn(w.String())
+}
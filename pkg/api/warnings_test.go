@@ -0,0 +1,63 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestWarningString(t *testing.T) {
+	assert.Equal(t, Warning{Service: "web", Attribute: "depends_on", Message: "is ignored"}.String(), `service "web": depends_on is ignored`)
+	assert.Equal(t, Warning{Service: "web", Message: "is missing dependency db"}.String(), `service "web": is missing dependency db`)
+	assert.Equal(t, Warning{Message: "found orphan containers"}.String(), "found orphan containers")
+}
+
+func TestWarnCollector(t *testing.T) {
+	collector := &WarningCollector{}
+	ctx := WithWarningCollector(context.Background(), collector)
+	Warn(ctx, Warning{Code: WarningOrphanContainers, Message: "first"})
+	Warn(ctx, Warning{Code: WarningDependency, Service: "web", Message: "second"})
+	assert.DeepEqual(t, collector.Warnings(), []Warning{
+		{Code: WarningOrphanContainers, Message: "first"},
+		{Code: WarningDependency, Service: "web", Message: "second"},
+	})
+}
+
+func TestWarnWithoutCollector(t *testing.T) {
+	var out bytes.Buffer
+	previous := logrus.StandardLogger().Out
+	logrus.SetOutput(&out)
+	defer logrus.SetOutput(previous)
+	Warn(context.Background(), Warning{Code: WarningDependency, Service: "web", Message: "is missing dependency db"})
+	assert.Check(t, is.Contains(out.String(), `service \"web\": is missing dependency db`))
+}
+
+func TestWarnCollectorDrain(t *testing.T) {
+	collector := &WarningCollector{}
+	ctx := WithWarningCollector(context.Background(), collector)
+	Warn(ctx, Warning{Code: WarningOrphanContainers, Message: "first"})
+	assert.DeepEqual(t, collector.Drain(), []Warning{{Code: WarningOrphanContainers, Message: "first"}})
+	Warn(ctx, Warning{Code: WarningDependency, Message: "second"})
+	assert.DeepEqual(t, collector.Drain(), []Warning{{Code: WarningDependency, Message: "second"}})
+	assert.Check(t, is.Len(collector.Drain(), 0))
+}
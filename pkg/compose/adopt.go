@@ -0,0 +1,311 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
+	moby "github.com/docker/docker/api/types"
+	containerType "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/errdefs"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v2/pkg/utils"
+)
+
+// expectedContainerName returns the name container is expected to have according to the naming scheme in use,
+// or an empty string if container doesn't match a project service
+func expectedContainerName(namer api.ContainerNamer, project *types.Project, c moby.Container) string {
+	service, err := project.GetService(c.Labels[api.ServiceLabel])
+	if err != nil {
+		return ""
+	}
+	number, err := strconv.Atoi(c.Labels[api.ContainerNumberLabel])
+	if err != nil {
+		return ""
+	}
+	return getContainerName(namer, project.Name, service, number)
+}
+
+// staleContainers selects service containers which name doesn't match the naming scheme currently in use,
+// typically created by a previous version of compose or with a distinct --compatibility setting
+func staleContainers(namer api.ContainerNamer, project *types.Project, containers Containers) Containers {
+	return containers.filter(func(c moby.Container) bool {
+		if !isNotOneOff(c) {
+			return false
+		}
+		expected := expectedContainerName(namer, project, c)
+		return expected != "" && getCanonicalContainerName(c) != expected
+	})
+}
+
+// handleStaleContainers reconciles containers left by a previous run which don't match current naming scheme.
+// Containers compose can't manage (missing config hash) and which would conflict with a container to be created
+// are replaced if adoption or recreation is forced, and reported otherwise, as compose didn't create them. Others get
+// adopted (i.e. renamed) if requested, or reported
+func (s *composeService) handleStaleContainers(ctx context.Context, project *types.Project, observed Containers, adopt bool, force bool) error {
+	untracked, err := s.apiClient().ContainerList(ctx, containerType.ListOptions{
+		Filters: filters.NewArgs(projectFilter(project.Name), oneOffFilter(false)),
+		All:     true,
+	})
+	if err != nil {
+		return err
+	}
+
+	expectedNames := map[string]struct{}{}
+	for _, service := range project.Services {
+		for i := 1; i <= service.GetScale(); i++ {
+			expectedNames[getContainerName(s.containerNamer(), project.Name, service, i)] = struct{}{}
+		}
+	}
+
+	w := progress.ContextWriter(ctx)
+	for _, c := range untracked {
+		if _, tracked := c.Labels[api.ConfigHashLabel]; tracked {
+			continue
+		}
+		if _, conflict := expectedNames[getCanonicalContainerName(c)]; !conflict {
+			continue
+		}
+		if !adopt && !force {
+			api.Warn(ctx, api.Warning{
+				Code:    api.WarningUnmanagedResource,
+				Service: c.Labels[api.ServiceLabel],
+				Message: fmt.Sprintf("container %s was not created by compose and uses the name of a service container. "+
+					"You can run this command with the --adopt or --force-recreate flag to replace it", getCanonicalContainerName(c)),
+			})
+			continue
+		}
+		w.Event(progress.NewEvent(getContainerProgressName(c), progress.Working, "Replacing stale container"))
+		if err := s.stopAndRemoveContainer(ctx, c, nil, false); err != nil {
+			return err
+		}
+	}
+
+	stale := staleContainers(s.containerNamer(), project, observed)
+	if len(stale) == 0 {
+		return nil
+	}
+	if !adopt {
+		api.Warn(ctx, api.Warning{
+			Code: api.WarningUnmanagedResource,
+			Message: fmt.Sprintf("Found containers (%s) which names don't match the current naming scheme. "+
+				"You can run this command with the --adopt flag to rename them, "+
+				"or use --compatibility if they were created by docker-compose v1.", stale.names()),
+		})
+		return nil
+	}
+	for i, c := range observed {
+		expected := expectedContainerName(s.containerNamer(), project, c)
+		if expected == "" || getCanonicalContainerName(c) == expected || !isNotOneOff(c) {
+			continue
+		}
+		eventName := getContainerProgressName(c)
+		w.Event(progress.NewEvent(eventName, progress.Working, "Adopting"))
+		if err := s.apiClient().ContainerRename(ctx, c.ID, expected); err != nil {
+			w.Event(progress.ErrorMessageEvent(eventName, "Error while Adopting"))
+			return err
+		}
+		observed[i].Names = []string{"/" + expected}
+		w.Event(progress.NewEvent(eventName, progress.Done, "Adopted as "+expected))
+	}
+	return nil
+}
+
+// selectAdoptableOrphans selects, for the services without containers in project, the containers of another project
+// running a service with the same name and image. imageIDs are the IDs of the service images available locally.
+// Services which match containers from distinct projects are ambiguous, and reported as warnings
+func selectAdoptableOrphans(project *types.Project, services []string, observed Containers, candidates Containers,
+	imageIDs map[string]string) (map[string]Containers, []string) {
+	adoptable := map[string]Containers{}
+	var warnings []string
+	for _, name := range services {
+		service, ok := project.Services[name]
+		if !ok || len(observed.filter(isService(name))) > 0 {
+			continue
+		}
+		image := api.GetImageNameOrDefault(service, project.Name)
+		byProject := map[string]Containers{}
+		for _, c := range candidates {
+			p := c.Labels[api.ProjectLabel]
+			if p == "" || p == project.Name || c.Labels[api.ServiceLabel] != name || !isNotOneOff(c) {
+				continue
+			}
+			if c.Image != image && (imageIDs[image] == "" || c.ImageID != imageIDs[image]) {
+				continue
+			}
+			byProject[p] = append(byProject[p], c)
+		}
+		switch len(byProject) {
+		case 0:
+		case 1:
+			for _, containers := range byProject {
+				adoptable[name] = containers
+			}
+		default:
+			var projects []string
+			for p := range byProject {
+				projects = append(projects, p)
+			}
+			sort.Strings(projects)
+			warnings = append(warnings, fmt.Sprintf("service %q matches containers from projects %v, none is adopted", name, projects))
+		}
+	}
+	return adoptable, warnings
+}
+
+// adoptedVolumes maps the project volumes which don't exist yet to the named volumes the adoptable containers mount at
+// the same location
+func adoptedVolumes(project *types.Project, adoptable map[string]Containers, exists func(name string) bool) map[string]string {
+	volumes := map[string]string{}
+	for name, containers := range adoptable {
+		service := project.Services[name]
+		for _, v := range service.Volumes {
+			if v.Type != types.VolumeTypeVolume || v.Source == "" {
+				continue
+			}
+			volume, ok := project.Volumes[v.Source]
+			if !ok || bool(volume.External) || volumes[v.Source] != "" || exists(volume.Name) {
+				continue
+			}
+			for _, c := range containers {
+				for _, m := range c.Mounts {
+					if m.Type == mount.TypeVolume && m.Destination == v.Target && m.Name != "" && m.Name != volume.Name {
+						volumes[v.Source] = m.Name
+					}
+				}
+			}
+		}
+	}
+	return volumes
+}
+
+// prepareOrphansAdoption looks for containers of another project to be adopted, and makes project volumes use the
+// volumes those containers mount
+func (s *composeService) prepareOrphansAdoption(ctx context.Context, project *types.Project, services []string,
+	observed Containers) (map[string]Containers, error) {
+	candidates, err := s.apiClient().ContainerList(ctx, containerType.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", api.ServiceLabel), oneOffFilter(false)),
+		All:     true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	imageIDs := map[string]string{}
+	for _, name := range services {
+		if service, ok := project.Services[name]; ok {
+			image := api.GetImageNameOrDefault(service, project.Name)
+			if inspect, _, err := s.apiClient().ImageInspectWithRaw(ctx, image); err == nil {
+				imageIDs[image] = inspect.ID
+			}
+		}
+	}
+	adoptable, warnings := selectAdoptableOrphans(project, services, observed, candidates, imageIDs)
+	for _, warning := range warnings {
+		api.Warn(ctx, api.Warning{Code: api.WarningOrphanContainers, Message: warning})
+	}
+
+	var inspectErr error
+	volumes := adoptedVolumes(project, adoptable, func(name string) bool {
+		_, err := s.apiClient().VolumeInspect(ctx, name)
+		if err != nil && !errdefs.IsNotFound(err) {
+			inspectErr = err
+		}
+		return err == nil
+	})
+	if inspectErr != nil {
+		return nil, inspectErr
+	}
+	for key, name := range volumes {
+		// volume was created for another project, manage it as an external one so it isn't removed by `down`
+		volume := project.Volumes[key]
+		logrus.Infof("Volume %q is adopted as %q", name, key)
+		volume.Name = name
+		volume.External = true
+		project.Volumes[key] = volume
+	}
+	return adoptable, nil
+}
+
+// adoptOrphans moves containers of another project into project. As the Docker Engine doesn't allow changing the
+// labels of a container, those are recreated, reusing their volumes
+func (s *composeService) adoptOrphans(ctx context.Context, project *types.Project, adoptable map[string]Containers,
+	timeout *time.Duration) (Containers, error) {
+	var adopted Containers
+	names := make([]string, 0, len(adoptable))
+	for name := range adoptable {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, c := range adoptable[name] {
+			created, err := s.adoptContainer(ctx, project, project.Services[name], c, timeout)
+			if err != nil {
+				return adopted, err
+			}
+			adopted = append(adopted, created)
+		}
+	}
+	return adopted, nil
+}
+
+func (s *composeService) adoptContainer(ctx context.Context, project *types.Project, service types.ServiceConfig,
+	orphan moby.Container, timeout *time.Duration) (moby.Container, error) {
+	w := progress.ContextWriter(ctx)
+	eventName := getContainerProgressName(orphan)
+	w.Event(progress.NewEvent(eventName, progress.Working, "Adopting"))
+
+	number, err := strconv.Atoi(orphan.Labels[api.ContainerNumberLabel])
+	if err != nil {
+		return moby.Container{}, fmt.Errorf("container %s has an invalid %s label: %w", getCanonicalContainerName(orphan), api.ContainerNumberLabel, err)
+	}
+	name := getContainerName(s.containerNamer(), project.Name, service, number)
+	tmpName := fmt.Sprintf("%s_%s", orphan.ID[:12], name)
+	created, err := s.createMobyContainer(ctx, project, service, tmpName, number, &orphan, createOptions{
+		UseNetworkAliases: true,
+		Labels:            mergeLabels(service.Labels, service.CustomLabels).Add(api.ContainerReplaceLabel, orphan.ID),
+	}, w)
+	if err != nil {
+		w.Event(progress.ErrorMessageEvent(eventName, "Error while Adopting"))
+		return created, err
+	}
+	err = s.apiClient().ContainerStop(ctx, orphan.ID, containerType.StopOptions{Timeout: utils.DurationSecondToInt(timeout)})
+	if err != nil {
+		return created, err
+	}
+	err = s.apiClient().ContainerRemove(ctx, orphan.ID, containerType.RemoveOptions{})
+	if err != nil {
+		return created, err
+	}
+	err = s.apiClient().ContainerRename(ctx, created.ID, name)
+	if err != nil {
+		return created, err
+	}
+	created.Names = []string{"/" + name}
+	created.State = ContainerCreated
+	w.Event(progress.NewEvent(eventName, progress.Done, "Adopted as "+name))
+	return created, nil
+}
@@ -0,0 +1,134 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/compose/v2/pkg/api"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/mount"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestStaleContainers(t *testing.T) {
+	project := &types.Project{
+		Name: "myapp",
+		Services: types.Services{
+			"web": {Name: "web"},
+		},
+	}
+	container := func(name string, number string, oneOff bool) moby.Container {
+		labels := map[string]string{
+			api.ServiceLabel:         "web",
+			api.ContainerNumberLabel: number,
+		}
+		if oneOff {
+			labels[api.OneoffLabel] = "True"
+		}
+		return moby.Container{ID: name, Names: []string{"/" + name}, Labels: labels}
+	}
+
+	stale := staleContainers(api.DefaultContainerNamer, project, Containers{
+		container("myapp-web-1", "1", false),
+		container("myapp_web_2", "2", false),
+		container("myapp_web_run_1", "1", true),
+	})
+	assert.DeepEqual(t, stale.names(), []string{"myapp_web_2"})
+}
+
+func TestHandleStaleContainersUnmanaged(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	apiClient, cli := prepareMocks(mockCtrl)
+	tested := composeService{dockerCli: cli}
+	project := &types.Project{
+		Name:     "myapp",
+		Services: types.Services{"web": {Name: "web"}},
+	}
+	// container was not created by compose, so it's reported but not removed
+	apiClient.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return([]moby.Container{
+		{ID: "123", Names: []string{"/myapp-web-1"}, Labels: map[string]string{api.ServiceLabel: "web"}},
+	}, nil)
+
+	collector := &api.WarningCollector{}
+	ctx := api.WithWarningCollector(context.Background(), collector)
+	err := tested.handleStaleContainers(ctx, project, nil, false, false)
+	assert.NilError(t, err)
+	warnings := collector.Warnings()
+	assert.Assert(t, is.Len(warnings, 1))
+	assert.Check(t, is.Contains(warnings[0].Message, "--adopt or --force-recreate"))
+}
+
+func TestSelectAdoptableOrphans(t *testing.T) {
+	project := &types.Project{
+		Name: "newapp",
+		Services: types.Services{
+			"web":   {Name: "web", Image: "nginx"},
+			"db":    {Name: "db", Image: "postgres"},
+			"cache": {Name: "cache", Image: "redis"},
+		},
+	}
+	container := func(id, project, service, image, imageID string) moby.Container {
+		return moby.Container{ID: id, Image: image, ImageID: imageID, Labels: map[string]string{
+			api.ProjectLabel: project,
+			api.ServiceLabel: service,
+		}}
+	}
+	observed := Containers{container("new-web", "newapp", "web", "nginx", "sha256:1")}
+	candidates := Containers{
+		container("old-web", "oldapp", "web", "nginx", "sha256:1"),
+		container("old-db", "oldapp", "db", "postgres:16", "sha256:2"),
+		container("other-db", "otherapp", "db", "mysql", "sha256:3"),
+		container("old-cache-1", "oldapp", "cache", "redis", "sha256:4"),
+		container("other-cache-1", "otherapp", "cache", "redis", "sha256:4"),
+	}
+
+	adoptable, warnings := selectAdoptableOrphans(project, []string{"web", "db", "cache"}, observed, candidates,
+		map[string]string{"postgres": "sha256:2"})
+	assert.DeepEqual(t, adoptable, map[string]Containers{"db": {candidates[1]}})
+	assert.DeepEqual(t, warnings, []string{`service "cache" matches containers from projects [oldapp otherapp], none is adopted`})
+}
+
+func TestAdoptedVolumes(t *testing.T) {
+	project := &types.Project{
+		Name: "newapp",
+		Services: types.Services{
+			"db": {Name: "db", Volumes: []types.ServiceVolumeConfig{
+				{Type: types.VolumeTypeVolume, Source: "data", Target: "/var/lib/data"},
+				{Type: types.VolumeTypeVolume, Source: "logs", Target: "/var/log"},
+			}},
+		},
+		Volumes: types.Volumes{
+			"data": {Name: "newapp_data"},
+			"logs": {Name: "newapp_logs"},
+		},
+	}
+	adoptable := map[string]Containers{"db": {{Mounts: []moby.MountPoint{
+		{Type: mount.TypeVolume, Name: "oldapp_data", Destination: "/var/lib/data"},
+		{Type: mount.TypeVolume, Name: "oldapp_logs", Destination: "/var/log"},
+	}}}}
+	volumes := adoptedVolumes(project, adoptable, func(name string) bool {
+		return name == "newapp_logs"
+	})
+	assert.DeepEqual(t, volumes, map[string]string{"data": "oldapp_data"})
+}
@@ -47,7 +47,7 @@ func (s *composeService) attach(ctx context.Context, project *types.Project, lis
 
 	var names []string
 	for _, c := range containers {
-		names = append(names, getContainerNameWithoutProject(c))
+		names = append(names, s.getContainerNameWithoutProject(c))
 	}
 
 	fmt.Fprintf(s.stdout(), "Attaching to %s\n", strings.Join(names, ", "))
@@ -63,7 +63,7 @@ func (s *composeService) attach(ctx context.Context, project *types.Project, lis
 
 func (s *composeService) attachContainer(ctx context.Context, container moby.Container, listener api.ContainerEventListener) error {
 	serviceName := container.Labels[api.ServiceLabel]
-	containerName := getContainerNameWithoutProject(container)
+	containerName := s.getContainerNameWithoutProject(container)
 
 	listener(api.ContainerEvent{
 		Type:      api.ContainerEventAttach,
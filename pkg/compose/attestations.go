@@ -0,0 +1,93 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/docker/buildx/controller/pb"
+	"github.com/docker/buildx/util/buildflags"
+	"github.com/docker/buildx/util/imagetools"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// toAttestations converts SBOM and provenance build options into BuildKit attestations, using the same syntax as
+// `docker buildx build --sbom --provenance`
+func toAttestations(options api.BuildOptions) (map[string]*string, error) {
+	var in []string
+	if options.SBOM != "" {
+		in = append(in, buildflags.CanonicalizeAttest("sbom", options.SBOM))
+	}
+	if options.Provenance != "" {
+		in = append(in, buildflags.CanonicalizeAttest("provenance", options.Provenance))
+	}
+	attests, err := buildflags.ParseAttests(in)
+	if err != nil {
+		return nil, err
+	}
+	return pb.CreateAttestations(attests), nil
+}
+
+// fetchImageSBOMs retrieves from the registry the SBOM attested for images. Images which were not pushed or pulled,
+// or have no SBOM attached, are ignored
+func (s *composeService) fetchImageSBOMs(ctx context.Context, images map[string]api.ImageSummary) error {
+	sboms := map[string]json.RawMessage{}
+	l := sync.Mutex{}
+	eg, ctx := errgroup.WithContext(ctx)
+	for id, img := range images {
+		id, img := id, img
+		if img.Digest == "" || img.Repository == "" {
+			continue
+		}
+		eg.Go(func() error {
+			ref := img.Repository + "@" + img.Digest
+			printer, err := imagetools.NewPrinter(ctx, imagetools.Opt{Auth: s.configFile()}, ref, "{{json .SBOM}}")
+			if err != nil {
+				logrus.Debugf("unable to retrieve SBOM for %s: %v", ref, err)
+				return nil
+			}
+			var buf bytes.Buffer
+			if err := printer.Print(false, &buf); err != nil {
+				logrus.Debugf("unable to retrieve SBOM for %s: %v", ref, err)
+				return nil
+			}
+			sbom := bytes.TrimSpace(buf.Bytes())
+			if len(sbom) == 0 || string(sbom) == "null" || string(sbom) == "{}" {
+				return nil
+			}
+			l.Lock()
+			sboms[id] = sbom
+			l.Unlock()
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+	for id, sbom := range sboms {
+		img := images[id]
+		img.SBOM = sbom
+		images[id] = img
+	}
+	return nil
+}
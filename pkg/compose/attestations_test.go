@@ -0,0 +1,49 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestToAttestations(t *testing.T) {
+	attests, err := toAttestations(api.BuildOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(attests), 0)
+
+	attests, err = toAttestations(api.BuildOptions{SBOM: "true", Provenance: "mode=max"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, attests, map[string]*string{
+		"sbom":       strPtr("type=sbom,disabled=false"),
+		"provenance": strPtr("type=provenance,mode=max"),
+	})
+
+	attests, err = toAttestations(api.BuildOptions{SBOM: "generator=docker/scout-sbom-indexer:1", Provenance: "false"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, attests, map[string]*string{
+		"sbom":       strPtr("type=sbom,generator=docker/scout-sbom-indexer:1"),
+		"provenance": nil,
+	})
+}
@@ -37,6 +37,7 @@ import (
 	cliopts "github.com/docker/cli/opts"
 	"github.com/docker/compose/v2/internal/tracing"
 	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/graph"
 	"github.com/docker/compose/v2/pkg/progress"
 	"github.com/docker/compose/v2/pkg/utils"
 	"github.com/docker/docker/builder/remotecontext/urlutil"
@@ -59,6 +60,9 @@ func (s *composeService) Build(ctx context.Context, project *types.Project, opti
 	if err != nil {
 		return err
 	}
+	if options.Print {
+		return s.printBuildPlan(project, options)
+	}
 	return progress.RunWithTitle(ctx, func(ctx context.Context) error {
 		_, err := s.build(ctx, project, options, nil)
 		return err
@@ -76,6 +80,10 @@ func (s *composeService) build(ctx context.Context, project *types.Project, opti
 	if err != nil {
 		return nil, err
 	}
+	if buildkitEnabled && !s.containerRuntime().Buildkit {
+		logrus.Debugf("container runtime %s doesn't support BuildKit, using classic builder", s.containerRuntime().Name)
+		buildkitEnabled = false
+	}
 
 	imageIDs := map[string]string{}
 	serviceToBeBuild := map[string]serviceToBuild{}
@@ -100,6 +108,29 @@ func (s *composeService) build(ctx context.Context, project *types.Project, opti
 		return imageIDs, err
 	}
 
+	buildGraph, err := newBuildGraph(project, serviceToBeBuild)
+	if err != nil {
+		return nil, err
+	}
+
+	contextSizes := map[string]int64{}
+	if options.ContextSizeWarning > 0 || !buildkitEnabled {
+		for name, toBuild := range serviceToBeBuild {
+			if !isLocalDir(toBuild.service.Build.Context) {
+				continue
+			}
+			size, err := buildContextSize(toBuild.service.Build.Context)
+			if err != nil {
+				logrus.Debugf("unable to compute service %s build context size: %v", name, err)
+				continue
+			}
+			contextSizes[name] = size.total
+			if warning := contextSizeWarning(name, size, options.ContextSizeWarning); warning != "" {
+				api.Warn(ctx, api.Warning{Code: api.WarningBuildContext, Message: warning})
+			}
+		}
+	}
+
 	// Initialize buildkit nodes
 	var (
 		b     *builder.Builder
@@ -152,7 +183,7 @@ func (s *composeService) build(ctx context.Context, project *types.Project, opti
 		}
 		return -1
 	}
-	err = InDependencyOrder(ctx, project, func(ctx context.Context, name string) error {
+	err = graph.UpDirectionTraversal(func(ctx context.Context, name string) error {
 		serviceToBuild, ok := serviceToBeBuild[name]
 		if !ok {
 			return nil
@@ -160,7 +191,7 @@ func (s *composeService) build(ctx context.Context, project *types.Project, opti
 		service := serviceToBuild.service
 
 		if !buildkitEnabled {
-			id, err := s.doBuildClassic(ctx, project, service, options)
+			id, err := s.doBuildClassic(ctx, project, service, options, contextSizes[name])
 			if err != nil {
 				return err
 			}
@@ -173,10 +204,14 @@ func (s *composeService) build(ctx context.Context, project *types.Project, opti
 		}
 
 		if options.Memory != 0 {
-			fmt.Fprintln(s.stderr(), "WARNING: --memory is not supported by BuildKit and will be ignored")
+			api.Warn(ctx, api.Warning{
+				Code:    api.WarningUnsupportedOption,
+				Service: name,
+				Message: "--memory is not supported by BuildKit and will be ignored",
+			})
 		}
 
-		buildOptions, err := s.toBuildOptions(project, service, options)
+		buildOptions, err := s.toBuildOptions(ctx, project, service, options)
 		if err != nil {
 			return err
 		}
@@ -188,9 +223,7 @@ func (s *composeService) build(ctx context.Context, project *types.Project, opti
 		builtDigests[getServiceIndex(name)] = digest
 
 		return nil
-	}, func(traversal *graphTraversal) {
-		traversal.maxConcurrency = s.maxConcurrency
-	})
+	}, graph.WithMaxConcurrency[string](s.maxConcurrency)).Visit(ctx, buildGraph)
 
 	// enforce all build event get consumed
 	if buildkitEnabled {
@@ -350,7 +383,7 @@ func resolveAndMergeBuildArgs(
 	return result
 }
 
-func (s *composeService) toBuildOptions(project *types.Project, service types.ServiceConfig, options api.BuildOptions) (build.Options, error) {
+func (s *composeService) toBuildOptions(ctx context.Context, project *types.Project, service types.ServiceConfig, options api.BuildOptions) (build.Options, error) {
 	plats, err := parsePlatforms(service)
 	if err != nil {
 		return build.Options{}, err
@@ -377,7 +410,7 @@ func (s *composeService) toBuildOptions(project *types.Project, service types.Se
 	}
 
 	if len(service.Build.Secrets) > 0 {
-		secretsProvider, err := addSecretsConfig(project, service)
+		secretsProvider, err := addSecretsConfig(ctx, project, service)
 		if err != nil {
 			return build.Options{}, err
 		}
@@ -421,6 +454,11 @@ func (s *composeService) toBuildOptions(project *types.Project, service types.Se
 		return build.Options{}, err
 	}
 
+	attests, err := toAttestations(options)
+	if err != nil {
+		return build.Options{}, err
+	}
+
 	return build.Options{
 		Inputs: build.Inputs{
 			ContextPath:      service.Build.Context,
@@ -444,6 +482,7 @@ func (s *composeService) toBuildOptions(project *types.Project, service types.Se
 		Session:      sessionConfig,
 		Allow:        allow,
 		SourcePolicy: sp,
+		Attests:      attests,
 	}, nil
 }
 
@@ -494,7 +533,7 @@ func sshAgentProvider(sshKeys types.SSHConfig) (session.Attachable, error) {
 	return sshprovider.NewSSHAgentProvider(sshConfig)
 }
 
-func addSecretsConfig(project *types.Project, service types.ServiceConfig) (session.Attachable, error) {
+func addSecretsConfig(ctx context.Context, project *types.Project, service types.ServiceConfig) (session.Attachable, error) {
 	var sources []secretsprovider.Source
 	for _, secret := range service.Build.Secrets {
 		config := project.Secrets[secret.Source]
@@ -517,7 +556,12 @@ func addSecretsConfig(project *types.Project, service types.ServiceConfig) (sess
 			return nil, fmt.Errorf("build.secrets only supports environment or file-based secrets: %q", secret.Source)
 		}
 		if secret.UID != "" || secret.GID != "" || secret.Mode != nil {
-			logrus.Warn("secrets `uid`, `gid` and `mode` are not supported by BuildKit, they will be ignored")
+			api.Warn(ctx, api.Warning{
+				Code:      api.WarningIgnoredAttribute,
+				Service:   service.Name,
+				Attribute: "build.secrets",
+				Message:   "`uid`, `gid` and `mode` are not supported by BuildKit, they will be ignored",
+			})
 		}
 	}
 	store, err := secretsprovider.NewStore(sources)
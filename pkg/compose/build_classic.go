@@ -32,6 +32,7 @@ import (
 	"github.com/docker/docker/api/types/registry"
 
 	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/buildx/util/buildflags"
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command/image/build"
 	dockertypes "github.com/docker/docker/api/types"
@@ -40,14 +41,13 @@ import (
 	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/jsonmessage"
-	"github.com/docker/docker/pkg/progress"
-	"github.com/docker/docker/pkg/streamformatter"
 
 	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
 )
 
 //nolint:gocyclo
-func (s *composeService) doBuildClassic(ctx context.Context, project *types.Project, service types.ServiceConfig, options api.BuildOptions) (string, error) {
+func (s *composeService) doBuildClassic(ctx context.Context, project *types.Project, service types.ServiceConfig, options api.BuildOptions, contextSize int64) (string, error) {
 	var (
 		buildCtx      io.ReadCloser
 		dockerfileCtx io.ReadCloser
@@ -78,12 +78,29 @@ func (s *composeService) doBuildClassic(ctx context.Context, project *types.Proj
 	if len(service.Build.Secrets) > 0 {
 		return "", fmt.Errorf("the classic builder doesn't support secrets, set DOCKER_BUILDKIT=1 to use BuildKit")
 	}
+	if options.SBOM != "" || options.Provenance != "" {
+		return "", fmt.Errorf("the classic builder doesn't support attestations, set DOCKER_BUILDKIT=1 to use BuildKit")
+	}
+	if len(service.Build.CacheTo) > 0 {
+		api.Warn(ctx, api.Warning{
+			Code:      api.WarningUnsupportedOption,
+			Service:   service.Name,
+			Attribute: "build.cache_to",
+			Message:   "is not supported by the classic builder, set DOCKER_BUILDKIT=1 to use BuildKit",
+		})
+	}
 
 	if service.Build.Labels == nil {
 		service.Build.Labels = make(map[string]string)
 	}
 	service.Build.Labels[api.ImageBuilderLabel] = "classic"
 
+	if service.Build.DockerfileInline != "" {
+		// same as `docker build -f -`, the Dockerfile is added to the build context archive with a random name
+		dockerfileName = "-"
+		dockerfileCtx = io.NopCloser(strings.NewReader(service.Build.DockerfileInline))
+	}
+
 	switch {
 	case isLocalDir(specifiedContext):
 		contextDir, relDockerfile, err = build.GetContextFromLocalDir(specifiedContext, dockerfileName)
@@ -144,14 +161,13 @@ func (s *composeService) doBuildClassic(ctx context.Context, project *types.Proj
 		}
 	}
 
-	buildCtx, err = build.Compress(buildCtx)
+	// report upload progress on the uncompressed archive, so it can be compared with the build context size
+	buildCtx = newContextUploadReader(buildCtx, progress.ContextWriter(ctx), service.Name, contextSize)
+	body, err := build.Compress(buildCtx)
 	if err != nil {
 		return "", err
 	}
 
-	progressOutput := streamformatter.NewProgressOutput(progBuff)
-	body := progress.NewProgressReader(buildCtx, progressOutput, 0, "", "Sending build context to Docker daemon")
-
 	configFile := s.configFile()
 	creds, err := configFile.GetAllCredentials()
 	if err != nil {
@@ -161,7 +177,7 @@ func (s *composeService) doBuildClassic(ctx context.Context, project *types.Proj
 	for k, auth := range creds {
 		authConfigs[k] = registry.AuthConfig(auth)
 	}
-	buildOptions := imageBuildOptions(s.dockerCli, project, service, options)
+	buildOptions := imageBuildOptions(ctx, s.dockerCli, project, service, options)
 	imageName := api.GetImageNameOrDefault(service, project.Name)
 	buildOptions.Tags = append(buildOptions.Tags, imageName)
 	buildOptions.Dockerfile = relDockerfile
@@ -218,7 +234,7 @@ func isLocalDir(c string) bool {
 	return err == nil
 }
 
-func imageBuildOptions(dockerCli command.Cli, project *types.Project, service types.ServiceConfig, options api.BuildOptions) dockertypes.ImageBuildOptions {
+func imageBuildOptions(ctx context.Context, dockerCli command.Cli, project *types.Project, service types.ServiceConfig, options api.BuildOptions) dockertypes.ImageBuildOptions {
 	config := service.Build
 	return dockertypes.ImageBuildOptions{
 		Version:     dockertypes.BuilderV1,
@@ -232,5 +248,35 @@ func imageBuildOptions(dockerCli command.Cli, project *types.Project, service ty
 		ExtraHosts:  config.ExtraHosts.AsList(":"),
 		Target:      config.Target,
 		Isolation:   container.Isolation(config.Isolation),
+		CacheFrom:   classicCacheFrom(ctx, service),
+	}
+}
+
+// classicCacheFrom returns the images set by cache_from the classic builder can use as cache source. Other cache
+// types are only supported by BuildKit
+func classicCacheFrom(ctx context.Context, service types.ServiceConfig) []string {
+	entries, err := buildflags.ParseCacheEntry(service.Build.CacheFrom)
+	if err != nil {
+		api.Warn(ctx, api.Warning{
+			Code:      api.WarningIgnoredAttribute,
+			Service:   service.Name,
+			Attribute: "build.cache_from",
+			Message:   fmt.Sprintf("is invalid and ignored: %v", err),
+		})
+		return nil
+	}
+	var images []string
+	for _, entry := range entries {
+		if entry.Type != "registry" || entry.Attrs["ref"] == "" {
+			api.Warn(ctx, api.Warning{
+				Code:      api.WarningUnsupportedOption,
+				Service:   service.Name,
+				Attribute: "build.cache_from",
+				Message:   fmt.Sprintf("type %q is not supported by the classic builder, set DOCKER_BUILDKIT=1 to use BuildKit", entry.Type),
+			})
+			continue
+		}
+		images = append(images, entry.Attrs["ref"])
 	}
+	return images
 }
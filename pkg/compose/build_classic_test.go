@@ -0,0 +1,119 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli/config/configfile"
+	dockertypes "github.com/docker/docker/api/types"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+func TestClassicCacheFrom(t *testing.T) {
+	service := types.ServiceConfig{
+		Name: "web",
+		Build: &types.BuildConfig{
+			CacheFrom: []string{
+				"registry.example.com/web:cache",
+				"type=registry,ref=registry.example.com/web:buildcache",
+				"type=local,src=/tmp/cache",
+			},
+		},
+	}
+	collector := &api.WarningCollector{}
+	ctx := api.WithWarningCollector(context.Background(), collector)
+	assert.DeepEqual(t, classicCacheFrom(ctx, service), []string{
+		"registry.example.com/web:cache",
+		"registry.example.com/web:buildcache",
+	})
+	assert.DeepEqual(t, collector.Warnings(), []api.Warning{{
+		Code:      api.WarningUnsupportedOption,
+		Service:   "web",
+		Attribute: "build.cache_from",
+		Message:   `type "local" is not supported by the classic builder, set DOCKER_BUILDKIT=1 to use BuildKit`,
+	}})
+}
+
+func TestClassicBuildDockerfileInline(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	apiClient, cli := prepareMocks(mockCtrl)
+	cli.EXPECT().ConfigFile().Return(configfile.New("")).AnyTimes()
+	apiClient.EXPECT().DaemonHost().Return("unix:///var/run/docker.sock").AnyTimes()
+	tested := composeService{dockerCli: cli}
+
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "index.html"), []byte("hello"), 0o600))
+	const dockerfile = "FROM nginx\nCOPY index.html /usr/share/nginx/html/\n"
+	project := &types.Project{Name: "test"}
+	service := types.ServiceConfig{
+		Name:  "web",
+		Build: &types.BuildConfig{Context: dir, DockerfileInline: dockerfile},
+	}
+
+	apiClient.EXPECT().ImageBuild(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, body io.Reader, options dockertypes.ImageBuildOptions) (dockertypes.ImageBuildResponse, error) {
+			files, err := readBuildContext(body)
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(files["index.html"], "hello"))
+			assert.Check(t, is.Equal(files[options.Dockerfile], dockerfile))
+			return dockertypes.ImageBuildResponse{
+				Body: io.NopCloser(strings.NewReader(`{"aux":{"ID":"sha256:1234"}}`)),
+			}, nil
+		})
+
+	id, err := tested.doBuildClassic(context.Background(), project, service, api.BuildOptions{}, 0)
+	assert.NilError(t, err)
+	assert.Equal(t, id, "sha256:1234")
+}
+
+func readBuildContext(body io.Reader) (map[string]string, error) {
+	gz, err := gzip.NewReader(body)
+	if err != nil {
+		return nil, err
+	}
+	files := map[string]string{}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[header.Name] = string(content)
+	}
+}
@@ -0,0 +1,169 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli/command/image/build"
+	"github.com/docker/go-units"
+	"github.com/moby/patternmatcher"
+
+	"github.com/docker/compose/v2/pkg/progress"
+)
+
+// contextSize is the size of a local build context, once files excluded by .dockerignore are left aside
+type contextSize struct {
+	total int64
+	// entries are the sizes of top-level files and directories within the build context
+	entries map[string]int64
+}
+
+func buildContextSize(contextDir string) (contextSize, error) {
+	size := contextSize{entries: map[string]int64{}}
+	excludes, err := build.ReadDockerignore(contextDir)
+	if err != nil {
+		return size, err
+	}
+	pm, err := patternmatcher.New(excludes)
+	if err != nil {
+		return size, err
+	}
+	err = filepath.WalkDir(contextDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(contextDir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		excluded, err := pm.MatchesOrParentMatches(rel)
+		if err != nil {
+			return err
+		}
+		if excluded {
+			if d.IsDir() && !pm.Exclusions() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		top, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+		size.entries[top] += info.Size()
+		size.total += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// largest returns the n largest top-level entries of the build context, with their size
+func (c contextSize) largest(n int) []string {
+	names := make([]string, 0, len(c.entries))
+	for name := range c.entries {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if c.entries[names[i]] == c.entries[names[j]] {
+			return names[i] < names[j]
+		}
+		return c.entries[names[i]] > c.entries[names[j]]
+	})
+	if len(names) > n {
+		names = names[:n]
+	}
+	for i, name := range names {
+		names[i] = fmt.Sprintf("%s (%s)", name, units.HumanSize(float64(c.entries[name])))
+	}
+	return names
+}
+
+// contextSizeWarning tells about a build context larger than threshold, naming the entries which contribute the most
+func contextSizeWarning(service string, size contextSize, threshold int64) string {
+	if threshold <= 0 || size.total <= threshold {
+		return ""
+	}
+	return fmt.Sprintf("service %q: build context is %s, more than %s. Largest entries are %s, consider adding unneeded ones to .dockerignore",
+		service, units.HumanSize(float64(size.total)), units.HumanSize(float64(threshold)), strings.Join(size.largest(3), ", "))
+}
+
+// contextUploadReader reports bytes read from the build context as it is sent to the Docker Engine
+type contextUploadReader struct {
+	io.ReadCloser
+	w       progress.Writer
+	id      string
+	parent  string
+	total   int64
+	current int64
+}
+
+func newContextUploadReader(r io.ReadCloser, w progress.Writer, service string, total int64) *contextUploadReader {
+	return &contextUploadReader{
+		ReadCloser: r,
+		w:          w,
+		id:         service + " context",
+		parent:     service,
+		total:      total,
+	}
+}
+
+func (r *contextUploadReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.current += int64(n)
+	if err == io.EOF {
+		r.w.Event(progress.Event{
+			ID:       r.id,
+			ParentID: r.parent,
+			Status:   progress.Done,
+			Text:     "Sent",
+			Current:  r.current,
+			Total:    r.current,
+			Percent:  100,
+		})
+		return n, err
+	}
+	total := r.total
+	if total < r.current {
+		// tar headers make the archive slightly larger than the files it contains
+		total = r.current
+	}
+	percent := 0
+	if total > 0 {
+		percent = int(r.current * 99 / total)
+	}
+	r.w.Event(progress.Event{
+		ID:         r.id,
+		ParentID:   r.parent,
+		Status:     progress.Working,
+		Text:       "Sending build context",
+		StatusText: units.HumanSize(float64(r.current)),
+		Current:    r.current,
+		Total:      total,
+		Percent:    percent,
+	})
+	return n, err
+}
@@ -0,0 +1,59 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestBuildContextSize(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, size int) {
+		path := filepath.Join(dir, name)
+		assert.NilError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NilError(t, os.WriteFile(path, []byte(strings.Repeat("x", size)), 0o644))
+	}
+	write(".dockerignore", 0)
+	write("Dockerfile", 100)
+	write("node_modules/a/index.js", 5000)
+	write("data/dump.sql", 3000)
+	write("data/keep.sql", 1000)
+	write("src/main.go", 500)
+	write("build.log", 200)
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, ".dockerignore"), []byte("node_modules\n*.log\ndata/*\n!data/keep.sql\n"), 0o644))
+
+	size, err := buildContextSize(dir)
+	assert.NilError(t, err)
+	assert.Equal(t, size.total, int64(1641))
+	assert.DeepEqual(t, size.entries, map[string]int64{
+		".dockerignore": 41,
+		"Dockerfile":    100,
+		"data":          1000,
+		"src":           500,
+	})
+	assert.DeepEqual(t, size.largest(2), []string{"data (1kB)", "src (500B)"})
+
+	assert.Equal(t, contextSizeWarning("web", size, 0), "")
+	assert.Equal(t, contextSizeWarning("web", size, 2000), "")
+	assert.Equal(t, contextSizeWarning("web", size, 1000),
+		`service "web": build context is 1.641kB, more than 1kB. Largest entries are data (1kB), src (500B), Dockerfile (100B), consider adding unneeded ones to .dockerignore`)
+}
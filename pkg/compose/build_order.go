@@ -0,0 +1,160 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/distribution/reference"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// newBuildGraph returns the service dependency graph, with additional edges from services being built to the services
+// building an image they use, so those get built first. Services which don't depend on each other's images are built
+// in parallel
+func newBuildGraph(project *types.Project, toBuild map[string]serviceToBuild) (*Graph, error) {
+	g, err := NewGraph(project, ServiceStopped)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range sortedKeys(toBuild) {
+		for _, dependency := range buildImageDependencies(project, toBuild[name].service, toBuild) {
+			if err := g.AddEdge(name, dependency); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if cycle, err := g.HasCycles(); cycle {
+		return nil, fmt.Errorf("services build images from each other: %w", err)
+	}
+	return g, nil
+}
+
+// buildImageDependencies returns the services, among the ones being built, which build an image service uses as a
+// base image or build context
+func buildImageDependencies(project *types.Project, service types.ServiceConfig, toBuild map[string]serviceToBuild) []string {
+	images := map[string]string{}
+	for _, name := range sortedKeys(toBuild) {
+		if name == service.Name {
+			continue
+		}
+		if ref := normalizeImageRef(api.GetImageNameOrDefault(toBuild[name].service, project.Name)); ref != "" {
+			images[ref] = name
+		}
+	}
+	if len(images) == 0 {
+		return nil
+	}
+
+	var refs []string
+	for _, c := range service.Build.AdditionalContexts {
+		if target, ok := strings.CutPrefix(c, types.ServicePrefix); ok {
+			if _, ok := toBuild[target]; ok {
+				refs = append(refs, api.GetImageNameOrDefault(toBuild[target].service, project.Name))
+			}
+			continue
+		}
+		if image, ok := strings.CutPrefix(c, "docker-image://"); ok {
+			refs = append(refs, image)
+		}
+	}
+	refs = append(refs, dockerfileImages(service.Build)...)
+
+	var dependencies []string
+	seen := map[string]bool{}
+	for _, ref := range refs {
+		name, ok := images[normalizeImageRef(ref)]
+		if ok && !seen[name] {
+			seen[name] = true
+			dependencies = append(dependencies, name)
+		}
+	}
+	return dependencies
+}
+
+// dockerfileImages returns the images a Dockerfile uses with FROM and COPY --from instructions, but the ones which
+// refer to a build stage or use a build argument
+func dockerfileImages(build *types.BuildConfig) []string {
+	var r io.Reader
+	if build.DockerfileInline != "" {
+		r = strings.NewReader(build.DockerfileInline)
+	} else {
+		dockerfile := build.Dockerfile
+		if dockerfile == "" {
+			dockerfile = "Dockerfile"
+		}
+		if !isLocalDir(build.Context) {
+			return nil
+		}
+		f, err := os.Open(dockerFilePath(build.Context, dockerfile))
+		if err != nil {
+			logrus.Debugf("unable to read Dockerfile to compute build order: %v", err)
+			return nil
+		}
+		defer f.Close() //nolint:errcheck
+		r = f
+	}
+	result, err := parser.Parse(r)
+	if err != nil {
+		logrus.Debugf("unable to parse Dockerfile to compute build order: %v", err)
+		return nil
+	}
+
+	var images []string
+	stages := map[string]bool{}
+	for _, node := range result.AST.Children {
+		var image string
+		switch strings.ToLower(node.Value) {
+		case "from":
+			if node.Next == nil {
+				continue
+			}
+			image = node.Next.Value
+			if as := node.Next.Next; as != nil && strings.EqualFold(as.Value, "as") && as.Next != nil {
+				stages[strings.ToLower(as.Next.Value)] = true
+			}
+		case "copy":
+			for _, flag := range node.Flags {
+				if from, ok := strings.CutPrefix(flag, "--from="); ok {
+					image = from
+				}
+			}
+		}
+		if image == "" || strings.Contains(image, "$") || stages[strings.ToLower(image)] {
+			continue
+		}
+		images = append(images, image)
+	}
+	return images
+}
+
+// normalizeImageRef returns the fully qualified form of an image reference, with the default tag, so references to
+// the same image can be compared. Returns an empty string if ref is not a valid reference
+func normalizeImageRef(ref string) string {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return ""
+	}
+	return reference.TagNameOnly(named).String()
+}
@@ -0,0 +1,80 @@
+/*
+   Copyright 2023 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestBuildImageDependencies(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte(`
+FROM example.com/base:1.0 AS builder
+FROM golang AS tools
+COPY --from=builder /out /out
+COPY --from=myapp-assets /assets /assets
+FROM ${BASE}
+`), 0o600)
+	assert.NilError(t, err)
+
+	base := types.ServiceConfig{Name: "base", Image: "example.com/base:1.0", Build: &types.BuildConfig{Context: "."}}
+	assets := types.ServiceConfig{Name: "assets", Build: &types.BuildConfig{Context: "."}}
+	plugin := types.ServiceConfig{Name: "plugin", Build: &types.BuildConfig{Context: "."}}
+	app := types.ServiceConfig{Name: "app", Build: &types.BuildConfig{
+		Context:            dir,
+		AdditionalContexts: types.Mapping{"plugin": "service:plugin"},
+	}}
+	other := types.ServiceConfig{Name: "other", Build: &types.BuildConfig{DockerfileInline: "FROM alpine"}}
+	project := &types.Project{
+		Name:     "myapp",
+		Services: types.Services{"base": base, "assets": assets, "plugin": plugin, "app": app, "other": other},
+	}
+	toBuild := map[string]serviceToBuild{}
+	for name, service := range project.Services {
+		toBuild[name] = serviceToBuild{name: name, service: service}
+	}
+
+	assert.Check(t, is.DeepEqual(buildImageDependencies(project, app, toBuild), []string{"plugin", "base", "assets"}))
+	assert.Check(t, is.Len(buildImageDependencies(project, other, toBuild), 0))
+
+	g, err := newBuildGraph(project, toBuild)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(g.Vertices["app"].Children, 3))
+	assert.Check(t, is.Len(g.Leaves(), 4))
+}
+
+func TestBuildImageDependenciesCycle(t *testing.T) {
+	project := &types.Project{
+		Name: "myapp",
+		Services: types.Services{
+			"a": {Name: "a", Image: "a", Build: &types.BuildConfig{DockerfileInline: "FROM b"}},
+			"b": {Name: "b", Image: "b", Build: &types.BuildConfig{DockerfileInline: "FROM a"}},
+		},
+	}
+	toBuild := map[string]serviceToBuild{}
+	for name, service := range project.Services {
+		toBuild[name] = serviceToBuild{name: name, service: service}
+	}
+	_, err := newBuildGraph(project, toBuild)
+	assert.ErrorContains(t, err, "services build images from each other")
+}
@@ -0,0 +1,127 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/compose-spec/compose-go/v2/types"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// buildPlan is the resolved build configuration of a service, as printed by `compose build --print`
+type buildPlan struct {
+	Service          string            `json:"service"`
+	Context          string            `json:"context"`
+	Dockerfile       string            `json:"dockerfile,omitempty"`
+	DockerfileInline string            `json:"dockerfile_inline,omitempty"`
+	Target           string            `json:"target,omitempty"`
+	Args             map[string]string `json:"args,omitempty"`
+	Tags             []string          `json:"tags"`
+	Platforms        []string          `json:"platforms,omitempty"`
+	CacheFrom        []string          `json:"cache_from,omitempty"`
+	CacheTo          []string          `json:"cache_to,omitempty"`
+	NoCache          bool              `json:"no_cache,omitempty"`
+	Pull             bool              `json:"pull,omitempty"`
+	Push             bool              `json:"push,omitempty"`
+	Secrets          []buildSecretPlan `json:"secrets,omitempty"`
+	SSH              []string          `json:"ssh,omitempty"`
+}
+
+// buildSecretPlan tells where a build secret is read from, never its value
+type buildSecretPlan struct {
+	ID   string `json:"id"`
+	File string `json:"file,omitempty"`
+	Env  string `json:"env,omitempty"`
+}
+
+// printBuildPlan writes the resolved build configuration of the services to be built as JSON, without building
+func (s *composeService) printBuildPlan(project *types.Project, options api.BuildOptions) error {
+	plans, err := s.buildPlans(project, options)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(s.stdout())
+	enc.SetIndent("", "  ")
+	return enc.Encode(plans)
+}
+
+func (s *composeService) buildPlans(project *types.Project, options api.BuildOptions) ([]buildPlan, error) {
+	var policy types.DependencyOption = types.IgnoreDependencies
+	if options.Deps {
+		policy = types.IncludeDependencies
+	}
+	plans := []buildPlan{}
+	err := project.ForEachService(options.Services, func(name string, service *types.ServiceConfig) error {
+		if service.Build == nil {
+			return nil
+		}
+		plan, err := s.buildPlan(project, *service, options)
+		if err != nil {
+			return err
+		}
+		plans = append(plans, plan)
+		return nil
+	}, policy)
+	sort.Slice(plans, func(i, j int) bool {
+		return plans[i].Service < plans[j].Service
+	})
+	return plans, err
+}
+
+func (s *composeService) buildPlan(project *types.Project, service types.ServiceConfig, options api.BuildOptions) (buildPlan, error) {
+	plan := buildPlan{
+		Service:          service.Name,
+		Context:          service.Build.Context,
+		Dockerfile:       dockerFilePath(service.Build.Context, service.Build.Dockerfile),
+		DockerfileInline: service.Build.DockerfileInline,
+		Target:           service.Build.Target,
+		Args:             flatten(resolveAndMergeBuildArgs(s.dockerCli, project, service, options)),
+		Tags:             append([]string{api.GetImageNameOrDefault(service, project.Name)}, service.Build.Tags...),
+		Platforms:        service.Build.Platforms,
+		CacheFrom:        service.Build.CacheFrom,
+		CacheTo:          service.Build.CacheTo,
+		NoCache:          service.Build.NoCache || options.NoCache,
+		Pull:             service.Build.Pull || options.Pull,
+		Push:             options.Push && service.Image != "",
+	}
+	if len(plan.Platforms) == 0 && service.Platform != "" {
+		plan.Platforms = []string{service.Platform}
+	}
+	for _, secret := range service.Build.Secrets {
+		config := project.Secrets[secret.Source]
+		id := secret.Source
+		if secret.Target != "" {
+			id = secret.Target
+		}
+		switch {
+		case config.File != "":
+			plan.Secrets = append(plan.Secrets, buildSecretPlan{ID: id, File: config.File})
+		case config.Environment != "":
+			plan.Secrets = append(plan.Secrets, buildSecretPlan{ID: id, Env: config.Environment})
+		default:
+			return plan, fmt.Errorf("build.secrets only supports environment or file-based secrets: %q", secret.Source)
+		}
+	}
+	for _, key := range append(service.Build.SSH, options.SSHs...) {
+		plan.SSH = append(plan.SSH, key.ID)
+	}
+	return plan, nil
+}
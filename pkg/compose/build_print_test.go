@@ -0,0 +1,90 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/streams"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/mocks"
+)
+
+func TestPrintBuildPlan(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	apiClient := mocks.NewMockAPIClient(mockCtrl)
+	apiClient.EXPECT().DaemonHost().Return("unix:///var/run/docker.sock").AnyTimes()
+	cli := mocks.NewMockCli(mockCtrl)
+	cli.EXPECT().Client().Return(apiClient).AnyTimes()
+	cli.EXPECT().ConfigFile().Return(&configfile.ConfigFile{}).AnyTimes()
+	out := &bytes.Buffer{}
+	cli.EXPECT().Out().Return(streams.NewOut(out)).AnyTimes()
+	tested := composeService{dockerCli: cli}
+
+	token := "s3cr3t"
+	project := &types.Project{
+		Name:        "myproject",
+		Environment: types.Mapping{"VERSION": "1.2"},
+		Services: types.Services{
+			"web": {
+				Name:     "web",
+				Image:    "registry.example.com/web",
+				Platform: "linux/arm64",
+				Build: &types.BuildConfig{
+					Context:    "/src/web",
+					Dockerfile: "Dockerfile.prod",
+					Args:       types.NewMappingWithEquals([]string{"VERSION"}),
+					Tags:       []string{"registry.example.com/web:1.2"},
+					CacheFrom:  []string{"type=registry,ref=registry.example.com/web:cache"},
+					Secrets:    []types.ServiceSecretConfig{{Source: "token", Target: "npm"}},
+				},
+			},
+			"db": {Name: "db", Image: "postgres"},
+		},
+		Secrets: types.Secrets{
+			"token": {Environment: "NPM_TOKEN", Content: token},
+		},
+	}
+
+	err := tested.Build(context.Background(), project, api.BuildOptions{Print: true, Push: true})
+	assert.NilError(t, err)
+	assert.Assert(t, !bytes.Contains(out.Bytes(), []byte(token)))
+
+	var plans []buildPlan
+	assert.NilError(t, json.Unmarshal(out.Bytes(), &plans))
+	assert.DeepEqual(t, plans, []buildPlan{{
+		Service:    "web",
+		Context:    "/src/web",
+		Dockerfile: "/src/web/Dockerfile.prod",
+		Args:       map[string]string{"VERSION": "1.2"},
+		Tags:       []string{"registry.example.com/web", "registry.example.com/web:1.2"},
+		Platforms:  []string{"linux/arm64"},
+		CacheFrom:  []string{"type=registry,ref=registry.example.com/web:cache"},
+		Push:       true,
+		Secrets:    []buildSecretPlan{{ID: "npm", Env: "NPM_TOKEN"}},
+	}})
+}
@@ -0,0 +1,104 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/versions"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// engineFeature is a service attribute which requires a minimal Docker Engine API version
+type engineFeature struct {
+	attribute  string
+	apiVersion string
+	engine     string
+	used       func(service types.ServiceConfig) bool
+	disable    func(service *types.ServiceConfig)
+}
+
+var engineFeatures = []engineFeature{
+	{
+		attribute:  "cgroup",
+		apiVersion: "1.41",
+		engine:     "v20.10",
+		used: func(service types.ServiceConfig) bool {
+			return service.Cgroup != ""
+		},
+		disable: func(service *types.ServiceConfig) {
+			service.Cgroup = ""
+		},
+	},
+	{
+		attribute:  "annotations",
+		apiVersion: "1.43",
+		engine:     "v24",
+		used: func(service types.ServiceConfig) bool {
+			return len(service.Annotations) > 0
+		},
+		disable: func(service *types.ServiceConfig) {
+			service.Annotations = nil
+		},
+	},
+	{
+		attribute:  "healthcheck.start_interval",
+		apiVersion: "1.44",
+		engine:     "v25",
+		used: func(service types.ServiceConfig) bool {
+			return service.HealthCheck != nil && service.HealthCheck.StartInterval != nil
+		},
+		disable: func(service *types.ServiceConfig) {
+			service.HealthCheck.StartInterval = nil
+		},
+	},
+}
+
+// disableUnsupportedFeatures removes from project services the attributes the engine doesn't support, with a
+// warning, so we don't fail after some containers have been created. Engine version is only queried if needed
+func (s *composeService) disableUnsupportedFeatures(ctx context.Context, project *types.Project) error {
+	var apiVersion string
+	for name, service := range project.Services {
+		for _, feature := range engineFeatures {
+			if !feature.used(service) {
+				continue
+			}
+			if apiVersion == "" {
+				version, err := s.RuntimeVersion(ctx)
+				if err != nil {
+					return err
+				}
+				apiVersion = version
+			}
+			if versions.LessThan(apiVersion, feature.apiVersion) {
+				api.Warn(ctx, api.Warning{
+					Code:      api.WarningIgnoredAttribute,
+					Service:   name,
+					Attribute: feature.attribute,
+					Message: fmt.Sprintf("requires Docker Engine %s or later (API %s), engine API version is %s. Attribute will be ignored",
+						feature.engine, feature.apiVersion, apiVersion),
+				})
+				feature.disable(&service)
+			}
+		}
+		project.Services[name] = service
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	moby "github.com/docker/docker/api/types"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestDisableUnsupportedFeatures(t *testing.T) {
+	startInterval := types.Duration(time.Second)
+	newProject := func() *types.Project {
+		return &types.Project{
+			Services: types.Services{
+				"web": {
+					Name:        "web",
+					Cgroup:      "host",
+					Annotations: types.Mapping{"com.example": "foo"},
+					HealthCheck: &types.HealthCheckConfig{StartInterval: &startInterval},
+				},
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		apiVersion    string
+		cgroup        string
+		annotations   types.Mapping
+		startInterval *types.Duration
+	}{
+		{apiVersion: "1.44", cgroup: "host", annotations: types.Mapping{"com.example": "foo"}, startInterval: &startInterval},
+		{apiVersion: "1.43", cgroup: "host", annotations: types.Mapping{"com.example": "foo"}},
+		{apiVersion: "1.40"},
+	} {
+		t.Run(tc.apiVersion, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			api, cli := prepareMocks(mockCtrl)
+			tested := composeService{dockerCli: cli}
+
+			runtimeVersion = runtimeVersionCache{}
+			api.EXPECT().ServerVersion(gomock.Any()).Return(moby.Version{APIVersion: tc.apiVersion}, nil)
+
+			project := newProject()
+			err := tested.disableUnsupportedFeatures(context.Background(), project)
+			assert.NilError(t, err)
+			web := project.Services["web"]
+			assert.Equal(t, web.Cgroup, tc.cgroup)
+			assert.DeepEqual(t, web.Annotations, tc.annotations)
+			assert.DeepEqual(t, web.HealthCheck.StartInterval, tc.startInterval)
+		})
+	}
+	runtimeVersion = runtimeVersionCache{}
+}
+
+func TestDisableUnsupportedFeaturesNotUsed(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, cli := prepareMocks(mockCtrl)
+	tested := composeService{dockerCli: cli}
+
+	project := &types.Project{Services: types.Services{"web": {Name: "web"}}}
+	assert.NilError(t, tested.disableUnsupportedFeatures(context.Background(), project))
+}
@@ -0,0 +1,115 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/checkpoint"
+	containerType "github.com/docker/docker/api/types/container"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
+	"github.com/docker/compose/v2/pkg/utils"
+)
+
+func (s *composeService) Checkpoint(ctx context.Context, project *types.Project, options api.CheckpointOptions) error {
+	return progress.RunWithTitle(ctx, func(ctx context.Context) error {
+		return s.checkpoint(ctx, project, options)
+	}, s.stdinfo(), "Checkpointing")
+}
+
+// checkpoint saves running containers state, dependents first, so that services are not left running without the
+// services they depend on
+func (s *composeService) checkpoint(ctx context.Context, project *types.Project, options api.CheckpointOptions) error {
+	containers, err := s.getContainers(ctx, project.Name, oneOffExclude, false, options.Services...)
+	if err != nil {
+		return err
+	}
+	if len(containers) == 0 {
+		return fmt.Errorf("no running container to checkpoint for project %q", project.Name)
+	}
+
+	w := progress.ContextWriter(ctx)
+	return InReverseDependencyOrder(ctx, project, func(ctx context.Context, service string) error {
+		eg, ctx := errgroup.WithContext(ctx)
+		for _, c := range containers.filter(isService(service)) {
+			c := c
+			eg.Go(func() error {
+				eventName := getContainerProgressName(c)
+				w.Event(progress.NewEvent(eventName, progress.Working, "Checkpointing"))
+				err := s.apiClient().CheckpointCreate(ctx, c.ID, checkpoint.CreateOptions{
+					CheckpointID: options.Name,
+					Exit:         !options.LeaveRunning,
+				})
+				if err != nil {
+					w.Event(progress.ErrorMessageEvent(eventName, "Error while Checkpointing"))
+					return err
+				}
+				w.Event(progress.NewEvent(eventName, progress.Done, "Checkpointed"))
+				return nil
+			})
+		}
+		return eg.Wait()
+	})
+}
+
+func (s *composeService) Restore(ctx context.Context, project *types.Project, options api.CheckpointOptions) error {
+	return progress.RunWithTitle(ctx, func(ctx context.Context) error {
+		return s.restore(ctx, project, options)
+	}, s.stdinfo(), "Restoring")
+}
+
+// restore starts stopped containers from checkpoint, in dependency order
+func (s *composeService) restore(ctx context.Context, project *types.Project, options api.CheckpointOptions) error {
+	containers, err := s.getContainers(ctx, project.Name, oneOffExclude, true, options.Services...)
+	if err != nil {
+		return err
+	}
+	if len(containers) == 0 {
+		return fmt.Errorf("no container to restore for project %q", project.Name)
+	}
+
+	w := progress.ContextWriter(ctx)
+	return InDependencyOrder(ctx, project, func(ctx context.Context, service string) error {
+		if len(options.Services) > 0 && !utils.StringContains(options.Services, service) {
+			return nil
+		}
+		eg, ctx := errgroup.WithContext(ctx)
+		for _, c := range containers.filter(isService(service)) {
+			c := c
+			eg.Go(func() error {
+				eventName := getContainerProgressName(c)
+				if c.State == ContainerRunning {
+					return fmt.Errorf("container %s is running, it must be stopped to be restored", getCanonicalContainerName(c))
+				}
+				w.Event(progress.NewEvent(eventName, progress.Working, "Restoring"))
+				err := s.apiClient().ContainerStart(ctx, c.ID, containerType.StartOptions{CheckpointID: options.Name})
+				if err != nil {
+					w.Event(progress.ErrorMessageEvent(eventName, "Error while Restoring"))
+					return err
+				}
+				w.Event(progress.NewEvent(eventName, progress.Done, "Restored"))
+				return nil
+			})
+		}
+		return eg.Wait()
+	})
+}
@@ -0,0 +1,104 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/checkpoint"
+	containerType "github.com/docker/docker/api/types/container"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	compose "github.com/docker/compose/v2/pkg/api"
+)
+
+func checkpointProject() *types.Project {
+	return &types.Project{
+		Name: strings.ToLower(testProject),
+		Services: types.Services{
+			"service1": {Name: "service1"},
+			"service2": {Name: "service2", DependsOn: types.DependsOnConfig{"service1": {Condition: types.ServiceConditionStarted}}},
+		},
+	}
+}
+
+func TestCheckpointReverseDependencyOrder(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	api.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return([]moby.Container{
+		testContainer("service1", "123", false),
+		testContainer("service2", "789", false),
+	}, nil)
+	gomock.InOrder(
+		api.EXPECT().CheckpointCreate(gomock.Any(), "789", checkpoint.CreateOptions{CheckpointID: "snap", Exit: true}).Return(nil),
+		api.EXPECT().CheckpointCreate(gomock.Any(), "123", checkpoint.CreateOptions{CheckpointID: "snap", Exit: true}).Return(nil),
+	)
+
+	err := tested.checkpoint(context.Background(), checkpointProject(), compose.CheckpointOptions{Name: "snap"})
+	assert.NilError(t, err)
+}
+
+func TestRestoreDependencyOrder(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	service1 := testContainer("service1", "123", false)
+	service1.State = ContainerExited
+	service2 := testContainer("service2", "789", false)
+	service2.State = ContainerExited
+	api.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return([]moby.Container{service1, service2}, nil)
+	gomock.InOrder(
+		api.EXPECT().ContainerStart(gomock.Any(), "123", containerType.StartOptions{CheckpointID: "snap"}).Return(nil),
+		api.EXPECT().ContainerStart(gomock.Any(), "789", containerType.StartOptions{CheckpointID: "snap"}).Return(nil),
+	)
+
+	err := tested.restore(context.Background(), checkpointProject(), compose.CheckpointOptions{Name: "snap"})
+	assert.NilError(t, err)
+}
+
+func TestRestoreRunningContainer(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	running := testContainer("service1", "123", false)
+	running.State = ContainerRunning
+	api.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return([]moby.Container{running}, nil)
+
+	err := tested.restore(context.Background(), checkpointProject(), compose.CheckpointOptions{Name: "snap", Services: []string{"service1"}})
+	assert.ErrorContains(t, err, "must be stopped to be restored")
+}
@@ -25,6 +25,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/docker/compose/v2/internal/desktop"
 	"github.com/docker/compose/v2/internal/experimental"
@@ -52,14 +53,22 @@ func init() {
 	}
 }
 
+// Option configures the compose.Service created by NewComposeService
+type Option func(*composeService)
+
 // NewComposeService create a local implementation of the compose.Service API
-func NewComposeService(dockerCli command.Cli) api.Service {
-	return &composeService{
+func NewComposeService(dockerCli command.Cli, options ...Option) api.Service {
+	s := &composeService{
 		dockerCli:      dockerCli,
 		clock:          clockwork.NewRealClock(),
 		maxConcurrency: -1,
 		dryRun:         false,
+		locks:          &projectLocks{held: map[string]*heldLock{}},
+	}
+	for _, option := range options {
+		option(s)
 	}
+	return s
 }
 
 type composeService struct {
@@ -70,6 +79,14 @@ type composeService struct {
 	clock          clockwork.Clock
 	maxConcurrency int
 	dryRun         bool
+
+	lockTimeout time.Duration
+	secretsExec bool
+	hooks       bool
+	locks       *projectLocks
+
+	runtime *ContainerRuntime
+	namer   api.ContainerNamer
 }
 
 // Close releases any connections/resources held by the underlying clients.
@@ -154,15 +171,17 @@ func getCanonicalContainerName(c moby.Container) string {
 	return strings.TrimPrefix(c.Names[0], "/")
 }
 
-func getContainerNameWithoutProject(c moby.Container) string {
+// getContainerNameWithoutProject returns container name without the project prefix when container is named by the
+// configured naming scheme, or the name set by container_name otherwise
+func (s *composeService) getContainerNameWithoutProject(c moby.Container) string {
 	project := c.Labels[api.ProjectLabel]
-	defaultName := getDefaultContainerName(project, c.Labels[api.ServiceLabel], c.Labels[api.ContainerNumberLabel])
 	name := getCanonicalContainerName(c)
-	if name != defaultName {
+	number, err := strconv.Atoi(c.Labels[api.ContainerNumberLabel])
+	if err != nil || name != s.containerNamer().ContainerName(project, c.Labels[api.ServiceLabel], number) {
 		// service declares a custom container_name
 		return name
 	}
-	return name[len(project)+1:]
+	return strings.TrimPrefix(name, project+api.Separator)
 }
 
 // projectFromName builds a types.Project based on actual resources with compose labels set
@@ -286,6 +305,9 @@ var swarmEnabled = struct {
 }{}
 
 func (s *composeService) isSWarmEnabled(ctx context.Context) (bool, error) {
+	if !s.containerRuntime().Swarm {
+		return false, nil
+	}
 	swarmEnabled.once.Do(func() {
 		info, err := s.apiClient().Info(ctx)
 		if err != nil {
@@ -0,0 +1,529 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composetest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/errdefs"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+type containerState struct {
+	moby.ContainerJSON
+	waiters []waiter
+}
+
+type waiter struct {
+	condition container.WaitCondition
+	ch        chan container.WaitResponse
+}
+
+// ContainerCreate implements client.APIClient
+func (e *Engine) ContainerCreate(_ context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, _ *ocispec.Platform, name string) (container.CreateResponse, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	id := newID()
+	if name == "" {
+		name = "composetest_" + id[:12]
+	}
+	if c, ok := e.findContainer(name); ok {
+		return container.CreateResponse{}, errdefs.Conflict(fmt.Errorf("Conflict. The container name %q is already in use by container %q. "+
+			"You have to remove (or rename) that container to be able to reuse that name.", "/"+name, c.ID))
+	}
+	if config == nil {
+		config = &container.Config{}
+	}
+	if hostConfig == nil {
+		hostConfig = &container.HostConfig{}
+	}
+	img, ok := e.findImage(config.Image)
+	if !ok {
+		return container.CreateResponse{}, errdefs.NotFound(fmt.Errorf("No such image: %s", config.Image))
+	}
+
+	endpoints := map[string]*network.EndpointSettings{}
+	if networkingConfig != nil {
+		for name, settings := range networkingConfig.EndpointsConfig {
+			endpoints[name] = settings
+		}
+	}
+	if len(endpoints) == 0 && (hostConfig.NetworkMode == "" || hostConfig.NetworkMode.IsDefault()) {
+		endpoints["bridge"] = &network.EndpointSettings{}
+	}
+	networks := map[string]*network.EndpointSettings{}
+	for name, settings := range endpoints {
+		n, ok := e.findNetwork(name)
+		if !ok {
+			return container.CreateResponse{}, errdefs.NotFound(fmt.Errorf("network %s not found", name))
+		}
+		endpoint := network.EndpointSettings{}
+		if settings != nil {
+			endpoint = *settings
+		}
+		endpoint.NetworkID = n.ID
+		networks[n.Name] = &endpoint
+	}
+
+	mounts, err := e.createMounts(hostConfig)
+	if err != nil {
+		return container.CreateResponse{}, err
+	}
+
+	c := &containerState{
+		ContainerJSON: moby.ContainerJSON{
+			ContainerJSONBase: &moby.ContainerJSONBase{
+				ID:      id,
+				Created: time.Now().UTC().Format(time.RFC3339Nano),
+				Path:    strings.Join(config.Entrypoint, " "),
+				Args:    config.Cmd,
+				State: &moby.ContainerState{
+					Status: "created",
+				},
+				Image:      img.ID,
+				Name:       "/" + name,
+				Driver:     "overlay2",
+				Platform:   "linux",
+				HostConfig: hostConfig,
+			},
+			Mounts: mounts,
+			Config: config,
+			NetworkSettings: &moby.NetworkSettings{
+				Networks: networks,
+			},
+		},
+	}
+	e.containers[id] = c
+	e.emitContainer(c, events.ActionCreate)
+	return container.CreateResponse{ID: id}, nil
+}
+
+// createMounts resolves container mounts, creating the volumes which don't exist yet
+func (e *Engine) createMounts(hostConfig *container.HostConfig) ([]moby.MountPoint, error) {
+	var mounts []moby.MountPoint
+	for _, bind := range hostConfig.Binds {
+		parts := strings.Split(bind, ":")
+		if len(parts) < 2 {
+			return nil, errdefs.InvalidParameter(fmt.Errorf("invalid volume specification: %q", bind))
+		}
+		m := mount.Mount{Type: mount.TypeBind, Source: parts[0], Target: parts[1]}
+		if len(parts) > 2 {
+			m.ReadOnly = strings.Contains(parts[2], "ro")
+		}
+		if !strings.HasPrefix(m.Source, "/") {
+			m.Type = mount.TypeVolume
+		}
+		mounts = append(mounts, e.createMount(m))
+	}
+	for _, m := range hostConfig.Mounts {
+		mounts = append(mounts, e.createMount(m))
+	}
+	return mounts, nil
+}
+
+func (e *Engine) createMount(m mount.Mount) moby.MountPoint {
+	point := moby.MountPoint{
+		Type:        m.Type,
+		Source:      m.Source,
+		Destination: m.Target,
+		RW:          !m.ReadOnly,
+	}
+	if m.Type != mount.TypeVolume {
+		return point
+	}
+	v, ok := e.volumes[m.Source]
+	if !ok {
+		var labels map[string]string
+		if m.VolumeOptions != nil {
+			labels = m.VolumeOptions.Labels
+		}
+		v = e.createVolume(m.Source, labels, m.Source == "")
+	}
+	point.Name = v.Name
+	point.Source = v.Mountpoint
+	point.Driver = v.Driver
+	return point
+}
+
+// ContainerInspect implements client.APIClient
+func (e *Engine) ContainerInspect(_ context.Context, ref string) (moby.ContainerJSON, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	c, ok := e.findContainer(ref)
+	if !ok {
+		return moby.ContainerJSON{}, containerNotFound(ref)
+	}
+	return c.inspect(), nil
+}
+
+// ContainerList implements client.APIClient, supporting `label`, `name`, `id` and `status` filters
+func (e *Engine) ContainerList(_ context.Context, options container.ListOptions) ([]moby.Container, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var list []moby.Container
+	for _, c := range e.containers {
+		if !options.All && !c.State.Running && !options.Filters.Contains("status") {
+			continue
+		}
+		if !matchLabels(c.Config.Labels, options.Filters) || !matchName(c.Name, c.ID, options.Filters) {
+			continue
+		}
+		if options.Filters.Contains("status") && !options.Filters.ExactMatch("status", c.State.Status) {
+			continue
+		}
+		list = append(list, c.summary())
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Created > list[j].Created
+	})
+	return list, nil
+}
+
+// ContainerStart implements client.APIClient
+func (e *Engine) ContainerStart(_ context.Context, ref string, _ container.StartOptions) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	c, ok := e.findContainer(ref)
+	if !ok {
+		return containerNotFound(ref)
+	}
+	if c.State.Running {
+		return nil
+	}
+	for name, endpoint := range c.NetworkSettings.Networks {
+		n, ok := e.networks[endpoint.NetworkID]
+		if !ok {
+			return errdefs.NotFound(fmt.Errorf("network %s not found", name))
+		}
+		if endpoint.EndpointID == "" {
+			endpoint.EndpointID = newID()
+		}
+		n.Containers[c.ID] = moby.EndpointResource{
+			Name:       strings.TrimPrefix(c.Name, "/"),
+			EndpointID: endpoint.EndpointID,
+		}
+	}
+	c.State = &moby.ContainerState{
+		Status:    "running",
+		Running:   true,
+		Pid:       len(e.containers) + 1000,
+		StartedAt: time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	if c.Config.Healthcheck != nil && !(len(c.Config.Healthcheck.Test) > 0 && c.Config.Healthcheck.Test[0] == "NONE") {
+		c.State.Health = &moby.Health{Status: moby.Healthy}
+	}
+	e.emitContainer(c, events.ActionStart)
+	return nil
+}
+
+// ContainerStop implements client.APIClient
+func (e *Engine) ContainerStop(_ context.Context, ref string, _ container.StopOptions) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	c, ok := e.findContainer(ref)
+	if !ok {
+		return containerNotFound(ref)
+	}
+	if c.State.Running {
+		e.exit(c, 0)
+		e.emitContainer(c, events.ActionStop)
+	}
+	return nil
+}
+
+// ContainerKill implements client.APIClient
+func (e *Engine) ContainerKill(_ context.Context, ref string, _ string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	c, ok := e.findContainer(ref)
+	if !ok {
+		return containerNotFound(ref)
+	}
+	if !c.State.Running {
+		return errdefs.Conflict(fmt.Errorf("Container %s is not running", c.ID))
+	}
+	e.emitContainer(c, events.ActionKill)
+	e.exit(c, 137)
+	return nil
+}
+
+// ContainerRestart implements client.APIClient
+func (e *Engine) ContainerRestart(ctx context.Context, ref string, options container.StopOptions) error {
+	if err := e.ContainerStop(ctx, ref, options); err != nil {
+		return err
+	}
+	return e.ContainerStart(ctx, ref, container.StartOptions{})
+}
+
+// ContainerPause implements client.APIClient
+func (e *Engine) ContainerPause(_ context.Context, ref string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	c, ok := e.findContainer(ref)
+	if !ok {
+		return containerNotFound(ref)
+	}
+	if !c.State.Running {
+		return errdefs.Conflict(fmt.Errorf("Container %s is not running", c.ID))
+	}
+	c.State.Paused = true
+	c.State.Status = "paused"
+	e.emitContainer(c, events.ActionPause)
+	return nil
+}
+
+// ContainerUnpause implements client.APIClient
+func (e *Engine) ContainerUnpause(_ context.Context, ref string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	c, ok := e.findContainer(ref)
+	if !ok {
+		return containerNotFound(ref)
+	}
+	if !c.State.Paused {
+		return errdefs.Conflict(fmt.Errorf("Container %s is not paused", c.ID))
+	}
+	c.State.Paused = false
+	c.State.Status = "running"
+	e.emitContainer(c, events.ActionUnPause)
+	return nil
+}
+
+// ContainerRemove implements client.APIClient
+func (e *Engine) ContainerRemove(_ context.Context, ref string, options container.RemoveOptions) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	c, ok := e.findContainer(ref)
+	if !ok {
+		return containerNotFound(ref)
+	}
+	if c.State.Running {
+		if !options.Force {
+			return errdefs.Conflict(fmt.Errorf("cannot remove container %q: container is running: stop the container before removing or force remove", c.Name))
+		}
+		e.emitContainer(c, events.ActionKill)
+		e.exit(c, 137)
+	}
+	delete(e.containers, c.ID)
+	if options.RemoveVolumes {
+		for _, m := range c.Mounts {
+			if v, ok := e.volumes[m.Name]; ok && v.anonymous && len(e.volumeUsers(v.Name)) == 0 {
+				delete(e.volumes, v.Name)
+				e.emit(events.VolumeEventType, events.ActionDestroy, v.Name, map[string]string{"driver": v.Driver})
+			}
+		}
+	}
+	for _, w := range c.waiters {
+		w.ch <- container.WaitResponse{StatusCode: int64(c.State.ExitCode)}
+	}
+	c.waiters = nil
+	e.emitContainer(c, events.ActionDestroy)
+	return nil
+}
+
+// ContainerRename implements client.APIClient
+func (e *Engine) ContainerRename(_ context.Context, ref string, name string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	c, ok := e.findContainer(ref)
+	if !ok {
+		return containerNotFound(ref)
+	}
+	if other, ok := e.findContainer(name); ok && other.ID != c.ID {
+		return errdefs.Conflict(fmt.Errorf("Conflict. The container name %q is already in use by container %q. "+
+			"You have to remove (or rename) that container to be able to reuse that name.", "/"+name, other.ID))
+	}
+	c.Name = "/" + strings.TrimPrefix(name, "/")
+	e.emitContainer(c, events.ActionRename)
+	return nil
+}
+
+// ContainerWait implements client.APIClient
+func (e *Engine) ContainerWait(ctx context.Context, ref string, condition container.WaitCondition) (<-chan container.WaitResponse, <-chan error) {
+	result := make(chan container.WaitResponse, 1)
+	errs := make(chan error, 1)
+
+	e.mu.Lock()
+	c, ok := e.findContainer(ref)
+	if !ok {
+		e.mu.Unlock()
+		errs <- containerNotFound(ref)
+		return result, errs
+	}
+	if (condition == "" || condition == container.WaitConditionNotRunning) && !c.State.Running {
+		e.mu.Unlock()
+		result <- container.WaitResponse{StatusCode: int64(c.State.ExitCode)}
+		return result, errs
+	}
+	ch := make(chan container.WaitResponse, 1)
+	c.waiters = append(c.waiters, waiter{condition: condition, ch: ch})
+	e.mu.Unlock()
+
+	go func() {
+		select {
+		case r := <-ch:
+			result <- r
+		case <-ctx.Done():
+			errs <- ctx.Err()
+		}
+	}()
+	return result, errs
+}
+
+// Exit simulates the main process of a running container exiting with code
+func (e *Engine) Exit(ref string, code int) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	c, ok := e.findContainer(ref)
+	if !ok {
+		return containerNotFound(ref)
+	}
+	if !c.State.Running {
+		return errdefs.Conflict(fmt.Errorf("Container %s is not running", c.ID))
+	}
+	e.exit(c, code)
+	return nil
+}
+
+// exit stops container, releasing its network endpoints and notifying waiters. Must be called with e.mu held
+func (e *Engine) exit(c *containerState, code int) {
+	for _, endpoint := range c.NetworkSettings.Networks {
+		if n, ok := e.networks[endpoint.NetworkID]; ok {
+			delete(n.Containers, c.ID)
+		}
+	}
+	c.State = &moby.ContainerState{
+		Status:     "exited",
+		ExitCode:   code,
+		StartedAt:  c.State.StartedAt,
+		FinishedAt: time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	var waiters []waiter
+	for _, w := range c.waiters {
+		if w.condition == container.WaitConditionRemoved {
+			waiters = append(waiters, w)
+			continue
+		}
+		w.ch <- container.WaitResponse{StatusCode: int64(code)}
+	}
+	c.waiters = waiters
+	e.emitContainer(c, events.ActionDie)
+}
+
+// findContainer looks up a container by ID, ID prefix or name. Must be called with e.mu held
+func (e *Engine) findContainer(ref string) (*containerState, bool) {
+	if c, ok := e.containers[ref]; ok {
+		return c, true
+	}
+	name := "/" + strings.TrimPrefix(ref, "/")
+	for _, c := range e.containers {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	var found *containerState
+	for id, c := range e.containers {
+		if ref != "" && strings.HasPrefix(id, ref) {
+			if found != nil {
+				return nil, false
+			}
+			found = c
+		}
+	}
+	return found, found != nil
+}
+
+func (e *Engine) emitContainer(c *containerState, action events.Action) {
+	attributes := map[string]string{
+		"name":  strings.TrimPrefix(c.Name, "/"),
+		"image": c.Config.Image,
+	}
+	for k, v := range c.Config.Labels {
+		attributes[k] = v
+	}
+	if action == events.ActionDie {
+		attributes["exitCode"] = fmt.Sprint(c.State.ExitCode)
+	}
+	e.emit(events.ContainerEventType, action, c.ID, attributes)
+}
+
+func containerNotFound(ref string) error {
+	return errdefs.NotFound(fmt.Errorf("No such container: %s", ref))
+}
+
+// inspect returns a copy of container state, so callers can't alter it
+func (c *containerState) inspect() moby.ContainerJSON {
+	base := *c.ContainerJSONBase
+	state := *c.State
+	base.State = &state
+	networks := map[string]*network.EndpointSettings{}
+	for name, endpoint := range c.NetworkSettings.Networks {
+		copied := *endpoint
+		networks[name] = &copied
+	}
+	return moby.ContainerJSON{
+		ContainerJSONBase: &base,
+		Mounts:            append([]moby.MountPoint(nil), c.Mounts...),
+		Config:            c.Config,
+		NetworkSettings:   &moby.NetworkSettings{Networks: networks},
+	}
+}
+
+func (c *containerState) summary() moby.Container {
+	created, _ := time.Parse(time.RFC3339Nano, c.Created)
+	inspect := c.inspect()
+	return moby.Container{
+		ID:      c.ID,
+		Names:   []string{c.Name},
+		Image:   c.Config.Image,
+		ImageID: c.Image,
+		Command: strings.TrimSpace(c.Path + " " + strings.Join(c.Args, " ")),
+		Created: created.Unix(),
+		Labels:  c.Config.Labels,
+		State:   c.State.Status,
+		Status:  c.status(),
+		HostConfig: struct {
+			NetworkMode string `json:",omitempty"`
+		}{NetworkMode: string(c.HostConfig.NetworkMode)},
+		NetworkSettings: &moby.SummaryNetworkSettings{Networks: inspect.NetworkSettings.Networks},
+		Mounts:          inspect.Mounts,
+	}
+}
+
+// status is the human-readable container status, as displayed by `docker ps`
+func (c *containerState) status() string {
+	switch c.State.Status {
+	case "running":
+		return "Up"
+	case "paused":
+		return "Up (Paused)"
+	case "exited":
+		return fmt.Sprintf("Exited (%d)", c.State.ExitCode)
+	default:
+		return "Created"
+	}
+}
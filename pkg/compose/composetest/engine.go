@@ -0,0 +1,310 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package composetest provides an in-memory fake of the Docker Engine, so compose flows like up, down and
+// convergence can be exercised without a Docker daemon:
+//
+//	engine := composetest.NewEngine()
+//	engine.AddImage("nginx")
+//	cli, err := composetest.NewCli(engine)
+//	service := compose.NewComposeService(cli)
+//
+// Engine implements the subset of the engine API used by Compose to manage containers, networks, volumes and
+// images. Other methods of client.APIClient panic.
+package composetest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/flags"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/system"
+	"github.com/docker/docker/client"
+)
+
+const (
+	// APIVersion is the engine API version reported by Engine
+	APIVersion = "1.45"
+	// Host is the address of the Docker Engine used by the command.Cli created by NewCli
+	Host = "tcp://composetest.invalid:2375"
+)
+
+// Engine is an in-memory Docker Engine. It is safe for concurrent use
+type Engine struct {
+	// APIClient is nil, so that methods not implemented by Engine panic when called
+	client.APIClient
+
+	mu          sync.Mutex
+	containers  map[string]*containerState
+	networks    map[string]*moby.NetworkResource
+	volumes     map[string]*volumeState
+	images      map[string]*imageState
+	subscribers []*subscriber
+}
+
+// subscriber queues events for an Events call. The queue is unbounded, so that emit never blocks the engine nor
+// loses events while the consumer is busy
+type subscriber struct {
+	mu      sync.Mutex
+	pending []events.Message
+	notify  chan struct{}
+}
+
+func (s *subscriber) push(msg events.Message) {
+	s.mu.Lock()
+	s.pending = append(s.pending, msg)
+	s.mu.Unlock()
+	select {
+	case s.notify <- struct{}{}:
+	default:
+		// a notification is already pending, consumer will get this event along with the previous ones
+	}
+}
+
+func (s *subscriber) pop() []events.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pending := s.pending
+	s.pending = nil
+	return pending
+}
+
+var _ client.APIClient = &Engine{}
+
+// NewEngine creates an empty Engine, with the default `bridge`, `host` and `none` networks
+func NewEngine() *Engine {
+	e := &Engine{
+		containers: map[string]*containerState{},
+		networks:   map[string]*moby.NetworkResource{},
+		volumes:    map[string]*volumeState{},
+		images:     map[string]*imageState{},
+	}
+	for _, name := range []string{"bridge", "host", "none"} {
+		id := newID()
+		e.networks[id] = &moby.NetworkResource{
+			Name:       name,
+			ID:         id,
+			Driver:     name,
+			Scope:      "local",
+			Containers: map[string]moby.EndpointResource{},
+		}
+	}
+	return e
+}
+
+// NewCli creates a command.Cli using engine as API client, with output streams discarded unless set by options
+func NewCli(engine *Engine, options ...command.CLIOption) (command.Cli, error) {
+	options = append([]command.CLIOption{
+		command.WithAPIClient(engine),
+		command.WithCombinedStreams(io.Discard),
+		command.WithInputStream(io.NopCloser(strings.NewReader(""))),
+	}, options...)
+	cli, err := command.NewDockerCli(options...)
+	if err != nil {
+		return nil, err
+	}
+	err = cli.Initialize(&flags.ClientOptions{Hosts: []string{Host}})
+	return cli, err
+}
+
+func newID() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ClientVersion implements client.APIClient
+func (e *Engine) ClientVersion() string {
+	return APIVersion
+}
+
+// DaemonHost implements client.APIClient
+func (e *Engine) DaemonHost() string {
+	return Host
+}
+
+// NegotiateAPIVersion implements client.APIClient
+func (e *Engine) NegotiateAPIVersion(context.Context) {}
+
+// NegotiateAPIVersionPing implements client.APIClient
+func (e *Engine) NegotiateAPIVersionPing(moby.Ping) {}
+
+// Close implements client.APIClient
+func (e *Engine) Close() error {
+	return nil
+}
+
+// Ping implements client.APIClient
+func (e *Engine) Ping(context.Context) (moby.Ping, error) {
+	return moby.Ping{APIVersion: APIVersion, OSType: "linux"}, nil
+}
+
+// ServerVersion implements client.APIClient
+func (e *Engine) ServerVersion(context.Context) (moby.Version, error) {
+	return moby.Version{
+		Version:    "26.1.2",
+		APIVersion: APIVersion,
+		Os:         "linux",
+		Arch:       runtime.GOARCH,
+	}, nil
+}
+
+// Info implements client.APIClient
+func (e *Engine) Info(context.Context) (system.Info, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	info := system.Info{
+		ID:            "composetest",
+		Name:          "composetest",
+		ServerVersion: "26.1.2",
+		OSType:        "linux",
+		Architecture:  "x86_64",
+		NCPU:          4,
+		MemTotal:      8 << 30,
+		Containers:    len(e.containers),
+		Images:        len(e.images),
+	}
+	for _, c := range e.containers {
+		switch {
+		case c.State.Paused:
+			info.ContainersPaused++
+		case c.State.Running:
+			info.ContainersRunning++
+		default:
+			info.ContainersStopped++
+		}
+	}
+	return info, nil
+}
+
+// Events implements client.APIClient, reporting container, network and volume events as those happen
+func (e *Engine) Events(ctx context.Context, options moby.EventsOptions) (<-chan events.Message, <-chan error) {
+	messages := make(chan events.Message)
+	errs := make(chan error, 1)
+	sub := &subscriber{notify: make(chan struct{}, 1)}
+	e.mu.Lock()
+	e.subscribers = append(e.subscribers, sub)
+	e.mu.Unlock()
+	go func() {
+		defer e.unsubscribe(sub)
+		for {
+			select {
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			case <-sub.notify:
+				for _, msg := range sub.pop() {
+					if !matchEvent(msg, options.Filters) {
+						continue
+					}
+					select {
+					case messages <- msg:
+					case <-ctx.Done():
+						errs <- ctx.Err()
+						return
+					}
+				}
+			}
+		}
+	}()
+	return messages, errs
+}
+
+func (e *Engine) unsubscribe(sub *subscriber) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for i, s := range e.subscribers {
+		if s == sub {
+			e.subscribers = append(e.subscribers[:i], e.subscribers[i+1:]...)
+			return
+		}
+	}
+}
+
+// emit sends an event to subscribers. Must be called with e.mu held
+func (e *Engine) emit(typ events.Type, action events.Action, id string, attributes map[string]string) {
+	now := time.Now()
+	msg := events.Message{
+		Type:     typ,
+		Action:   action,
+		Actor:    events.Actor{ID: id, Attributes: attributes},
+		Scope:    "local",
+		Time:     now.Unix(),
+		TimeNano: now.UnixNano(),
+	}
+	for _, sub := range e.subscribers {
+		sub.push(msg)
+	}
+}
+
+func matchEvent(msg events.Message, args filters.Args) bool {
+	if args.Contains("type") && !args.ExactMatch("type", string(msg.Type)) {
+		return false
+	}
+	if args.Contains("event") && !args.ExactMatch("event", string(msg.Action)) {
+		return false
+	}
+	return matchLabels(msg.Actor.Attributes, args)
+}
+
+// matchLabels tells if labels match all the `label` filters, set as `key` or `key=value`
+func matchLabels(labels map[string]string, args filters.Args) bool {
+	for _, l := range args.Get("label") {
+		key, value, hasValue := strings.Cut(l, "=")
+		actual, ok := labels[key]
+		if !ok || (hasValue && actual != value) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchName tells if resource matches the `name` filters, as a substring, and the `id` filters, as a prefix
+func matchName(name string, id string, args filters.Args) bool {
+	if names := args.Get("name"); len(names) > 0 {
+		matched := false
+		for _, n := range names {
+			if strings.Contains(strings.TrimPrefix(name, "/"), strings.TrimPrefix(n, "/")) {
+				matched = true
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if ids := args.Get("id"); len(ids) > 0 {
+		matched := false
+		for _, i := range ids {
+			if strings.HasPrefix(id, i) {
+				matched = true
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
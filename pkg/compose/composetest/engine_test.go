@@ -0,0 +1,163 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composetest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/loader"
+	"github.com/compose-spec/compose-go/v2/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/compose"
+)
+
+func loadProject(t *testing.T, yaml string) *types.Project {
+	t.Helper()
+	project, err := loader.LoadWithContext(context.Background(), types.ConfigDetails{
+		WorkingDir:  t.TempDir(),
+		ConfigFiles: []types.ConfigFile{{Filename: "compose.yaml", Content: []byte(yaml)}},
+		Environment: types.Mapping{},
+	}, func(options *loader.Options) {
+		options.SetProjectName("test", true)
+	})
+	assert.NilError(t, err)
+	for name, s := range project.Services {
+		s.CustomLabels = map[string]string{
+			api.ProjectLabel: project.Name,
+			api.ServiceLabel: name,
+			api.VersionLabel: api.ComposeVersion,
+			api.OneoffLabel:  "False",
+		}
+		project.Services[name] = s
+	}
+	return project
+}
+
+func projectContainers(t *testing.T, engine *Engine) []moby.Container {
+	t.Helper()
+	containers, err := engine.ContainerList(context.Background(), container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", api.ProjectLabel+"=test")),
+	})
+	assert.NilError(t, err)
+	return containers
+}
+
+func TestUpDown(t *testing.T) {
+	ctx := context.Background()
+	engine := NewEngine()
+	engine.AddImage("nginx")
+	cli, err := NewCli(engine)
+	assert.NilError(t, err)
+	service := compose.NewComposeService(cli)
+
+	project := loadProject(t, `
+services:
+  web:
+    image: nginx
+    depends_on: [db]
+    volumes:
+      - data:/data
+  db:
+    image: postgres:16
+volumes:
+  data: {}
+`)
+	up := api.UpOptions{
+		Create: api.CreateOptions{Recreate: api.RecreateDiverged, RecreateDependencies: api.RecreateDiverged},
+		Start:  api.StartOptions{Project: project},
+	}
+	assert.NilError(t, service.Up(ctx, project, up))
+
+	containers := projectContainers(t, engine)
+	assert.Equal(t, len(containers), 2)
+	ids := map[string]string{}
+	for _, c := range containers {
+		assert.Equal(t, c.State, "running")
+		assert.Check(t, c.NetworkSettings.Networks["test_default"] != nil)
+		ids[c.Labels[api.ServiceLabel]] = c.ID
+	}
+	_, err = engine.VolumeInspect(ctx, "test_data")
+	assert.NilError(t, err)
+	_, _, err = engine.ImageInspectWithRaw(ctx, "postgres:16")
+	assert.NilError(t, err, "missing image is expected to be pulled")
+
+	// running up again with an unchanged model must not recreate containers
+	assert.NilError(t, service.Up(ctx, project, up))
+	for _, c := range projectContainers(t, engine) {
+		assert.Equal(t, c.ID, ids[c.Labels[api.ServiceLabel]])
+	}
+
+	assert.NilError(t, service.Down(ctx, project.Name, api.DownOptions{Project: project, Volumes: true}))
+	assert.Equal(t, len(projectContainers(t, engine)), 0)
+	_, err = engine.NetworkInspect(ctx, "test_default", moby.NetworkInspectOptions{})
+	assert.ErrorContains(t, err, "not found")
+	volumes, err := engine.VolumeList(ctx, volume.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(volumes.Volumes), 0)
+}
+
+func TestContainerWait(t *testing.T) {
+	ctx := context.Background()
+	engine := NewEngine()
+	engine.AddImage("alpine")
+	created, err := engine.ContainerCreate(ctx, &container.Config{Image: "alpine"}, nil, nil, nil, "test")
+	assert.NilError(t, err)
+	assert.NilError(t, engine.ContainerStart(ctx, "test", container.StartOptions{}))
+
+	result, errs := engine.ContainerWait(ctx, created.ID, container.WaitConditionNotRunning)
+	assert.NilError(t, engine.Exit(created.ID, 3))
+	select {
+	case r := <-result:
+		assert.Equal(t, r.StatusCode, int64(3))
+	case err := <-errs:
+		t.Fatal(err)
+	}
+
+	err = engine.ContainerKill(ctx, created.ID, "SIGKILL")
+	assert.ErrorContains(t, err, "is not running")
+	_, err = engine.ContainerCreate(ctx, &container.Config{Image: "alpine"}, nil, nil, nil, "test")
+	assert.ErrorContains(t, err, "is already in use")
+	_, err = engine.ContainerCreate(ctx, &container.Config{Image: "unknown"}, nil, nil, nil, "other")
+	assert.ErrorContains(t, err, "No such image")
+}
+
+func TestEventsNotDropped(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	engine := NewEngine()
+	messages, _ := engine.Events(ctx, moby.EventsOptions{})
+
+	// emit more events than a buffered channel would hold before consuming any
+	const count = 500
+	for i := 0; i < count; i++ {
+		_, err := engine.VolumeCreate(ctx, volume.CreateOptions{Name: fmt.Sprintf("volume-%d", i)})
+		assert.NilError(t, err)
+	}
+	for i := 0; i < count; i++ {
+		msg := <-messages
+		assert.Equal(t, msg.Actor.ID, fmt.Sprintf("volume-%d", i))
+	}
+}
@@ -0,0 +1,227 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composetest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/distribution/reference"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+type imageState struct {
+	moby.ImageInspect
+	labels map[string]string
+}
+
+// AddImage makes an image available in engine under ref, as if it was pulled or built, and returns the image ID
+func (e *Engine) AddImage(ref string) string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.addImage(ref)
+}
+
+// addImage must be called with e.mu held
+func (e *Engine) addImage(ref string) string {
+	if img, ok := e.findImage(ref); ok {
+		return img.ID
+	}
+	id := "sha256:" + newID()
+	img := &imageState{
+		ImageInspect: moby.ImageInspect{
+			ID:           id,
+			RepoTags:     []string{normalizeImageRef(ref)},
+			RepoDigests:  []string{},
+			Created:      time.Now().UTC().Format(time.RFC3339Nano),
+			Config:       &container.Config{},
+			Architecture: runtime.GOARCH,
+			Os:           "linux",
+		},
+	}
+	if named, err := reference.ParseNormalizedNamed(ref); err == nil {
+		if canonical, ok := named.(reference.Canonical); ok {
+			img.RepoTags = []string{}
+			img.RepoDigests = []string{reference.FamiliarName(canonical) + "@" + canonical.Digest().String()}
+		}
+	}
+	e.images[id] = img
+	e.emit(events.ImageEventType, events.ActionPull, ref, map[string]string{"name": ref})
+	return id
+}
+
+// ImageInspectWithRaw implements client.APIClient
+func (e *Engine) ImageInspectWithRaw(_ context.Context, ref string) (moby.ImageInspect, []byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	img, ok := e.findImage(ref)
+	if !ok {
+		return moby.ImageInspect{}, nil, imageNotFound(ref)
+	}
+	raw, err := json.Marshal(img.ImageInspect)
+	return img.ImageInspect, raw, err
+}
+
+// ImageList implements client.APIClient, supporting `label` filters
+func (e *Engine) ImageList(_ context.Context, options image.ListOptions) ([]image.Summary, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var list []image.Summary
+	for _, img := range e.images {
+		if !matchLabels(img.labels, options.Filters) {
+			continue
+		}
+		created, _ := time.Parse(time.RFC3339Nano, img.Created)
+		list = append(list, image.Summary{
+			ID:          img.ID,
+			RepoTags:    img.RepoTags,
+			RepoDigests: img.RepoDigests,
+			Created:     created.Unix(),
+			Labels:      img.labels,
+			Size:        img.Size,
+			Containers:  -1,
+			SharedSize:  -1,
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Created > list[j].Created
+	})
+	return list, nil
+}
+
+// ImagePull implements client.APIClient, making the image available as soon as it is called
+func (e *Engine) ImagePull(_ context.Context, ref string, _ image.PullOptions) (io.ReadCloser, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return nil, errdefs.InvalidParameter(err)
+	}
+	named = reference.TagNameOnly(named)
+	e.addImage(ref)
+
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	tag := "latest"
+	if tagged, ok := named.(reference.Tagged); ok {
+		tag = tagged.Tag()
+	}
+	for _, msg := range []jsonmessage.JSONMessage{
+		{Status: "Pulling from " + reference.Path(named), ID: tag},
+		{Status: "Status: Downloaded newer image for " + reference.FamiliarString(named)},
+	} {
+		if err := enc.Encode(msg); err != nil {
+			return nil, err
+		}
+	}
+	return io.NopCloser(&out), nil
+}
+
+// ImageRemove implements client.APIClient
+func (e *Engine) ImageRemove(_ context.Context, ref string, options image.RemoveOptions) ([]image.DeleteResponse, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	img, ok := e.findImage(ref)
+	if !ok {
+		return nil, imageNotFound(ref)
+	}
+	if !options.Force {
+		for _, c := range e.containers {
+			if c.Image == img.ID {
+				return nil, errdefs.Conflict(fmt.Errorf("conflict: unable to remove repository reference %q (must force) - container %s is using its referenced image %s",
+					ref, c.ID[:12], strings.TrimPrefix(img.ID, "sha256:")[:12]))
+			}
+		}
+	}
+
+	var deleted []image.DeleteResponse
+	tag := normalizeImageRef(ref)
+	for i, t := range img.RepoTags {
+		if t == tag {
+			img.RepoTags = append(img.RepoTags[:i:i], img.RepoTags[i+1:]...)
+			deleted = append(deleted, image.DeleteResponse{Untagged: t})
+			e.emit(events.ImageEventType, events.ActionUnTag, img.ID, map[string]string{"name": t})
+			break
+		}
+	}
+	if len(deleted) > 0 && len(img.RepoTags) > 0 {
+		// image is still referenced by other tags
+		return deleted, nil
+	}
+	delete(e.images, img.ID)
+	e.emit(events.ImageEventType, events.ActionDelete, img.ID, map[string]string{"name": ref})
+	return append(deleted, image.DeleteResponse{Deleted: img.ID}), nil
+}
+
+// findImage looks up an image by reference, ID or ID prefix. Must be called with e.mu held
+func (e *Engine) findImage(ref string) (*imageState, bool) {
+	tag := normalizeImageRef(ref)
+	for _, img := range e.images {
+		for _, t := range img.RepoTags {
+			if t == tag {
+				return img, true
+			}
+		}
+		for _, d := range img.RepoDigests {
+			if d == tag {
+				return img, true
+			}
+		}
+	}
+	id := strings.TrimPrefix(ref, "sha256:")
+	if id == "" {
+		return nil, false
+	}
+	var found *imageState
+	for _, img := range e.images {
+		if strings.HasPrefix(strings.TrimPrefix(img.ID, "sha256:"), id) {
+			if found != nil {
+				return nil, false
+			}
+			found = img
+		}
+	}
+	return found, found != nil
+}
+
+// normalizeImageRef converts ref to the familiar form used in RepoTags, e.g. `nginx` to `nginx:latest`
+func normalizeImageRef(ref string) string {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return ref
+	}
+	if _, ok := named.(reference.Canonical); ok {
+		return reference.FamiliarString(named)
+	}
+	return reference.FamiliarString(reference.TagNameOnly(named))
+}
+
+func imageNotFound(ref string) error {
+	return errdefs.NotFound(fmt.Errorf("No such image: %s", ref))
+}
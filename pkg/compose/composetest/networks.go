@@ -0,0 +1,205 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composetest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/errdefs"
+)
+
+// NetworkCreate implements client.APIClient
+func (e *Engine) NetworkCreate(_ context.Context, name string, options moby.NetworkCreate) (moby.NetworkCreateResponse, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, n := range e.networks {
+		if n.Name == name {
+			return moby.NetworkCreateResponse{}, errdefs.Conflict(fmt.Errorf("network with name %s already exists", name))
+		}
+	}
+	driver := options.Driver
+	if driver == "" {
+		driver = "bridge"
+	}
+	ipam := network.IPAM{Driver: "default"}
+	if options.IPAM != nil {
+		ipam = *options.IPAM
+	}
+	id := newID()
+	e.networks[id] = &moby.NetworkResource{
+		Name:       name,
+		ID:         id,
+		Created:    time.Now().UTC(),
+		Scope:      "local",
+		Driver:     driver,
+		EnableIPv6: options.EnableIPv6,
+		IPAM:       ipam,
+		Internal:   options.Internal,
+		Attachable: options.Attachable,
+		Options:    options.Options,
+		Labels:     options.Labels,
+		Containers: map[string]moby.EndpointResource{},
+	}
+	e.emit(events.NetworkEventType, events.ActionCreate, id, map[string]string{"name": name, "type": driver})
+	return moby.NetworkCreateResponse{ID: id}, nil
+}
+
+// NetworkInspect implements client.APIClient
+func (e *Engine) NetworkInspect(_ context.Context, ref string, _ moby.NetworkInspectOptions) (moby.NetworkResource, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	n, ok := e.findNetwork(ref)
+	if !ok {
+		return moby.NetworkResource{}, networkNotFound(ref)
+	}
+	return copyNetwork(n), nil
+}
+
+// NetworkList implements client.APIClient, supporting `label`, `name` and `id` filters
+func (e *Engine) NetworkList(_ context.Context, options moby.NetworkListOptions) ([]moby.NetworkResource, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var list []moby.NetworkResource
+	for _, n := range e.networks {
+		if matchLabels(n.Labels, options.Filters) && matchName(n.Name, n.ID, options.Filters) {
+			list = append(list, copyNetwork(n))
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list, nil
+}
+
+// NetworkRemove implements client.APIClient
+func (e *Engine) NetworkRemove(_ context.Context, ref string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	n, ok := e.findNetwork(ref)
+	if !ok {
+		return networkNotFound(ref)
+	}
+	if isPredefinedNetwork(n.Name) {
+		return errdefs.Forbidden(fmt.Errorf("%s is a pre-defined network and cannot be removed", n.Name))
+	}
+	if len(n.Containers) > 0 {
+		return errdefs.Forbidden(fmt.Errorf("error while removing network: network %s id %s has active endpoints", n.Name, n.ID))
+	}
+	delete(e.networks, n.ID)
+	e.emit(events.NetworkEventType, events.ActionDestroy, n.ID, map[string]string{"name": n.Name, "type": n.Driver})
+	return nil
+}
+
+// NetworkConnect implements client.APIClient
+func (e *Engine) NetworkConnect(_ context.Context, ref string, containerRef string, config *network.EndpointSettings) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	n, ok := e.findNetwork(ref)
+	if !ok {
+		return networkNotFound(ref)
+	}
+	c, ok := e.findContainer(containerRef)
+	if !ok {
+		return containerNotFound(containerRef)
+	}
+	if _, ok := c.NetworkSettings.Networks[n.Name]; ok {
+		return errdefs.Forbidden(fmt.Errorf("endpoint with name %s already exists in network %s", strings.TrimPrefix(c.Name, "/"), n.Name))
+	}
+	endpoint := network.EndpointSettings{}
+	if config != nil {
+		endpoint = *config
+	}
+	endpoint.NetworkID = n.ID
+	if c.State.Running {
+		endpoint.EndpointID = newID()
+		n.Containers[c.ID] = moby.EndpointResource{
+			Name:       strings.TrimPrefix(c.Name, "/"),
+			EndpointID: endpoint.EndpointID,
+		}
+	}
+	c.NetworkSettings.Networks[n.Name] = &endpoint
+	e.emit(events.NetworkEventType, events.ActionConnect, n.ID, map[string]string{"name": n.Name, "type": n.Driver, "container": c.ID})
+	return nil
+}
+
+// NetworkDisconnect implements client.APIClient
+func (e *Engine) NetworkDisconnect(_ context.Context, ref string, containerRef string, _ bool) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	n, ok := e.findNetwork(ref)
+	if !ok {
+		return networkNotFound(ref)
+	}
+	c, ok := e.findContainer(containerRef)
+	if !ok {
+		return containerNotFound(containerRef)
+	}
+	if _, ok := c.NetworkSettings.Networks[n.Name]; !ok {
+		return errdefs.Forbidden(fmt.Errorf("container %s is not connected to network %s", c.ID, n.Name))
+	}
+	delete(c.NetworkSettings.Networks, n.Name)
+	delete(n.Containers, c.ID)
+	e.emit(events.NetworkEventType, events.ActionDisconnect, n.ID, map[string]string{"name": n.Name, "type": n.Driver, "container": c.ID})
+	return nil
+}
+
+// findNetwork looks up a network by ID, name or ID prefix. Must be called with e.mu held
+func (e *Engine) findNetwork(ref string) (*moby.NetworkResource, bool) {
+	if n, ok := e.networks[ref]; ok {
+		return n, true
+	}
+	for _, n := range e.networks {
+		if n.Name == ref {
+			return n, true
+		}
+	}
+	var found *moby.NetworkResource
+	for id, n := range e.networks {
+		if ref != "" && strings.HasPrefix(id, ref) {
+			if found != nil {
+				return nil, false
+			}
+			found = n
+		}
+	}
+	return found, found != nil
+}
+
+func networkNotFound(ref string) error {
+	return errdefs.NotFound(fmt.Errorf("network %s not found", ref))
+}
+
+func isPredefinedNetwork(name string) bool {
+	return name == "bridge" || name == "host" || name == "none"
+}
+
+// copyNetwork returns a copy of n, so callers can't alter engine state
+func copyNetwork(n *moby.NetworkResource) moby.NetworkResource {
+	copied := *n
+	copied.Containers = map[string]moby.EndpointResource{}
+	for id, endpoint := range n.Containers {
+		copied.Containers[id] = endpoint
+	}
+	return copied
+}
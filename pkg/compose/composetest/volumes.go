@@ -0,0 +1,137 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composetest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+)
+
+type volumeState struct {
+	volume.Volume
+	anonymous bool
+}
+
+// VolumeCreate implements client.APIClient. As the Docker Engine does, creating an existing volume returns it
+func (e *Engine) VolumeCreate(_ context.Context, options volume.CreateOptions) (volume.Volume, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	v, ok := e.volumes[options.Name]
+	if !ok {
+		v = e.createVolume(options.Name, options.Labels, options.Name == "")
+		if options.Driver != "" {
+			v.Driver = options.Driver
+		}
+		v.Options = options.DriverOpts
+	}
+	return v.Volume, nil
+}
+
+// createVolume registers a new volume, with a generated name if none is set. Must be called with e.mu held
+func (e *Engine) createVolume(name string, labels map[string]string, anonymous bool) *volumeState {
+	if name == "" {
+		name = newID()
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	if anonymous {
+		labels["com.docker.volume.anonymous"] = ""
+	}
+	v := &volumeState{
+		Volume: volume.Volume{
+			Name:       name,
+			Driver:     "local",
+			Mountpoint: "/var/lib/docker/volumes/" + name + "/_data",
+			Scope:      "local",
+			Labels:     labels,
+			CreatedAt:  time.Now().UTC().Format(time.RFC3339),
+		},
+		anonymous: anonymous,
+	}
+	e.volumes[name] = v
+	e.emit(events.VolumeEventType, events.ActionCreate, name, map[string]string{"driver": v.Driver})
+	return v
+}
+
+// VolumeInspect implements client.APIClient
+func (e *Engine) VolumeInspect(_ context.Context, name string) (volume.Volume, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	v, ok := e.volumes[name]
+	if !ok {
+		return volume.Volume{}, volumeNotFound(name)
+	}
+	return v.Volume, nil
+}
+
+// VolumeList implements client.APIClient, supporting `label` and `name` filters
+func (e *Engine) VolumeList(_ context.Context, options volume.ListOptions) (volume.ListResponse, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	list := volume.ListResponse{Volumes: []*volume.Volume{}}
+	for _, v := range e.volumes {
+		if matchLabels(v.Labels, options.Filters) && matchName(v.Name, v.Name, options.Filters) {
+			copied := v.Volume
+			list.Volumes = append(list.Volumes, &copied)
+		}
+	}
+	sort.Slice(list.Volumes, func(i, j int) bool {
+		return list.Volumes[i].Name < list.Volumes[j].Name
+	})
+	return list, nil
+}
+
+// VolumeRemove implements client.APIClient
+func (e *Engine) VolumeRemove(_ context.Context, name string, _ bool) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	v, ok := e.volumes[name]
+	if !ok {
+		return volumeNotFound(name)
+	}
+	if users := e.volumeUsers(name); len(users) > 0 {
+		return errdefs.Conflict(fmt.Errorf("remove %s: volume is in use - %v", name, users))
+	}
+	delete(e.volumes, name)
+	e.emit(events.VolumeEventType, events.ActionDestroy, name, map[string]string{"driver": v.Driver})
+	return nil
+}
+
+// volumeUsers lists the IDs of containers mounting volume. Must be called with e.mu held
+func (e *Engine) volumeUsers(name string) []string {
+	var users []string
+	for _, c := range e.containers {
+		for _, m := range c.Mounts {
+			if m.Name == name {
+				users = append(users, c.ID)
+			}
+		}
+	}
+	sort.Strings(users)
+	return users
+}
+
+func volumeNotFound(name string) error {
+	return errdefs.NotFound(fmt.Errorf("get %s: no such volume", name))
+}
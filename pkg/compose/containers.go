@@ -96,6 +96,10 @@ func (s *composeService) getSpecifiedContainer(ctx context.Context, projectName
 	sort.Slice(containers, func(i, j int) bool {
 		x, _ := strconv.Atoi(containers[i].Labels[api.ContainerNumberLabel])
 		y, _ := strconv.Atoi(containers[j].Labels[api.ContainerNumberLabel])
+		if x == y {
+			// one-off containers created by `run` also have number 1, prefer service replica
+			return isNotOneOff(containers[i]) && !isNotOneOff(containers[j])
+		}
 		return x < y
 	})
 	container := containers[0]
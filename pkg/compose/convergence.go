@@ -30,13 +30,10 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/compose-spec/compose-go/v2/types"
-	"github.com/containerd/containerd/platforms"
 	"github.com/docker/compose/v2/internal/tracing"
 	moby "github.com/docker/docker/api/types"
 	containerType "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/versions"
-	specs "github.com/opencontainers/image-spec/specs-go/v1"
-	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/docker/compose/v2/pkg/api"
@@ -147,6 +144,9 @@ func (c *convergence) ensureService(ctx context.Context, project *types.Project,
 		// If we don't get a container number (?) just sort by creation date
 		return containers[i].Created < containers[j].Created
 	})
+
+	rolling := getRollingUpdate(service)
+	var rollingIndexes []int
 	for i, container := range containers {
 		if i >= expected {
 			// Scale Down
@@ -162,6 +162,10 @@ func (c *convergence) ensureService(ctx context.Context, project *types.Project,
 		if err != nil {
 			return err
 		}
+		if mustRecreate && rolling != nil && container.State == ContainerRunning {
+			rollingIndexes = append(rollingIndexes, i)
+			continue
+		}
 		if mustRecreate {
 			i, container := i, container
 			eg.Go(tracing.SpanWrapFuncForErrGroup(ctx, "container/recreate", tracing.ContainerOptions(container), func(ctx context.Context) error {
@@ -175,6 +179,10 @@ func (c *convergence) ensureService(ctx context.Context, project *types.Project,
 		// Enforce non-diverged containers are running
 		w := progress.ContextWriter(ctx)
 		name := getContainerProgressName(container)
+		current := container
+		eg.Go(func() error {
+			return c.service.updateRestartPolicy(ctx, service, current)
+		})
 		switch container.State {
 		case ContainerRunning:
 			w.Event(progress.RunningEvent(name))
@@ -191,11 +199,11 @@ func (c *convergence) ensureService(ctx context.Context, project *types.Project,
 		updated[i] = container
 	}
 
-	next := nextContainerNumber(containers)
+	next := nextContainerNumber(ctx, containers)
 	for i := 0; i < expected-actual; i++ {
 		// Scale UP
 		number := next + i
-		name := getContainerName(project.Name, service, number)
+		name := getContainerName(c.service.containerNamer(), project.Name, service, number)
 		i := i
 		eventOpts := tracing.SpanOptions{trace.WithAttributes(attribute.String("container.name", name))}
 		eg.Go(tracing.EventWrapFuncForErrGroup(ctx, "service/scale/up", eventOpts, func(ctx context.Context) error {
@@ -212,6 +220,12 @@ func (c *convergence) ensureService(ctx context.Context, project *types.Project,
 		continue
 	}
 
+	if len(rollingIndexes) > 0 {
+		eg.Go(tracing.SpanWrapFuncForErrGroup(ctx, "service/rolling-update", tracing.ServiceOptions(service), func(ctx context.Context) error {
+			return c.rollingUpdate(ctx, project, service, *rolling, containers, rollingIndexes, updated, inherit, timeout)
+		}))
+	}
+
 	err = eg.Wait()
 	c.setObservedState(service.Name, updated)
 	return err
@@ -293,31 +307,80 @@ func (c *convergence) resolveSharedNamespaces(service *types.ServiceConfig) erro
 }
 
 func mustRecreate(expected types.ServiceConfig, actual moby.Container, policy string) (bool, error) {
+	reason, err := recreateReason(expected, actual, policy)
+	return reason != "", err
+}
+
+// recreateReason explains why actual container has to be recreated to match expected service configuration, or
+// returns an empty string if container is up-to-date
+func recreateReason(expected types.ServiceConfig, actual moby.Container, policy string) (string, error) {
 	if policy == api.RecreateNever {
-		return false, nil
+		return "", nil
+	}
+	if policy == api.RecreateForce {
+		return "recreate forced", nil
 	}
-	if policy == api.RecreateForce || expected.Extensions[extLifecycle] == forceRecreate {
-		return true, nil
+	if expected.Extensions[extLifecycle] == forceRecreate {
+		return "dependency recreated", nil
 	}
 	configHash, err := ServiceHash(expected)
 	if err != nil {
-		return false, err
+		return "", err
 	}
-	configChanged := actual.Labels[api.ConfigHashLabel] != configHash
-	imageUpdated := actual.Labels[api.ImageDigestLabel] != expected.CustomLabels[api.ImageDigestLabel]
-	return configChanged || imageUpdated, nil
+	var reasons []string
+	if actual.Labels[api.ConfigHashLabel] != configHash {
+		legacyHash, err := legacyServiceHash(expected)
+		if err != nil {
+			return "", err
+		}
+		if actual.Labels[api.ConfigHashLabel] != legacyHash {
+			reasons = append(reasons, "configuration changed")
+		}
+	}
+	if digest := expected.CustomLabels[api.ImageDigestLabel]; actual.Labels[api.ImageDigestLabel] != digest {
+		if digest == "" {
+			reasons = append(reasons, "image will be pulled or built")
+		} else {
+			reasons = append(reasons, "image changed")
+		}
+	}
+	return strings.Join(reasons, ", "), nil
 }
 
-func getContainerName(projectName string, service types.ServiceConfig, number int) string {
-	name := getDefaultContainerName(projectName, service.Name, strconv.Itoa(number))
-	if service.ContainerName != "" {
-		name = service.ContainerName
+// updateRestartPolicy applies service restart policy to an up-to-date container, if it changed since creation
+func (s *composeService) updateRestartPolicy(ctx context.Context, service types.ServiceConfig, actual moby.Container) error {
+	inspected, err := s.apiClient().ContainerInspect(ctx, actual.ID)
+	if err != nil {
+		return err
+	}
+	if inspected.ContainerJSONBase == nil || inspected.HostConfig == nil {
+		return nil
+	}
+	expected := getRestartPolicy(service)
+	if expected.Name == "" {
+		expected.Name = containerType.RestartPolicyDisabled
+	}
+	current := inspected.HostConfig.RestartPolicy
+	if current.Name == "" {
+		current.Name = containerType.RestartPolicyDisabled
 	}
-	return name
+	if current == expected {
+		return nil
+	}
+	_, err = s.apiClient().ContainerUpdate(ctx, actual.ID, containerType.UpdateConfig{RestartPolicy: expected})
+	if err != nil {
+		return err
+	}
+	w := progress.ContextWriter(ctx)
+	w.Event(progress.NewEvent(getContainerProgressName(actual), progress.Done, "Restart policy updated"))
+	return nil
 }
 
-func getDefaultContainerName(projectName, serviceName, index string) string {
-	return strings.Join([]string{projectName, serviceName, index}, api.Separator)
+func getContainerName(namer api.ContainerNamer, projectName string, service types.ServiceConfig, number int) string {
+	if service.ContainerName != "" {
+		return service.ContainerName
+	}
+	return namer.ContainerName(projectName, service.Name, number)
 }
 
 func getContainerProgressName(container moby.Container) string {
@@ -360,12 +423,21 @@ func (s *composeService) waitDependencies(ctx context.Context, project *types.Pr
 			if config.Required {
 				return fmt.Errorf("%s is missing dependency %s", dependant, dep)
 			}
-			logrus.Warnf("%s is missing dependency %s", dependant, dep)
+			api.Warn(ctx, api.Warning{
+				Code:    api.WarningDependency,
+				Service: dependant,
+				Message: fmt.Sprintf("%s is missing dependency %s", dependant, dep),
+			})
 			continue
 		}
 
+		probe, err := getDependencyProbe(dep, config)
+		if err != nil {
+			return err
+		}
+
 		dep, config := dep, config
-		eg.Go(func() error {
+		waitCondition := func() error {
 			ticker := time.NewTicker(500 * time.Millisecond)
 			defer ticker.Stop()
 			for {
@@ -380,7 +452,11 @@ func (s *composeService) waitDependencies(ctx context.Context, project *types.Pr
 					if err != nil {
 						if !config.Required {
 							w.Events(containerReasonEvents(waitingFor, progress.SkippedEvent, fmt.Sprintf("optional dependency %q is not running or is unhealthy", dep)))
-							logrus.Warnf("optional dependency %q is not running or is unhealthy: %s", dep, err.Error())
+							api.Warn(ctx, api.Warning{
+								Code:    api.WarningDependency,
+								Service: dependant,
+								Message: fmt.Sprintf("optional dependency %q is not running or is unhealthy: %s", dep, err.Error()),
+							})
 							return nil
 						}
 						return err
@@ -394,7 +470,11 @@ func (s *composeService) waitDependencies(ctx context.Context, project *types.Pr
 					if err != nil {
 						if !config.Required {
 							w.Events(containerReasonEvents(waitingFor, progress.SkippedEvent, fmt.Sprintf("optional dependency %q failed to start", dep)))
-							logrus.Warnf("optional dependency %q failed to start: %s", dep, err.Error())
+							api.Warn(ctx, api.Warning{
+								Code:    api.WarningDependency,
+								Service: dependant,
+								Message: fmt.Sprintf("optional dependency %q failed to start: %s", dep, err.Error()),
+							})
 							return nil
 						}
 						w.Events(containerEvents(waitingFor, progress.ErrorEvent))
@@ -419,7 +499,11 @@ func (s *composeService) waitDependencies(ctx context.Context, project *types.Pr
 						if !config.Required {
 							// optional -> mark as skipped & don't propagate error
 							w.Events(containerReasonEvents(waitingFor, progress.SkippedEvent, fmt.Sprintf("optional dependency %s", messageSuffix)))
-							logrus.Warnf("optional dependency %s", messageSuffix)
+							api.Warn(ctx, api.Warning{
+								Code:    api.WarningDependency,
+								Service: dependant,
+								Message: fmt.Sprintf("optional dependency %s", messageSuffix),
+							})
 							return nil
 						}
 
@@ -427,18 +511,36 @@ func (s *composeService) waitDependencies(ctx context.Context, project *types.Pr
 						w.Events(containerReasonEvents(waitingFor, progress.ErrorMessageEvent, msg))
 						return errors.New(msg)
 					}
+				case types.ServiceConditionStarted:
+					// only waiting for a probe, dependency already started by InDependencyOrder
+					return nil
 				default:
-					logrus.Warnf("unsupported depends_on condition: %s", config.Condition)
+					api.Warn(ctx, api.Warning{
+						Code:      api.WarningIgnoredAttribute,
+						Service:   dependant,
+						Attribute: "depends_on",
+						Message:   fmt.Sprintf("condition %s is not supported", config.Condition),
+					})
 					return nil
 				}
 			}
+		}
+		eg.Go(func() error {
+			if err := waitCondition(); err != nil || probe == nil {
+				return err
+			}
+			var dnsNames map[string][]string
+			if probe.DNS {
+				dnsNames = dependencyDNSNames(project, dependant, dep)
+			}
+			return s.waitDependencyProbe(ctx, dep, config, *probe, dnsNames, waitingFor)
 		})
 	}
 	return eg.Wait()
 }
 
 func shouldWaitForDependency(serviceName string, dependencyConfig types.ServiceDependency, project *types.Project) (bool, error) {
-	if dependencyConfig.Condition == types.ServiceConditionStarted {
+	if dependencyConfig.Condition == types.ServiceConditionStarted && !hasDependencyProbe(dependencyConfig) {
 		// already managed by InDependencyOrder
 		return false, nil
 	}
@@ -457,16 +559,22 @@ func shouldWaitForDependency(serviceName string, dependencyConfig types.ServiceD
 	return true, nil
 }
 
-func nextContainerNumber(containers []moby.Container) int {
+func nextContainerNumber(ctx context.Context, containers []moby.Container) int {
 	max := 0
 	for _, c := range containers {
 		s, ok := c.Labels[api.ContainerNumberLabel]
 		if !ok {
-			logrus.Warnf("container %s is missing %s label", c.ID, api.ContainerNumberLabel)
+			api.Warn(ctx, api.Warning{
+				Code:    api.WarningInvalidLabel,
+				Message: fmt.Sprintf("container %s is missing %s label", c.ID, api.ContainerNumberLabel),
+			})
 		}
 		n, err := strconv.Atoi(s)
 		if err != nil {
-			logrus.Warnf("container %s has invalid %s label: %s", c.ID, api.ContainerNumberLabel, s)
+			api.Warn(ctx, api.Warning{
+				Code:    api.WarningInvalidLabel,
+				Message: fmt.Sprintf("container %s has invalid %s label: %s", c.ID, api.ContainerNumberLabel, s),
+			})
 			continue
 		}
 		if n > max {
@@ -486,6 +594,9 @@ func (s *composeService) createContainer(ctx context.Context, project *types.Pro
 	if err != nil {
 		return
 	}
+	// only containers without a predecessor are recorded, rolling back a replacement would leave the service without
+	// a container
+	recordCreated(ctx, containerResource, container.ID, name)
 	w.Event(progress.CreatedEvent(eventName))
 	return
 }
@@ -505,7 +616,7 @@ func (s *composeService) recreateContainer(ctx context.Context, project *types.P
 	if inherit {
 		inherited = &replaced
 	}
-	name := getContainerName(project.Name, service, number)
+	name := getContainerName(s.containerNamer(), project.Name, service, number)
 	tmpName := fmt.Sprintf("%s_%s", replaced.ID[:12], name)
 	opts := createOptions{
 		AutoRemove:        false,
@@ -581,18 +692,9 @@ func (s *composeService) createMobyContainer(ctx context.Context,
 	if err != nil {
 		return created, err
 	}
-	platform := service.Platform
-	if platform == "" {
-		platform = project.Environment["DOCKER_DEFAULT_PLATFORM"]
-	}
-	var plat *specs.Platform
-	if platform != "" {
-		var p specs.Platform
-		p, err = platforms.Parse(platform)
-		if err != nil {
-			return created, err
-		}
-		plat = &p
+	plat, err := servicePlatform(project, service)
+	if err != nil {
+		return created, err
 	}
 
 	response, err := s.apiClient().ContainerCreate(ctx, cfgs.Container, cfgs.Host, cfgs.Network, plat, name)
@@ -636,7 +738,7 @@ func (s *composeService) createMobyContainer(ctx context.Context,
 				// primary network already configured as part of ContainerCreate
 				continue
 			}
-			epSettings := createEndpointSettings(project, service, number, networkKey, cfgs.Links, opts.UseNetworkAliases)
+			epSettings := createEndpointSettings(s.containerNamer(), project, service, number, networkKey, cfgs.Links, opts.UseNetworkAliases)
 			if err := s.apiClient().NetworkConnect(ctx, mobyNetworkName, created.ID, epSettings); err != nil {
 				return created, err
 			}
@@ -783,7 +885,7 @@ func (s *composeService) startService(ctx context.Context, project *types.Projec
 		w.Event(progress.StartingEvent(eventName))
 		err := s.apiClient().ContainerStart(ctx, container.ID, containerType.StartOptions{})
 		if err != nil {
-			return err
+			return deviceDriverError(service, err)
 		}
 		w.Event(progress.StartedEvent(eventName))
 	}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/docker/cli/cli/config/configfile"
@@ -29,6 +30,7 @@ import (
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/go-connections/nat"
+	"github.com/google/go-cmp/cmp"
 	"go.uber.org/mock/gomock"
 	"gotest.tools/v3/assert"
 
@@ -434,3 +436,147 @@ func TestCreateMobyContainer(t *testing.T) {
 	})
 
 }
+
+func TestGetRollingUpdate(t *testing.T) {
+	service := types.ServiceConfig{Name: "web"}
+	assert.Check(t, getRollingUpdate(service) == nil)
+
+	service.Deploy = &types.DeployConfig{UpdateConfig: &types.UpdateConfig{}}
+	assert.DeepEqual(t, *getRollingUpdate(service), rollingUpdate{parallelism: 1, startFirst: true}, cmp.AllowUnexported(rollingUpdate{}))
+
+	parallelism := uint64(2)
+	service.Deploy.UpdateConfig = &types.UpdateConfig{
+		Parallelism: &parallelism,
+		Delay:       types.Duration(time.Second),
+		Monitor:     types.Duration(time.Minute),
+	}
+	assert.DeepEqual(t, *getRollingUpdate(service), rollingUpdate{parallelism: 2, delay: time.Second, monitor: time.Minute, startFirst: true},
+		cmp.AllowUnexported(rollingUpdate{}))
+
+	service.Deploy.UpdateConfig.Order = updateOrderStopFirst
+	assert.Check(t, !getRollingUpdate(service).startFirst)
+
+	// replacement can't bind the same host port while replaced container is running
+	service.Deploy.UpdateConfig.Order = ""
+	service.Ports = []types.ServicePortConfig{{Target: 80, Published: "8080"}}
+	assert.Check(t, !getRollingUpdate(service).startFirst)
+
+	service.Deploy.UpdateConfig.Order = updateOrderStartFirst
+	assert.Check(t, getRollingUpdate(service).startFirst)
+}
+
+func TestStartAndWaitHealthyMonitor(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	apiClient, cli := prepareMocks(mockCtrl)
+	tested := composeService{dockerCli: cli}
+
+	inspect := func(status string) moby.ContainerJSON {
+		return moby.ContainerJSON{
+			ContainerJSONBase: &moby.ContainerJSONBase{Name: "/myapp-web-1", State: &moby.ContainerState{Status: status}},
+			Config:            &containerType.Config{},
+		}
+	}
+	gomock.InOrder(
+		apiClient.EXPECT().ContainerStart(gomock.Any(), "123", gomock.Any()).Return(nil),
+		apiClient.EXPECT().ContainerInspect(gomock.Any(), "123").Return(inspect("running"), nil),
+		apiClient.EXPECT().ContainerInspect(gomock.Any(), "123").Return(inspect("restarting"), nil),
+	)
+
+	// container is running, but fails within the monitor period
+	err := tested.startAndWaitHealthy(context.Background(), moby.Container{ID: "123", Names: []string{"/myapp-web-1"}}, time.Minute)
+	assert.Error(t, err, "container myapp-web-1 failed while monitored")
+}
+
+func TestRecreateReasonLegacyHash(t *testing.T) {
+	service := types.ServiceConfig{Name: "web", Image: "nginx", Restart: types.RestartPolicyAlways}
+	legacy, err := legacyServiceHash(service)
+	assert.NilError(t, err)
+	reason, err := recreateReason(service, moby.Container{
+		Labels: map[string]string{api.ConfigHashLabel: legacy},
+	}, api.RecreateDiverged)
+	assert.NilError(t, err)
+	assert.Equal(t, reason, "")
+}
+
+func TestRecreateReasonPolicies(t *testing.T) {
+	service := types.ServiceConfig{Name: "web", Image: "nginx"}
+	hash, err := ServiceHash(service)
+	assert.NilError(t, err)
+	upToDate := moby.Container{Labels: map[string]string{api.ConfigHashLabel: hash}}
+	diverged := moby.Container{Labels: map[string]string{api.ConfigHashLabel: "outdated"}}
+	dependent := service
+	dependent.Extensions = types.Extensions{extLifecycle: forceRecreate}
+
+	for _, tc := range []struct {
+		name     string
+		service  types.ServiceConfig
+		actual   moby.Container
+		policy   string
+		expected string
+	}{
+		{name: "diverged up-to-date", service: service, actual: upToDate, policy: api.RecreateDiverged},
+		{name: "diverged changed", service: service, actual: diverged, policy: api.RecreateDiverged, expected: "configuration changed"},
+		{name: "diverged dependency", service: dependent, actual: upToDate, policy: api.RecreateDiverged, expected: "dependency recreated"},
+		{name: "force up-to-date", service: service, actual: upToDate, policy: api.RecreateForce, expected: "recreate forced"},
+		{name: "force changed", service: service, actual: diverged, policy: api.RecreateForce, expected: "recreate forced"},
+		{name: "never up-to-date", service: service, actual: upToDate, policy: api.RecreateNever},
+		{name: "never changed", service: service, actual: diverged, policy: api.RecreateNever},
+		{name: "never dependency", service: dependent, actual: diverged, policy: api.RecreateNever},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			reason, err := recreateReason(tc.service, tc.actual, tc.policy)
+			assert.NilError(t, err)
+			assert.Equal(t, reason, tc.expected)
+		})
+	}
+}
+
+func TestUpdateRestartPolicy(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	apiClient, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+	inspect := func(policy containerType.RestartPolicyMode) moby.ContainerJSON {
+		return moby.ContainerJSON{ContainerJSONBase: &moby.ContainerJSONBase{
+			HostConfig: &containerType.HostConfig{RestartPolicy: containerType.RestartPolicy{Name: policy}},
+		}}
+	}
+	ctx := context.Background()
+	c := testContainer("web", "123", false)
+
+	apiClient.EXPECT().ContainerInspect(gomock.Any(), "123").Return(inspect(""), nil)
+	assert.NilError(t, tested.updateRestartPolicy(ctx, types.ServiceConfig{Name: "web"}, c))
+
+	apiClient.EXPECT().ContainerInspect(gomock.Any(), "123").Return(inspect(containerType.RestartPolicyDisabled), nil)
+	apiClient.EXPECT().ContainerUpdate(gomock.Any(), "123", containerType.UpdateConfig{
+		RestartPolicy: containerType.RestartPolicy{Name: containerType.RestartPolicyUnlessStopped},
+	}).Return(containerType.ContainerUpdateOKBody{}, nil)
+	assert.NilError(t, tested.updateRestartPolicy(ctx, types.ServiceConfig{Name: "web", Restart: types.RestartPolicyUnlessStopped}, c))
+}
+
+func TestRestoreContainer(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	apiClient, cli := prepareMocks(mockCtrl)
+	tested := composeService{dockerCli: cli}
+
+	created := moby.Container{ID: "456"}
+	replaced := moby.Container{ID: "123"}
+	gomock.InOrder(
+		apiClient.EXPECT().ContainerRemove(gomock.Any(), "456", containerType.RemoveOptions{Force: true}).Return(nil),
+		apiClient.EXPECT().ContainerStart(gomock.Any(), "123", gomock.Any()).Return(nil),
+	)
+	// replaced container was stopped by a stop-first update, so gets restarted
+	assert.NilError(t, tested.restoreContainer(context.Background(), created, replaced, true))
+
+	apiClient.EXPECT().ContainerRemove(gomock.Any(), "456", containerType.RemoveOptions{Force: true}).Return(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.NilError(t, tested.restoreContainer(ctx, created, replaced, false))
+}
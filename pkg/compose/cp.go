@@ -80,11 +80,23 @@ func (s *composeService) copy(ctx context.Context, projectName string, options a
 		return errors.New("unknown copy direction")
 	}
 
+	if options.Sync {
+		if direction != toService || srcPath == "-" {
+			return errors.New("--sync only copies a local directory to a service")
+		}
+		copyFunc = s.syncToContainer
+	}
+
 	containers, err := s.listContainersTargetedForCopy(ctx, projectName, options.Index, direction, serviceName)
 	if err != nil {
 		return err
 	}
 
+	// stdin can only be consumed once, so it can't be fanned out to all replicas
+	if srcPath == "-" && len(containers) > 1 {
+		return fmt.Errorf("cannot copy from stdin to %d containers of service %q, use --index to select one", len(containers), serviceName)
+	}
+
 	w := progress.ContextWriter(ctx)
 	g := errgroup.Group{}
 	for _, cont := range containers {
@@ -0,0 +1,79 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/docker/compose/v2/internal/sync"
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// syncToContainer transfers the content of a local directory into dstPath, only sending files changed since the
+// last sync to the same container, as recorded by an index cached on host
+func (s *composeService) syncToContainer(ctx context.Context, containerID string, srcPath string, dstPath string, _ api.CopyOptions) error {
+	srcPath, err := filepath.Abs(srcPath)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(srcPath)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory, --sync requires a directory as source", srcPath)
+	}
+	if !path.IsAbs(dstPath) {
+		return fmt.Errorf("destination %q must be an absolute path with --sync", dstPath)
+	}
+
+	indexFile, err := syncIndexFile(containerID, srcPath, dstPath)
+	if err != nil {
+		return err
+	}
+	previous, err := sync.LoadIndex(indexFile)
+	if err != nil {
+		return err
+	}
+	changes, current, err := sync.Changes(srcPath, dstPath, previous)
+	if err != nil {
+		return err
+	}
+	if len(changes) > 0 {
+		syncer := sync.NewTar("", tarDockerClient{s: s})
+		if err := syncer.SyncContainers(ctx, []string{containerID}, changes); err != nil {
+			return err
+		}
+	}
+	return current.Save(indexFile)
+}
+
+// syncIndexFile is the location of the index of files synced from srcPath into a container, under user's cache
+// directory. A recreated container gets a new ID, so all files get synced again
+func syncIndexFile(containerID string, srcPath string, dstPath string) (string, error) {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	key := sha256.Sum256([]byte(containerID + "\x00" + srcPath + "\x00" + dstPath))
+	return filepath.Join(cache, "docker-compose", "sync", fmt.Sprintf("%x.json", key)), nil
+}
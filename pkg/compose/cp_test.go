@@ -0,0 +1,84 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	moby "github.com/docker/docker/api/types"
+	containerType "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	compose "github.com/docker/compose/v2/pkg/api"
+)
+
+func TestSplitCpArg(t *testing.T) {
+	tests := []struct {
+		arg       string
+		container string
+		path      string
+	}{
+		{arg: "service:/etc/hosts", container: "service", path: "/etc/hosts"},
+		{arg: "/etc/hosts", container: "", path: "/etc/hosts"},
+		{arg: "./file:name.txt", container: "", path: "./file:name.txt"},
+		{arg: "file.txt", container: "", path: "file.txt"},
+		{arg: "-", container: "", path: "-"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			container, path := splitCpArg(tt.arg)
+			assert.Equal(t, container, tt.container)
+			assert.Equal(t, path, tt.path)
+		})
+	}
+}
+
+func TestCopyFromServiceWithAll(t *testing.T) {
+	tested := composeService{}
+	err := tested.copy(context.Background(), testProject, compose.CopyOptions{
+		Source:      "service1:/etc/hosts",
+		Destination: "hosts",
+		All:         true,
+	})
+	assert.Error(t, err, "cannot use the --all flag when copying from a service")
+}
+
+func TestCopyStdinToReplicas(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	ctx := context.Background()
+	api.EXPECT().ContainerList(ctx, containerType.ListOptions{
+		Filters: filters.NewArgs(projectFilter(strings.ToLower(testProject)), serviceFilter("service1"), hasConfigHashLabel(), oneOffFilter(false)),
+		All:     true,
+	}).Return([]moby.Container{testContainer("service1", "123", false), testContainer("service1", "456", false)}, nil)
+
+	err := tested.copy(ctx, testProject, compose.CopyOptions{
+		Source:      "-",
+		Destination: "service1:/tmp",
+	})
+	assert.Error(t, err, `cannot copy from stdin to 2 containers of service "service1", use --index to select one`)
+}
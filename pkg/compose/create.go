@@ -44,7 +44,6 @@ import (
 	volume_api "github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/go-connections/nat"
-	"github.com/docker/go-units"
 	"github.com/sirupsen/logrus"
 
 	"github.com/docker/compose/v2/pkg/api"
@@ -68,7 +67,16 @@ type createConfigs struct {
 
 func (s *composeService) Create(ctx context.Context, project *types.Project, createOpts api.CreateOptions) error {
 	return progress.RunWithTitle(ctx, func(ctx context.Context) error {
-		return s.create(ctx, project, createOpts)
+		return s.withProjectLock(ctx, project.Name, func() error {
+			project, err := s.runProviders(ctx, project, "up", nil)
+			if err != nil {
+				return err
+			}
+			createOpts.Services = withoutProviderServices(project, createOpts.Services)
+			return s.withRollbackOnCancel(ctx, createOpts.RollbackOnCancel, func(ctx context.Context) error {
+				return s.create(ctx, project, createOpts)
+			})
+		})
 	}, s.stdinfo(), "Creating")
 }
 
@@ -82,17 +90,116 @@ func (s *composeService) create(ctx context.Context, project *types.Project, opt
 		return err
 	}
 
+	err = applyHealthcheckOverrides(project, options.HealthCheck)
+	if err != nil {
+		return err
+	}
+
+	// references were checked when project was loaded, only check those still valid after selection and scaling
+	err = checkServiceReferences(project, true)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkContainerNames(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	err = validateDevices(project, s.isLocalEngine)
+	if err != nil {
+		return err
+	}
+
+	err = validateHostConfigs(project)
+	if err != nil {
+		return err
+	}
+
+	err = validateNetworkAddresses(project)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkPlatforms(ctx, project, options.Services)
+	if err != nil {
+		return err
+	}
+
+	err = s.disableUnsupportedFeatures(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	err = s.prepareSecrets(ctx, project)
+	if err != nil {
+		return err
+	}
+
 	var observedState Containers
 	observedState, err = s.getContainers(ctx, project.Name, oneOffInclude, true)
 	if err != nil {
 		return err
 	}
 
+	err = s.handleStaleContainers(ctx, project, observedState, options.Adopt, options.Recreate == api.RecreateForce)
+	if err != nil {
+		return err
+	}
+
+	err = s.resolveProjectNetworks(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	err = s.validateExternalNetworks(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	warnDefaultRoute(ctx, project)
+
+	err = s.validatePublishedPorts(ctx, project, options.Services)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkResources(ctx, project, options.Services, options.IgnoreResourceCheck)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkRequirements(ctx, project, options.Services)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkLogDrivers(ctx, project, options.Services)
+	if err != nil {
+		return err
+	}
+
+	warnings, err := s.Warnings(ctx, project)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		api.Warn(ctx, warning)
+	}
+
 	err = s.ensureImagesExists(ctx, project, options.Build, options.QuietPull)
 	if err != nil {
 		return err
 	}
 
+	var adoptable map[string]Containers
+	if options.AdoptOrphans {
+		adoptable, err = s.prepareOrphansAdoption(ctx, project, options.Services, observedState)
+		if err != nil {
+			return err
+		}
+	}
+
 	prepareNetworks(project)
 
 	if err := s.ensureNetworks(ctx, project.Networks); err != nil {
@@ -103,6 +210,12 @@ func (s *composeService) create(ctx context.Context, project *types.Project, opt
 		return err
 	}
 
+	adopted, err := s.adoptOrphans(ctx, project, adoptable, options.Timeout)
+	observedState = append(observedState, adopted...)
+	if err != nil {
+		return err
+	}
+
 	allServiceNames := append(project.ServiceNames(), project.DisabledServiceNames()...)
 	orphans := observedState.filter(isNotService(allServiceNames...))
 	if len(orphans) > 0 && !options.IgnoreOrphans {
@@ -112,10 +225,13 @@ func (s *composeService) create(ctx context.Context, project *types.Project, opt
 				return err
 			}
 		} else {
-			logrus.Warnf("Found orphan containers (%s) for this project. If "+
-				"you removed or renamed this service in your compose "+
-				"file, you can run this command with the "+
-				"--remove-orphans flag to clean it up.", orphans.names())
+			api.Warn(ctx, api.Warning{
+				Code: api.WarningOrphanContainers,
+				Message: fmt.Sprintf("Found orphan containers (%s) for this project. If "+
+					"you removed or renamed this service in your compose "+
+					"file, you can run this command with the "+
+					"--remove-orphans flag to clean it up.", orphans.names()),
+			})
 		}
 	}
 	return newConvergence(options.Services, observedState, s).apply(ctx, project, options)
@@ -236,6 +352,11 @@ func (s *composeService) getCreateConfigs(ctx context.Context,
 		stdinOpen = service.StdinOpen
 	)
 
+	extraHosts, err := getExtraHosts(service)
+	if err != nil {
+		return createConfigs{}, err
+	}
+
 	proxyConfig := types.MappingWithEquals(s.configFile().ParseProxyConfig(s.apiClient().DaemonHost(), nil))
 	env := proxyConfig.OverrideBy(service.Environment)
 
@@ -278,13 +399,9 @@ func (s *composeService) getCreateConfigs(ctx context.Context,
 		Healthcheck:     healthcheck,
 		StopTimeout:     ToSeconds(service.StopGracePeriod),
 	} // VOLUMES/MOUNTS/FILESYSTEMS
-	tmpfs := map[string]string{}
-	for _, t := range service.Tmpfs {
-		if arr := strings.SplitN(t, ":", 2); len(arr) > 1 {
-			tmpfs[arr[0]] = arr[1]
-		} else {
-			tmpfs[arr[0]] = ""
-		}
+	tmpfs, err := toTmpfs(service.Name, service.Tmpfs)
+	if err != nil {
+		return createConfigs{}, err
 	}
 	binds, mounts, err := s.buildContainerVolumes(ctx, *p, service, inherit)
 	if err != nil {
@@ -300,7 +417,7 @@ func (s *composeService) getCreateConfigs(ctx context.Context,
 	if err != nil {
 		return createConfigs{}, err
 	}
-	networkMode, networkingConfig := defaultNetworkSettings(p, service, number, links, opts.UseNetworkAliases, apiVersion)
+	networkMode, networkingConfig := defaultNetworkSettings(s.containerNamer(), p, service, number, links, opts.UseNetworkAliases, apiVersion)
 	portBindings := buildContainerPortBindingOptions(service)
 
 	// MISC
@@ -339,7 +456,7 @@ func (s *composeService) getCreateConfigs(ctx context.Context,
 		DNS:            service.DNS,
 		DNSSearch:      service.DNSSearch,
 		DNSOptions:     service.DNSOpts,
-		ExtraHosts:     service.ExtraHosts.AsList(":"),
+		ExtraHosts:     extraHosts.AsList(":"),
 		SecurityOpt:    securityOpts,
 		StorageOpt:     service.StorageOpt,
 		UsernsMode:     container.UsernsMode(service.UserNSMode),
@@ -421,8 +538,8 @@ func (s *composeService) prepareContainerMACAddress(ctx context.Context, service
 	return macAddress, nil
 }
 
-func getAliases(project *types.Project, service types.ServiceConfig, serviceIndex int, networkKey string, useNetworkAliases bool) []string {
-	aliases := []string{getContainerName(project.Name, service, serviceIndex)}
+func getAliases(namer api.ContainerNamer, project *types.Project, service types.ServiceConfig, serviceIndex int, networkKey string, useNetworkAliases bool) []string {
+	aliases := []string{getContainerName(namer, project.Name, service, serviceIndex)}
 	if useNetworkAliases {
 		aliases = append(aliases, service.Name)
 		if cfg := service.Networks[networkKey]; cfg != nil {
@@ -432,31 +549,24 @@ func getAliases(project *types.Project, service types.ServiceConfig, serviceInde
 	return aliases
 }
 
-func createEndpointSettings(p *types.Project, service types.ServiceConfig, serviceIndex int, networkKey string, links []string, useNetworkAliases bool) *network.EndpointSettings {
+func createEndpointSettings(namer api.ContainerNamer, p *types.Project, service types.ServiceConfig, serviceIndex int, networkKey string, links []string, useNetworkAliases bool) *network.EndpointSettings {
 	config := service.Networks[networkKey]
 	var ipam *network.EndpointIPAMConfig
-	var (
-		ipv4Address string
-		ipv6Address string
-		macAddress  string
-	)
+	var macAddress string
 	if config != nil {
-		ipv4Address = config.Ipv4Address
-		ipv6Address = config.Ipv6Address
+		// static addresses are requested through IPAMConfig, IPAddress and GlobalIPv6Address are only set by the engine
 		ipam = &network.EndpointIPAMConfig{
-			IPv4Address:  ipv4Address,
-			IPv6Address:  ipv6Address,
+			IPv4Address:  config.Ipv4Address,
+			IPv6Address:  config.Ipv6Address,
 			LinkLocalIPs: config.LinkLocalIPs,
 		}
 		macAddress = config.MacAddress
 	}
 	return &network.EndpointSettings{
-		Aliases:     getAliases(p, service, serviceIndex, networkKey, useNetworkAliases),
-		Links:       links,
-		IPAddress:   ipv4Address,
-		IPv6Gateway: ipv6Address,
-		IPAMConfig:  ipam,
-		MacAddress:  macAddress,
+		Aliases:    getAliases(namer, p, service, serviceIndex, networkKey, useNetworkAliases),
+		Links:      links,
+		IPAMConfig: ipam,
+		MacAddress: macAddress,
 	}
 }
 
@@ -517,6 +627,7 @@ func (s *composeService) prepareLabels(labels types.Labels, service types.Servic
 
 // defaultNetworkSettings determines the container.NetworkMode and corresponding network.NetworkingConfig (nil if not applicable).
 func defaultNetworkSettings(
+	namer api.ContainerNamer,
 	project *types.Project,
 	service types.ServiceConfig,
 	serviceIndex int,
@@ -540,7 +651,7 @@ func defaultNetworkSettings(
 	}
 	primaryNetworkMobyNetworkName := project.Networks[primaryNetworkKey].Name
 	endpointsConfig := map[string]*network.EndpointSettings{
-		primaryNetworkMobyNetworkName: createEndpointSettings(project, service, serviceIndex, primaryNetworkKey, links, useNetworkAliases),
+		primaryNetworkMobyNetworkName: createEndpointSettings(namer, project, service, serviceIndex, primaryNetworkKey, links, useNetworkAliases),
 	}
 
 	// Starting from API version 1.44, the Engine will take several EndpointsConfigs
@@ -551,7 +662,7 @@ func defaultNetworkSettings(
 		serviceNetworks := service.NetworksByPriority()
 		for _, networkKey := range serviceNetworks[1:] {
 			mobyNetworkName := project.Networks[networkKey].Name
-			epSettings := createEndpointSettings(project, service, serviceIndex, networkKey, links, useNetworkAliases)
+			epSettings := createEndpointSettings(namer, project, service, serviceIndex, networkKey, links, useNetworkAliases)
 			endpointsConfig[mobyNetworkName] = epSettings
 		}
 	}
@@ -646,29 +757,16 @@ func getDeployResources(s types.ServiceConfig) container.Resources {
 	}
 
 	for _, device := range s.Devices {
-		// FIXME should use docker/cli parseDevice, unfortunately private
-		src := ""
-		dst := ""
-		permissions := "rwm"
-		arr := strings.Split(device, ":")
-		switch len(arr) {
-		case 3:
-			permissions = arr[2]
-			fallthrough
-		case 2:
-			dst = arr[1]
-			fallthrough
-		case 1:
-			src = arr[0]
+		// invalid entries are reported by validateDevices before containers get created
+		mapping, request, err := parseDevice(device)
+		if err != nil {
+			continue
 		}
-		if dst == "" {
-			dst = src
+		if request != nil {
+			resources.DeviceRequests = append(resources.DeviceRequests, *request)
+			continue
 		}
-		resources.Devices = append(resources.Devices, container.DeviceMapping{
-			PathOnHost:        src,
-			PathInContainer:   dst,
-			CgroupPermissions: permissions,
-		})
+		resources.Devices = append(resources.Devices, *mapping)
 	}
 
 	ulimits := toUlimits(s.Ulimits)
@@ -676,26 +774,6 @@ func getDeployResources(s types.ServiceConfig) container.Resources {
 	return resources
 }
 
-func toUlimits(m map[string]*types.UlimitsConfig) []*units.Ulimit {
-	var ulimits []*units.Ulimit
-	for name, u := range m {
-		soft := u.Single
-		if u.Soft != 0 {
-			soft = u.Soft
-		}
-		hard := u.Single
-		if u.Hard != 0 {
-			hard = u.Hard
-		}
-		ulimits = append(ulimits, &units.Ulimit{
-			Name: name,
-			Hard: int64(hard),
-			Soft: int64(soft),
-		})
-	}
-	return ulimits
-}
-
 func setReservations(reservations *types.Resource, resources *container.Resources) {
 	if reservations == nil {
 		return
@@ -707,9 +785,14 @@ func setReservations(reservations *types.Resource, resources *container.Resource
 	}
 
 	for _, device := range reservations.Devices {
+		count := int(device.Count)
+		if count == 0 && len(device.IDs) == 0 {
+			// neither count nor device_ids set, reserve all devices matching capabilities
+			count = -1
+		}
 		resources.DeviceRequests = append(resources.DeviceRequests, container.DeviceRequest{
 			Capabilities: [][]string{device.Capabilities},
-			Count:        int(device.Count),
+			Count:        count,
 			DeviceIDs:    device.IDs,
 			Driver:       device.Driver,
 		})
@@ -819,7 +902,7 @@ func (s *composeService) buildContainerVolumes(
 		return nil, nil, err
 	}
 
-	mountOptions, err := buildContainerMountOptions(p, service, imgInspect, inherit)
+	mountOptions, err := buildContainerMountOptions(ctx, p, service, imgInspect, inherit)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -852,7 +935,7 @@ MOUNTS:
 	return binds, mounts, nil
 }
 
-func buildContainerMountOptions(p types.Project, s types.ServiceConfig, img moby.ImageInspect, inherit *moby.Container) ([]mount.Mount, error) {
+func buildContainerMountOptions(ctx context.Context, p types.Project, s types.ServiceConfig, img moby.ImageInspect, inherit *moby.Container) ([]mount.Mount, error) {
 	var mounts = map[string]mount.Mount{}
 	if inherit != nil {
 		for _, m := range inherit.Mounts {
@@ -894,7 +977,7 @@ func buildContainerMountOptions(p types.Project, s types.ServiceConfig, img moby
 		}
 	}
 
-	mounts, err := fillBindMounts(p, s, mounts)
+	mounts, err := fillBindMounts(ctx, p, s, mounts)
 	if err != nil {
 		return nil, err
 	}
@@ -906,16 +989,16 @@ func buildContainerMountOptions(p types.Project, s types.ServiceConfig, img moby
 	return values, nil
 }
 
-func fillBindMounts(p types.Project, s types.ServiceConfig, m map[string]mount.Mount) (map[string]mount.Mount, error) {
+func fillBindMounts(ctx context.Context, p types.Project, s types.ServiceConfig, m map[string]mount.Mount) (map[string]mount.Mount, error) {
 	for _, v := range s.Volumes {
-		bindMount, err := buildMount(p, v)
+		bindMount, err := buildMount(ctx, p, v)
 		if err != nil {
 			return nil, err
 		}
 		m[bindMount.Target] = bindMount
 	}
 
-	secrets, err := buildContainerSecretMounts(p, s)
+	secrets, err := buildContainerSecretMounts(ctx, p, s)
 	if err != nil {
 		return nil, err
 	}
@@ -926,7 +1009,7 @@ func fillBindMounts(p types.Project, s types.ServiceConfig, m map[string]mount.M
 		m[s.Target] = s
 	}
 
-	configs, err := buildContainerConfigMounts(p, s)
+	configs, err := buildContainerConfigMounts(ctx, p, s)
 	if err != nil {
 		return nil, err
 	}
@@ -939,7 +1022,7 @@ func fillBindMounts(p types.Project, s types.ServiceConfig, m map[string]mount.M
 	return m, nil
 }
 
-func buildContainerConfigMounts(p types.Project, s types.ServiceConfig) ([]mount.Mount, error) {
+func buildContainerConfigMounts(ctx context.Context, p types.Project, s types.ServiceConfig) ([]mount.Mount, error) {
 	var mounts = map[string]mount.Mount{}
 
 	configsBaseDir := "/"
@@ -952,7 +1035,11 @@ func buildContainerConfigMounts(p types.Project, s types.ServiceConfig) ([]mount
 		}
 
 		if config.UID != "" || config.GID != "" || config.Mode != nil {
-			logrus.Warn("config `uid`, `gid` and `mode` are not supported, they will be ignored")
+			api.Warn(ctx, api.Warning{
+				Code:    api.WarningUnsupportedOption,
+				Service: s.Name,
+				Message: "config `uid`, `gid` and `mode` are not supported, they will be ignored",
+			})
 		}
 
 		definedConfig := p.Configs[config.Source]
@@ -971,7 +1058,7 @@ func buildContainerConfigMounts(p types.Project, s types.ServiceConfig) ([]mount
 			continue
 		}
 
-		bindMount, err := buildMount(p, types.ServiceVolumeConfig{
+		bindMount, err := buildMount(ctx, p, types.ServiceVolumeConfig{
 			Type:     types.VolumeTypeBind,
 			Source:   definedConfig.File,
 			Target:   target,
@@ -989,7 +1076,7 @@ func buildContainerConfigMounts(p types.Project, s types.ServiceConfig) ([]mount
 	return values, nil
 }
 
-func buildContainerSecretMounts(p types.Project, s types.ServiceConfig) ([]mount.Mount, error) {
+func buildContainerSecretMounts(ctx context.Context, p types.Project, s types.ServiceConfig) ([]mount.Mount, error) {
 	var mounts = map[string]mount.Mount{}
 
 	secretsDir := "/run/secrets/"
@@ -1002,7 +1089,11 @@ func buildContainerSecretMounts(p types.Project, s types.ServiceConfig) ([]mount
 		}
 
 		if secret.UID != "" || secret.GID != "" || secret.Mode != nil {
-			logrus.Warn("secrets `uid`, `gid` and `mode` are not supported, they will be ignored")
+			api.Warn(ctx, api.Warning{
+				Code:    api.WarningUnsupportedOption,
+				Service: s.Name,
+				Message: "secrets `uid`, `gid` and `mode` are not supported, they will be ignored",
+			})
 		}
 
 		definedSecret := p.Secrets[secret.Source]
@@ -1021,7 +1112,7 @@ func buildContainerSecretMounts(p types.Project, s types.ServiceConfig) ([]mount
 			continue
 		}
 
-		mnt, err := buildMount(p, types.ServiceVolumeConfig{
+		mnt, err := buildMount(ctx, p, types.ServiceVolumeConfig{
 			Type:     types.VolumeTypeBind,
 			Source:   definedSecret.File,
 			Target:   target,
@@ -1057,7 +1148,7 @@ func isWindowsAbs(p string) bool {
 	return false
 }
 
-func buildMount(project types.Project, volume types.ServiceVolumeConfig) (mount.Mount, error) {
+func buildMount(ctx context.Context, project types.Project, volume types.ServiceVolumeConfig) (mount.Mount, error) {
 	source := volume.Source
 	// on windows, filepath.IsAbs(source) is false for unix style abs path like /var/run/docker.sock.
 	// do not replace these with  filepath.Abs(source) that will include a default drive.
@@ -1078,7 +1169,7 @@ func buildMount(project types.Project, volume types.ServiceVolumeConfig) (mount.
 		}
 	}
 
-	bind, vol, tmpfs := buildMountOptions(project, volume)
+	bind, vol, tmpfs := buildMountOptions(ctx, project, volume)
 
 	volume.Target = path.Clean(volume.Target)
 
@@ -1098,22 +1189,34 @@ func buildMount(project types.Project, volume types.ServiceVolumeConfig) (mount.
 	}, nil
 }
 
-func buildMountOptions(project types.Project, volume types.ServiceVolumeConfig) (*mount.BindOptions, *mount.VolumeOptions, *mount.TmpfsOptions) {
+func buildMountOptions(ctx context.Context, project types.Project, volume types.ServiceVolumeConfig) (*mount.BindOptions, *mount.VolumeOptions, *mount.TmpfsOptions) {
 	switch volume.Type {
 	case "bind":
 		if volume.Volume != nil {
-			logrus.Warnf("mount of type `bind` should not define `volume` option")
+			api.Warn(ctx, api.Warning{
+				Code:    api.WarningUnsupportedOption,
+				Message: "mount of type `bind` should not define `volume` option",
+			})
 		}
 		if volume.Tmpfs != nil {
-			logrus.Warnf("mount of type `bind` should not define `tmpfs` option")
+			api.Warn(ctx, api.Warning{
+				Code:    api.WarningUnsupportedOption,
+				Message: "mount of type `bind` should not define `tmpfs` option",
+			})
 		}
 		return buildBindOption(volume.Bind), nil, nil
 	case "volume":
 		if volume.Bind != nil {
-			logrus.Warnf("mount of type `volume` should not define `bind` option")
+			api.Warn(ctx, api.Warning{
+				Code:    api.WarningUnsupportedOption,
+				Message: "mount of type `volume` should not define `bind` option",
+			})
 		}
 		if volume.Tmpfs != nil {
-			logrus.Warnf("mount of type `volume` should not define `tmpfs` option")
+			api.Warn(ctx, api.Warning{
+				Code:    api.WarningUnsupportedOption,
+				Message: "mount of type `volume` should not define `tmpfs` option",
+			})
 		}
 		if v, ok := project.Volumes[volume.Source]; ok && v.DriverOpts["o"] == types.VolumeTypeBind {
 			return buildBindOption(&types.ServiceVolumeBind{
@@ -1123,10 +1226,16 @@ func buildMountOptions(project types.Project, volume types.ServiceVolumeConfig)
 		return nil, buildVolumeOptions(volume.Volume), nil
 	case "tmpfs":
 		if volume.Bind != nil {
-			logrus.Warnf("mount of type `tmpfs` should not define `bind` option")
+			api.Warn(ctx, api.Warning{
+				Code:    api.WarningUnsupportedOption,
+				Message: "mount of type `tmpfs` should not define `bind` option",
+			})
 		}
 		if volume.Volume != nil {
-			logrus.Warnf("mount of type `tmpfs` should not define `volume` option")
+			api.Warn(ctx, api.Warning{
+				Code:    api.WarningUnsupportedOption,
+				Message: "mount of type `tmpfs` should not define `volume` option",
+			})
 		}
 		return nil, nil, buildTmpfsOptions(volume.Tmpfs)
 	}
@@ -1191,11 +1300,17 @@ func (s *composeService) resolveOrCreateNetwork(ctx context.Context, n *types.Ne
 		if inspect.Name == n.Name || inspect.ID == n.Name {
 			p, ok := inspect.Labels[api.ProjectLabel]
 			if !ok {
-				logrus.Warnf("a network with name %s exists but was not created by compose.\n"+
-					"Set `external: true` to use an existing network", n.Name)
+				api.Warn(ctx, api.Warning{
+					Code: api.WarningUnmanagedResource,
+					Message: fmt.Sprintf("a network with name %s exists but was not created by compose.\n"+
+						"Set `external: true` to use an existing network", n.Name),
+				})
 			} else if p != expectedProjectLabel {
-				logrus.Warnf("a network with name %s exists but was not created for project %q.\n"+
-					"Set `external: true` to use an existing network", n.Name, expectedProjectLabel)
+				api.Warn(ctx, api.Warning{
+					Code: api.WarningUnmanagedResource,
+					Message: fmt.Sprintf("a network with name %s exists but was not created for project %q.\n"+
+						"Set `external: true` to use an existing network", n.Name, expectedProjectLabel),
+				})
 			}
 			if inspect.Labels[api.NetworkLabel] != expectedNetworkLabel {
 				return fmt.Errorf("network %s was found but has incorrect label %s set to %q", n.Name, api.NetworkLabel, inspect.Labels[api.NetworkLabel])
@@ -1229,27 +1344,14 @@ func (s *composeService) resolveOrCreateNetwork(ctx context.Context, n *types.Ne
 	// scenario were a network with same name exists but doesn't have label, and use of `CheckDuplicate: true`
 	// prevents to create another one.
 	if len(networks) > 0 {
-		logrus.Warnf("a network with name %s exists but was not created by compose.\n"+
-			"Set `external: true` to use an existing network", n.Name)
+		api.Warn(ctx, api.Warning{
+			Code: api.WarningUnmanagedResource,
+			Message: fmt.Sprintf("a network with name %s exists but was not created by compose.\n"+
+				"Set `external: true` to use an existing network", n.Name),
+		})
 		return nil
 	}
 
-	var ipam *network.IPAM
-	if n.Ipam.Config != nil {
-		var config []network.IPAMConfig
-		for _, pool := range n.Ipam.Config {
-			config = append(config, network.IPAMConfig{
-				Subnet:     pool.Subnet,
-				IPRange:    pool.IPRange,
-				Gateway:    pool.Gateway,
-				AuxAddress: pool.AuxiliaryAddresses,
-			})
-		}
-		ipam = &network.IPAM{
-			Driver: n.Ipam.Driver,
-			Config: config,
-		}
-	}
 	createOpts := moby.NetworkCreate{
 		CheckDuplicate: true,
 		Labels:         n.Labels,
@@ -1257,7 +1359,6 @@ func (s *composeService) resolveOrCreateNetwork(ctx context.Context, n *types.Ne
 		Options:        n.DriverOpts,
 		Internal:       n.Internal,
 		Attachable:     n.Attachable,
-		IPAM:           ipam,
 		EnableIPv6:     n.EnableIPv6,
 	}
 
@@ -1282,45 +1383,54 @@ func (s *composeService) resolveOrCreateNetwork(ctx context.Context, n *types.Ne
 	w := progress.ContextWriter(ctx)
 	w.Event(progress.CreatingEvent(networkEventName))
 
-	_, err = s.apiClient().NetworkCreate(ctx, n.Name, createOpts)
+	response, err := s.apiClient().NetworkCreate(ctx, n.Name, createOpts)
 	if err != nil {
 		w.Event(progress.ErrorEvent(networkEventName))
 		return fmt.Errorf("failed to create network %s: %w", n.Name, err)
 	}
+	recordCreated(ctx, networkResource, response.ID, n.Name)
 	w.Event(progress.CreatedEvent(networkEventName))
 	return nil
 }
 
-func (s *composeService) resolveExternalNetwork(ctx context.Context, n *types.NetworkConfig) error {
+// lookupNetwork returns networks matching exactly name, or ID
+func (s *composeService) lookupNetwork(ctx context.Context, name string) ([]moby.NetworkResource, error) {
 	// NetworkInspect will match on ID prefix, so NetworkList with a name
 	// filter is used to look for an exact match to prevent e.g. a network
 	// named `db` from getting erroneously matched to a network with an ID
 	// like `db9086999caf`
 	networks, err := s.apiClient().NetworkList(ctx, moby.NetworkListOptions{
-		Filters: filters.NewArgs(filters.Arg("name", n.Name)),
+		Filters: filters.NewArgs(filters.Arg("name", name)),
 	})
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if len(networks) == 0 {
-		// in this instance, n.Name is really an ID
-		sn, err := s.apiClient().NetworkInspect(ctx, n.Name, moby.NetworkInspectOptions{})
+		// in this instance, name is really an ID
+		sn, err := s.apiClient().NetworkInspect(ctx, name, moby.NetworkInspectOptions{})
 		if err != nil {
-			return err
+			return nil, err
 		}
 		networks = append(networks, sn)
 
 	}
 
 	// NetworkList API doesn't return the exact name match, so we can retrieve more than one network with a request
-	networks = utils.Filter(networks, func(net moby.NetworkResource) bool {
-		// later in this function, the name is changed the to ID.
+	return utils.Filter(networks, func(net moby.NetworkResource) bool {
+		// later in resolveExternalNetwork, the name is changed the to ID.
 		// this function is called during the rebuild stage of `compose watch`.
 		// we still require just one network back, but we need to run the search on the ID
-		return net.Name == n.Name || net.ID == n.Name
-	})
+		return net.Name == name || net.ID == name
+	}), nil
+}
+
+func (s *composeService) resolveExternalNetwork(ctx context.Context, n *types.NetworkConfig) error {
+	networks, err := s.lookupNetwork(ctx, n.Name)
+	if err != nil {
+		return err
+	}
 
 	switch len(networks) {
 	case 1:
@@ -1364,10 +1474,16 @@ func (s *composeService) ensureVolume(ctx context.Context, volume types.VolumeCo
 	// Volume exists with name, but let's double-check this is the expected one
 	p, ok := inspected.Labels[api.ProjectLabel]
 	if !ok {
-		logrus.Warnf("volume %q already exists but was not created by Docker Compose. Use `external: true` to use an existing volume", volume.Name)
+		api.Warn(ctx, api.Warning{
+			Code:    api.WarningUnmanagedResource,
+			Message: fmt.Sprintf("volume %q already exists but was not created by Docker Compose. Use `external: true` to use an existing volume", volume.Name),
+		})
 	}
 	if ok && p != project {
-		logrus.Warnf("volume %q already exists but was created for project %q (expected %q). Use `external: true` to use an existing volume", volume.Name, p, project)
+		api.Warn(ctx, api.Warning{
+			Code:    api.WarningUnmanagedResource,
+			Message: fmt.Sprintf("volume %q already exists but was created for project %q (expected %q). Use `external: true` to use an existing volume", volume.Name, p, project),
+		})
 	}
 	return nil
 }
@@ -1386,6 +1502,7 @@ func (s *composeService) createVolume(ctx context.Context, volume types.VolumeCo
 		w.Event(progress.ErrorEvent(eventName))
 		return err
 	}
+	recordCreated(ctx, volumeResource, volume.Name, volume.Name)
 	w.Event(progress.CreatedEvent(eventName))
 	return nil
 }
@@ -17,6 +17,7 @@
 package compose
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"sort"
@@ -40,7 +41,7 @@ func TestBuildBindMount(t *testing.T) {
 		Source: "",
 		Target: "/data",
 	}
-	mount, err := buildMount(project, volume)
+	mount, err := buildMount(context.Background(), project, volume)
 	assert.NilError(t, err)
 	assert.Assert(t, filepath.IsAbs(mount.Source))
 	_, err = os.Stat(mount.Source)
@@ -55,7 +56,7 @@ func TestBuildNamedPipeMount(t *testing.T) {
 		Source: "\\\\.\\pipe\\docker_engine_windows",
 		Target: "\\\\.\\pipe\\docker_engine",
 	}
-	mount, err := buildMount(project, volume)
+	mount, err := buildMount(context.Background(), project, volume)
 	assert.NilError(t, err)
 	assert.Equal(t, mount.Type, mountTypes.TypeNamedPipe)
 }
@@ -74,7 +75,7 @@ func TestBuildVolumeMount(t *testing.T) {
 		Source: "myVolume",
 		Target: "/data",
 	}
-	mount, err := buildMount(project, volume)
+	mount, err := buildMount(context.Background(), project, volume)
 	assert.NilError(t, err)
 	assert.Equal(t, mount.Source, "myProject_myVolume")
 	assert.Equal(t, mount.Type, mountTypes.TypeVolume)
@@ -152,7 +153,7 @@ func TestBuildContainerMountOptions(t *testing.T) {
 		},
 	}
 
-	mounts, err := buildContainerMountOptions(project, project.Services["myService"], moby.ImageInspect{}, inherit)
+	mounts, err := buildContainerMountOptions(context.Background(), project, project.Services["myService"], moby.ImageInspect{}, inherit)
 	sort.Slice(mounts, func(i, j int) bool {
 		return mounts[i].Target < mounts[j].Target
 	})
@@ -164,7 +165,7 @@ func TestBuildContainerMountOptions(t *testing.T) {
 	assert.Equal(t, mounts[2].VolumeOptions.Subpath, "etc")
 	assert.Equal(t, mounts[3].Target, "\\\\.\\pipe\\docker_engine")
 
-	mounts, err = buildContainerMountOptions(project, project.Services["myService"], moby.ImageInspect{}, inherit)
+	mounts, err = buildContainerMountOptions(context.Background(), project, project.Services["myService"], moby.ImageInspect{}, inherit)
 	sort.Slice(mounts, func(i, j int) bool {
 		return mounts[i].Target < mounts[j].Target
 	})
@@ -177,6 +178,45 @@ func TestBuildContainerMountOptions(t *testing.T) {
 	assert.Equal(t, mounts[3].Target, "\\\\.\\pipe\\docker_engine")
 }
 
+func TestBuildContainerMountOptionsRenewAnonymousVolumes(t *testing.T) {
+	project := composetypes.Project{
+		Name: "myProject",
+		Services: composetypes.Services{
+			"myService": {
+				Name: "myService",
+				Volumes: []composetypes.ServiceVolumeConfig{
+					{Type: composetypes.VolumeTypeVolume, Target: "/anonymous"},
+					{Type: composetypes.VolumeTypeVolume, Source: "data", Target: "/named"},
+				},
+			},
+		},
+		Volumes: composetypes.Volumes{
+			"data": {Name: "myProject_data"},
+		},
+	}
+	previous := &moby.Container{
+		Mounts: []moby.MountPoint{
+			{Type: composetypes.VolumeTypeVolume, Name: "0123456789abcdef", Destination: "/anonymous", RW: true},
+			{Type: composetypes.VolumeTypeVolume, Name: "myProject_data", Destination: "/named", RW: true},
+		},
+	}
+
+	sources := func(inherit *moby.Container) map[string]string {
+		mounts, err := buildContainerMountOptions(context.Background(), project, project.Services["myService"], moby.ImageInspect{}, inherit)
+		assert.NilError(t, err)
+		sources := map[string]string{}
+		for _, m := range mounts {
+			sources[m.Target] = m.Source
+		}
+		return sources
+	}
+
+	// anonymous volume is inherited from the previous container
+	assert.DeepEqual(t, sources(previous), map[string]string{"/anonymous": "0123456789abcdef", "/named": "myProject_data"})
+	// --renew-anon-volumes creates a fresh anonymous volume, but named volumes are preserved
+	assert.DeepEqual(t, sources(nil), map[string]string{"/anonymous": "", "/named": "myProject_data"})
+}
+
 func TestDefaultNetworkSettings(t *testing.T) {
 	t.Run("returns the network with the highest priority when service has multiple networks", func(t *testing.T) {
 		service := composetypes.ServiceConfig{
@@ -205,7 +245,7 @@ func TestDefaultNetworkSettings(t *testing.T) {
 			}),
 		}
 
-		networkMode, networkConfig := defaultNetworkSettings(&project, service, 1, nil, true, "1.43")
+		networkMode, networkConfig := defaultNetworkSettings(api.DefaultContainerNamer, &project, service, 1, nil, true, "1.43")
 		assert.Equal(t, string(networkMode), "myProject_myNetwork2")
 		assert.Check(t, cmp.Len(networkConfig.EndpointsConfig, 1))
 		assert.Check(t, cmp.Contains(networkConfig.EndpointsConfig, "myProject_myNetwork2"))
@@ -233,7 +273,7 @@ func TestDefaultNetworkSettings(t *testing.T) {
 			}),
 		}
 
-		networkMode, networkConfig := defaultNetworkSettings(&project, service, 1, nil, true, "1.43")
+		networkMode, networkConfig := defaultNetworkSettings(api.DefaultContainerNamer, &project, service, 1, nil, true, "1.43")
 		assert.Equal(t, string(networkMode), "myProject_default")
 		assert.Check(t, cmp.Len(networkConfig.EndpointsConfig, 1))
 		assert.Check(t, cmp.Contains(networkConfig.EndpointsConfig, "myProject_default"))
@@ -250,7 +290,7 @@ func TestDefaultNetworkSettings(t *testing.T) {
 			},
 		}
 
-		networkMode, networkConfig := defaultNetworkSettings(&project, service, 1, nil, true, "1.43")
+		networkMode, networkConfig := defaultNetworkSettings(api.DefaultContainerNamer, &project, service, 1, nil, true, "1.43")
 		assert.Equal(t, string(networkMode), "none")
 		assert.Check(t, cmp.Nil(networkConfig))
 	})
@@ -270,8 +310,38 @@ func TestDefaultNetworkSettings(t *testing.T) {
 			}),
 		}
 
-		networkMode, networkConfig := defaultNetworkSettings(&project, service, 1, nil, true, "1.43")
+		networkMode, networkConfig := defaultNetworkSettings(api.DefaultContainerNamer, &project, service, 1, nil, true, "1.43")
 		assert.Equal(t, string(networkMode), "host")
 		assert.Check(t, cmp.Nil(networkConfig))
 	})
 }
+
+func TestGetExtraHosts(t *testing.T) {
+	service := composetypes.ServiceConfig{
+		Name:       "app",
+		ExtraHosts: composetypes.HostsList{"db": []string{"10.0.0.2"}},
+	}
+	hosts, err := getExtraHosts(service)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, hosts, service.ExtraHosts)
+
+	service.Extensions = composetypes.Extensions{extNeedsHostAccess: true}
+	hosts, err = getExtraHosts(service)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, hosts, composetypes.HostsList{
+		"db":                   []string{"10.0.0.2"},
+		"host.docker.internal": []string{"host-gateway"},
+	})
+
+	service.Extensions = composetypes.Extensions{extNeedsHostAccess: []any{"db", "gateway.local"}}
+	hosts, err = getExtraHosts(service)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, hosts, composetypes.HostsList{
+		"db":            []string{"10.0.0.2"},
+		"gateway.local": []string{"host-gateway"},
+	})
+
+	service.Extensions = composetypes.Extensions{extNeedsHostAccess: 42}
+	_, err = getExtraHosts(service)
+	assert.Error(t, err, `service "app": x-needs-host-access must be a boolean or a list of host names`)
+}
@@ -18,13 +18,14 @@ package compose
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"sync"
 	"testing"
 
 	"github.com/compose-spec/compose-go/v2/types"
-	"github.com/docker/compose/v2/pkg/utils"
+	"github.com/docker/compose/v2/pkg/api"
 	testify "github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gotest.tools/v3/assert"
@@ -123,6 +124,54 @@ func TestInDependencyReverseDownCommandOrder(t *testing.T) {
 	require.Equal(t, []string{"test1", "test2", "test3"}, order)
 }
 
+func TestInDependencyOrderContinueOnError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	project := createTestProject()
+	project.Services["test4"] = types.ServiceConfig{Name: "test4"}
+
+	var mu sync.Mutex
+	var visited []string
+	err := InDependencyOrder(ctx, project, func(ctx context.Context, service string) error {
+		if service == "test2" {
+			return fmt.Errorf("failed to start %s", service)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		visited = append(visited, service)
+		return nil
+	}, WithContinueOnError())
+	assert.ErrorContains(t, err, "failed to start test2")
+	sort.Strings(visited)
+	// test1 depends on failed test2 so is skipped, test4 is independent
+	assert.DeepEqual(t, []string{"test3", "test4"}, visited)
+}
+
+func TestInReverseDependencyOrderContinueOnError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	project := createTestProject()
+	project.Services["test4"] = types.ServiceConfig{Name: "test4"}
+
+	var mu sync.Mutex
+	var visited []string
+	err := InReverseDependencyOrder(ctx, project, func(ctx context.Context, service string) error {
+		if service == "test1" || service == "test4" {
+			return fmt.Errorf("failed to stop %s", service)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		visited = append(visited, service)
+		return nil
+	}, WithContinueOnError())
+	assert.ErrorContains(t, err, "failed to stop test1")
+	assert.ErrorContains(t, err, "failed to stop test4")
+	// test2 and test3 are still used by test1 which failed to stop
+	assert.Equal(t, len(visited), 0)
+}
+
 func TestBuildGraph(t *testing.T) {
 	testCases := []struct {
 		desc             string
@@ -140,7 +189,7 @@ func TestBuildGraph(t *testing.T) {
 			expectedVertices: map[string]*Vertex{
 				"test": {
 					Key:      "test",
-					Service:  "test",
+					Payload:  "test",
 					Status:   ServiceStopped,
 					Children: map[string]*Vertex{},
 					Parents:  map[string]*Vertex{},
@@ -162,14 +211,14 @@ func TestBuildGraph(t *testing.T) {
 			expectedVertices: map[string]*Vertex{
 				"test": {
 					Key:      "test",
-					Service:  "test",
+					Payload:  "test",
 					Status:   ServiceStopped,
 					Children: map[string]*Vertex{},
 					Parents:  map[string]*Vertex{},
 				},
 				"another": {
 					Key:      "another",
-					Service:  "another",
+					Payload:  "another",
 					Status:   ServiceStopped,
 					Children: map[string]*Vertex{},
 					Parents:  map[string]*Vertex{},
@@ -193,7 +242,7 @@ func TestBuildGraph(t *testing.T) {
 			expectedVertices: map[string]*Vertex{
 				"test": {
 					Key:     "test",
-					Service: "test",
+					Payload: "test",
 					Status:  ServiceStopped,
 					Children: map[string]*Vertex{
 						"another": {},
@@ -202,7 +251,7 @@ func TestBuildGraph(t *testing.T) {
 				},
 				"another": {
 					Key:      "another",
-					Service:  "another",
+					Payload:  "another",
 					Status:   ServiceStopped,
 					Children: map[string]*Vertex{},
 					Parents: map[string]*Vertex{
@@ -234,7 +283,7 @@ func TestBuildGraph(t *testing.T) {
 			expectedVertices: map[string]*Vertex{
 				"test": {
 					Key:     "test",
-					Service: "test",
+					Payload: "test",
 					Status:  ServiceStopped,
 					Children: map[string]*Vertex{
 						"another": {},
@@ -243,7 +292,7 @@ func TestBuildGraph(t *testing.T) {
 				},
 				"another": {
 					Key:     "another",
-					Service: "another",
+					Payload: "another",
 					Status:  ServiceStopped,
 					Children: map[string]*Vertex{
 						"another_dep": {},
@@ -254,7 +303,7 @@ func TestBuildGraph(t *testing.T) {
 				},
 				"another_dep": {
 					Key:      "another_dep",
-					Service:  "another_dep",
+					Payload:  "another_dep",
 					Status:   ServiceStopped,
 					Children: map[string]*Vertex{},
 					Parents: map[string]*Vertex{
@@ -306,7 +355,7 @@ func TestBuildGraphDependsOn(t *testing.T) {
 			expectedVertices: map[string]*Vertex{
 				"test": {
 					Key:      "test",
-					Service:  "test",
+					Payload:  "test",
 					Status:   ServiceStopped,
 					Children: map[string]*Vertex{},
 					Parents:  map[string]*Vertex{},
@@ -332,6 +381,59 @@ func TestBuildGraphDependsOn(t *testing.T) {
 	}
 }
 
+func TestBuildGraphNamespaceReferences(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			"app":     {Name: "app", NetworkMode: "service:gateway", Pid: "service:monitor"},
+			"gateway": {Name: "gateway"},
+			"monitor": {Name: "monitor"},
+		},
+	}
+	graph, err := NewGraph(project, ServiceStopped)
+	assert.NilError(t, err)
+	app := graph.Vertices["app"]
+	assert.Equal(t, len(app.Children), 2)
+	assert.Check(t, app.Children["gateway"] != nil)
+	assert.Check(t, app.Children["monitor"] != nil)
+}
+
+func TestCheckServiceReferences(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			"app":     {Name: "app", NetworkMode: "service:gateway", Ipc: "service:gateway"},
+			"gateway": {Name: "gateway"},
+		},
+	}
+	assert.NilError(t, CheckServiceReferences(project))
+
+	gateway := project.Services["gateway"]
+	gateway.Scale = intPtr(2)
+	project.Services["gateway"] = gateway
+	err := CheckServiceReferences(project)
+	var invalid api.InvalidServiceReferenceError
+	assert.Assert(t, errors.As(err, &invalid))
+	assert.DeepEqual(t, invalid, api.InvalidServiceReferenceError{Service: "app", Attribute: "ipc", Target: "gateway", Scale: 2})
+	assert.Error(t, err, `service "app" ipc refers to service "gateway" which is scaled to 2 containers, `+
+		"a single one is required to share its namespace")
+
+	delete(project.Services, "gateway")
+	err = CheckServiceReferences(project)
+	assert.Check(t, api.IsNotFoundError(err))
+	assert.Error(t, err, `service "app" ipc refers to undefined service "gateway"`)
+
+	// gateway excluded by selection, as with `up --no-deps app`
+	assert.NilError(t, checkServiceReferences(project, true))
+}
+
+func TestNewVertex(t *testing.T) {
+	v := NewVertex("web", "web", ServiceStarted)
+	assert.Equal(t, v.Key, "web")
+	assert.Equal(t, v.Payload, "web")
+	assert.Equal(t, v.Status, ServiceStarted)
+	assert.Equal(t, len(v.Children), 0)
+	assert.Equal(t, len(v.Parents), 0)
+}
+
 func isVertexEqual(a, b Vertex) bool {
 	childrenEquality := true
 	for c := range a.Children {
@@ -346,92 +448,7 @@ func isVertexEqual(a, b Vertex) bool {
 		}
 	}
 	return a.Key == b.Key &&
-		a.Service == b.Service &&
+		a.Payload == b.Payload &&
 		childrenEquality &&
 		parentEquality
 }
-
-func TestWith_RootNodesAndUp(t *testing.T) {
-	graph := &Graph{
-		lock:     sync.RWMutex{},
-		Vertices: map[string]*Vertex{},
-	}
-
-	/** graph topology:
-	           A   B
-		      / \ / \
-		     G   C   E
-		          \ /
-		           D
-		           |
-		           F
-	*/
-
-	graph.AddVertex("A", "A", 0)
-	graph.AddVertex("B", "B", 0)
-	graph.AddVertex("C", "C", 0)
-	graph.AddVertex("D", "D", 0)
-	graph.AddVertex("E", "E", 0)
-	graph.AddVertex("F", "F", 0)
-	graph.AddVertex("G", "G", 0)
-
-	_ = graph.AddEdge("C", "A")
-	_ = graph.AddEdge("C", "B")
-	_ = graph.AddEdge("E", "B")
-	_ = graph.AddEdge("D", "C")
-	_ = graph.AddEdge("D", "E")
-	_ = graph.AddEdge("F", "D")
-	_ = graph.AddEdge("G", "A")
-
-	tests := []struct {
-		name  string
-		nodes []string
-		want  []string
-	}{
-		{
-			name:  "whole graph",
-			nodes: []string{"A", "B"},
-			want:  []string{"A", "B", "C", "D", "E", "F", "G"},
-		},
-		{
-			name:  "only leaves",
-			nodes: []string{"F", "G"},
-			want:  []string{"F", "G"},
-		},
-		{
-			name:  "simple dependent",
-			nodes: []string{"D"},
-			want:  []string{"D", "F"},
-		},
-		{
-			name:  "diamond dependents",
-			nodes: []string{"B"},
-			want:  []string{"B", "C", "D", "E", "F"},
-		},
-		{
-			name:  "partial graph",
-			nodes: []string{"A"},
-			want:  []string{"A", "C", "D", "F", "G"},
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			mx := sync.Mutex{}
-			expected := utils.Set[string]{}
-			expected.AddAll("C", "G", "D", "F")
-			var visited []string
-
-			gt := downDirectionTraversal(func(ctx context.Context, s string) error {
-				mx.Lock()
-				defer mx.Unlock()
-				visited = append(visited, s)
-				return nil
-			})
-			WithRootNodesAndDown(tt.nodes)(gt)
-			err := gt.visit(context.TODO(), graph)
-			assert.NilError(t, err)
-			sort.Strings(visited)
-			assert.DeepEqual(t, tt.want, visited)
-		})
-	}
-}
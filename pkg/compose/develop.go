@@ -0,0 +1,142 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// extReload declares, in a service develop section, a command to run in service containers after files are synced
+const extReload = "x-reload"
+
+// ReloadConfig is the typed form of the develop x-reload extension
+type ReloadConfig struct {
+	// Command runs in service containers after files have been synced
+	Command types.ShellCommand
+}
+
+// DevelopmentConfig returns the development configuration of a service, set by the `develop` attribute or the
+// deprecated `x-develop` extension, or nil if service has none
+func DevelopmentConfig(ctx context.Context, service types.ServiceConfig, project *types.Project) (*types.DevelopConfig, error) {
+	if service.Develop != nil {
+		return service.Develop, nil
+	}
+	return loadDevelopmentConfig(ctx, service, project)
+}
+
+// DevelopmentReload returns the live-reload command declared by develop config, or nil if none is
+func DevelopmentReload(config *types.DevelopConfig) (*ReloadConfig, error) {
+	if config == nil {
+		return nil, nil
+	}
+	y, ok := config.Extensions[extReload]
+	if !ok {
+		return nil, nil
+	}
+	if m, ok := y.(map[string]any); ok {
+		y = m["command"]
+	}
+	var reload ReloadConfig
+	switch command := y.(type) {
+	case string:
+		// as for healthcheck CMD-SHELL, a string command is run by container's default shell
+		reload.Command = types.ShellCommand{"/bin/sh", "-c", command}
+	default:
+		if err := mapstructure.Decode(command, &reload.Command); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", extReload, err)
+		}
+	}
+	if len(reload.Command) == 0 {
+		return nil, fmt.Errorf("%s must define a command", extReload)
+	}
+	return &reload, nil
+}
+
+func loadDevelopmentConfig(ctx context.Context, service types.ServiceConfig, project *types.Project) (*types.DevelopConfig, error) {
+	var config types.DevelopConfig
+	y, ok := service.Extensions["x-develop"]
+	if !ok {
+		return nil, nil
+	}
+	api.Warn(ctx, api.Warning{
+		Code:      api.WarningDeprecated,
+		Service:   service.Name,
+		Attribute: "x-develop",
+		Message:   "is DEPRECATED, please use the official `develop` attribute",
+	})
+	err := mapstructure.Decode(y, &config)
+	if err != nil {
+		return nil, err
+	}
+	baseDir, err := filepath.EvalSymlinks(project.WorkingDir)
+	if err != nil {
+		return nil, fmt.Errorf("resolving symlink for %q: %w", project.WorkingDir, err)
+	}
+
+	for i, trigger := range config.Watch {
+		if !filepath.IsAbs(trigger.Path) {
+			trigger.Path = filepath.Join(baseDir, trigger.Path)
+		}
+		if p, err := filepath.EvalSymlinks(trigger.Path); err == nil {
+			// this might fail because the path doesn't exist, etc.
+			trigger.Path = p
+		}
+		trigger.Path = filepath.Clean(trigger.Path)
+		if trigger.Path == "" {
+			return nil, errors.New("watch rules MUST define a path")
+		}
+
+		if trigger.Action == types.WatchActionRebuild && service.Build == nil {
+			return nil, fmt.Errorf("service %s doesn't have a build section, can't apply 'rebuild' on watch", service.Name)
+		}
+
+		config.Watch[i] = trigger
+	}
+	return &config, nil
+}
+
+// reloadService runs the develop x-reload command in service running containers
+func (s *composeService) reloadService(ctx context.Context, project *types.Project, service types.ServiceConfig, logTo api.LogConsumer) error {
+	config, err := DevelopmentConfig(ctx, service, project)
+	if err != nil {
+		return err
+	}
+	reload, err := DevelopmentReload(config)
+	if err != nil || reload == nil {
+		return err
+	}
+	containers, err := s.getContainers(ctx, project.Name, oneOffExclude, false, service.Name)
+	if err != nil {
+		return err
+	}
+	logTo.Log(api.WatchLogger, fmt.Sprintf("Reloading service %q", service.Name))
+	client := tarDockerClient{s: s}
+	for _, c := range containers {
+		if err := client.Exec(ctx, c.ID, reload.Command, nil); err != nil {
+			return fmt.Errorf("reloading %s: %w", getCanonicalContainerName(c), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestDevelopmentConfig(t *testing.T) {
+	develop := &types.DevelopConfig{Watch: []types.Trigger{{Path: "/src", Action: types.WatchActionSync, Target: "/app"}}}
+	project := &types.Project{WorkingDir: t.TempDir()}
+
+	config, err := DevelopmentConfig(context.Background(), types.ServiceConfig{Name: "web", Develop: develop}, project)
+	assert.NilError(t, err)
+	assert.Equal(t, config, develop)
+
+	config, err = DevelopmentConfig(context.Background(), types.ServiceConfig{Name: "web"}, project)
+	assert.NilError(t, err)
+	assert.Assert(t, config == nil)
+
+	config, err = DevelopmentConfig(context.Background(), types.ServiceConfig{
+		Name: "web",
+		Extensions: types.Extensions{"x-develop": map[string]any{
+			"watch": []any{map[string]any{"path": "/src", "action": "sync", "target": "/app"}},
+		}},
+	}, project)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, config.Watch, develop.Watch)
+}
+
+func TestDevelopmentReload(t *testing.T) {
+	tests := []struct {
+		name     string
+		reload   any
+		expected types.ShellCommand
+		err      string
+	}{
+		{name: "string", reload: "kill -HUP 1", expected: types.ShellCommand{"/bin/sh", "-c", "kill -HUP 1"}},
+		{name: "list", reload: []any{"nginx", "-s", "reload"}, expected: types.ShellCommand{"nginx", "-s", "reload"}},
+		{name: "command", reload: map[string]any{"command": []any{"nginx", "-s", "reload"}}, expected: types.ShellCommand{"nginx", "-s", "reload"}},
+		{name: "empty", reload: map[string]any{}, err: "x-reload must define a command"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reload, err := DevelopmentReload(&types.DevelopConfig{Extensions: types.Extensions{extReload: tt.reload}})
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, reload.Command, tt.expected)
+		})
+	}
+
+	reload, err := DevelopmentReload(&types.DevelopConfig{})
+	assert.NilError(t, err)
+	assert.Assert(t, reload == nil)
+}
@@ -0,0 +1,133 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/docker/compose/v2/pkg/utils"
+	cdi "tags.cncf.io/container-device-interface/pkg/parser"
+)
+
+var (
+	deviceCgroupRuleRegexp = regexp.MustCompile(`^([acb]) ([0-9]+|\*):([0-9]+|\*) ([rwm]{1,3})$`)
+	devicePermissionRegexp = regexp.MustCompile(`^[rwm]{1,3}$`)
+)
+
+// parseDevice parses a `devices` entry, either a CDI device name (vendor.com/class=name)
+// which is returned as a device request, or a HOST:CONTAINER:PERMISSIONS mapping
+func parseDevice(device string) (*container.DeviceMapping, *container.DeviceRequest, error) {
+	if cdi.IsQualifiedName(device) {
+		return nil, &container.DeviceRequest{
+			Driver:    "cdi",
+			DeviceIDs: []string{device},
+		}, nil
+	}
+
+	// FIXME should use docker/cli parseDevice, unfortunately private
+	src := ""
+	dst := ""
+	permissions := "rwm"
+	arr := strings.Split(device, ":")
+	switch len(arr) {
+	case 3:
+		permissions = arr[2]
+		fallthrough
+	case 2:
+		dst = arr[1]
+		fallthrough
+	case 1:
+		src = arr[0]
+	default:
+		return nil, nil, fmt.Errorf("invalid device specification: %s", device)
+	}
+	if dst == "" {
+		dst = src
+	}
+	if !devicePermissionRegexp.MatchString(permissions) {
+		return nil, nil, fmt.Errorf("invalid device permissions %q in %s", permissions, device)
+	}
+	return &container.DeviceMapping{
+		PathOnHost:        src,
+		PathInContainer:   dst,
+		CgroupPermissions: permissions,
+	}, nil, nil
+}
+
+// validateDevices checks `devices` and `device_cgroup_rules` for all services before any container gets created,
+// so a typo doesn't surface as a daemon error after some services already started.
+// Devices host paths can only be checked when engine runs on the local host, as reported by isLocalEngine
+func validateDevices(project *types.Project, isLocalEngine func() bool) error {
+	var errs *multierror.Error
+	for _, service := range project.Services {
+		for _, device := range service.Devices {
+			mapping, _, err := parseDevice(device)
+			if err != nil {
+				errs = multierror.Append(errs, fmt.Errorf("service %q: %w", service.Name, err))
+				continue
+			}
+			if mapping == nil || !isLocalEngine() {
+				continue
+			}
+			if _, err := os.Stat(mapping.PathOnHost); err != nil {
+				errs = multierror.Append(errs, fmt.Errorf("service %q: device %s is not available: %w", service.Name, mapping.PathOnHost, err))
+			}
+		}
+		for _, rule := range service.DeviceCgroupRules {
+			if !deviceCgroupRuleRegexp.MatchString(rule) {
+				errs = multierror.Append(errs, fmt.Errorf("service %q: invalid device cgroup rule %q", service.Name, rule))
+			}
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// isLocalEngine tells if the Docker engine runs on this host, which is not the case for Docker Desktop
+// as engine runs inside a VM
+func (s *composeService) isLocalEngine() bool {
+	return runtime.GOOS == "linux" && !s.isDesktopIntegrationActive() &&
+		strings.HasPrefix(s.dockerCli.DockerEndpoint().Host, "unix://")
+}
+
+// deviceDriverError makes the engine error explicit when a device driver requested by service reservations,
+// typically `nvidia` for GPUs, is not available
+func deviceDriverError(service types.ServiceConfig, err error) error {
+	if !strings.Contains(err.Error(), "could not select device driver") ||
+		service.Deploy == nil || service.Deploy.Resources.Reservations == nil {
+		return err
+	}
+	for _, device := range service.Deploy.Resources.Reservations.Devices {
+		if !strings.Contains(err.Error(), fmt.Sprintf("%q", device.Driver)) {
+			continue
+		}
+		hint := ""
+		if device.Driver == "nvidia" || utils.StringContains(device.Capabilities, "gpu") {
+			hint = ", make sure the NVIDIA Container Toolkit is installed and the engine restarted"
+		}
+		return fmt.Errorf("service %q requires device driver %q with capabilities %v which is not available on the Docker engine%s: %w",
+			service.Name, device.Driver, device.Capabilities, hint, err)
+	}
+	return err
+}
@@ -0,0 +1,122 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"gotest.tools/v3/assert"
+)
+
+func TestParseDevice(t *testing.T) {
+	mapping, request, err := parseDevice("/dev/sda:/dev/xvda:rw")
+	assert.NilError(t, err)
+	assert.Check(t, request == nil)
+	assert.DeepEqual(t, *mapping, container.DeviceMapping{
+		PathOnHost:        "/dev/sda",
+		PathInContainer:   "/dev/xvda",
+		CgroupPermissions: "rw",
+	})
+
+	mapping, _, err = parseDevice("/dev/fuse")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, *mapping, container.DeviceMapping{
+		PathOnHost:        "/dev/fuse",
+		PathInContainer:   "/dev/fuse",
+		CgroupPermissions: "rwm",
+	})
+
+	mapping, request, err = parseDevice("nvidia.com/gpu=all")
+	assert.NilError(t, err)
+	assert.Check(t, mapping == nil)
+	assert.DeepEqual(t, *request, container.DeviceRequest{
+		Driver:    "cdi",
+		DeviceIDs: []string{"nvidia.com/gpu=all"},
+	})
+
+	_, _, err = parseDevice("/dev/sda:/dev/xvda:rwx")
+	assert.Error(t, err, `invalid device permissions "rwx" in /dev/sda:/dev/xvda:rwx`)
+}
+
+func TestValidateDevices(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			"valid": {
+				Name:              "valid",
+				Devices:           []string{"vendor.com/device=foo"},
+				DeviceCgroupRules: []string{"c 1:3 mr", "a *:* rwm"},
+			},
+			"invalid": {
+				Name:              "invalid",
+				Devices:           []string{"/dev/does-not-exist"},
+				DeviceCgroupRules: []string{"x 1:3 mr"},
+			},
+		},
+	}
+	err := validateDevices(project, func() bool { return false })
+	assert.ErrorContains(t, err, `service "invalid": invalid device cgroup rule "x 1:3 mr"`)
+
+	err = validateDevices(project, func() bool { return true })
+	assert.ErrorContains(t, err, "2 errors occurred")
+	assert.ErrorContains(t, err, `service "invalid": device /dev/does-not-exist is not available`)
+}
+
+func TestGetDeployResourcesDeviceRequests(t *testing.T) {
+	service := types.ServiceConfig{
+		Name: "gpu",
+		Deploy: &types.DeployConfig{
+			Resources: types.Resources{
+				Reservations: &types.Resource{
+					Devices: []types.DeviceRequest{
+						{Driver: "nvidia", Capabilities: []string{"gpu"}},
+						{Driver: "nvidia", Capabilities: []string{"gpu", "utility"}, Count: 2},
+						{Driver: "nvidia", Capabilities: []string{"gpu"}, IDs: []string{"GPU-1"}},
+					},
+				},
+			},
+		},
+	}
+	resources := getDeployResources(service)
+	assert.DeepEqual(t, resources.DeviceRequests, []container.DeviceRequest{
+		{Driver: "nvidia", Capabilities: [][]string{{"gpu"}}, Count: -1},
+		{Driver: "nvidia", Capabilities: [][]string{{"gpu", "utility"}}, Count: 2},
+		{Driver: "nvidia", Capabilities: [][]string{{"gpu"}}, DeviceIDs: []string{"GPU-1"}},
+	})
+}
+
+func TestDeviceDriverError(t *testing.T) {
+	service := types.ServiceConfig{
+		Name: "gpu",
+		Deploy: &types.DeployConfig{
+			Resources: types.Resources{
+				Reservations: &types.Resource{
+					Devices: []types.DeviceRequest{{Driver: "nvidia", Capabilities: []string{"gpu"}}},
+				},
+			},
+		},
+	}
+	engineErr := errors.New(`Error response from daemon: could not select device driver "nvidia" with capabilities: [[gpu]]`)
+	err := deviceDriverError(service, engineErr)
+	assert.Assert(t, errors.Is(err, engineErr))
+	assert.ErrorContains(t, err, `service "gpu" requires device driver "nvidia" with capabilities [gpu] which is not available on the Docker engine, make sure the NVIDIA Container Toolkit is installed`)
+
+	other := errors.New("port is already allocated")
+	assert.Equal(t, deviceDriverError(service, other), other)
+}
@@ -18,8 +18,10 @@ package compose
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/compose-spec/compose-go/v2/types"
@@ -38,20 +40,33 @@ import (
 type downOp func() error
 
 func (s *composeService) Down(ctx context.Context, projectName string, options api.DownOptions) error {
-	return progress.Run(ctx, func(ctx context.Context) error {
-		return s.down(ctx, strings.ToLower(projectName), options)
+	projectName = strings.ToLower(projectName)
+	if options.RemoveOrphans && options.Project != nil {
+		confirm, err := s.confirmOrphansRemoval(ctx, options.Project, options.AssumeYes)
+		if err != nil {
+			return err
+		}
+		// user declined, project is still brought down but orphan containers are kept
+		options.RemoveOrphans = confirm
+	}
+	if options.Removed != nil {
+		ctx = withRemovedReporter(ctx, options.Removed)
+	}
+	err := progress.Run(ctx, func(ctx context.Context) error {
+		return s.withProjectLock(ctx, projectName, func() error {
+			return s.down(ctx, projectName, options)
+		})
 	}, s.stdinfo())
+	s.runHook(ctx, options.Project, hookOnDown, "down", options.Services, err)
+	return err
 }
 
 func (s *composeService) down(ctx context.Context, projectName string, options api.DownOptions) error { //nolint:gocyclo
 	w := progress.ContextWriter(ctx)
 	resourceToRemove := false
 
-	include := oneOffExclude
-	if options.RemoveOrphans {
-		include = oneOffInclude
-	}
-	containers, err := s.getContainers(ctx, projectName, include, true)
+	// one-off containers created by `compose run` for project services are removed along with the service
+	containers, err := s.getContainers(ctx, projectName, oneOffInclude, true)
 	if err != nil {
 		return err
 	}
@@ -64,56 +79,106 @@ func (s *composeService) down(ctx context.Context, projectName string, options a
 		}
 	}
 
+	// secrets stored on host are shared by all project containers, so only get removed when the whole project is down
+	fullDown := len(options.Services) == 0 && removes(options, api.ResourceContainer)
+
 	// Check requested services exists in model
 	options.Services, err = checkSelectedServices(options, project)
 	if err != nil {
 		return err
 	}
 
+	if !removes(options, api.ResourceContainer) {
+		containers = nil
+	}
 	if len(containers) > 0 {
 		resourceToRemove = true
 	}
 
+	// removal goes on after a failure, so all resources which can be are removed, and errors are reported at the end
+	var errs []error
 	err = InReverseDependencyOrder(ctx, project, func(c context.Context, service string) error {
 		serviceContainers := containers.filter(isService(service))
-		err := s.removeContainers(ctx, serviceContainers, options.Timeout, options.Volumes)
+		err := s.removeContainers(ctx, serviceContainers, options.Timeout, options.Volumes && removes(options, api.ResourceVolume))
 		return err
-	}, WithRootNodesAndDown(options.Services))
+	}, WithRootNodesAndDown(options.Services), WithContinueOnError())
 	if err != nil {
-		return err
+		errs = append(errs, err)
+	}
+
+	if fullDown && len(errs) == 0 {
+		s.removeSecretsRuntimeDir(ctx, projectName)
+	}
+
+	if options.Project != nil {
+		if _, err := s.runProviders(ctx, project, "down", options.Services); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
 	orphans := containers.filter(isOrphaned(project))
 	if options.RemoveOrphans && len(orphans) > 0 {
 		err := s.removeContainers(ctx, orphans, options.Timeout, false)
 		if err != nil {
-			return err
+			errs = append(errs, err)
 		}
 	}
 
-	ops := s.ensureNetworksDown(ctx, project, w)
+	var ops []downOp
+	if removes(options, api.ResourceNetwork) {
+		ops = s.ensureNetworksDown(ctx, project, w)
+	}
 
-	if options.Images != "" {
+	if options.Images != "" && removes(options, api.ResourceImage) {
 		imgOps, err := s.ensureImagesDown(ctx, project, options, w)
 		if err != nil {
-			return err
+			errs = append(errs, err)
 		}
 		ops = append(ops, imgOps...)
 	}
 
-	if options.Volumes {
+	if options.Volumes && removes(options, api.ResourceVolume) {
 		ops = append(ops, s.ensureVolumesDown(ctx, project, w)...)
 	}
 
 	if !resourceToRemove && len(ops) == 0 {
-		fmt.Fprintf(s.stderr(), "Warning: No resource found to remove for project %q.\n", projectName)
+		api.Warn(ctx, api.Warning{
+			Code:    api.WarningNoResource,
+			Message: fmt.Sprintf("No resource found to remove for project %q.", projectName),
+		})
 	}
 
 	eg, _ := errgroup.WithContext(ctx)
 	for _, op := range ops {
 		eg.Go(op)
 	}
-	return eg.Wait()
+	if err := eg.Wait(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// removes tells if down has to remove resources of this kind
+func removes(options api.DownOptions, kind string) bool {
+	return len(options.Resources) == 0 || utils.StringContains(options.Resources, kind)
+}
+
+type removedReporterKey struct{}
+
+// withRemovedReporter sets the func called with each resource removed, as reported by down
+func withRemovedReporter(ctx context.Context, fn func(resource api.RemovedResource)) context.Context {
+	var mu sync.Mutex
+	return context.WithValue(ctx, removedReporterKey{}, func(resource api.RemovedResource) {
+		mu.Lock()
+		defer mu.Unlock()
+		fn(resource)
+	})
+}
+
+func reportRemoved(ctx context.Context, kind string, name string) {
+	if fn, ok := ctx.Value(removedReporterKey{}).(func(resource api.RemovedResource)); ok {
+		fn(api.RemovedResource{Type: kind, Name: name})
+	}
 }
 
 func checkSelectedServices(options api.DownOptions, project *types.Project) ([]string, error) {
@@ -141,6 +206,7 @@ func (s *composeService) ensureVolumesDown(ctx context.Context, project *types.P
 		}
 		volumeName := vol.Name
 		ops = append(ops, func() error {
+			s.warnForeignVolume(ctx, volumeName, project.Name)
 			return s.removeVolume(ctx, volumeName, w)
 		})
 	}
@@ -236,6 +302,7 @@ func (s *composeService) removeNetwork(ctx context.Context, composeNetworkName s
 			return fmt.Errorf("failed to remove network %s: %w", name, err)
 		}
 		w.Event(progress.RemovedEvent(eventName))
+		reportRemoved(ctx, api.ResourceNetwork, name)
 		found++
 	}
 
@@ -255,6 +322,7 @@ func (s *composeService) removeImage(ctx context.Context, image string, w progre
 	_, err := s.apiClient().ImageRemove(ctx, image, imageapi.RemoveOptions{})
 	if err == nil {
 		w.Event(progress.NewEvent(id, progress.Done, "Removed"))
+		reportRemoved(ctx, api.ResourceImage, image)
 		return nil
 	}
 	if errdefs.IsConflict(err) {
@@ -268,12 +336,29 @@ func (s *composeService) removeImage(ctx context.Context, image string, w progre
 	return err
 }
 
+// warnForeignVolume warns when a volume declared by project, but not as external, has not been created by Compose
+// for this project, as it might hold data user doesn't expect `down -v` to delete
+func (s *composeService) warnForeignVolume(ctx context.Context, id string, projectName string) {
+	inspected, err := s.apiClient().VolumeInspect(ctx, id)
+	if err != nil {
+		return
+	}
+	if inspected.Labels[api.ProjectLabel] != projectName {
+		api.Warn(ctx, api.Warning{
+			Code: api.WarningUnmanagedResource,
+			Message: fmt.Sprintf("volume %q was not created by Docker Compose for project %q, it will be removed. "+
+				"Declare it as `external: true` to preserve it", id, projectName),
+		})
+	}
+}
+
 func (s *composeService) removeVolume(ctx context.Context, id string, w progress.Writer) error {
 	resource := fmt.Sprintf("Volume %s", id)
 	w.Event(progress.NewEvent(resource, progress.Working, "Removing"))
 	err := s.apiClient().VolumeRemove(ctx, id, true)
 	if err == nil {
 		w.Event(progress.NewEvent(resource, progress.Done, "Removed"))
+		reportRemoved(ctx, api.ResourceVolume, id)
 		return nil
 	}
 	if errdefs.IsConflict(err) {
@@ -338,6 +423,9 @@ func (s *composeService) stopAndRemoveContainer(ctx context.Context, container m
 		w.Event(progress.ErrorMessageEvent(eventName, "Error while Removing"))
 		return err
 	}
+	if err == nil {
+		reportRemoved(ctx, api.ResourceContainer, getCanonicalContainerName(container))
+	}
 	w.Event(progress.RemovedEvent(eventName))
 	return nil
 }
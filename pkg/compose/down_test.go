@@ -18,6 +18,7 @@ package compose
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -47,11 +48,12 @@ func TestDown(t *testing.T) {
 		dockerCli: cli,
 	}
 
-	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt(false)).Return(
+	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt(true)).Return(
 		[]moby.Container{
 			testContainer("service1", "123", false),
 			testContainer("service2", "456", false),
 			testContainer("service2", "789", false),
+			testContainer("service1", "654", true),
 			testContainer("service_orphan", "321", true),
 		}, nil)
 	api.EXPECT().VolumeList(
@@ -73,10 +75,12 @@ func TestDown(t *testing.T) {
 	api.EXPECT().ContainerStop(gomock.Any(), "123", stopOptions).Return(nil)
 	api.EXPECT().ContainerStop(gomock.Any(), "456", stopOptions).Return(nil)
 	api.EXPECT().ContainerStop(gomock.Any(), "789", stopOptions).Return(nil)
+	api.EXPECT().ContainerStop(gomock.Any(), "654", stopOptions).Return(nil)
 
 	api.EXPECT().ContainerRemove(gomock.Any(), "123", containerType.RemoveOptions{Force: true}).Return(nil)
 	api.EXPECT().ContainerRemove(gomock.Any(), "456", containerType.RemoveOptions{Force: true}).Return(nil)
 	api.EXPECT().ContainerRemove(gomock.Any(), "789", containerType.RemoveOptions{Force: true}).Return(nil)
+	api.EXPECT().ContainerRemove(gomock.Any(), "654", containerType.RemoveOptions{Force: true}).Return(nil)
 
 	api.EXPECT().NetworkList(gomock.Any(), moby.NetworkListOptions{
 		Filters: filters.NewArgs(
@@ -145,6 +149,54 @@ func TestDownRemoveOrphans(t *testing.T) {
 	assert.NilError(t, err)
 }
 
+func TestDownContinuesOnError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt(true)).Return(
+		[]moby.Container{
+			testContainer("service1", "123", false),
+			testContainer("service2", "456", false),
+		}, nil)
+	api.EXPECT().VolumeList(
+		gomock.Any(),
+		volume.ListOptions{
+			Filters: filters.NewArgs(projectFilter(strings.ToLower(testProject))),
+		}).
+		Return(volume.ListResponse{}, nil)
+	api.EXPECT().NetworkList(gomock.Any(), moby.NetworkListOptions{Filters: filters.NewArgs(projectFilter(strings.ToLower(testProject)))}).
+		Return([]moby.NetworkResource{
+			{
+				Name:   "myProject_default",
+				Labels: map[string]string{compose.NetworkLabel: "default"},
+			}}, nil)
+
+	stopOptions := containerType.StopOptions{}
+	api.EXPECT().ContainerStop(gomock.Any(), "123", stopOptions).Return(nil)
+	api.EXPECT().ContainerStop(gomock.Any(), "456", stopOptions).Return(nil)
+
+	api.EXPECT().ContainerRemove(gomock.Any(), "123", containerType.RemoveOptions{Force: true}).Return(errors.New("removal failed"))
+	api.EXPECT().ContainerRemove(gomock.Any(), "456", containerType.RemoveOptions{Force: true}).Return(nil)
+
+	// network removal is still attempted after a container failed to be removed
+	api.EXPECT().NetworkList(gomock.Any(), moby.NetworkListOptions{
+		Filters: filters.NewArgs(
+			networkFilter("default"),
+			projectFilter(strings.ToLower(testProject)),
+		),
+	}).Return([]moby.NetworkResource{{ID: "abc123", Name: "myProject_default"}}, nil)
+	api.EXPECT().NetworkInspect(gomock.Any(), "abc123", gomock.Any()).Return(moby.NetworkResource{ID: "abc123"}, nil)
+	api.EXPECT().NetworkRemove(gomock.Any(), "abc123").Return(nil)
+
+	err := tested.Down(context.Background(), strings.ToLower(testProject), compose.DownOptions{})
+	assert.ErrorContains(t, err, "removal failed")
+}
+
 func TestDownRemoveVolumes(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -154,7 +206,7 @@ func TestDownRemoveVolumes(t *testing.T) {
 		dockerCli: cli,
 	}
 
-	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt(false)).Return(
+	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt(true)).Return(
 		[]moby.Container{testContainer("service1", "123", false)}, nil)
 	api.EXPECT().VolumeList(
 		gomock.Any(),
@@ -170,12 +222,67 @@ func TestDownRemoveVolumes(t *testing.T) {
 	api.EXPECT().ContainerStop(gomock.Any(), "123", containerType.StopOptions{}).Return(nil)
 	api.EXPECT().ContainerRemove(gomock.Any(), "123", containerType.RemoveOptions{Force: true, RemoveVolumes: true}).Return(nil)
 
+	api.EXPECT().VolumeInspect(gomock.Any(), "myProject_volume").Return(volume.Volume{
+		Name:   "myProject_volume",
+		Labels: map[string]string{compose.ProjectLabel: strings.ToLower(testProject)},
+	}, nil)
 	api.EXPECT().VolumeRemove(gomock.Any(), "myProject_volume", true).Return(nil)
 
 	err := tested.Down(context.Background(), strings.ToLower(testProject), compose.DownOptions{Volumes: true})
 	assert.NilError(t, err)
 }
 
+func TestDownResources(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt(true)).Return(
+		[]moby.Container{testContainer("service1", "123", false)}, nil)
+	api.EXPECT().VolumeList(
+		gomock.Any(),
+		volume.ListOptions{
+			Filters: filters.NewArgs(projectFilter(strings.ToLower(testProject))),
+		}).
+		Return(volume.ListResponse{
+			Volumes: []*volume.Volume{{Name: "myProject_volume"}},
+		}, nil)
+	api.EXPECT().NetworkList(gomock.Any(), moby.NetworkListOptions{Filters: filters.NewArgs(projectFilter(strings.ToLower(testProject)))}).
+		Return([]moby.NetworkResource{
+			{ID: "abc123", Name: "myProject_default", Labels: map[string]string{compose.NetworkLabel: "default"}},
+		}, nil)
+
+	// volumes are not selected, so those are kept even with Volumes set
+	api.EXPECT().ContainerStop(gomock.Any(), "123", containerType.StopOptions{}).Return(nil)
+	api.EXPECT().ContainerRemove(gomock.Any(), "123", containerType.RemoveOptions{Force: true}).Return(nil)
+
+	api.EXPECT().NetworkList(gomock.Any(), moby.NetworkListOptions{
+		Filters: filters.NewArgs(
+			projectFilter(strings.ToLower(testProject)),
+			networkFilter("default")),
+	}).Return([]moby.NetworkResource{{ID: "abc123", Name: "myProject_default"}}, nil)
+	api.EXPECT().NetworkInspect(gomock.Any(), "abc123", gomock.Any()).Return(moby.NetworkResource{ID: "abc123"}, nil)
+	api.EXPECT().NetworkRemove(gomock.Any(), "abc123").Return(nil)
+
+	var removed []compose.RemovedResource
+	err := tested.Down(context.Background(), strings.ToLower(testProject), compose.DownOptions{
+		Volumes:   true,
+		Resources: []string{compose.ResourceContainer, compose.ResourceNetwork},
+		Removed: func(resource compose.RemovedResource) {
+			removed = append(removed, resource)
+		},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, removed, []compose.RemovedResource{
+		{Type: compose.ResourceContainer, Name: "123"},
+		{Type: compose.ResourceNetwork, Name: "myProject_default"},
+	})
+}
+
 func TestDownRemoveImages(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -199,7 +306,7 @@ func TestDownRemoveImages(t *testing.T) {
 		dockerCli: cli,
 	}
 
-	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt(false)).
+	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt(true)).
 		Return([]moby.Container{
 			testContainer("service1", "123", false),
 		}, nil).
@@ -290,7 +397,7 @@ func TestDownRemoveImages_NoLabel(t *testing.T) {
 
 	container := testContainer("service1", "123", false)
 
-	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt(false)).Return(
+	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt(true)).Return(
 		[]moby.Container{container}, nil)
 
 	api.EXPECT().VolumeList(
@@ -0,0 +1,186 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
+	moby "github.com/docker/docker/api/types"
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	extDrain = "x-drain"
+
+	defaultDrainInterval = time.Second
+	defaultDrainTimeout  = 30 * time.Second
+)
+
+// drainConfig is the `x-drain` service extension: a probe command run inside service containers
+// which exits with status 0 once the container has no more active connections
+type drainConfig struct {
+	Command  []string
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+type rawDrainConfig struct {
+	Command  any    `mapstructure:"command"`
+	Interval string `mapstructure:"interval"`
+	Timeout  string `mapstructure:"timeout"`
+}
+
+// getDrainConfig parses service `x-drain` extension, returns nil if service doesn't declare one
+func getDrainConfig(service types.ServiceConfig) (*drainConfig, error) {
+	y, ok := service.Extensions[extDrain]
+	if !ok {
+		return nil, nil
+	}
+	var raw rawDrainConfig
+	if err := mapstructure.Decode(y, &raw); err != nil {
+		return nil, fmt.Errorf("service %q: invalid %s: %w", service.Name, extDrain, err)
+	}
+
+	config := drainConfig{
+		Interval: defaultDrainInterval,
+		Timeout:  defaultDrainTimeout,
+	}
+	switch cmd := raw.Command.(type) {
+	case string:
+		config.Command = []string{"/bin/sh", "-c", cmd}
+	case []any:
+		for _, arg := range cmd {
+			config.Command = append(config.Command, fmt.Sprint(arg))
+		}
+	case []string:
+		config.Command = cmd
+	}
+	if len(config.Command) == 0 {
+		return nil, fmt.Errorf("service %q: %s requires a command", service.Name, extDrain)
+	}
+
+	var err error
+	if raw.Interval != "" {
+		if config.Interval, err = time.ParseDuration(raw.Interval); err != nil {
+			return nil, fmt.Errorf("service %q: invalid %s interval: %w", service.Name, extDrain, err)
+		}
+	}
+	if raw.Timeout != "" {
+		if config.Timeout, err = time.ParseDuration(raw.Timeout); err != nil {
+			return nil, fmt.Errorf("service %q: invalid %s timeout: %w", service.Name, extDrain, err)
+		}
+	}
+	return &config, nil
+}
+
+// drainContainers runs the drain probe in all running containers until it succeeds or times out.
+// Failures are reported as warnings, as draining is best effort and must not prevent containers from being stopped
+func (s *composeService) drainContainers(ctx context.Context, w progress.Writer, service string, config drainConfig, containers Containers) {
+	var wg sync.WaitGroup
+	for _, container := range containers.filter(isRunning()) {
+		container := container
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			eventName := getContainerProgressName(container)
+			w.Event(progress.NewEvent(eventName, progress.Working, "Draining"))
+			drained, err := s.waitDrained(ctx, container.ID, config)
+			switch {
+			case err != nil:
+				w.Event(progress.NewEvent(eventName, progress.Warning, "Error while Draining"))
+				api.Warn(ctx, api.Warning{
+					Code:      api.WarningOperationFailed,
+					Service:   service,
+					Attribute: extDrain,
+					Message:   fmt.Sprintf("failed to drain container %s: %v", getCanonicalContainerName(container), err),
+				})
+			case !drained:
+				w.Event(progress.NewEvent(eventName, progress.Warning, fmt.Sprintf("Not drained after %s", config.Timeout)))
+			default:
+				w.Event(progress.NewEvent(eventName, progress.Done, "Drained"))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (s *composeService) waitDrained(ctx context.Context, containerID string, config drainConfig) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.Timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(config.Interval)
+	defer ticker.Stop()
+	for {
+		exitCode, err := s.runProbe(ctx, containerID, config.Command)
+		if errors.Is(err, context.DeadlineExceeded) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if exitCode == 0 {
+			return true, nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return false, nil
+			}
+			return false, ctx.Err()
+		}
+	}
+}
+
+// runProbe executes a command inside container and returns its exit code, discarding output
+func (s *composeService) runProbe(ctx context.Context, containerID string, cmd []string) (int, error) {
+	exec, err := s.apiClient().ContainerExecCreate(ctx, containerID, moby.ExecConfig{
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	conn, err := s.apiClient().ContainerExecAttach(ctx, exec.ID, moby.ExecStartCheck{})
+	if err != nil {
+		return 0, err
+	}
+	_, err = io.Copy(io.Discard, conn.Reader)
+	conn.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	inspect, err := s.apiClient().ContainerExecInspect(ctx, exec.ID)
+	if err != nil {
+		return 0, err
+	}
+	if inspect.Running {
+		return 0, fmt.Errorf("drain probe still running in container %s", containerID)
+	}
+	return inspect.ExitCode, nil
+}
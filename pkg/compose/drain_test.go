@@ -0,0 +1,93 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	moby "github.com/docker/docker/api/types"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
+)
+
+func TestGetDrainConfig(t *testing.T) {
+	config, err := getDrainConfig(types.ServiceConfig{Name: "test"})
+	assert.NilError(t, err)
+	assert.Assert(t, config == nil)
+
+	config, err = getDrainConfig(types.ServiceConfig{
+		Name: "test",
+		Extensions: types.Extensions{
+			extDrain: map[string]any{
+				"command": "test $(ss -H state established | wc -l) -eq 0",
+				"timeout": "10s",
+			},
+		},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, config.Command, []string{"/bin/sh", "-c", "test $(ss -H state established | wc -l) -eq 0"})
+	assert.Equal(t, config.Interval, defaultDrainInterval)
+	assert.Equal(t, config.Timeout, 10*time.Second)
+
+	config, err = getDrainConfig(types.ServiceConfig{
+		Name: "test",
+		Extensions: types.Extensions{
+			extDrain: map[string]any{
+				"command":  []any{"/drain.sh", "--check"},
+				"interval": "200ms",
+			},
+		},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, config.Command, []string{"/drain.sh", "--check"})
+	assert.Equal(t, config.Interval, 200*time.Millisecond)
+
+	_, err = getDrainConfig(types.ServiceConfig{
+		Name:       "test",
+		Extensions: types.Extensions{extDrain: map[string]any{}},
+	})
+	assert.ErrorContains(t, err, "x-drain requires a command")
+}
+
+func TestDrainContainersFailure(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	apiClient, cli := prepareMocks(mockCtrl)
+	tested := composeService{dockerCli: cli}
+
+	containers := Containers{
+		{ID: "123", Names: []string{"/myapp-web-1"}, State: "running"},
+		{ID: "456", Names: []string{"/myapp-web-2"}, State: "running"},
+	}
+	// a failure on one container doesn't cancel draining the others
+	apiClient.EXPECT().ContainerExecCreate(gomock.Any(), "123", gomock.Any()).Return(moby.IDResponse{}, errors.New("exec failed"))
+	apiClient.EXPECT().ContainerExecCreate(gomock.Any(), "456", gomock.Any()).Return(moby.IDResponse{}, errors.New("exec failed"))
+
+	collector := &api.WarningCollector{}
+	ctx := api.WithWarningCollector(context.Background(), collector)
+	config := drainConfig{Command: []string{"true"}, Interval: time.Second, Timeout: time.Minute}
+	tested.drainContainers(ctx, progress.ContextWriter(ctx), "web", config, containers)
+	assert.Equal(t, len(collector.Warnings()), 2)
+}
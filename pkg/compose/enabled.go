@@ -0,0 +1,126 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// extEnabled is a service extension set as a boolean, typically by interpolation as "${ENABLE_MONITORING:-false}",
+// so optional services can be toggled without override files
+const extEnabled = "x-enabled"
+
+// WithoutDisabledServices moves the services which `x-enabled` extension evaluates to false to the project disabled
+// services, as profiles do, so their existing containers are still managed, and returns their names. Enabled services
+// can't require a disabled one, optional dependencies on disabled services are removed
+func WithoutDisabledServices(project *types.Project) (*types.Project, []string, error) {
+	var disabled []string
+	for _, name := range project.ServiceNames() {
+		enabled, err := isServiceEnabled(project.Services[name])
+		if err != nil {
+			return nil, nil, err
+		}
+		if !enabled {
+			disabled = append(disabled, name)
+		}
+	}
+	// services disabled by profiles can be enabled when selected by name, unless x-enabled disables them as well
+	for _, name := range sortedKeys(project.DisabledServices) {
+		enabled, err := isServiceEnabled(project.DisabledServices[name])
+		if err != nil {
+			return nil, nil, err
+		}
+		if !enabled {
+			disabled = append(disabled, name)
+		}
+	}
+	if len(disabled) == 0 {
+		return project, nil, nil
+	}
+	isDisabled := func(name string) bool {
+		for _, d := range disabled {
+			if d == name {
+				return true
+			}
+		}
+		return false
+	}
+	for _, name := range disabled {
+		service, ok := project.Services[name]
+		if !ok {
+			continue
+		}
+		if project.DisabledServices == nil {
+			project.DisabledServices = types.Services{}
+		}
+		project.DisabledServices[name] = service
+		delete(project.Services, name)
+	}
+
+	var errs *multierror.Error
+	for _, name := range project.ServiceNames() {
+		service := project.Services[name]
+		for _, dependency := range sortedKeys(service.DependsOn) {
+			if !isDisabled(dependency) {
+				continue
+			}
+			if service.DependsOn[dependency].Required {
+				errs = multierror.Append(errs, api.DisabledServiceError{Service: dependency, ReferencedBy: name, Attribute: "depends_on"})
+				continue
+			}
+			delete(service.DependsOn, dependency)
+		}
+		refs := namespaceReferences(service)
+		for _, attribute := range sortedKeys(refs) {
+			if isDisabled(refs[attribute]) {
+				errs = multierror.Append(errs, api.DisabledServiceError{Service: refs[attribute], ReferencedBy: name, Attribute: attribute})
+			}
+		}
+		for _, volumesFrom := range service.VolumesFrom {
+			if target, _, _ := strings.Cut(volumesFrom, ":"); isDisabled(target) {
+				errs = multierror.Append(errs, api.DisabledServiceError{Service: target, ReferencedBy: name, Attribute: "volumes_from"})
+			}
+		}
+		project.Services[name] = service
+	}
+	return project, disabled, errs.ErrorOrNil()
+}
+
+// isServiceEnabled evaluates service `x-enabled` extension, services are enabled by default
+func isServiceEnabled(service types.ServiceConfig) (bool, error) {
+	switch v := service.Extensions[extEnabled].(type) {
+	case nil:
+		return true, nil
+	case bool:
+		return v, nil
+	case string:
+		enabled, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return false, api.InvalidAttributeError{Service: service.Name, Attribute: extEnabled, Value: v, Reason: "must be a boolean"}
+		}
+		return enabled, nil
+	default:
+		return false, api.InvalidAttributeError{Service: service.Name, Attribute: extEnabled, Value: fmt.Sprint(v), Reason: "must be a boolean"}
+	}
+}
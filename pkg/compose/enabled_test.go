@@ -0,0 +1,85 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+func TestWithoutDisabledServices(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			"web": {
+				Name: "web",
+				DependsOn: types.DependsOnConfig{
+					"db":         {Condition: types.ServiceConditionStarted, Required: true},
+					"monitoring": {Condition: types.ServiceConditionStarted, Required: false},
+				},
+			},
+			"db":         {Name: "db", Extensions: types.Extensions{extEnabled: "true"}},
+			"monitoring": {Name: "monitoring", Extensions: types.Extensions{extEnabled: "false"}},
+			"tracing":    {Name: "tracing", Extensions: types.Extensions{extEnabled: false}},
+		},
+		DisabledServices: types.Services{
+			"debug": {Name: "debug", Profiles: []string{"debug"}, Extensions: types.Extensions{extEnabled: "0"}},
+		},
+	}
+	project, disabled, err := WithoutDisabledServices(project)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, disabled, []string{"monitoring", "tracing", "debug"})
+	assert.DeepEqual(t, project.ServiceNames(), []string{"db", "web"})
+	assert.Check(t, is.Len(project.DisabledServices, 3))
+	_, ok := project.DisabledServices["monitoring"]
+	assert.Check(t, ok)
+	_, ok = project.Services["web"].DependsOn["monitoring"]
+	assert.Check(t, !ok)
+}
+
+func TestWithoutDisabledServicesRequired(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			"web": {
+				Name:        "web",
+				DependsOn:   types.DependsOnConfig{"db": {Condition: types.ServiceConditionHealthy, Required: true}},
+				NetworkMode: "service:db",
+			},
+			"db": {Name: "db", Extensions: types.Extensions{extEnabled: "false"}},
+		},
+	}
+	_, _, err := WithoutDisabledServices(project)
+	assert.Check(t, is.ErrorContains(err, `service "web" depends_on refers to service "db" which is disabled by x-enabled`))
+	assert.Check(t, is.ErrorContains(err, `service "web" network_mode refers to service "db" which is disabled by x-enabled`))
+	var disabled api.DisabledServiceError
+	assert.Check(t, errors.As(err, &disabled))
+}
+
+func TestWithoutDisabledServicesInvalid(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			"db": {Name: "db", Extensions: types.Extensions{extEnabled: "maybe"}},
+		},
+	}
+	_, _, err := WithoutDisabledServices(project)
+	assert.Error(t, err, `service "db": invalid x-enabled "maybe": must be a boolean`)
+}
@@ -0,0 +1,55 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	moby "github.com/docker/docker/api/types"
+	containerType "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	compose "github.com/docker/compose/v2/pkg/api"
+)
+
+func TestGetExecTargetPrefersServiceReplica(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	oneOff := testContainer("service1", "123", true)
+	oneOff.Labels[compose.ContainerNumberLabel] = "1"
+	replica := testContainer("service1", "456", false)
+	replica.Labels[compose.ContainerNumberLabel] = "1"
+
+	ctx := context.Background()
+	api.EXPECT().ContainerList(ctx, containerType.ListOptions{
+		Filters: filters.NewArgs(projectFilter(strings.ToLower(testProject)), serviceFilter("service1"), hasConfigHashLabel()),
+	}).Return([]moby.Container{oneOff, replica}, nil)
+
+	target, err := tested.getExecTarget(ctx, strings.ToLower(testProject), compose.RunOptions{Service: "service1"})
+	assert.NilError(t, err)
+	assert.Equal(t, target.ID, "456")
+}
@@ -0,0 +1,475 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/loader"
+	"github.com/compose-spec/compose-go/v2/types"
+	moby "github.com/docker/docker/api/types"
+	containerType "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/errdefs"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
+)
+
+const (
+	exportManifestVersion = 1
+
+	exportManifestEntry = "manifest.json"
+	exportConfigEntry   = "compose.yaml"
+	exportImagesEntry   = "images.tar"
+	exportVolumesDir    = "volumes"
+
+	// exportVolumeMountPoint is where helper containers mount the volume being exported or imported
+	exportVolumeMountPoint = "/volume"
+)
+
+// exportManifest describes the content of an archive created by Export. Entries are written in the archive in the
+// order Import needs them: manifest, project configuration, images, then volumes
+type exportManifest struct {
+	Version int               `json:"version"`
+	Project string            `json:"project"`
+	Images  []string          `json:"images,omitempty"`
+	Volumes map[string]string `json:"volumes,omitempty"`
+}
+
+func (s *composeService) Export(ctx context.Context, project *types.Project, options api.ExportOptions) error {
+	return progress.RunWithTitle(ctx, func(ctx context.Context) error {
+		return s.export(ctx, project, options)
+	}, s.stdinfo(), "Exporting")
+}
+
+func (s *composeService) export(ctx context.Context, project *types.Project, options api.ExportOptions) error {
+	manifest := exportManifest{
+		Version: exportManifestVersion,
+		Project: project.Name,
+		Images:  exportedImages(project),
+		Volumes: map[string]string{},
+	}
+
+	volumes, err := s.exportedVolumes(ctx, project)
+	if err != nil {
+		return err
+	}
+	for _, key := range volumes {
+		manifest.Volumes[key] = path.Join(exportVolumesDir, key+".tar")
+	}
+
+	config, err := exportedConfig(project).MarshalYAML()
+	if err != nil {
+		return err
+	}
+	meta, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(options.Output)
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck
+
+	tw := tar.NewWriter(f)
+	if err := writeArchiveEntry(tw, exportManifestEntry, strings.NewReader(string(meta))); err != nil {
+		return err
+	}
+	if err := writeArchiveEntry(tw, exportConfigEntry, strings.NewReader(string(config))); err != nil {
+		return err
+	}
+	if len(manifest.Images) > 0 {
+		if err := s.exportImages(ctx, tw, manifest.Images); err != nil {
+			return err
+		}
+	}
+	for _, key := range volumes {
+		if err := s.exportVolume(ctx, tw, project, key, manifest.Volumes[key]); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// exportedConfig returns a copy of project without the resource names computed by the loader, so that they get
+// computed again on import, according to the imported project name
+func exportedConfig(project *types.Project) *types.Project {
+	config := *project
+	config.Networks = types.Networks{}
+	for key, network := range project.Networks {
+		if network.Name == defaultResourceName(project.Name, key) {
+			network.Name = ""
+		}
+		config.Networks[key] = network
+	}
+	config.Volumes = types.Volumes{}
+	for key, volume := range project.Volumes {
+		if volume.Name == defaultResourceName(project.Name, key) {
+			volume.Name = ""
+		}
+		config.Volumes[key] = volume
+	}
+	return &config
+}
+
+func defaultResourceName(projectName string, key string) string {
+	return fmt.Sprintf("%s_%s", projectName, key)
+}
+
+// exportedImages returns the images used by project services, sorted and without duplicates
+func exportedImages(project *types.Project) []string {
+	seen := map[string]bool{}
+	var images []string
+	for _, service := range project.Services {
+		image := api.GetImageNameOrDefault(service, project.Name)
+		if !seen[image] {
+			seen[image] = true
+			images = append(images, image)
+		}
+	}
+	sort.Strings(images)
+	return images
+}
+
+// exportedVolumes returns the keys of project volumes which content has to be exported. External volumes are not
+// managed by the project and are ignored, as are volumes which have not been created yet
+func (s *composeService) exportedVolumes(ctx context.Context, project *types.Project) ([]string, error) {
+	var keys []string
+	for key, volume := range project.Volumes {
+		if volume.External {
+			continue
+		}
+		_, err := s.apiClient().VolumeInspect(ctx, volume.Name)
+		if errdefs.IsNotFound(err) {
+			api.Warn(ctx, api.Warning{
+				Code:    api.WarningOperationFailed,
+				Message: fmt.Sprintf("volume %q has not been created, its content will not be exported", volume.Name),
+			})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (s *composeService) exportImages(ctx context.Context, tw *tar.Writer, images []string) error {
+	w := progress.ContextWriter(ctx)
+	eventName := "Images"
+	w.Event(progress.NewEvent(eventName, progress.Working, "Exporting"))
+	content, err := s.apiClient().ImageSave(ctx, images)
+	if err != nil {
+		w.Event(progress.ErrorMessageEvent(eventName, "Error while Exporting"))
+		return err
+	}
+	defer content.Close() //nolint:errcheck
+	if err := writeArchiveEntry(tw, exportImagesEntry, content); err != nil {
+		w.Event(progress.ErrorMessageEvent(eventName, "Error while Exporting"))
+		return err
+	}
+	w.Event(progress.NewEvent(eventName, progress.Done, "Exported"))
+	return nil
+}
+
+func (s *composeService) exportVolume(ctx context.Context, tw *tar.Writer, project *types.Project, key string, entry string) error {
+	volume := project.Volumes[key]
+	w := progress.ContextWriter(ctx)
+	eventName := fmt.Sprintf("Volume %q", volume.Name)
+	w.Event(progress.NewEvent(eventName, progress.Working, "Exporting"))
+	err := s.withVolumeHelper(ctx, volumeHelperImage(project, key), volume.Name, true, func(id string) error {
+		content, _, err := s.apiClient().CopyFromContainer(ctx, id, exportVolumeMountPoint)
+		if err != nil {
+			return err
+		}
+		defer content.Close() //nolint:errcheck
+		return writeArchiveEntry(tw, entry, content)
+	})
+	if err != nil {
+		w.Event(progress.ErrorMessageEvent(eventName, "Error while Exporting"))
+		return err
+	}
+	w.Event(progress.NewEvent(eventName, progress.Done, "Exported"))
+	return nil
+}
+
+// volumeHelperImage selects the image of a service mounting volume, or any service image, to run a helper container.
+// As the helper container is never started, image content doesn't matter but it must be available on the engine
+func volumeHelperImage(project *types.Project, key string) string {
+	var fallback string
+	for _, name := range project.ServiceNames() {
+		service := project.Services[name]
+		image := api.GetImageNameOrDefault(service, project.Name)
+		for _, v := range service.Volumes {
+			if v.Type == types.VolumeTypeVolume && v.Source == key {
+				return image
+			}
+		}
+		if fallback == "" {
+			fallback = image
+		}
+	}
+	return fallback
+}
+
+// withVolumeHelper creates a container with volume mounted on exportVolumeMountPoint so that its content can be
+// copied from or to the volume, then removes it
+func (s *composeService) withVolumeHelper(ctx context.Context, image string, volume string, readOnly bool, fn func(id string) error) error {
+	if image == "" {
+		return fmt.Errorf("no image available to access volume %q", volume)
+	}
+	created, err := s.apiClient().ContainerCreate(ctx, &containerType.Config{
+		Image: image,
+		// container is never started, command only needs to be set for images which don't declare one
+		Entrypoint: []string{"true"},
+	}, &containerType.HostConfig{
+		Mounts: []mount.Mount{
+			{
+				Type:     mount.TypeVolume,
+				Source:   volume,
+				Target:   exportVolumeMountPoint,
+				ReadOnly: readOnly,
+			},
+		},
+	}, nil, nil, "")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err := s.apiClient().ContainerRemove(context.WithoutCancel(ctx), created.ID, containerType.RemoveOptions{Force: true})
+		if err != nil {
+			api.Warn(ctx, api.Warning{
+				Code:    api.WarningOperationFailed,
+				Message: fmt.Sprintf("failed to remove helper container %s: %v", created.ID, err),
+			})
+		}
+	}()
+	return fn(created.ID)
+}
+
+// writeArchiveEntry adds a file to the archive. As tar headers require the file size, content of unknown length is
+// first copied to a temporary file
+func writeArchiveEntry(tw *tar.Writer, name string, content io.Reader) error {
+	if r, ok := content.(*strings.Reader); ok {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: r.Size(), Typeflag: tar.TypeReg}); err != nil {
+			return err
+		}
+		_, err := io.Copy(tw, r)
+		return err
+	}
+
+	tmp, err := os.CreateTemp("", "compose-export-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+	defer tmp.Close()           //nolint:errcheck
+
+	size, err := io.Copy(tmp, content)
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: size, Typeflag: tar.TypeReg}); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, tmp)
+	return err
+}
+
+func (s *composeService) Import(ctx context.Context, options api.ImportOptions) error {
+	return progress.RunWithTitle(ctx, func(ctx context.Context) error {
+		return s.importArchive(ctx, options)
+	}, s.stdinfo(), "Importing")
+}
+
+func (s *composeService) importArchive(ctx context.Context, options api.ImportOptions) error {
+	f, err := os.Open(options.Input)
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck
+
+	tr := tar.NewReader(f)
+	manifest, err := readManifest(tr)
+	if err != nil {
+		return err
+	}
+
+	var project *types.Project
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case header.Name == exportConfigEntry:
+			name := manifest.Project
+			if options.ProjectName != "" {
+				name = options.ProjectName
+			}
+			project, err = s.importProject(ctx, tr, options.WorkingDir, name)
+		case project == nil:
+			return fmt.Errorf("invalid archive: %s must be set before %s", exportConfigEntry, header.Name)
+		case header.Name == exportImagesEntry:
+			err = s.importImages(ctx, tr)
+		case strings.HasPrefix(header.Name, exportVolumesDir+"/"):
+			err = s.importVolume(ctx, tr, project, manifest, header.Name)
+		default:
+			logrus.Debugf("ignoring unexpected archive entry %s", header.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if project == nil {
+		return fmt.Errorf("invalid archive: %s not found", exportConfigEntry)
+	}
+	return nil
+}
+
+func readManifest(tr *tar.Reader) (exportManifest, error) {
+	var manifest exportManifest
+	header, err := tr.Next()
+	if err != nil {
+		return manifest, fmt.Errorf("invalid archive: %w", err)
+	}
+	if header.Name != exportManifestEntry {
+		return manifest, fmt.Errorf("invalid archive: %s must be the first entry", exportManifestEntry)
+	}
+	if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return manifest, fmt.Errorf("invalid archive manifest: %w", err)
+	}
+	if manifest.Version != exportManifestVersion {
+		return manifest, fmt.Errorf("unsupported archive version %d", manifest.Version)
+	}
+	return manifest, nil
+}
+
+// importProject writes the exported configuration in workingDir, then creates project networks and volumes
+func (s *composeService) importProject(ctx context.Context, content io.Reader, workingDir string, name string) (*types.Project, error) {
+	config, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+	configFile := filepath.Join(workingDir, exportConfigEntry)
+	f, err := os.OpenFile(configFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("%s already exists", configFile)
+		}
+		return nil, err
+	}
+	if _, err := f.Write(config); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+
+	project, err := loader.LoadWithContext(ctx, types.ConfigDetails{
+		WorkingDir: workingDir,
+		ConfigFiles: []types.ConfigFile{
+			{Filename: configFile, Content: config},
+		},
+		Environment: types.NewMapping(os.Environ()),
+	}, func(options *loader.Options) {
+		options.SetProjectName(name, true)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	prepareNetworks(project)
+	if err := s.ensureNetworks(ctx, project.Networks); err != nil {
+		return nil, err
+	}
+	if err := s.ensureProjectVolumes(ctx, project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+func (s *composeService) importImages(ctx context.Context, content io.Reader) error {
+	w := progress.ContextWriter(ctx)
+	eventName := "Images"
+	w.Event(progress.NewEvent(eventName, progress.Working, "Importing"))
+	resp, err := s.apiClient().ImageLoad(ctx, content, true)
+	if err == nil {
+		_, err = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+	if err != nil {
+		w.Event(progress.ErrorMessageEvent(eventName, "Error while Importing"))
+		return err
+	}
+	w.Event(progress.NewEvent(eventName, progress.Done, "Imported"))
+	return nil
+}
+
+func (s *composeService) importVolume(ctx context.Context, content io.Reader, project *types.Project, manifest exportManifest, entry string) error {
+	var key string
+	for k, e := range manifest.Volumes {
+		if e == entry {
+			key = k
+		}
+	}
+	volume, ok := project.Volumes[key]
+	if !ok {
+		return fmt.Errorf("invalid archive: %s doesn't match a project volume", entry)
+	}
+
+	w := progress.ContextWriter(ctx)
+	eventName := fmt.Sprintf("Volume %q", volume.Name)
+	w.Event(progress.NewEvent(eventName, progress.Working, "Importing"))
+	err := s.withVolumeHelper(ctx, volumeHelperImage(project, key), volume.Name, false, func(id string) error {
+		// exported content is rooted on the mount point directory
+		return s.apiClient().CopyToContainer(ctx, id, path.Dir(exportVolumeMountPoint), content, moby.CopyToContainerOptions{})
+	})
+	if err != nil {
+		w.Event(progress.ErrorMessageEvent(eventName, "Error while Importing"))
+		return err
+	}
+	w.Event(progress.NewEvent(eventName, progress.Done, "Imported"))
+	return nil
+}
@@ -0,0 +1,151 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	moby "github.com/docker/docker/api/types"
+	containerType "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+
+	compose "github.com/docker/compose/v2/pkg/api"
+)
+
+func exportProject() *types.Project {
+	return &types.Project{
+		Name: "myproject",
+		Services: types.Services{
+			"db": {
+				Name:        "db",
+				Image:       "postgres",
+				NetworkMode: "none",
+				Volumes: []types.ServiceVolumeConfig{
+					{Type: types.VolumeTypeVolume, Source: "data", Target: "/var/lib/postgresql/data"},
+				},
+			},
+		},
+		Volumes: types.Volumes{
+			"data":   {Name: "myproject_data"},
+			"shared": {Name: "shared", External: true},
+			"unused": {Name: "myproject_unused"},
+		},
+	}
+}
+
+func volumeContent(t *testing.T) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	assert.NilError(t, tw.WriteHeader(&tar.Header{Name: "volume/", Mode: 0o755, Typeflag: tar.TypeDir}))
+	assert.NilError(t, tw.WriteHeader(&tar.Header{Name: "volume/PG_VERSION", Mode: 0o644, Size: 3, Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte("16\n"))
+	assert.NilError(t, err)
+	assert.NilError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func TestExportImport(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+	ctx := context.Background()
+	archive := filepath.Join(t.TempDir(), "snapshot.tar")
+	content := volumeContent(t)
+
+	api.EXPECT().VolumeInspect(gomock.Any(), "myproject_data").Return(volume.Volume{Name: "myproject_data"}, nil)
+	api.EXPECT().VolumeInspect(gomock.Any(), "myproject_unused").Return(volume.Volume{}, errdefs.NotFound(errors.New("not found")))
+	api.EXPECT().ImageSave(gomock.Any(), []string{"postgres"}).Return(io.NopCloser(strings.NewReader("images")), nil)
+	api.EXPECT().ContainerCreate(gomock.Any(), gomock.Any(), gomock.Any(), nil, nil, "").
+		DoAndReturn(func(_ context.Context, config *containerType.Config, hostConfig *containerType.HostConfig, _, _ any, _ string) (containerType.CreateResponse, error) {
+			assert.Equal(t, config.Image, "postgres")
+			assert.Equal(t, hostConfig.Mounts[0].Source, "myproject_data")
+			assert.Check(t, hostConfig.Mounts[0].ReadOnly)
+			return containerType.CreateResponse{ID: "helper"}, nil
+		})
+	api.EXPECT().CopyFromContainer(gomock.Any(), "helper", "/volume").Return(io.NopCloser(bytes.NewReader(content)), moby.ContainerPathStat{}, nil)
+	api.EXPECT().ContainerRemove(gomock.Any(), "helper", containerType.RemoveOptions{Force: true}).Return(nil)
+
+	err := tested.export(ctx, exportProject(), compose.ExportOptions{Output: archive})
+	assert.NilError(t, err)
+
+	var entries []string
+	f, err := os.Open(archive)
+	assert.NilError(t, err)
+	defer f.Close() //nolint:errcheck
+	tr := tar.NewReader(f)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		assert.NilError(t, err)
+		entries = append(entries, header.Name)
+	}
+	assert.DeepEqual(t, entries, []string{"manifest.json", "compose.yaml", "images.tar", "volumes/data.tar"})
+
+	dir := t.TempDir()
+	api.EXPECT().VolumeInspect(gomock.Any(), "imported_data").Return(volume.Volume{}, errdefs.NotFound(errors.New("not found")))
+	api.EXPECT().VolumeInspect(gomock.Any(), "imported_unused").Return(volume.Volume{}, errdefs.NotFound(errors.New("not found")))
+	api.EXPECT().VolumeInspect(gomock.Any(), "shared").Return(volume.Volume{Name: "shared"}, nil)
+	api.EXPECT().VolumeCreate(gomock.Any(), gomock.Any()).Return(volume.Volume{}, nil).Times(2)
+	api.EXPECT().ImageLoad(gomock.Any(), gomock.Any(), true).
+		DoAndReturn(func(_ context.Context, input io.Reader, _ bool) (moby.ImageLoadResponse, error) {
+			b, err := io.ReadAll(input)
+			assert.NilError(t, err)
+			assert.Equal(t, string(b), "images")
+			return moby.ImageLoadResponse{Body: io.NopCloser(strings.NewReader(""))}, nil
+		})
+	api.EXPECT().ContainerCreate(gomock.Any(), gomock.Any(), gomock.Any(), nil, nil, "").
+		DoAndReturn(func(_ context.Context, _ *containerType.Config, hostConfig *containerType.HostConfig, _, _ any, _ string) (containerType.CreateResponse, error) {
+			assert.Equal(t, hostConfig.Mounts[0].Source, "imported_data")
+			assert.Check(t, !hostConfig.Mounts[0].ReadOnly)
+			return containerType.CreateResponse{ID: "helper"}, nil
+		})
+	api.EXPECT().CopyToContainer(gomock.Any(), "helper", "/", gomock.Any(), moby.CopyToContainerOptions{}).
+		DoAndReturn(func(_ context.Context, _, _ string, input io.Reader, _ moby.CopyToContainerOptions) error {
+			b, err := io.ReadAll(input)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, b, content)
+			return nil
+		})
+	api.EXPECT().ContainerRemove(gomock.Any(), "helper", containerType.RemoveOptions{Force: true}).Return(nil)
+
+	err = tested.importArchive(ctx, compose.ImportOptions{Input: archive, WorkingDir: dir, ProjectName: "imported"})
+	assert.NilError(t, err)
+	_, err = os.Stat(filepath.Join(dir, "compose.yaml"))
+	assert.NilError(t, err)
+
+	err = tested.importArchive(ctx, compose.ImportOptions{Input: archive, WorkingDir: dir})
+	assert.Check(t, is.ErrorContains(err, "already exists"))
+}
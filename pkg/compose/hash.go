@@ -25,12 +25,27 @@ import (
 
 // ServiceHash computes the configuration hash for a service.
 func ServiceHash(o types.ServiceConfig) (string, error) {
+	// restart policy is updated in place on existing containers, so it doesn't require to recreate them
+	o.Restart = ""
+	if o.Deploy != nil {
+		deploy := *o.Deploy
+		deploy.RestartPolicy = nil
+		o.Deploy = &deploy
+	}
+	return legacyServiceHash(o)
+}
+
+// legacyServiceHash computes the configuration hash as it was set on containers before restart policy was
+// excluded, so those are not recreated on upgrade
+func legacyServiceHash(o types.ServiceConfig) (string, error) {
 	// remove the Build config when generating the service hash
 	o.Build = nil
 	o.PullPolicy = ""
 	o.Scale = nil
 	if o.Deploy != nil {
-		o.Deploy.Replicas = nil
+		deploy := *o.Deploy
+		deploy.Replicas = nil
+		o.Deploy = &deploy
 	}
 
 	bytes, err := json.Marshal(o)
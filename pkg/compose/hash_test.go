@@ -41,3 +41,20 @@ func serviceConfig(replicas int) types.ServiceConfig {
 		Image: "bar",
 	}
 }
+
+func TestServiceHashIgnoresRestartPolicy(t *testing.T) {
+	service := serviceConfig(1)
+	hash, err := ServiceHash(service)
+	assert.NilError(t, err)
+	legacy, err := legacyServiceHash(service)
+	assert.NilError(t, err)
+
+	service.Restart = types.RestartPolicyAlways
+	changed, err := ServiceHash(service)
+	assert.NilError(t, err)
+	assert.Equal(t, hash, changed)
+
+	changedLegacy, err := legacyServiceHash(service)
+	assert.NilError(t, err)
+	assert.Assert(t, legacy != changedLegacy)
+}
@@ -0,0 +1,149 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/mitchellh/mapstructure"
+)
+
+// extHealthcheckOverride is a top-level extension, typically set by an override file, which attributes replace the
+// ones of all services' healthchecks
+const extHealthcheckOverride = "x-healthcheck-override"
+
+type rawHealthcheckOverride struct {
+	Test          any    `mapstructure:"test"`
+	Interval      string `mapstructure:"interval"`
+	Timeout       string `mapstructure:"timeout"`
+	StartPeriod   string `mapstructure:"start_period"`
+	StartInterval string `mapstructure:"start_interval"`
+	Retries       uint64 `mapstructure:"retries"`
+	Disable       bool   `mapstructure:"disable"`
+}
+
+// getHealthcheckOverride parses project `x-healthcheck-override` extension, returns nil if project doesn't declare one
+func getHealthcheckOverride(project *types.Project) (*types.HealthCheckConfig, error) {
+	y, ok := project.Extensions[extHealthcheckOverride]
+	if !ok {
+		return nil, nil
+	}
+	var raw rawHealthcheckOverride
+	if err := mapstructure.Decode(y, &raw); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", extHealthcheckOverride, err)
+	}
+
+	override := types.HealthCheckConfig{
+		Disable: raw.Disable,
+	}
+	switch test := raw.Test.(type) {
+	case nil:
+	case string:
+		override.Test = types.HealthCheckTest{"CMD-SHELL", test}
+	case []any:
+		for _, arg := range test {
+			override.Test = append(override.Test, fmt.Sprint(arg))
+		}
+	default:
+		return nil, fmt.Errorf("invalid %s test: must be a string or a list", extHealthcheckOverride)
+	}
+	if raw.Retries > 0 {
+		retries := raw.Retries
+		override.Retries = &retries
+	}
+	for _, d := range []struct {
+		name   string
+		value  string
+		target **types.Duration
+	}{
+		{"interval", raw.Interval, &override.Interval},
+		{"timeout", raw.Timeout, &override.Timeout},
+		{"start_period", raw.StartPeriod, &override.StartPeriod},
+		{"start_interval", raw.StartInterval, &override.StartInterval},
+	} {
+		if d.value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(d.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %s: %w", extHealthcheckOverride, d.name, err)
+		}
+		value := types.Duration(duration)
+		*d.target = &value
+	}
+	return &override, nil
+}
+
+// applyHealthcheckOverrides overrides services' healthchecks with project `x-healthcheck-override` extension, then
+// with options, which typically are set by command line flags and take precedence
+func applyHealthcheckOverrides(project *types.Project, options *types.HealthCheckConfig) error {
+	override, err := getHealthcheckOverride(project)
+	if err != nil {
+		return err
+	}
+	for _, o := range []*types.HealthCheckConfig{override, options} {
+		if o == nil {
+			continue
+		}
+		for name, service := range project.Services {
+			service.HealthCheck = overrideHealthcheck(service.HealthCheck, *o)
+			project.Services[name] = service
+		}
+	}
+	return nil
+}
+
+// overrideHealthcheck returns healthcheck with attributes set by override replaced. Attributes which are not set by
+// the service nor the override are inherited from the image healthcheck by the Docker Engine. The test command is only
+// replaced for services declaring a healthcheck, and a disabled healthcheck, as for init containers, is kept disabled
+func overrideHealthcheck(healthcheck *types.HealthCheckConfig, override types.HealthCheckConfig) *types.HealthCheckConfig {
+	if healthcheck != nil && healthcheck.Disable {
+		return healthcheck
+	}
+	if override.Disable {
+		return &types.HealthCheckConfig{Disable: true}
+	}
+	var merged types.HealthCheckConfig
+	if healthcheck != nil {
+		merged = *healthcheck
+	}
+	if len(override.Test) > 0 && healthcheck != nil {
+		merged.Test = override.Test
+	}
+	if override.Interval != nil {
+		merged.Interval = override.Interval
+	}
+	if override.Timeout != nil {
+		merged.Timeout = override.Timeout
+	}
+	if override.StartPeriod != nil {
+		merged.StartPeriod = override.StartPeriod
+	}
+	if override.StartInterval != nil {
+		merged.StartInterval = override.StartInterval
+	}
+	if override.Retries != nil {
+		merged.Retries = override.Retries
+	}
+	if healthcheck == nil && merged.Test == nil && merged.Interval == nil && merged.Timeout == nil &&
+		merged.StartPeriod == nil && merged.StartInterval == nil && merged.Retries == nil {
+		return nil
+	}
+	return &merged
+}
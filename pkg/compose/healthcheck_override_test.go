@@ -0,0 +1,142 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func duration(d time.Duration) *types.Duration {
+	value := types.Duration(d)
+	return &value
+}
+
+func TestApplyHealthcheckOverrides(t *testing.T) {
+	retries := uint64(3)
+	project := &types.Project{
+		Services: types.Services{
+			"db": {
+				Name: "db",
+				HealthCheck: &types.HealthCheckConfig{
+					Test:     types.HealthCheckTest{"CMD", "pg_isready"},
+					Interval: duration(30 * time.Second),
+					Retries:  &retries,
+				},
+			},
+			"web": {Name: "web"},
+		},
+		Extensions: types.Extensions{
+			extHealthcheckOverride: map[string]any{
+				"interval": "1s",
+				"timeout":  "5s",
+			},
+		},
+	}
+
+	err := applyHealthcheckOverrides(project, &types.HealthCheckConfig{Interval: duration(500 * time.Millisecond)})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, project.Services["db"].HealthCheck, &types.HealthCheckConfig{
+		Test:     types.HealthCheckTest{"CMD", "pg_isready"},
+		Interval: duration(500 * time.Millisecond),
+		Timeout:  duration(5 * time.Second),
+		Retries:  &retries,
+	})
+	// web relies on the image healthcheck, if any, with overridden attributes
+	assert.DeepEqual(t, project.Services["web"].HealthCheck, &types.HealthCheckConfig{
+		Interval: duration(500 * time.Millisecond),
+		Timeout:  duration(5 * time.Second),
+	})
+}
+
+func TestApplyHealthcheckOverridesDisable(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			"db": {
+				Name:        "db",
+				HealthCheck: &types.HealthCheckConfig{Test: types.HealthCheckTest{"CMD", "pg_isready"}},
+			},
+			"web": {Name: "web"},
+		},
+		Extensions: types.Extensions{
+			extHealthcheckOverride: map[string]any{"disable": true},
+		},
+	}
+
+	err := applyHealthcheckOverrides(project, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, project.Services["db"].HealthCheck, &types.HealthCheckConfig{Disable: true})
+	assert.DeepEqual(t, project.Services["web"].HealthCheck, &types.HealthCheckConfig{Disable: true})
+}
+
+func TestHealthcheckOverrideTest(t *testing.T) {
+	project := &types.Project{
+		Extensions: types.Extensions{
+			extHealthcheckOverride: map[string]any{"test": []any{"CMD", "true"}},
+		},
+	}
+	override, err := getHealthcheckOverride(project)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, override.Test, types.HealthCheckTest{"CMD", "true"})
+
+	project.Extensions[extHealthcheckOverride] = map[string]any{"interval": "fast"}
+	_, err = getHealthcheckOverride(project)
+	assert.Check(t, is.ErrorContains(err, "invalid x-healthcheck-override interval"))
+}
+
+func TestApplyHealthcheckOverridesTest(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			"db": {
+				Name:        "db",
+				HealthCheck: &types.HealthCheckConfig{Test: types.HealthCheckTest{"CMD", "pg_isready"}},
+			},
+			"web": {Name: "web"},
+			"db-init": {
+				Name:        "db-init",
+				HealthCheck: &types.HealthCheckConfig{Disable: true},
+			},
+		},
+	}
+
+	err := applyHealthcheckOverrides(project, &types.HealthCheckConfig{
+		Test:     types.HealthCheckTest{"CMD-SHELL", "true"},
+		Interval: duration(time.Second),
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, project.Services["db"].HealthCheck, &types.HealthCheckConfig{
+		Test:     types.HealthCheckTest{"CMD-SHELL", "true"},
+		Interval: duration(time.Second),
+	})
+	// test command only applies to services declaring a healthcheck
+	assert.DeepEqual(t, project.Services["web"].HealthCheck, &types.HealthCheckConfig{Interval: duration(time.Second)})
+	// disabled healthcheck is never enabled back
+	assert.DeepEqual(t, project.Services["db-init"].HealthCheck, &types.HealthCheckConfig{Disable: true})
+}
+
+func TestApplyHealthcheckOverridesNone(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{"web": {Name: "web"}},
+	}
+	err := applyHealthcheckOverrides(project, nil)
+	assert.NilError(t, err)
+	assert.Check(t, project.Services["web"].HealthCheck == nil)
+}
@@ -0,0 +1,165 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/mattn/go-shellwords"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// extHooks is a top-level extension declaring host commands to run once the project is up or down, which get the
+// outcome of the operation as JSON on their standard input
+const extHooks = "x-hooks"
+
+const (
+	hookOnUp   = "on_up"
+	hookOnDown = "on_down"
+)
+
+type rawHooks struct {
+	OnUp   any `mapstructure:"on_up"`
+	OnDown any `mapstructure:"on_down"`
+}
+
+// hookEvent is the outcome of an operation, passed to hooks as JSON
+type hookEvent struct {
+	Project    string   `json:"project"`
+	WorkingDir string   `json:"working_dir"`
+	Operation  string   `json:"operation"`
+	Status     string   `json:"status"`
+	Error      string   `json:"error,omitempty"`
+	Services   []string `json:"services"`
+}
+
+// hookCommand returns the command project `x-hooks` extension declares for hook, if any
+func hookCommand(project *types.Project, hook string) ([]string, error) {
+	y, ok := project.Extensions[extHooks]
+	if !ok {
+		return nil, nil
+	}
+	var raw rawHooks
+	if err := mapstructure.Decode(y, &raw); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", extHooks, err)
+	}
+	command := raw.OnUp
+	if hook == hookOnDown {
+		command = raw.OnDown
+	}
+	switch command := command.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return shellwords.Parse(command)
+	case []any:
+		args := make([]string, len(command))
+		for i, arg := range command {
+			args[i] = fmt.Sprint(arg)
+		}
+		return args, nil
+	default:
+		return nil, fmt.Errorf("invalid %s: %s must be a string or a list", extHooks, hook)
+	}
+}
+
+// SetHooks defines if host commands set by x-hooks can run
+func (s *composeService) SetHooks(enabled bool) {
+	s.hooks = enabled
+}
+
+// runHook runs the command declared for hook, from project working directory, with the outcome of operation on its
+// standard input. A failing hook doesn't fail the operation, which already completed, but is reported as a warning
+func (s *composeService) runHook(ctx context.Context, project *types.Project, hook string, operation string, services []string, opErr error) {
+	if project == nil || s.dryRun {
+		return
+	}
+	warn := func(err error) {
+		api.Warn(ctx, api.Warning{Code: api.WarningHook, Attribute: extHooks + "." + hook, Message: err.Error()})
+	}
+	command, err := hookCommand(project, hook)
+	if err != nil {
+		warn(err)
+		return
+	}
+	if len(command) == 0 {
+		return
+	}
+	if !s.hooks {
+		warn(fmt.Errorf("ignored, running host commands requires --hooks or COMPOSE_HOOKS=1"))
+		return
+	}
+
+	if len(services) == 0 {
+		services = project.ServiceNames()
+	}
+	event := hookEvent{
+		Project:    project.Name,
+		WorkingDir: project.WorkingDir,
+		Operation:  operation,
+		Status:     "success",
+		Services:   services,
+	}
+	if opErr != nil {
+		event.Status = "failure"
+		event.Error = opErr.Error()
+	}
+	input, err := json.Marshal(event)
+	if err != nil {
+		warn(err)
+		return
+	}
+
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Dir = project.WorkingDir
+	cmd.Env = append(os.Environ(), project.Environment.Values()...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = s.stdinfo()
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() > 0 {
+			err = fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		warn(fmt.Errorf("command %q failed: %w", strings.Join(command, " "), err))
+	}
+}
+
+type upHookKey struct{}
+
+// withUpHook sets the func start calls once all services are started, as attached `up` doesn't return until
+// containers exit
+func withUpHook(ctx context.Context, fn func()) context.Context {
+	return context.WithValue(ctx, upHookKey{}, fn)
+}
+
+func runUpHook(ctx context.Context) {
+	if fn, ok := ctx.Value(upHookKey{}).(func()); ok {
+		fn()
+	}
+}
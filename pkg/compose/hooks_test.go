@@ -0,0 +1,106 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+func TestRunHook(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, cli := prepareMocks(mockCtrl)
+	tested := composeService{dockerCli: cli, hooks: true}
+
+	dir := t.TempDir()
+	project := &types.Project{
+		Name:       "myapp",
+		WorkingDir: dir,
+		Services:   types.Services{"web": {Name: "web"}, "db": {Name: "db"}},
+		Extensions: types.Extensions{extHooks: map[string]any{
+			"on_up":   "sh -c 'cat > up.json'",
+			"on_down": []any{"sh", "-c", "echo unreachable >&2; exit 3"},
+		}},
+	}
+
+	collector := &api.WarningCollector{}
+	ctx := api.WithWarningCollector(context.Background(), collector)
+	tested.runHook(ctx, project, hookOnUp, "up", nil, errors.New("boom"))
+
+	b, err := os.ReadFile(filepath.Join(dir, "up.json"))
+	assert.NilError(t, err)
+	var event hookEvent
+	assert.NilError(t, json.Unmarshal(b, &event))
+	assert.DeepEqual(t, event, hookEvent{
+		Project:    "myapp",
+		WorkingDir: dir,
+		Operation:  "up",
+		Status:     "failure",
+		Error:      "boom",
+		Services:   []string{"db", "web"},
+	})
+	assert.Check(t, is.Len(collector.Warnings(), 0))
+
+	tested.runHook(ctx, project, hookOnDown, "down", []string{"web"}, nil)
+	warnings := collector.Warnings()
+	assert.Assert(t, is.Len(warnings, 1))
+	assert.Equal(t, warnings[0].Code, api.WarningHook)
+	assert.Equal(t, warnings[0].Attribute, "x-hooks.on_down")
+	assert.Check(t, is.Contains(warnings[0].Message, "exit status 3: unreachable"))
+}
+
+func TestRunHookDisabled(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, cli := prepareMocks(mockCtrl)
+	tested := composeService{dockerCli: cli}
+
+	dir := t.TempDir()
+	project := &types.Project{
+		Name:       "myapp",
+		WorkingDir: dir,
+		Extensions: types.Extensions{extHooks: map[string]any{"on_up": "touch up.json"}},
+	}
+
+	collector := &api.WarningCollector{}
+	ctx := api.WithWarningCollector(context.Background(), collector)
+	tested.runHook(ctx, project, hookOnUp, "up", nil, nil)
+
+	_, err := os.Stat(filepath.Join(dir, "up.json"))
+	assert.Check(t, os.IsNotExist(err))
+	warnings := collector.Warnings()
+	assert.Assert(t, is.Len(warnings, 1))
+	assert.Check(t, is.Contains(warnings[0].Message, "COMPOSE_HOOKS=1"))
+}
+
+func TestHookCommandInvalid(t *testing.T) {
+	project := &types.Project{Extensions: types.Extensions{extHooks: map[string]any{"on_up": 42}}}
+	_, err := hookCommand(project, hookOnUp)
+	assert.Error(t, err, "invalid x-hooks: on_up must be a string or a list")
+}
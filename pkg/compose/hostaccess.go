@@ -0,0 +1,72 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+
+	"github.com/compose-spec/compose-go/v2/types"
+)
+
+const (
+	extNeedsHostAccess = "x-needs-host-access"
+
+	defaultHostAccessName = "host.docker.internal"
+	hostGateway           = "host-gateway"
+)
+
+// getExtraHosts returns service extra_hosts, completed with host-gateway entries for services declaring
+// `x-needs-host-access`. This makes `host.docker.internal` (or the configured host names) resolve to the host on
+// Linux engines, where it isn't defined by default. An explicit extra_hosts entry for the same name wins
+func getExtraHosts(service types.ServiceConfig) (types.HostsList, error) {
+	value, ok := service.Extensions[extNeedsHostAccess]
+	if !ok {
+		return service.ExtraHosts, nil
+	}
+
+	var names []string
+	switch v := value.(type) {
+	case bool:
+		if !v {
+			return service.ExtraHosts, nil
+		}
+		names = []string{defaultHostAccessName}
+	case string:
+		names = []string{v}
+	case []any:
+		for _, name := range v {
+			s, ok := name.(string)
+			if !ok {
+				return nil, fmt.Errorf("service %q: %s must be a boolean or a list of host names", service.Name, extNeedsHostAccess)
+			}
+			names = append(names, s)
+		}
+	default:
+		return nil, fmt.Errorf("service %q: %s must be a boolean or a list of host names", service.Name, extNeedsHostAccess)
+	}
+
+	hosts := types.HostsList{}
+	for name, ips := range service.ExtraHosts {
+		hosts[name] = ips
+	}
+	for _, name := range names {
+		if _, ok := hosts[name]; !ok {
+			hosts[name] = []string{hostGateway}
+		}
+	}
+	return hosts, nil
+}
@@ -0,0 +1,195 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/go-units"
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// validateHostConfigs checks `ulimits`, `shm_size`, `tmpfs` and `sysctls` for all services before any container gets
+// created, so an invalid value doesn't surface as a daemon error after some services already started
+func validateHostConfigs(project *types.Project) error {
+	var errs *multierror.Error
+	for _, name := range project.ServiceNames() {
+		service := project.Services[name]
+		if err := validateUlimits(service.Name, service.Ulimits); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+		if service.ShmSize < 0 {
+			errs = multierror.Append(errs, api.InvalidAttributeError{
+				Service:   service.Name,
+				Attribute: "shm_size",
+				Value:     strconv.FormatInt(int64(service.ShmSize), 10),
+				Reason:    "must not be negative",
+			})
+		}
+		if _, err := toTmpfs(service.Name, service.Tmpfs); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+		for _, v := range service.Volumes {
+			if v.Type == types.VolumeTypeTmpfs && v.Tmpfs != nil && v.Tmpfs.Mode > 0o7777 {
+				errs = multierror.Append(errs, api.InvalidAttributeError{
+					Service:   service.Name,
+					Attribute: "volumes.tmpfs.mode",
+					Value:     fmt.Sprintf("%o", v.Tmpfs.Mode),
+					Reason:    "must be a file mode, as 1777",
+				})
+			}
+		}
+		for _, key := range sortedKeys(service.Sysctls) {
+			if reason := validateSysctl(key, service.Sysctls[key]); reason != "" {
+				errs = multierror.Append(errs, api.InvalidAttributeError{
+					Service:   service.Name,
+					Attribute: "sysctls",
+					Value:     key,
+					Reason:    reason,
+				})
+			}
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// toUlimits converts `ulimits`, sorted by name. A limit set with a single value applies to both the soft and hard
+// limits, as does a soft limit set without a hard one
+func toUlimits(m map[string]*types.UlimitsConfig) []*units.Ulimit {
+	var ulimits []*units.Ulimit
+	for _, name := range sortedKeys(m) {
+		u := m[name]
+		soft := u.Single
+		if u.Soft != 0 {
+			soft = u.Soft
+		}
+		hard := soft
+		if u.Hard != 0 {
+			hard = u.Hard
+		}
+		ulimits = append(ulimits, &units.Ulimit{
+			Name: name,
+			Hard: int64(hard),
+			Soft: int64(soft),
+		})
+	}
+	return ulimits
+}
+
+// validateUlimits checks ulimits names are known and soft limits don't exceed hard limits
+func validateUlimits(service string, m map[string]*types.UlimitsConfig) error {
+	for _, ulimit := range toUlimits(m) {
+		value := fmt.Sprintf("%d:%d", ulimit.Soft, ulimit.Hard)
+		if _, err := units.ParseUlimit(fmt.Sprintf("%s=%s", ulimit.Name, value)); err != nil {
+			return api.InvalidAttributeError{
+				Service:   service,
+				Attribute: "ulimits." + ulimit.Name,
+				Value:     value,
+				Reason:    err.Error(),
+			}
+		}
+	}
+	return nil
+}
+
+// toTmpfs converts service `tmpfs` entries, set as PATH[:OPTIONS], into the mount options by path the engine expects
+func toTmpfs(service string, entries types.StringList) (map[string]string, error) {
+	tmpfs := map[string]string{}
+	for _, entry := range entries {
+		path, options, _ := strings.Cut(entry, ":")
+		invalid := func(reason string) error {
+			return api.InvalidAttributeError{Service: service, Attribute: "tmpfs", Value: entry, Reason: reason}
+		}
+		if !strings.HasPrefix(path, "/") {
+			return nil, invalid("mount path must be absolute")
+		}
+		if _, ok := tmpfs[path]; ok {
+			return nil, invalid("mount path is declared more than once")
+		}
+		if options != "" {
+			for _, option := range strings.Split(options, ",") {
+				if reason := validateTmpfsOption(option); reason != "" {
+					return nil, invalid(reason)
+				}
+			}
+		}
+		tmpfs[path] = options
+	}
+	return tmpfs, nil
+}
+
+// validateTmpfsOption returns the reason why a tmpfs mount option is invalid, or an empty string. Only values of
+// well-known options are checked, other flags and options are passed to the engine as-is as the kernel may support them
+func validateTmpfsOption(option string) string {
+	key, value, hasValue := strings.Cut(option, "=")
+	if !hasValue {
+		return ""
+	}
+	switch key {
+	case "size", "nr_blocks", "nr_inodes":
+		if !isTmpfsSize(value) {
+			return fmt.Sprintf("invalid %s %q", key, value)
+		}
+	case "mode":
+		if m, err := strconv.ParseUint(value, 8, 32); err != nil || m > 0o7777 {
+			return fmt.Sprintf("invalid mode %q, must be an octal file mode", value)
+		}
+	case "uid", "gid":
+		if _, err := strconv.ParseUint(value, 10, 32); err != nil {
+			return fmt.Sprintf("invalid %s %q", key, value)
+		}
+	}
+	return ""
+}
+
+// isTmpfsSize checks value is a size with optional unit suffix, or a percentage of RAM
+func isTmpfsSize(value string) bool {
+	if percent, ok := strings.CutSuffix(value, "%"); ok {
+		_, err := strconv.ParseUint(percent, 10, 32)
+		return err == nil
+	}
+	_, err := units.RAMInBytes(value)
+	return err == nil
+}
+
+// validateSysctl returns the reason why a sysctl is invalid, or an empty string
+func validateSysctl(key string, value string) string {
+	switch {
+	case key == "":
+		return "name must not be empty"
+	case strings.ContainsAny(key, " \t="):
+		return "name must not contain whitespace or '='"
+	case value == "":
+		return "value must not be empty"
+	}
+	return ""
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
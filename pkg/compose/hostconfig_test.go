@@ -0,0 +1,150 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/go-units"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+func TestToUlimits(t *testing.T) {
+	ulimits := toUlimits(map[string]*types.UlimitsConfig{
+		"nproc":   {Single: 65535},
+		"nofile":  {Soft: 20000, Hard: 40000},
+		"memlock": {Soft: -1, Hard: -1},
+		"core":    {Soft: 10},
+	})
+	assert.DeepEqual(t, ulimits, []*units.Ulimit{
+		{Name: "core", Soft: 10, Hard: 10},
+		{Name: "memlock", Soft: -1, Hard: -1},
+		{Name: "nofile", Soft: 20000, Hard: 40000},
+		{Name: "nproc", Soft: 65535, Hard: 65535},
+	})
+
+	resources := getDeployResources(types.ServiceConfig{
+		Ulimits: map[string]*types.UlimitsConfig{"nofile": {Single: 1024}},
+	})
+	assert.DeepEqual(t, resources.Ulimits, []*units.Ulimit{{Name: "nofile", Soft: 1024, Hard: 1024}})
+}
+
+func TestToTmpfs(t *testing.T) {
+	tmpfs, err := toTmpfs("web", types.StringList{"/run", "/tmp:rw,noexec,nosuid,size=64m,mode=1777,uid=1000"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, tmpfs, map[string]string{
+		"/run": "",
+		"/tmp": "rw,noexec,nosuid,size=64m,mode=1777,uid=1000",
+	})
+
+	// options unknown to compose are left for the engine and kernel to check
+	tmpfs, err = toTmpfs("web", types.StringList{"/tmp:size=50%,huge=within_size,noswap,rprivate"})
+	assert.NilError(t, err)
+	assert.Equal(t, tmpfs["/tmp"], "size=50%,huge=within_size,noswap,rprivate")
+}
+
+func TestValidateHostConfigs(t *testing.T) {
+	tests := []struct {
+		name    string
+		service types.ServiceConfig
+		err     string
+	}{
+		{
+			name: "valid",
+			service: types.ServiceConfig{
+				Ulimits: map[string]*types.UlimitsConfig{"nofile": {Soft: 1024, Hard: 2048}},
+				ShmSize: 64 * 1024 * 1024,
+				Tmpfs:   types.StringList{"/tmp:size=1g"},
+				Sysctls: types.Mapping{"net.core.somaxconn": "1024"},
+				Volumes: []types.ServiceVolumeConfig{
+					{Type: types.VolumeTypeTmpfs, Target: "/cache", Tmpfs: &types.ServiceVolumeTmpfs{Size: 1024, Mode: 0o1777}},
+				},
+			},
+		},
+		{
+			name:    "unknown ulimit",
+			service: types.ServiceConfig{Ulimits: map[string]*types.UlimitsConfig{"files": {Single: 1024}}},
+			err:     `service "web": invalid ulimits.files "1024:1024": invalid ulimit type: files`,
+		},
+		{
+			name:    "soft ulimit above hard",
+			service: types.ServiceConfig{Ulimits: map[string]*types.UlimitsConfig{"nofile": {Soft: 4096, Hard: 1024}}},
+			err:     `service "web": invalid ulimits.nofile "4096:1024": ulimit soft limit must be less than or equal to hard limit: 4096 > 1024`,
+		},
+		{
+			name:    "negative shm_size",
+			service: types.ServiceConfig{ShmSize: -1},
+			err:     `service "web": invalid shm_size "-1": must not be negative`,
+		},
+		{
+			name:    "relative tmpfs",
+			service: types.ServiceConfig{Tmpfs: types.StringList{"tmp"}},
+			err:     `service "web": invalid tmpfs "tmp": mount path must be absolute`,
+		},
+		{
+			name:    "duplicate tmpfs",
+			service: types.ServiceConfig{Tmpfs: types.StringList{"/tmp", "/tmp:size=1m"}},
+			err:     `service "web": invalid tmpfs "/tmp:size=1m": mount path is declared more than once`,
+		},
+		{
+			name:    "invalid tmpfs size",
+			service: types.ServiceConfig{Tmpfs: types.StringList{"/tmp:size=big"}},
+			err:     `service "web": invalid tmpfs "/tmp:size=big": invalid size "big"`,
+		},
+		{
+			name:    "invalid tmpfs mode",
+			service: types.ServiceConfig{Tmpfs: types.StringList{"/tmp:mode=999"}},
+			err:     `service "web": invalid tmpfs "/tmp:mode=999": invalid mode "999", must be an octal file mode`,
+		},
+		{
+			name: "invalid tmpfs volume mode",
+			service: types.ServiceConfig{Volumes: []types.ServiceVolumeConfig{
+				{Type: types.VolumeTypeTmpfs, Target: "/cache", Tmpfs: &types.ServiceVolumeTmpfs{Mode: 0o17777}},
+			}},
+			err: `service "web": invalid volumes.tmpfs.mode "17777": must be a file mode, as 1777`,
+		},
+		{
+			name:    "invalid sysctl name",
+			service: types.ServiceConfig{Sysctls: types.Mapping{"net.core somaxconn": "1024"}},
+			err:     `service "web": invalid sysctls "net.core somaxconn": name must not contain whitespace or '='`,
+		},
+		{
+			name:    "empty sysctl value",
+			service: types.ServiceConfig{Sysctls: types.Mapping{"net.core.somaxconn": ""}},
+			err:     `service "web": invalid sysctls "net.core.somaxconn": value must not be empty`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.service.Name = "web"
+			err := validateHostConfigs(&types.Project{Services: types.Services{"web": tt.service}})
+			if tt.err == "" {
+				assert.NilError(t, err)
+				return
+			}
+			assert.Check(t, is.ErrorContains(err, tt.err))
+			var invalid api.InvalidAttributeError
+			assert.Check(t, errors.As(err, &invalid))
+			assert.Equal(t, invalid.Service, "web")
+		})
+	}
+}
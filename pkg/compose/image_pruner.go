@@ -55,6 +55,10 @@ type ImagePruneOptions struct {
 	// RemoveOrphans will result in the removal of images that were built for
 	// the project regardless of whether they are for a known service if true.
 	RemoveOrphans bool
+
+	// Dangling will result in the removal of images built for the project
+	// which are no longer referenced by any service, including untagged ones.
+	Dangling bool
 }
 
 // ImagePruner handles image removal during Compose `down` operations.
@@ -73,13 +77,24 @@ func NewImagePruner(imageClient client.ImageAPIClient, project *types.Project) *
 
 // ImagesToPrune returns the set of images that should be removed.
 func (p *ImagePruner) ImagesToPrune(ctx context.Context, opts ImagePruneOptions) ([]string, error) {
-	if opts.Mode == ImagePruneNone {
+	if opts.Mode == ImagePruneNone && !opts.Dangling {
 		return nil, nil
-	} else if opts.Mode != ImagePruneLocal && opts.Mode != ImagePruneAll {
+	} else if opts.Mode != ImagePruneNone && opts.Mode != ImagePruneLocal && opts.Mode != ImagePruneAll {
 		return nil, fmt.Errorf("unsupported image prune mode: %s", opts.Mode)
 	}
 	var images []string
 
+	if opts.Dangling {
+		unreferenced, err := p.unreferencedImages(ctx)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, unreferenced...)
+	}
+	if opts.Mode == ImagePruneNone {
+		return normalizeAndDedupeImages(images), nil
+	}
+
 	if opts.Mode == ImagePruneAll {
 		namedImages, err := p.namedImages(ctx)
 		if err != nil {
@@ -169,6 +184,42 @@ func (p *ImagePruner) labeledLocalImages(ctx context.Context) ([]image.Summary,
 	return projectImages, nil
 }
 
+// unreferencedImages are images built for the project which don't match the
+// image name of any service, either because they have been replaced by a more
+// recent build (and so are dangling) or because the service has been renamed
+// or removed from the project. Images of services disabled by profiles are
+// still referenced.
+func (p *ImagePruner) unreferencedImages(ctx context.Context) ([]string, error) {
+	projectImages, err := p.client.ImageList(ctx, image.ListOptions{
+		Filters: filters.NewArgs(projectFilter(p.project.Name)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	referenced := map[string]struct{}{}
+	for _, services := range []types.Services{p.project.Services, p.project.DisabledServices} {
+		for _, service := range services {
+			referenced[familiarName(api.GetImageNameOrDefault(service, p.project.Name))] = struct{}{}
+		}
+	}
+
+	var images []string
+	for _, img := range projectImages {
+		inUse := false
+		for _, tag := range img.RepoTags {
+			if _, ok := referenced[familiarName(tag)]; ok {
+				inUse = true
+				break
+			}
+		}
+		if !inUse {
+			images = append(images, img.ID)
+		}
+	}
+	return images, nil
+}
+
 // unlabeledLocalImages are images that match the implicit naming convention
 // for locally-built images but did not get labeled, presumably because they
 // were produced by an older version of Compose.
@@ -231,12 +282,7 @@ func normalizeAndDedupeImages(images []string) []string {
 		// since some references come from user input (service.image) and some
 		// come from the engine API, we standardize them, opting for the
 		// familiar name format since they'll also be displayed in the CLI
-		ref, err := reference.ParseNormalizedNamed(img)
-		if err == nil {
-			ref = reference.TagNameOnly(ref)
-			img = reference.FamiliarString(ref)
-		}
-		seen[img] = struct{}{}
+		seen[familiarName(img)] = struct{}{}
 	}
 	ret := make([]string, 0, len(seen))
 	for v := range seen {
@@ -246,3 +292,13 @@ func normalizeAndDedupeImages(images []string) []string {
 	sort.Strings(ret)
 	return ret
 }
+
+// familiarName returns the familiar format of an image reference, with implicit
+// tag set, or the reference as is if it can't be parsed (e.g. an image ID).
+func familiarName(img string) string {
+	ref, err := reference.ParseNormalizedNamed(img)
+	if err != nil {
+		return img
+	}
+	return reference.FamiliarString(reference.TagNameOnly(ref))
+}
@@ -22,14 +22,17 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/distribution/reference"
 	moby "github.com/docker/docker/api/types"
 	containerType "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/errdefs"
+	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
 	"github.com/docker/compose/v2/pkg/utils"
 )
 
@@ -66,6 +69,16 @@ func (s *composeService) Images(ctx context.Context, projectName string, options
 	if err != nil {
 		return nil, err
 	}
+	if options.CheckUpdates {
+		if err := s.checkImageUpdates(ctx, images); err != nil {
+			return nil, err
+		}
+	}
+	if options.SBOM {
+		if err := s.fetchImageSBOMs(ctx, images); err != nil {
+			return nil, err
+		}
+	}
 	summary := make([]api.ImageSummary, len(containers))
 	for i, container := range containers {
 		img, ok := images[container.ImageID]
@@ -95,6 +108,7 @@ func (s *composeService) getImages(ctx context.Context, images []string) (map[st
 			}
 			tag := ""
 			repository := ""
+			digest := ""
 			if len(inspect.RepoTags) > 0 {
 				ref, err := reference.ParseDockerRef(inspect.RepoTags[0])
 				if err != nil {
@@ -104,12 +118,14 @@ func (s *composeService) getImages(ctx context.Context, images []string) (map[st
 				if tagged, ok := ref.(reference.Tagged); ok {
 					tag = tagged.Tag()
 				}
+				digest = repoDigest(ref, inspect.RepoDigests)
 			}
 			l.Lock()
 			summary[img] = api.ImageSummary{
 				ID:         inspect.ID,
 				Repository: repository,
 				Tag:        tag,
+				Digest:     digest,
 				Size:       inspect.Size,
 			}
 			l.Unlock()
@@ -118,3 +134,83 @@ func (s *composeService) getImages(ctx context.Context, images []string) (map[st
 	}
 	return summary, eg.Wait()
 }
+
+// repoDigest returns the digest of the image in ref repository, among the image repo digests
+func repoDigest(ref reference.Named, repoDigests []string) string {
+	for _, d := range repoDigests {
+		named, err := reference.ParseNormalizedNamed(d)
+		if err != nil {
+			continue
+		}
+		if canonical, ok := named.(reference.Canonical); ok && named.Name() == ref.Name() {
+			return canonical.Digest().String()
+		}
+	}
+	return ""
+}
+
+// checkImageUpdates flags images whose tag resolves to another digest in the registry. Images which were not pulled,
+// or can't be resolved by the registry, like ones only built locally, are ignored
+func (s *composeService) checkImageUpdates(ctx context.Context, images map[string]api.ImageSummary) error {
+	resolve := ImageDigestResolver(ctx, s.configFile(), s.apiClient())
+	updated := map[string]bool{}
+	l := sync.Mutex{}
+	eg, _ := errgroup.WithContext(ctx)
+	for id, img := range images {
+		id, img := id, img
+		if img.Digest == "" || img.Tag == "" {
+			continue
+		}
+		eg.Go(func() error {
+			ref, err := reference.ParseDockerRef(img.Repository + ":" + img.Tag)
+			if err != nil {
+				return err
+			}
+			digest, err := resolve(ref)
+			if err != nil {
+				logrus.Debugf("unable to check updates for %s: %v", ref, err)
+				return nil
+			}
+			l.Lock()
+			updated[id] = digest.String() != img.Digest
+			l.Unlock()
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+	for id, update := range updated {
+		img := images[id]
+		img.UpdateAvailable = update
+		images[id] = img
+	}
+	return nil
+}
+
+func (s *composeService) ImagesPrune(ctx context.Context, project *types.Project, options api.ImagesPruneOptions) ([]string, error) {
+	images, err := NewImagePruner(s.apiClient(), project).ImagesToPrune(ctx, ImagePruneOptions{
+		Mode:          ImagePruneMode(options.Mode),
+		RemoveOrphans: options.RemoveOrphans,
+		Dangling:      options.Dangling,
+	})
+	if err != nil || len(images) == 0 {
+		return nil, err
+	}
+
+	err = progress.RunWithTitle(ctx, func(ctx context.Context) error {
+		w := progress.ContextWriter(ctx)
+		eg, ctx := errgroup.WithContext(ctx)
+		for _, img := range images {
+			img := img
+			eg.Go(func() error {
+				return s.removeImage(ctx, img, w)
+			})
+		}
+		return eg.Wait()
+	}, s.stdinfo(), "Removing")
+	if err != nil {
+		return nil, err
+	}
+	return images, nil
+}
@@ -0,0 +1,141 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli/config/configfile"
+	clitypes "github.com/docker/cli/cli/config/types"
+	moby "github.com/docker/docker/api/types"
+	containerType "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	compose "github.com/docker/compose/v2/pkg/api"
+)
+
+func TestImagesPruneDangling(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	project := &types.Project{
+		Name: strings.ToLower(testProject),
+		Services: types.Services{
+			"app": {Name: "app", Build: &types.BuildConfig{Context: "."}},
+			"web": {Name: "web", Image: "registry.example.com/web:1.0", Build: &types.BuildConfig{Context: "."}},
+		},
+		DisabledServices: types.Services{
+			"debug": {Name: "debug", Profiles: []string{"debug"}, Build: &types.BuildConfig{Context: "."}},
+		},
+	}
+
+	api.EXPECT().ImageList(gomock.Any(), image.ListOptions{
+		Filters: filters.NewArgs(projectFilter(strings.ToLower(testProject))),
+	}).Return([]image.Summary{
+		{ID: "sha256:app", RepoTags: []string{"testproject-app:latest"}},
+		{ID: "sha256:web", RepoTags: []string{"registry.example.com/web:1.0"}},
+		{ID: "sha256:old"},
+		{ID: "sha256:debug", RepoTags: []string{"testproject-debug:latest"}},
+		{ID: "sha256:removed", RepoTags: []string{"testproject-worker:latest"}},
+	}, nil)
+	api.EXPECT().ImageRemove(gomock.Any(), "sha256:old", image.RemoveOptions{}).Return(nil, nil)
+	api.EXPECT().ImageRemove(gomock.Any(), "sha256:removed", image.RemoveOptions{}).Return(nil, nil)
+
+	removed, err := tested.ImagesPrune(context.Background(), project, compose.ImagesPruneOptions{Dangling: true})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, removed, []string{"sha256:old", "sha256:removed"})
+}
+
+func TestImagesCheckUpdates(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	cli.EXPECT().ConfigFile().Return(&configfile.ConfigFile{
+		AuthConfigs: map[string]clitypes.AuthConfig{},
+	}).AnyTimes()
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	web := testContainer("web", "123", false)
+	web.ImageID = "sha256:web"
+	db := testContainer("db", "456", false)
+	db.ImageID = "sha256:db"
+	app := testContainer("app", "789", false)
+	app.ImageID = "sha256:app"
+	api.EXPECT().ContainerList(gomock.Any(), containerType.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(projectFilter(strings.ToLower(testProject))),
+	}).Return([]moby.Container{web, db, app}, nil)
+
+	webDigest := "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	dbDigest := "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	api.EXPECT().ImageInspectWithRaw(gomock.Any(), "sha256:web").Return(moby.ImageInspect{
+		ID:          "sha256:web",
+		RepoTags:    []string{"nginx:1.25"},
+		RepoDigests: []string{"nginx@" + webDigest},
+		Size:        42,
+	}, nil, nil)
+	api.EXPECT().ImageInspectWithRaw(gomock.Any(), "sha256:db").Return(moby.ImageInspect{
+		ID:          "sha256:db",
+		RepoTags:    []string{"postgres:16"},
+		RepoDigests: []string{"postgres@" + dbDigest},
+	}, nil, nil)
+	api.EXPECT().ImageInspectWithRaw(gomock.Any(), "sha256:app").Return(moby.ImageInspect{
+		ID:       "sha256:app",
+		RepoTags: []string{"testproject-app:latest"},
+	}, nil, nil)
+	api.EXPECT().DistributionInspect(gomock.Any(), "docker.io/library/nginx:1.25", gomock.Any()).Return(registry.DistributionInspect{
+		Descriptor: v1.Descriptor{Digest: digest.Digest("sha256:3333333333333333333333333333333333333333333333333333333333333333")},
+	}, nil)
+	api.EXPECT().DistributionInspect(gomock.Any(), "docker.io/library/postgres:16", gomock.Any()).Return(registry.DistributionInspect{
+		Descriptor: v1.Descriptor{Digest: digest.Digest(dbDigest)},
+	}, nil)
+
+	images, err := tested.Images(context.Background(), testProject, compose.ImagesOptions{CheckUpdates: true})
+	assert.NilError(t, err)
+	assert.Equal(t, len(images), 3)
+	for _, img := range images {
+		switch img.ID {
+		case "sha256:web":
+			assert.Equal(t, img.Digest, webDigest)
+			assert.Equal(t, img.Size, int64(42))
+			assert.Check(t, img.UpdateAvailable)
+		case "sha256:db":
+			assert.Equal(t, img.Digest, dbDigest)
+			assert.Check(t, !img.UpdateAvailable)
+		case "sha256:app":
+			assert.Equal(t, img.Digest, "")
+			assert.Check(t, !img.UpdateAvailable)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/mattn/go-shellwords"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// extInit declares commands to run to completion, in order, before the service container starts
+const extInit = "x-init"
+
+// initContainer is an item of the `x-init` service extension. Unset attributes are inherited from the service
+type initContainer struct {
+	Image       string            `mapstructure:"image"`
+	Command     any               `mapstructure:"command"`
+	Entrypoint  any               `mapstructure:"entrypoint"`
+	Environment map[string]string `mapstructure:"environment"`
+	User        string            `mapstructure:"user"`
+	WorkingDir  string            `mapstructure:"working_dir"`
+}
+
+// WithInitContainers declares a service for each init container set by services `x-init` extension. Those run once,
+// in order, after service dependencies and before the service container starts, so they get integrated into the
+// dependency graph and dependents of the service wait for them too
+func WithInitContainers(project *types.Project) (*types.Project, error) {
+	var err error
+	project.Services, err = withInitContainers(project.Services, project)
+	if err != nil {
+		return nil, err
+	}
+	// services disabled by profiles keep their init containers, so those get enabled along with the service
+	project.DisabledServices, err = withInitContainers(project.DisabledServices, project)
+	return project, err
+}
+
+func withInitContainers(services types.Services, project *types.Project) (types.Services, error) {
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		service := services[name]
+		y, ok := service.Extensions[extInit]
+		if !ok {
+			continue
+		}
+		var inits []initContainer
+		if err := mapstructure.Decode(y, &inits); err != nil {
+			return nil, fmt.Errorf("service %q: invalid %s: %w", name, extInit, err)
+		}
+
+		dependsOn := service.DependsOn
+		for i, init := range inits {
+			initName := fmt.Sprintf("%s-init-%d", name, i+1)
+			if _, ok := project.Services[initName]; ok {
+				return nil, fmt.Errorf("service %q: %s container conflicts with service %q", name, extInit, initName)
+			}
+			if _, ok := project.DisabledServices[initName]; ok {
+				return nil, fmt.Errorf("service %q: %s container conflicts with service %q", name, extInit, initName)
+			}
+			initService, err := init.toService(initName, service, project.Name)
+			if err != nil {
+				return nil, fmt.Errorf("service %q: invalid %s: %w", name, extInit, err)
+			}
+			initService.DependsOn = dependsOn
+			services[initName] = initService
+			dependsOn = types.DependsOnConfig{
+				initName: {Condition: types.ServiceConditionCompletedSuccessfully, Required: true},
+			}
+		}
+		// init containers depend on service dependencies, and service depends on the last init container
+		serviceDependsOn := types.DependsOnConfig{}
+		for dependency, config := range service.DependsOn {
+			serviceDependsOn[dependency] = config
+		}
+		for dependency, config := range dependsOn {
+			serviceDependsOn[dependency] = config
+		}
+		service.DependsOn = serviceDependsOn
+		delete(service.Extensions, extInit)
+		services[name] = service
+	}
+	return services, nil
+}
+
+// toService converts init container to a service. Init containers reuse the image of the service, which is built once
+// for the service, and are never attached, so they don't trigger a cascade stop by exiting
+func (c initContainer) toService(name string, service types.ServiceConfig, projectName string) (types.ServiceConfig, error) {
+	initService := types.ServiceConfig{
+		Name:        name,
+		Profiles:    service.Profiles,
+		Image:       api.GetImageNameOrDefault(service, projectName),
+		PullPolicy:  service.PullPolicy,
+		Platform:    service.Platform,
+		Environment: types.MappingWithEquals{},
+		Volumes:     service.Volumes,
+		VolumesFrom: service.VolumesFrom,
+		Networks:    service.Networks,
+		NetworkMode: service.NetworkMode,
+		Secrets:     service.Secrets,
+		Configs:     service.Configs,
+		User:        service.User,
+		WorkingDir:  service.WorkingDir,
+		Entrypoint:  service.Entrypoint,
+		Command:     service.Command,
+		ExtraHosts:  service.ExtraHosts,
+		DNS:         service.DNS,
+		Restart:     types.RestartPolicyNo,
+		LogDriver:   service.LogDriver,
+		LogOpt:      service.LogOpt,
+		Logging:     service.Logging,
+		Labels:      types.Labels{api.InitContainerLabel: service.Name},
+		Init:        service.Init,
+		ReadOnly:    service.ReadOnly,
+		Tmpfs:       service.Tmpfs,
+		CapAdd:      service.CapAdd,
+		CapDrop:     service.CapDrop,
+		SecurityOpt: service.SecurityOpt,
+		HealthCheck: &types.HealthCheckConfig{Disable: true},
+	}
+	if service.Build != nil {
+		// image is built for the service, and can't be pulled
+		initService.PullPolicy = types.PullPolicyBuild
+	}
+	for k, v := range service.Labels {
+		if k != api.InitContainerLabel {
+			initService.Labels[k] = v
+		}
+	}
+	for k, v := range service.Environment {
+		initService.Environment[k] = v
+	}
+	if c.Image != "" {
+		initService.Image = c.Image
+		initService.PullPolicy = ""
+		initService.Entrypoint = nil
+		initService.Command = nil
+	}
+	if c.Entrypoint != nil {
+		entrypoint, err := initCommand(c.Entrypoint)
+		if err != nil {
+			return initService, err
+		}
+		initService.Entrypoint = entrypoint
+	}
+	if c.Command != nil {
+		command, err := initCommand(c.Command)
+		if err != nil {
+			return initService, err
+		}
+		initService.Command = command
+	}
+	for k, v := range c.Environment {
+		value := v
+		initService.Environment[k] = &value
+	}
+	if c.User != "" {
+		initService.User = c.User
+	}
+	if c.WorkingDir != "" {
+		initService.WorkingDir = c.WorkingDir
+	}
+	return initService, nil
+}
+
+func initCommand(command any) ([]string, error) {
+	switch command := command.(type) {
+	case string:
+		return shellwords.Parse(command)
+	case []any:
+		args := make([]string, len(command))
+		for i, arg := range command {
+			args[i] = fmt.Sprint(arg)
+		}
+		return args, nil
+	default:
+		return nil, fmt.Errorf("command must be a string or a list")
+	}
+}
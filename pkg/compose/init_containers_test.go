@@ -0,0 +1,132 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/graph"
+)
+
+func TestWithInitContainers(t *testing.T) {
+	dbStarted := types.ServiceDependency{Condition: types.ServiceConditionStarted, Required: true}
+	completed := func() types.ServiceDependency {
+		return types.ServiceDependency{Condition: types.ServiceConditionCompletedSuccessfully, Required: true}
+	}
+	value := "bar"
+	project := &types.Project{
+		Name: "myproject",
+		Services: types.Services{
+			"db": {Name: "db", Image: "postgres"},
+			"app": {
+				Name:        "app",
+				Image:       "myapp",
+				Command:     types.ShellCommand{"serve"},
+				Environment: types.MappingWithEquals{"FOO": &value},
+				DependsOn:   types.DependsOnConfig{"db": dbStarted},
+				Extensions: types.Extensions{extInit: []any{
+					map[string]any{"command": "migrate --all"},
+					map[string]any{"image": "busybox", "command": []any{"chown", "-R", 1000, "/data"}, "user": "root"},
+				}},
+			},
+			"web": {Name: "web", Image: "nginx", DependsOn: types.DependsOnConfig{"app": dbStarted}},
+		},
+		DisabledServices: types.Services{
+			"debug": {
+				Name:       "debug",
+				Image:      "debug",
+				Profiles:   []string{"debug"},
+				Extensions: types.Extensions{extInit: []any{map[string]any{"command": "setup"}}},
+			},
+		},
+	}
+
+	project, err := WithInitContainers(project)
+	assert.NilError(t, err)
+
+	init1 := project.Services["app-init-1"]
+	assert.Equal(t, init1.Image, "myapp")
+	assert.DeepEqual(t, init1.Command, types.ShellCommand{"migrate", "--all"})
+	assert.DeepEqual(t, init1.Environment, types.MappingWithEquals{"FOO": &value})
+	assert.DeepEqual(t, init1.DependsOn, types.DependsOnConfig{"db": dbStarted})
+	assert.Equal(t, init1.Restart, types.RestartPolicyNo)
+	assert.Equal(t, init1.Labels[api.InitContainerLabel], "app")
+
+	init2 := project.Services["app-init-2"]
+	assert.Equal(t, init2.Image, "busybox")
+	assert.DeepEqual(t, init2.Command, types.ShellCommand{"chown", "-R", "1000", "/data"})
+	assert.Equal(t, init2.User, "root")
+	assert.DeepEqual(t, init2.DependsOn, types.DependsOnConfig{"app-init-1": completed()})
+
+	app := project.Services["app"]
+	assert.DeepEqual(t, app.DependsOn, types.DependsOnConfig{"db": dbStarted, "app-init-2": completed()})
+	_, ok := app.Extensions[extInit]
+	assert.Check(t, !ok)
+
+	debugInit := project.DisabledServices["debug-init-1"]
+	assert.DeepEqual(t, debugInit.Profiles, []string{"debug"})
+
+	// dependents of the service wait for init containers as well
+	var order []string
+	err = InDependencyOrder(context.Background(), project, func(_ context.Context, name string) error {
+		order = append(order, name)
+		return nil
+	}, graph.WithMaxConcurrency[string](1))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, order, []string{"db", "app-init-1", "app-init-2", "app", "web"})
+}
+
+func TestWithInitContainersBuild(t *testing.T) {
+	project := &types.Project{
+		Name: "myproject",
+		Services: types.Services{
+			"app": {
+				Name:       "app",
+				Build:      &types.BuildConfig{Context: "."},
+				Extensions: types.Extensions{extInit: []any{map[string]any{"command": "migrate"}}},
+			},
+		},
+	}
+	project, err := WithInitContainers(project)
+	assert.NilError(t, err)
+
+	// init container runs the image built for the service
+	init1 := project.Services["app-init-1"]
+	assert.Equal(t, init1.Image, "myproject-app")
+	assert.Check(t, init1.Build == nil)
+	assert.Equal(t, init1.PullPolicy, types.PullPolicyBuild)
+}
+
+func TestWithInitContainersConflict(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			"app": {
+				Name:       "app",
+				Image:      "myapp",
+				Extensions: types.Extensions{extInit: []any{map[string]any{"command": "migrate"}}},
+			},
+			"app-init-1": {Name: "app-init-1", Image: "busybox"},
+		},
+	}
+	_, err := WithInitContainers(project)
+	assert.Error(t, err, `service "app": x-init container conflicts with service "app-init-1"`)
+}
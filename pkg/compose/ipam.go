@@ -0,0 +1,183 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// validateNetworkAddresses checks `ipam` configuration of the networks compose creates, and services' static
+// `ipv4_address` and `ipv6_address` belong to one of the subnets declared by the network they connect to. Networks
+// which are not created by compose are skipped, as their subnets are only known by the engine
+func validateNetworkAddresses(project *types.Project) error {
+	var errs *multierror.Error
+	subnets := map[string][]netip.Prefix{}
+	for _, key := range sortedKeys(project.Networks) {
+		n := project.Networks[key]
+		if !isManagedNetwork(n) {
+			continue
+		}
+		for _, pool := range n.Ipam.Config {
+			if pool == nil {
+				continue
+			}
+			subnet, err := validateIPAMPool(key, *pool)
+			if err != nil {
+				errs = multierror.Append(errs, err)
+				continue
+			}
+			if subnet.IsValid() {
+				subnets[key] = append(subnets[key], subnet)
+			}
+		}
+	}
+
+	for _, name := range project.ServiceNames() {
+		service := project.Services[name]
+		for _, key := range sortedKeys(service.Networks) {
+			config := service.Networks[key]
+			n, ok := project.Networks[key]
+			if config == nil || !ok || !isManagedNetwork(n) {
+				continue
+			}
+			if config.Ipv4Address != "" {
+				if reason := validateStaticAddress(config.Ipv4Address, false, subnets[key]); reason != "" {
+					errs = multierror.Append(errs, api.InvalidAttributeError{
+						Service:   service.Name,
+						Attribute: fmt.Sprintf("networks.%s.ipv4_address", key),
+						Value:     config.Ipv4Address,
+						Reason:    reason,
+					})
+				}
+			}
+			if config.Ipv6Address != "" {
+				reason := validateStaticAddress(config.Ipv6Address, true, subnets[key])
+				if reason == "" && !n.EnableIPv6 {
+					reason = fmt.Sprintf("network %q doesn't set enable_ipv6", key)
+				}
+				if reason != "" {
+					errs = multierror.Append(errs, api.InvalidAttributeError{
+						Service:   service.Name,
+						Attribute: fmt.Sprintf("networks.%s.ipv6_address", key),
+						Value:     config.Ipv6Address,
+						Reason:    reason,
+					})
+				}
+			}
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// isManagedNetwork tells if network is created by compose, so its ipam configuration is the one declared by the model
+func isManagedNetwork(n types.NetworkConfig) bool {
+	if n.External {
+		return false
+	}
+	_, ok := n.Extensions[extProjectNetwork]
+	return !ok
+}
+
+// validateIPAMPool checks gateway, ip_range and aux_addresses of an ipam pool belong to its subnet, and returns the
+// subnet, which is invalid if the pool doesn't declare one and lets the ipam driver allocate it
+func validateIPAMPool(network string, pool types.IPAMPool) (netip.Prefix, error) {
+	invalid := func(attribute string, value string, reason string) error {
+		return fmt.Errorf("network %q: invalid ipam %s %q: %s", network, attribute, value, reason)
+	}
+	if pool.Subnet == "" {
+		if pool.Gateway != "" || pool.IPRange != "" || len(pool.AuxiliaryAddresses) > 0 {
+			return netip.Prefix{}, fmt.Errorf("network %q: ipam gateway, ip_range and aux_addresses require a subnet", network)
+		}
+		return netip.Prefix{}, nil
+	}
+	subnet, err := netip.ParsePrefix(pool.Subnet)
+	if err != nil {
+		return netip.Prefix{}, invalid("subnet", pool.Subnet, "must be in CIDR notation, as 172.28.0.0/16")
+	}
+	subnet = subnet.Masked()
+
+	var errs *multierror.Error
+	if pool.Gateway != "" {
+		if reason := validateAddressInSubnet(pool.Gateway, subnet); reason != "" {
+			errs = multierror.Append(errs, invalid("gateway", pool.Gateway, reason))
+		}
+	}
+	if pool.IPRange != "" {
+		ipRange, err := netip.ParsePrefix(pool.IPRange)
+		switch {
+		case err != nil:
+			errs = multierror.Append(errs, invalid("ip_range", pool.IPRange, "must be in CIDR notation"))
+		case ipRange.Bits() < subnet.Bits() || !subnet.Contains(ipRange.Addr()):
+			errs = multierror.Append(errs, invalid("ip_range", pool.IPRange, fmt.Sprintf("must be within subnet %s", subnet)))
+		}
+	}
+	for _, name := range sortedKeys(pool.AuxiliaryAddresses) {
+		address := pool.AuxiliaryAddresses[name]
+		if reason := validateAddressInSubnet(address, subnet); reason != "" {
+			errs = multierror.Append(errs, invalid("aux_addresses."+name, address, reason))
+		}
+	}
+	return subnet, errs.ErrorOrNil()
+}
+
+// validateAddressInSubnet returns the reason why address doesn't belong to subnet, or an empty string
+func validateAddressInSubnet(address string, subnet netip.Prefix) string {
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		return "not a valid IP address"
+	}
+	if !subnet.Contains(addr) {
+		return fmt.Sprintf("must be within subnet %s", subnet)
+	}
+	return ""
+}
+
+// validateStaticAddress returns the reason why address can't be assigned to a container connected to a network with
+// subnets, or an empty string. The engine only accepts static addresses for networks with user configured subnets
+func validateStaticAddress(address string, ipv6 bool, subnets []netip.Prefix) string {
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		return "not a valid IP address"
+	}
+	family := "IPv4"
+	if ipv6 {
+		family = "IPv6"
+	}
+	if addr.Is6() != ipv6 {
+		return fmt.Sprintf("not an %s address", family)
+	}
+	var candidates []netip.Prefix
+	for _, subnet := range subnets {
+		if subnet.Addr().Is6() != ipv6 {
+			continue
+		}
+		if subnet.Contains(addr) {
+			return ""
+		}
+		candidates = append(candidates, subnet)
+	}
+	if len(candidates) == 0 {
+		return fmt.Sprintf("network has no %s subnet configured by ipam", family)
+	}
+	return fmt.Sprintf("must be within network subnets %v", candidates)
+}
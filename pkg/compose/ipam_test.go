@@ -0,0 +1,133 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestValidateNetworkAddresses(t *testing.T) {
+	dualStack := types.NetworkConfig{
+		EnableIPv6: true,
+		Ipam: types.IPAMConfig{Config: []*types.IPAMPool{
+			{
+				Subnet:             "172.28.0.0/16",
+				Gateway:            "172.28.0.1",
+				IPRange:            "172.28.5.0/24",
+				AuxiliaryAddresses: types.Mapping{"host": "172.28.1.5"},
+			},
+			{Subnet: "2001:db8::/64", Gateway: "2001:db8::1"},
+		}},
+	}
+	tests := []struct {
+		name     string
+		network  types.NetworkConfig
+		endpoint types.ServiceNetworkConfig
+		err      string
+	}{
+		{
+			name:     "valid",
+			network:  dualStack,
+			endpoint: types.ServiceNetworkConfig{Ipv4Address: "172.28.5.10", Ipv6Address: "2001:db8::10"},
+		},
+		{
+			name:     "external network",
+			network:  types.NetworkConfig{External: true},
+			endpoint: types.ServiceNetworkConfig{Ipv4Address: "10.0.0.10"},
+		},
+		{
+			name:    "invalid subnet",
+			network: types.NetworkConfig{Ipam: types.IPAMConfig{Config: []*types.IPAMPool{{Subnet: "172.28.0.0"}}}},
+			err:     `network "back": invalid ipam subnet "172.28.0.0": must be in CIDR notation`,
+		},
+		{
+			name:    "gateway out of subnet",
+			network: types.NetworkConfig{Ipam: types.IPAMConfig{Config: []*types.IPAMPool{{Subnet: "172.28.0.0/16", Gateway: "172.29.0.1"}}}},
+			err:     `network "back": invalid ipam gateway "172.29.0.1": must be within subnet 172.28.0.0/16`,
+		},
+		{
+			name:    "ip_range out of subnet",
+			network: types.NetworkConfig{Ipam: types.IPAMConfig{Config: []*types.IPAMPool{{Subnet: "172.28.0.0/16", IPRange: "172.28.0.0/8"}}}},
+			err:     `network "back": invalid ipam ip_range "172.28.0.0/8": must be within subnet 172.28.0.0/16`,
+		},
+		{
+			name: "aux address out of subnet",
+			network: types.NetworkConfig{Ipam: types.IPAMConfig{Config: []*types.IPAMPool{
+				{Subnet: "172.28.0.0/16", AuxiliaryAddresses: types.Mapping{"host": "10.0.0.5"}},
+			}}},
+			err: `network "back": invalid ipam aux_addresses.host "10.0.0.5": must be within subnet 172.28.0.0/16`,
+		},
+		{
+			name:    "gateway without subnet",
+			network: types.NetworkConfig{Ipam: types.IPAMConfig{Config: []*types.IPAMPool{{Gateway: "172.28.0.1"}}}},
+			err:     `network "back": ipam gateway, ip_range and aux_addresses require a subnet`,
+		},
+		{
+			name:     "ipv4_address out of subnet",
+			network:  dualStack,
+			endpoint: types.ServiceNetworkConfig{Ipv4Address: "10.0.0.10"},
+			err:      `service "web": invalid networks.back.ipv4_address "10.0.0.10": must be within network subnets [172.28.0.0/16]`,
+		},
+		{
+			name:     "ipv4_address without subnet",
+			endpoint: types.ServiceNetworkConfig{Ipv4Address: "10.0.0.10"},
+			err:      `service "web": invalid networks.back.ipv4_address "10.0.0.10": network has no IPv4 subnet configured by ipam`,
+		},
+		{
+			name:     "ipv6 address set as ipv4_address",
+			network:  dualStack,
+			endpoint: types.ServiceNetworkConfig{Ipv4Address: "2001:db8::10"},
+			err:      `service "web": invalid networks.back.ipv4_address "2001:db8::10": not an IPv4 address`,
+		},
+		{
+			name: "ipv6_address without enable_ipv6",
+			network: types.NetworkConfig{Ipam: types.IPAMConfig{Config: []*types.IPAMPool{
+				{Subnet: "2001:db8::/64"},
+			}}},
+			endpoint: types.ServiceNetworkConfig{Ipv6Address: "2001:db8::10"},
+			err:      `service "web": invalid networks.back.ipv6_address "2001:db8::10": network "back" doesn't set enable_ipv6`,
+		},
+		{
+			name:     "ipv6_address out of subnet",
+			network:  dualStack,
+			endpoint: types.ServiceNetworkConfig{Ipv6Address: "2001:db9::10"},
+			err:      `service "web": invalid networks.back.ipv6_address "2001:db9::10": must be within network subnets [2001:db8::/64]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint := tt.endpoint
+			project := &types.Project{
+				Networks: types.Networks{"back": tt.network},
+				Services: types.Services{"web": {
+					Name:     "web",
+					Networks: map[string]*types.ServiceNetworkConfig{"back": &endpoint},
+				}},
+			}
+			err := validateNetworkAddresses(project)
+			if tt.err == "" {
+				assert.NilError(t, err)
+				return
+			}
+			assert.Check(t, is.ErrorContains(err, tt.err))
+		})
+	}
+}
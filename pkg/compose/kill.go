@@ -29,8 +29,11 @@ import (
 )
 
 func (s *composeService) Kill(ctx context.Context, projectName string, options api.KillOptions) error {
+	projectName = strings.ToLower(projectName)
 	return progress.RunWithTitle(ctx, func(ctx context.Context) error {
-		return s.kill(ctx, strings.ToLower(projectName), options)
+		return s.withProjectLock(ctx, projectName, func() error {
+			return s.kill(ctx, projectName, options)
+		})
 	}, s.stdinfo(), "Killing")
 }
 
@@ -67,10 +70,10 @@ func (s *composeService) kill(ctx context.Context, projectName string, options a
 			eg.Go(func() error {
 				eventName := getContainerProgressName(container)
 				w.Event(progress.KillingEvent(eventName))
-				err := s.apiClient().ContainerKill(ctx, container.ID, options.Signal)
+				err := s.apiClient().ContainerKill(ctx, container.ID, killSignal(container, options))
 				if err != nil {
 					w.Event(progress.ErrorMessageEvent(eventName, "Error while Killing"))
-					return err
+					return WrapCategorisedComposeError(err, KillFailure)
 				}
 				w.Event(progress.KilledEvent(eventName))
 				return nil
@@ -78,3 +81,11 @@ func (s *composeService) kill(ctx context.Context, projectName string, options a
 		})
 	return eg.Wait()
 }
+
+// killSignal returns the signal to send to a container, as set for its service or the default one
+func killSignal(container moby.Container, options api.KillOptions) string {
+	if signal, ok := options.Signals[container.Labels[api.ServiceLabel]]; ok {
+		return signal
+	}
+	return options.Signal
+}
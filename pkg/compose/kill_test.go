@@ -18,6 +18,7 @@ package compose
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -102,6 +103,41 @@ func TestKillSignal(t *testing.T) {
 	assert.NilError(t, err)
 }
 
+func TestKillServiceSignals(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	name := strings.ToLower(testProject)
+
+	ctx := context.Background()
+	api.EXPECT().ContainerList(ctx, containerType.ListOptions{
+		Filters: filters.NewArgs(projectFilter(name), hasConfigHashLabel()),
+	}).Return(
+		[]moby.Container{testContainer("service1", "123", false), testContainer("service2", "456", false)}, nil)
+	api.EXPECT().VolumeList(
+		gomock.Any(),
+		volume.ListOptions{
+			Filters: filters.NewArgs(projectFilter(strings.ToLower(testProject))),
+		}).
+		Return(volume.ListResponse{}, nil)
+	api.EXPECT().NetworkList(gomock.Any(), moby.NetworkListOptions{Filters: filters.NewArgs(projectFilter(strings.ToLower(testProject)))}).
+		Return([]moby.NetworkResource{
+			{ID: "abc123", Name: "testProject_default"},
+		}, nil)
+	api.EXPECT().ContainerKill(anyCancellableContext(), "123", "SIGHUP").Return(nil)
+	api.EXPECT().ContainerKill(anyCancellableContext(), "456", "SIGTERM").Return(errors.New("no such process"))
+
+	err := tested.kill(ctx, name, compose.KillOptions{Signal: "SIGTERM", Signals: map[string]string{"service1": "SIGHUP"}})
+	var composeErr Error
+	assert.Assert(t, errors.As(err, &composeErr))
+	assert.Equal(t, composeErr.GetMetricsFailureCategory(), KillFailure)
+}
+
 func testContainer(service string, id string, oneOff bool) moby.Container {
 	// canonical docker names in the API start with a leading slash, some
 	// parts of Compose code will attempt to strip this off, so make sure
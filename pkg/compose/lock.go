@@ -0,0 +1,110 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/docker/compose/v2/internal/locker"
+)
+
+type projectLocks struct {
+	mu   sync.Mutex
+	held map[string]*heldLock
+}
+
+type heldLock struct {
+	lock  *locker.ProjectLock
+	count int
+	// ready is closed once the first user of the lock acquired it, or failed to, with err set
+	ready chan struct{}
+	err   error
+}
+
+// SetLockTimeout defines how long a command waits for another compose process to release the project lock
+func (s *composeService) SetLockTimeout(timeout time.Duration) {
+	s.lockTimeout = timeout
+}
+
+// withProjectLock runs fn while holding the advisory lock which prevents concurrent compose processes to run
+// conflicting operations on the same project. Lock is reentrant, so nested operations don't deadlock.
+func (s *composeService) withProjectLock(ctx context.Context, projectName string, fn func() error) error {
+	if s.dryRun || s.locks == nil {
+		return fn()
+	}
+	release, err := s.lockProject(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return fn()
+}
+
+func (s *composeService) lockProject(ctx context.Context, projectName string) (func(), error) {
+	s.locks.mu.Lock()
+	held, ok := s.locks.held[projectName]
+	if !ok {
+		held = &heldLock{ready: make(chan struct{})}
+		s.locks.held[projectName] = held
+	}
+	// registered as a user, so lock can't be released meanwhile
+	held.count++
+	s.locks.mu.Unlock()
+
+	release := func() {
+		s.locks.mu.Lock()
+		defer s.locks.mu.Unlock()
+		held.count--
+		if held.count == 0 {
+			delete(s.locks.held, projectName)
+			if held.lock != nil {
+				_ = held.lock.Unlock()
+			}
+		}
+	}
+
+	if !ok {
+		// wait for the file lock without holding mu, so operations on other projects, and nested ones, don't block
+		held.lock, held.err = acquireProjectLock(ctx, projectName, s.lockTimeout)
+		close(held.ready)
+	}
+	select {
+	case <-held.ready:
+	case <-ctx.Done():
+		release()
+		return nil, ctx.Err()
+	}
+	if held.err != nil {
+		release()
+		return nil, held.err
+	}
+	return release, nil
+}
+
+func acquireProjectLock(ctx context.Context, projectName string, timeout time.Duration) (*locker.ProjectLock, error) {
+	l, err := locker.NewProjectLock(projectName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot take exclusive lock for project %q: %w", projectName, err)
+	}
+	if err := l.Lock(ctx, timeout); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
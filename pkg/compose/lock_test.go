@@ -0,0 +1,60 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/internal/locker"
+)
+
+func TestLockProjectDoesNotBlockOtherProjects(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	// lock for project "busy" is held by another process
+	other, err := locker.NewProjectLock("busy")
+	assert.NilError(t, err)
+	assert.NilError(t, other.TryLock())
+
+	tested := composeService{locks: &projectLocks{held: map[string]*heldLock{}}, lockTimeout: time.Minute}
+	waiting := make(chan error, 1)
+	go func() {
+		release, err := tested.lockProject(context.Background(), "busy")
+		if err == nil {
+			release()
+		}
+		waiting <- err
+	}()
+
+	// while waiting for "busy", locks on other projects, nested ones included, are taken right away
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	release, err := tested.lockProject(ctx, "free")
+	assert.NilError(t, err)
+	nested, err := tested.lockProject(ctx, "free")
+	assert.NilError(t, err)
+	nested()
+	release()
+
+	assert.NilError(t, other.Unlock())
+	assert.NilError(t, <-waiting)
+	assert.Equal(t, len(tested.locks.held), 0)
+}
@@ -0,0 +1,76 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	containerType "github.com/docker/docker/api/types/container"
+)
+
+// readableLogDrivers store logs so the engine can read them back, other drivers rely on the engine's dual logging cache
+var readableLogDrivers = []string{"json-file", "local", "journald"}
+
+// checkLogDrivers makes sure logging drivers set by services are supported by the Docker Engine, either built-in
+// or installed as a plugin, so a typo doesn't get silently replaced by the engine's default driver
+func (s *composeService) checkLogDrivers(ctx context.Context, project *types.Project, services []string) error {
+	drivers := map[string]string{}
+	for _, name := range services {
+		service, ok := project.Services[name]
+		if !ok || service.Logging == nil || service.Logging.Driver == "" || service.Logging.Driver == "none" {
+			continue
+		}
+		drivers[name] = service.Logging.Driver
+	}
+	if len(drivers) == 0 {
+		return nil
+	}
+
+	info, err := s.apiClient().Info(ctx)
+	if err != nil {
+		return err
+	}
+	supported := info.Plugins.Log
+	for _, name := range services {
+		driver, ok := drivers[name]
+		if !ok {
+			continue
+		}
+		if slices.Contains(supported, driver) || (!strings.Contains(driver, ":") && slices.Contains(supported, driver+":latest")) {
+			continue
+		}
+		return fmt.Errorf("service %q uses logging driver %q, which is not supported by Docker Engine. Supported drivers are: %s",
+			name, driver, strings.Join(supported, ", "))
+	}
+	return nil
+}
+
+// isReadableLogConfig tells if the engine can read back logs for a container using this logging configuration
+func isReadableLogConfig(config containerType.LogConfig) bool {
+	switch {
+	case config.Type == "" || slices.Contains(readableLogDrivers, config.Type):
+		return true
+	case config.Type == "none":
+		return false
+	default:
+		return config.Config["cache-disabled"] != "true"
+	}
+}
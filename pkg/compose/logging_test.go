@@ -0,0 +1,69 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	containerType "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/system"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestCheckLogDrivers(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+	project := &types.Project{
+		Name: testProject,
+		Services: types.Services{
+			"web":    {Name: "web", Logging: &types.LoggingConfig{Driver: "syslog"}},
+			"worker": {Name: "worker", Logging: &types.LoggingConfig{Driver: "loki"}},
+			"db":     {Name: "db", Logging: &types.LoggingConfig{Driver: "sylog"}},
+			"cache":  {Name: "cache", Logging: &types.LoggingConfig{Driver: "none"}},
+		},
+	}
+	api.EXPECT().Info(gomock.Any()).Return(system.Info{
+		Plugins: system.PluginsInfo{Log: []string{"json-file", "syslog", "loki:latest"}},
+	}, nil).Times(2)
+
+	err := tested.checkLogDrivers(context.Background(), project, []string{"web", "worker", "cache"})
+	assert.NilError(t, err)
+
+	err = tested.checkLogDrivers(context.Background(), project, []string{"web", "db"})
+	assert.Error(t, err, `service "db" uses logging driver "sylog", which is not supported by Docker Engine. `+
+		`Supported drivers are: json-file, syslog, loki:latest`)
+
+	// no logging driver set, engine isn't queried
+	err = tested.checkLogDrivers(context.Background(), project, []string{"cache"})
+	assert.NilError(t, err)
+}
+
+func TestIsReadableLogConfig(t *testing.T) {
+	assert.Check(t, isReadableLogConfig(containerType.LogConfig{}))
+	assert.Check(t, isReadableLogConfig(containerType.LogConfig{Type: "json-file"}))
+	assert.Check(t, isReadableLogConfig(containerType.LogConfig{Type: "syslog"}))
+	assert.Check(t, !isReadableLogConfig(containerType.LogConfig{Type: "syslog", Config: map[string]string{"cache-disabled": "true"}}))
+	assert.Check(t, !isReadableLogConfig(containerType.LogConfig{Type: "none"}))
+}
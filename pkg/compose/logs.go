@@ -19,6 +19,7 @@ package compose
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"time"
 
@@ -26,7 +27,6 @@ import (
 	containerType "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/stdcopy"
-	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/docker/compose/v2/pkg/api"
@@ -69,7 +69,11 @@ func (s *composeService) Logs(
 			err := s.logContainers(ctx, consumer, c, options)
 			var notImplErr errdefs.ErrNotImplemented
 			if errors.As(err, &notImplErr) {
-				logrus.Warnf("Can't retrieve logs for %q: %s", getCanonicalContainerName(c), err.Error())
+				api.Warn(ctx, api.Warning{
+					Code:    api.WarningOperationFailed,
+					Service: c.Labels[api.ServiceLabel],
+					Message: fmt.Sprintf("Can't retrieve logs for %q: %s", getCanonicalContainerName(c), err.Error()),
+				})
 				return nil
 			}
 			return err
@@ -87,7 +91,7 @@ func (s *composeService) Logs(
 		for _, c := range containers {
 			printer.HandleEvent(api.ContainerEvent{
 				Type:      api.ContainerEventAttach,
-				Container: getContainerNameWithoutProject(c),
+				Container: s.getContainerNameWithoutProject(c),
 				ID:        c.ID,
 				Service:   c.Labels[api.ServiceLabel],
 			})
@@ -97,7 +101,7 @@ func (s *composeService) Logs(
 			err := s.watchContainers(ctx, projectName, options.Services, nil, printer.HandleEvent, containers, func(c types.Container, t time.Time) error {
 				printer.HandleEvent(api.ContainerEvent{
 					Type:      api.ContainerEventAttach,
-					Container: getContainerNameWithoutProject(c),
+					Container: s.getContainerNameWithoutProject(c),
 					ID:        c.ID,
 					Service:   c.Labels[api.ServiceLabel],
 				})
@@ -140,6 +144,15 @@ func (s *composeService) logContainers(ctx context.Context, consumer api.LogCons
 		return err
 	}
 
+	if cnt.HostConfig != nil && !isReadableLogConfig(cnt.HostConfig.LogConfig) {
+		api.Warn(ctx, api.Warning{
+			Code:    api.WarningOperationFailed,
+			Service: c.Labels[api.ServiceLabel],
+			Message: fmt.Sprintf("Can't retrieve logs for %q: logging driver %q does not support reading", getCanonicalContainerName(c), cnt.HostConfig.LogConfig.Type),
+		})
+		return nil
+	}
+
 	r, err := s.apiClient().ContainerLogs(ctx, cnt.ID, containerType.LogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
@@ -154,7 +167,7 @@ func (s *composeService) logContainers(ctx context.Context, consumer api.LogCons
 	}
 	defer r.Close() //nolint:errcheck
 
-	name := getContainerNameWithoutProject(c)
+	name := s.getContainerNameWithoutProject(c)
 	w := utils.GetWriter(func(line string) {
 		consumer.Log(name, line)
 	})
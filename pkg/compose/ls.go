@@ -27,7 +27,6 @@ import (
 	moby "github.com/docker/docker/api/types"
 	containerType "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
-	"github.com/sirupsen/logrus"
 )
 
 func (s *composeService) List(ctx context.Context, opts api.ListOptions) ([]api.Stack, error) {
@@ -39,10 +38,58 @@ func (s *composeService) List(ctx context.Context, opts api.ListOptions) ([]api.
 		return nil, err
 	}
 
-	return containersToStacks(list)
+	return containersToStacks(ctx, list)
 }
 
-func containersToStacks(containers []moby.Container) ([]api.Stack, error) {
+func (s *composeService) Projects(ctx context.Context, opts api.ListOptions) ([]api.ProjectSummary, error) {
+	list, err := s.apiClient().ContainerList(ctx, containerType.ListOptions{
+		Filters: filters.NewArgs(hasProjectLabelFilter(), hasConfigHashLabel()),
+		All:     opts.All,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return containersToProjects(ctx, list)
+}
+
+func containersToProjects(ctx context.Context, containers []moby.Container) ([]api.ProjectSummary, error) {
+	containersByLabel, keys, err := groupContainerByLabel(containers, api.ProjectLabel)
+	if err != nil {
+		return nil, err
+	}
+	var projects []api.ProjectSummary
+	for _, project := range keys {
+		containers := containersByLabel[project]
+		configFiles, err := combinedConfigFiles(containers)
+		if err != nil {
+			api.Warn(ctx, api.Warning{Code: api.WarningInvalidLabel, Message: err.Error()})
+			configFiles = "N/A"
+		}
+
+		services := map[string]bool{}
+		running := 0
+		for _, c := range containers {
+			services[c.Labels[api.ServiceLabel]] = true
+			if c.State == ContainerRunning {
+				running++
+			}
+		}
+
+		projects = append(projects, api.ProjectSummary{
+			Name:        project,
+			Status:      combinedStatus(containerToState(containers)),
+			ConfigFiles: configFiles,
+			WorkingDir:  containers[0].Labels[api.WorkingDirLabel],
+			Services:    len(services),
+			Containers:  len(containers),
+			Running:     running,
+		})
+	}
+	return projects, nil
+}
+
+func containersToStacks(ctx context.Context, containers []moby.Container) ([]api.Stack, error) {
 	containersByLabel, keys, err := groupContainerByLabel(containers, api.ProjectLabel)
 	if err != nil {
 		return nil, err
@@ -51,7 +98,7 @@ func containersToStacks(containers []moby.Container) ([]api.Stack, error) {
 	for _, project := range keys {
 		configFiles, err := combinedConfigFiles(containersByLabel[project])
 		if err != nil {
-			logrus.Warn(err.Error())
+			api.Warn(ctx, api.Warning{Code: api.WarningInvalidLabel, Message: err.Error()})
 			configFiles = "N/A"
 		}
 
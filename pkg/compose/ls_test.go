@@ -17,6 +17,7 @@
 package compose
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -44,7 +45,7 @@ func TestContainersToStacks(t *testing.T) {
 			Labels: map[string]string{api.ProjectLabel: "project2", api.ConfigFilesLabel: "/home/project2-docker-compose.yaml"},
 		},
 	}
-	stacks, err := containersToStacks(containers)
+	stacks, err := containersToStacks(context.Background(), containers)
 	assert.NilError(t, err)
 	assert.DeepEqual(t, stacks, []api.Stack{
 		{
@@ -62,6 +63,44 @@ func TestContainersToStacks(t *testing.T) {
 	})
 }
 
+func TestContainersToProjects(t *testing.T) {
+	labels := func(project, service string) map[string]string {
+		return map[string]string{
+			api.ProjectLabel:     project,
+			api.ServiceLabel:     service,
+			api.ConfigFilesLabel: "/home/" + project + "/compose.yaml",
+			api.WorkingDirLabel:  "/home/" + project,
+		}
+	}
+	containers := []moby.Container{
+		{ID: "c1", State: "running", Labels: labels("project1", "web")},
+		{ID: "c2", State: "running", Labels: labels("project1", "web")},
+		{ID: "c3", State: "exited", Labels: labels("project1", "db")},
+		{ID: "c4", State: "exited", Labels: labels("project2", "job")},
+	}
+	projects, err := containersToProjects(context.Background(), containers)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, projects, []api.ProjectSummary{
+		{
+			Name:        "project1",
+			Status:      "exited(1), running(2)",
+			ConfigFiles: "/home/project1/compose.yaml",
+			WorkingDir:  "/home/project1",
+			Services:    2,
+			Containers:  3,
+			Running:     2,
+		},
+		{
+			Name:        "project2",
+			Status:      "exited(1)",
+			ConfigFiles: "/home/project2/compose.yaml",
+			WorkingDir:  "/home/project2",
+			Services:    1,
+			Containers:  1,
+		},
+	})
+}
+
 func TestStacksMixedStatus(t *testing.T) {
 	assert.Equal(t, combinedStatus([]string{"running"}), "running(1)")
 	assert.Equal(t, combinedStatus([]string{"running", "running", "running"}), "running(3)")
@@ -39,8 +39,12 @@ const (
 	BuildFailureStatus = "failure-build"
 	// PullFailureStatus failure pulling imge
 	PullFailureStatus = "failure-pull"
+	// KillFailureStatus failure killing containers
+	KillFailureStatus = "failure-kill"
 	// CanceledStatus command canceled
 	CanceledStatus = "canceled"
+	// RolledBackStatus command canceled, and resources it created removed
+	RolledBackStatus = "canceled-rolled-back"
 )
 
 var (
@@ -54,6 +58,8 @@ var (
 	BuildFailure = FailureCategory{MetricsStatus: BuildFailureStatus, ExitCode: 17}
 	// PullFailure failure while pulling image
 	PullFailure = FailureCategory{MetricsStatus: PullFailureStatus, ExitCode: 18}
+	// KillFailure failure while killing containers
+	KillFailure = FailureCategory{MetricsStatus: KillFailureStatus, ExitCode: 19}
 )
 
 // ByExitCode retrieve FailureCategory based on command exit code
@@ -71,6 +77,8 @@ func ByExitCode(exitCode int) FailureCategory {
 		return BuildFailure
 	case 18:
 		return PullFailure
+	case 19:
+		return KillFailure
 	case 130:
 		return FailureCategory{MetricsStatus: CanceledStatus, ExitCode: exitCode}
 	default:
@@ -0,0 +1,91 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	containerType "github.com/docker/docker/api/types/container"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// WithContainerNamer sets the naming scheme used for service containers, instead of {project}-{service}-{number}.
+// Containers created with another naming scheme are still reported by compose, but their name is no longer shortened
+// in logs and events
+func WithContainerNamer(namer api.ContainerNamer) Option {
+	return func(s *composeService) {
+		s.namer = namer
+	}
+}
+
+// SetContainerNamer overrides the {project}-{service}-{number} naming scheme of service containers
+func (s *composeService) SetContainerNamer(namer api.ContainerNamer) {
+	s.namer = namer
+}
+
+func (s *composeService) containerNamer() api.ContainerNamer {
+	if s.namer == nil {
+		return api.DefaultContainerNamer
+	}
+	return s.namer
+}
+
+// checkContainerNames detects a custom naming scheme generating the same name for distinct containers, or a name
+// already used by a container which isn't the one compose expects
+func (s *composeService) checkContainerNames(ctx context.Context, project *types.Project) error {
+	if s.namer == nil {
+		// default naming scheme is unique by construction, container_name being checked by CheckContainerNameUnicity
+		return nil
+	}
+	type slot struct {
+		service string
+		number  int
+	}
+	names := map[string]slot{}
+	for _, service := range project.Services {
+		if service.ContainerName != "" {
+			continue
+		}
+		for number := 1; number <= service.GetScale(); number++ {
+			name := getContainerName(s.namer, project.Name, service, number)
+			if other, ok := names[name]; ok {
+				return fmt.Errorf("container name %q is generated for both %s #%d and %s #%d", name, other.service, other.number, service.Name, number)
+			}
+			names[name] = slot{service: service.Name, number: number}
+		}
+	}
+
+	containers, err := s.apiClient().ContainerList(ctx, containerType.ListOptions{All: true})
+	if err != nil {
+		return err
+	}
+	for _, c := range containers {
+		expected, ok := names[getCanonicalContainerName(c)]
+		if !ok {
+			continue
+		}
+		if c.Labels[api.ProjectLabel] != project.Name || c.Labels[api.ServiceLabel] != expected.service ||
+			c.Labels[api.ContainerNumberLabel] != strconv.Itoa(expected.number) {
+			return fmt.Errorf("container name %q generated for %s #%d is already in use by container %s", getCanonicalContainerName(c), expected.service, expected.number, c.ID)
+		}
+	}
+	return nil
+}
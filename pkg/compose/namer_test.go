@@ -0,0 +1,104 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	moby "github.com/docker/docker/api/types"
+	containerType "github.com/docker/docker/api/types/container"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	compose "github.com/docker/compose/v2/pkg/api"
+)
+
+func branchNamer(branch string) compose.ContainerNamer {
+	return compose.ContainerNamerFunc(func(projectName string, serviceName string, number int) string {
+		return fmt.Sprintf("%s-%s-%s-%d", projectName, branch, serviceName, number)
+	})
+}
+
+func TestContainerNamerAliases(t *testing.T) {
+	project := &types.Project{Name: "myapp"}
+	service := types.ServiceConfig{Name: "web"}
+	aliases := getAliases(branchNamer("feature-x"), project, service, 2, "default", true)
+	assert.DeepEqual(t, aliases, []string{"myapp-feature-x-web-2", "web"})
+
+	service.ContainerName = "custom"
+	assert.Equal(t, getContainerName(branchNamer("feature-x"), project.Name, service, 1), "custom")
+}
+
+func TestCheckContainerNames(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+	project := &types.Project{
+		Name: testProject,
+		Services: types.Services{
+			"web": {Name: "web", Scale: intPtr(2)},
+		},
+	}
+
+	// default naming scheme doesn't need any check
+	assert.NilError(t, tested.checkContainerNames(context.Background(), project))
+
+	tested.SetContainerNamer(compose.ContainerNamerFunc(func(projectName string, serviceName string, _ int) string {
+		return projectName + "-" + serviceName
+	}))
+	err := tested.checkContainerNames(context.Background(), project)
+	assert.ErrorContains(t, err, `container name "testProject-web" is generated for both web #1 and web #2`)
+
+	tested.SetContainerNamer(branchNamer("main"))
+	other := testContainer("web", "123", false)
+	other.Names = []string{"/testProject-main-web-2"}
+	other.Labels[compose.ProjectLabel] = "otherProject"
+	api.EXPECT().ContainerList(gomock.Any(), containerType.ListOptions{All: true}).Return([]moby.Container{
+		testContainer("web", "456", false),
+		other,
+	}, nil)
+	err = tested.checkContainerNames(context.Background(), project)
+	assert.ErrorContains(t, err, `container name "testProject-main-web-2" generated for web #2 is already in use by container 123`)
+}
+
+func TestContainerNameWithoutProject(t *testing.T) {
+	container := func(name string) moby.Container {
+		return moby.Container{
+			Names: []string{"/" + name},
+			Labels: map[string]string{
+				compose.ProjectLabel:         "myapp",
+				compose.ServiceLabel:         "web",
+				compose.ContainerNumberLabel: "1",
+			},
+		}
+	}
+
+	tested := NewComposeService(nil).(*composeService)
+	assert.Equal(t, tested.getContainerNameWithoutProject(container("myapp-web-1")), "web-1")
+	assert.Equal(t, tested.getContainerNameWithoutProject(container("custom")), "custom")
+
+	tested = NewComposeService(nil, WithContainerNamer(branchNamer("main"))).(*composeService)
+	assert.Equal(t, tested.getContainerNameWithoutProject(container("myapp-main-web-1")), "main-web-1")
+	assert.Equal(t, tested.getContainerNameWithoutProject(container("myapp-web-1")), "myapp-web-1")
+}
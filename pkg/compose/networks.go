@@ -0,0 +1,235 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/errdefs"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/utils"
+)
+
+// extProjectNetwork declares a network is the one created by another compose project, set as the project name or
+// as a mapping with the project name and the network key within this project, which defaults to the same key
+const extProjectNetwork = "x-project"
+
+type projectNetworkConfig struct {
+	Name    string `mapstructure:"name"`
+	Network string `mapstructure:"network"`
+}
+
+// resolveProjectNetworks sets networks declared by x-project as external ones, named after the network the other
+// project created, as found by its compose labels. Projects can then interconnect without hardcoding network names
+func (s *composeService) resolveProjectNetworks(ctx context.Context, project *types.Project) error {
+	for key, n := range project.Networks {
+		ext, ok := n.Extensions[extProjectNetwork]
+		if !ok {
+			continue
+		}
+		var config projectNetworkConfig
+		if name, ok := ext.(string); ok {
+			config.Name = name
+		} else if err := mapstructure.Decode(ext, &config); err != nil {
+			return fmt.Errorf("network %s: invalid %s: %w", key, extProjectNetwork, err)
+		}
+		if config.Name == "" {
+			return fmt.Errorf("network %s: %s requires a project name", key, extProjectNetwork)
+		}
+		if config.Network == "" {
+			config.Network = key
+		}
+
+		networks, err := s.apiClient().NetworkList(ctx, moby.NetworkListOptions{
+			Filters: filters.NewArgs(projectFilter(strings.ToLower(config.Name)), networkFilter(config.Network)),
+		})
+		if err != nil {
+			return err
+		}
+		if len(networks) == 0 {
+			return fmt.Errorf("network %s of project %s not found, project must be started first: %w", config.Network, config.Name, api.ErrNotFound)
+		}
+		n.Name = networks[0].Name
+		n.External = true
+		project.Networks[key] = n
+	}
+	return nil
+}
+
+// validateExternalNetworks checks all networks declared as external exist before anything gets pulled, built or
+// created, so user gets the full list of missing networks at once
+func (s *composeService) validateExternalNetworks(ctx context.Context, project *types.Project) error {
+	var missing []string
+	for _, n := range project.Networks {
+		if !n.External {
+			continue
+		}
+		networks, err := s.lookupNetwork(ctx, n.Name)
+		if err != nil && !errdefs.IsNotFound(err) {
+			return err
+		}
+		if len(networks) == 0 {
+			missing = append(missing, n.Name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	enabled, err := s.isSWarmEnabled(ctx)
+	if err != nil {
+		return err
+	}
+	if enabled {
+		// Swarm nodes do not register overlay networks created on a different node unless they're in use,
+		// see resolveExternalNetwork
+		return nil
+	}
+	sort.Strings(missing)
+	return api.MissingNetworksError{Networks: missing}
+}
+
+func (s *composeService) Networks(ctx context.Context, projectName string) ([]api.NetworkSummary, error) {
+	projectName = strings.ToLower(projectName)
+	networks, err := s.apiClient().NetworkList(ctx, moby.NetworkListOptions{
+		Filters: filters.NewArgs(projectFilter(projectName)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	containers, err := s.getContainers(ctx, projectName, oneOffInclude, true)
+	if err != nil {
+		return nil, err
+	}
+	services := map[string]string{}
+	for _, c := range containers {
+		services[c.ID] = c.Labels[api.ServiceLabel]
+	}
+
+	summary := make([]api.NetworkSummary, 0, len(networks))
+	for _, n := range networks {
+		// NetworkList doesn't report attached containers
+		inspect, err := s.apiClient().NetworkInspect(ctx, n.ID, moby.NetworkInspectOptions{})
+		if err != nil {
+			return nil, err
+		}
+		network := api.NetworkSummary{
+			ID:       inspect.ID,
+			Name:     inspect.Name,
+			Network:  inspect.Labels[api.NetworkLabel],
+			Driver:   inspect.Driver,
+			Scope:    inspect.Scope,
+			Internal: inspect.Internal,
+		}
+		for _, config := range inspect.IPAM.Config {
+			if config.Subnet != "" {
+				network.Subnets = append(network.Subnets, config.Subnet)
+			}
+		}
+		for id := range inspect.Containers {
+			if service, ok := services[id]; ok && !utils.StringContains(network.Services, service) {
+				network.Services = append(network.Services, service)
+			}
+		}
+		sort.Strings(network.Services)
+		summary = append(summary, network)
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		return summary[i].Name < summary[j].Name
+	})
+	return summary, nil
+}
+
+// NetworkOrder returns the keys of the networks service containers get connected to, sorted by decreasing priority
+// as compose connects them, and the key of the network the Docker Engine selects for the default route, if any.
+// The engine doesn't know about priorities: it routes through a network with a gateway, preferring dual-stack networks,
+// then the first one sorted by name
+func NetworkOrder(project *types.Project, service types.ServiceConfig) ([]string, string) {
+	if len(service.Networks) == 0 {
+		if service.NetworkMode != "" {
+			return nil, ""
+		}
+		service.Networks = map[string]*types.ServiceNetworkConfig{"default": nil}
+	}
+	order := service.NetworksByPriority()
+
+	var route string
+	rank := func(n types.NetworkConfig) int {
+		if n.EnableIPv6 {
+			return 3
+		}
+		return 1
+	}
+	for _, key := range order {
+		n, ok := project.Networks[key]
+		if !ok || n.Internal {
+			continue
+		}
+		if route == "" {
+			route = key
+			continue
+		}
+		current := project.Networks[route]
+		if rank(n) > rank(current) || (rank(n) == rank(current) && n.Name < current.Name) {
+			route = key
+		}
+	}
+	return order, route
+}
+
+// warnDefaultRoute warns about services for which the network with the highest priority is not the one the Docker
+// Engine selects for the default route
+func warnDefaultRoute(ctx context.Context, project *types.Project) {
+	for _, name := range project.ServiceNames() {
+		service := project.Services[name]
+		order, route := NetworkOrder(project, service)
+		if route == "" {
+			continue
+		}
+		for _, key := range order {
+			if project.Networks[key].Internal {
+				continue
+			}
+			if key != route && networkPriority(service, key) > networkPriority(service, route) {
+				api.Warn(ctx, api.Warning{
+					Code:      api.WarningUnsupportedOption,
+					Service:   service.Name,
+					Attribute: fmt.Sprintf("networks.%s.priority", key),
+					Message: fmt.Sprintf("doesn't select the default route, the Docker Engine routes through network %s",
+						project.Networks[route].Name),
+				})
+			}
+			break
+		}
+	}
+}
+
+func networkPriority(service types.ServiceConfig, key string) int {
+	if config := service.Networks[key]; config != nil {
+		return config.Priority
+	}
+	return 0
+}
@@ -0,0 +1,229 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/api/types/system"
+	"github.com/docker/docker/errdefs"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	compose "github.com/docker/compose/v2/pkg/api"
+)
+
+func TestValidateExternalNetworks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	nameFilter := func(name string) moby.NetworkListOptions {
+		return moby.NetworkListOptions{Filters: filters.NewArgs(filters.Arg("name", name))}
+	}
+	api.EXPECT().NetworkList(gomock.Any(), nameFilter("front")).
+		Return([]moby.NetworkResource{{ID: "abc", Name: "front"}}, nil)
+	api.EXPECT().NetworkList(gomock.Any(), nameFilter("back")).
+		Return([]moby.NetworkResource{{ID: "def", Name: "backend"}}, nil)
+	api.EXPECT().NetworkList(gomock.Any(), nameFilter("db")).Return(nil, nil)
+	api.EXPECT().NetworkInspect(gomock.Any(), "db", gomock.Any()).
+		Return(moby.NetworkResource{}, errdefs.NotFound(errors.New("network db not found")))
+	api.EXPECT().Info(gomock.Any()).
+		Return(system.Info{Swarm: swarm.Info{LocalNodeState: swarm.LocalNodeStateInactive}}, nil).AnyTimes()
+
+	project := &types.Project{
+		Name: strings.ToLower(testProject),
+		Networks: types.Networks{
+			"front":   {Name: "front", External: true},
+			"back":    {Name: "back", External: true},
+			"db":      {Name: "db", External: true},
+			"default": {Name: "myProject_default"},
+		},
+	}
+	err := tested.validateExternalNetworks(context.Background(), project)
+	assert.Assert(t, compose.IsNotFoundError(err))
+	assert.DeepEqual(t, err, compose.MissingNetworksError{Networks: []string{"back", "db"}})
+}
+
+func TestNetworks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	api.EXPECT().NetworkList(gomock.Any(), moby.NetworkListOptions{
+		Filters: filters.NewArgs(projectFilter(strings.ToLower(testProject))),
+	}).Return([]moby.NetworkResource{{ID: "abc", Name: "testproject_default"}}, nil)
+	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt(true)).Return([]moby.Container{
+		testContainer("service1", "123", false),
+		testContainer("service1", "456", false),
+		testContainer("service2", "789", false),
+	}, nil)
+	api.EXPECT().NetworkInspect(gomock.Any(), "abc", gomock.Any()).Return(moby.NetworkResource{
+		ID:     "abc",
+		Name:   "testproject_default",
+		Driver: "bridge",
+		Labels: map[string]string{compose.NetworkLabel: "default"},
+		IPAM: network.IPAM{
+			Config: []network.IPAMConfig{{Subnet: "172.18.0.0/16"}},
+		},
+		Containers: map[string]moby.EndpointResource{
+			"123":   {},
+			"456":   {},
+			"789":   {},
+			"other": {},
+		},
+	}, nil)
+
+	networks, err := tested.Networks(context.Background(), testProject)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, networks, []compose.NetworkSummary{{
+		ID:       "abc",
+		Name:     "testproject_default",
+		Network:  "default",
+		Driver:   "bridge",
+		Subnets:  []string{"172.18.0.0/16"},
+		Services: []string{"service1", "service2"},
+	}})
+}
+
+func TestResolveProjectNetworks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	project := &types.Project{
+		Name: testProject,
+		Networks: types.Networks{
+			"default": {Name: "testProject_default"},
+			"backend": {
+				Name:       "testProject_backend",
+				Extensions: types.Extensions{extProjectNetwork: "Payments"},
+			},
+			"events": {
+				Name:       "testProject_events",
+				Extensions: types.Extensions{extProjectNetwork: map[string]any{"name": "broker", "network": "default"}},
+			},
+		},
+	}
+	api.EXPECT().NetworkList(gomock.Any(), moby.NetworkListOptions{
+		Filters: filters.NewArgs(projectFilter("payments"), networkFilter("backend")),
+	}).Return([]moby.NetworkResource{{Name: "payments_backend"}}, nil)
+	api.EXPECT().NetworkList(gomock.Any(), moby.NetworkListOptions{
+		Filters: filters.NewArgs(projectFilter("broker"), networkFilter("default")),
+	}).Return([]moby.NetworkResource{{Name: "broker-net"}}, nil)
+
+	err := tested.resolveProjectNetworks(context.Background(), project)
+	assert.NilError(t, err)
+	assert.Equal(t, project.Networks["backend"].Name, "payments_backend")
+	assert.Check(t, bool(project.Networks["backend"].External))
+	assert.Equal(t, project.Networks["events"].Name, "broker-net")
+	assert.Check(t, bool(project.Networks["events"].External))
+	assert.Equal(t, project.Networks["default"].Name, "testProject_default")
+	assert.Check(t, !bool(project.Networks["default"].External))
+}
+
+func TestResolveProjectNetworksNotFound(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	project := &types.Project{
+		Name: testProject,
+		Networks: types.Networks{
+			"backend": {Extensions: types.Extensions{extProjectNetwork: "payments"}},
+		},
+	}
+	api.EXPECT().NetworkList(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	err := tested.resolveProjectNetworks(context.Background(), project)
+	assert.Check(t, compose.IsNotFoundError(err))
+	assert.ErrorContains(t, err, "network backend of project payments not found, project must be started first")
+}
+
+func TestNetworkOrder(t *testing.T) {
+	project := &types.Project{
+		Networks: types.Networks{
+			"default":  {Name: "app_default"},
+			"backend":  {Name: "app_backend", Internal: true},
+			"frontend": {Name: "app_frontend"},
+			"public":   {Name: "app_public"},
+		},
+		Services: types.Services{
+			"web": {
+				Name: "web",
+				Networks: map[string]*types.ServiceNetworkConfig{
+					"backend":  {Priority: 1000},
+					"public":   {Priority: 100},
+					"frontend": nil,
+				},
+			},
+			"db":   {Name: "db"},
+			"host": {Name: "host", NetworkMode: "host"},
+		},
+	}
+
+	order, route := NetworkOrder(project, project.Services["web"])
+	assert.DeepEqual(t, order, []string{"backend", "public", "frontend"})
+	assert.Equal(t, route, "frontend")
+
+	order, route = NetworkOrder(project, project.Services["db"])
+	assert.DeepEqual(t, order, []string{"default"})
+	assert.Equal(t, route, "default")
+
+	order, route = NetworkOrder(project, project.Services["host"])
+	assert.Equal(t, len(order), 0)
+	assert.Equal(t, route, "")
+
+	collector := &compose.WarningCollector{}
+	warnDefaultRoute(compose.WithWarningCollector(context.Background(), collector), project)
+	assert.DeepEqual(t, collector.Warnings(), []compose.Warning{{
+		Code:      compose.WarningUnsupportedOption,
+		Service:   "web",
+		Attribute: "networks.public.priority",
+		Message:   "doesn't select the default route, the Docker Engine routes through network app_frontend",
+	}})
+
+	public := project.Networks["public"]
+	public.EnableIPv6 = true
+	project.Networks["public"] = public
+	_, route = NetworkOrder(project, project.Services["web"])
+	assert.Equal(t, route, "public")
+}
@@ -0,0 +1,80 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/prompt"
+)
+
+func (s *composeService) Orphans(ctx context.Context, project *types.Project) ([]api.ContainerSummary, error) {
+	orphans, err := s.getOrphans(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	summary := make([]api.ContainerSummary, len(orphans))
+	for i, c := range orphans {
+		summary[i] = api.ContainerSummary{
+			ID:      c.ID,
+			Name:    getCanonicalContainerName(c),
+			Names:   c.Names,
+			Image:   c.Image,
+			Project: c.Labels[api.ProjectLabel],
+			Service: c.Labels[api.ServiceLabel],
+			Command: c.Command,
+			State:   c.State,
+			Status:  c.Status,
+			Created: c.Created,
+			Labels:  c.Labels,
+		}
+	}
+	return summary, nil
+}
+
+func (s *composeService) getOrphans(ctx context.Context, project *types.Project) (Containers, error) {
+	containers, err := s.getContainers(ctx, project.Name, oneOffInclude, true)
+	if err != nil {
+		return nil, err
+	}
+	return containers.filter(isOrphaned(project)).sorted(), nil
+}
+
+// confirmOrphansRemoval lists orphan containers about to be removed, and asks user for confirmation when running
+// interactively. In dry-run mode, orphans are only listed
+func (s *composeService) confirmOrphansRemoval(ctx context.Context, project *types.Project, assumeYes bool) (bool, error) {
+	orphans, err := s.getOrphans(ctx, project)
+	if err != nil {
+		return false, err
+	}
+	if len(orphans) == 0 {
+		return true, nil
+	}
+	names := strings.Join(orphans.names(), ", ")
+	if s.dryRun {
+		_, _ = fmt.Fprintf(s.stdout(), "Orphan containers to be removed: %s\n", names)
+		return true, nil
+	}
+	if assumeYes || !s.stdin().IsTerminal() {
+		return true, nil
+	}
+	return prompt.NewPrompt(s.stdin(), s.stdout()).Confirm(fmt.Sprintf("Going to remove orphan containers %s", names), false)
+}
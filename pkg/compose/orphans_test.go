@@ -0,0 +1,100 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli/streams"
+	moby "github.com/docker/docker/api/types"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/mocks"
+)
+
+func orphansProject() *types.Project {
+	return &types.Project{
+		Name: strings.ToLower(testProject),
+		Services: types.Services{
+			"service1": {Name: "service1"},
+		},
+	}
+}
+
+func TestOrphans(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt(true)).Return(
+		[]moby.Container{
+			testContainer("service1", "123", false),
+			testContainer("service_orphan", "321", false),
+			testContainer("service_orphan", "654", true),
+		}, nil)
+
+	orphans, err := tested.Orphans(context.Background(), orphansProject())
+	assert.NilError(t, err)
+	assert.Equal(t, len(orphans), 2)
+	assert.Equal(t, orphans[0].Name, "321")
+	assert.Equal(t, orphans[0].Service, "service_orphan")
+	assert.Equal(t, orphans[1].Name, "654")
+}
+
+func TestConfirmOrphansRemoval(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api := mocks.NewMockAPIClient(mockCtrl)
+	cli := mocks.NewMockCli(mockCtrl)
+	cli.EXPECT().Client().Return(api).AnyTimes()
+	var out bytes.Buffer
+	cli.EXPECT().Out().Return(streams.NewOut(&out)).AnyTimes()
+	cli.EXPECT().In().Return(streams.NewIn(io.NopCloser(strings.NewReader("")))).AnyTimes()
+
+	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt(true)).Return(
+		[]moby.Container{
+			testContainer("service1", "123", false),
+			testContainer("service_orphan", "321", false),
+		}, nil).Times(2)
+
+	t.Run("non interactive", func(t *testing.T) {
+		tested := composeService{dockerCli: cli}
+		confirm, err := tested.confirmOrphansRemoval(context.Background(), orphansProject(), false)
+		assert.NilError(t, err)
+		assert.Check(t, confirm)
+		assert.Equal(t, out.String(), "")
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		tested := composeService{dockerCli: cli, dryRun: true}
+		confirm, err := tested.confirmOrphansRemoval(context.Background(), orphansProject(), false)
+		assert.NilError(t, err)
+		assert.Check(t, confirm)
+		assert.Equal(t, out.String(), "Orphan containers to be removed: 321\n")
+	})
+}
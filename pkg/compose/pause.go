@@ -18,13 +18,17 @@ package compose
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/compose-spec/compose-go/v2/types"
 	moby "github.com/docker/docker/api/types"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/docker/compose/v2/pkg/api"
 	"github.com/docker/compose/v2/pkg/progress"
+	"github.com/docker/compose/v2/pkg/utils"
 )
 
 func (s *composeService) Pause(ctx context.Context, projectName string, options api.PauseOptions) error {
@@ -41,6 +45,11 @@ func (s *composeService) pause(ctx context.Context, projectName string, options
 
 	if options.Project != nil {
 		containers = containers.filter(isService(options.Project.ServiceNames()...))
+		if !options.Force {
+			if err := checkHealthyDependents(options.Project, options.Services); err != nil {
+				return err
+			}
+		}
 	}
 
 	w := progress.ContextWriter(ctx)
@@ -59,6 +68,31 @@ func (s *composeService) pause(ctx context.Context, projectName string, options
 	return eg.Wait()
 }
 
+// checkHealthyDependents prevents pausing services other services, left running, depend on to be healthy
+func checkHealthyDependents(project *types.Project, services []string) error {
+	if len(services) == 0 {
+		// whole project is paused
+		return nil
+	}
+	var errs []string
+	for _, name := range project.ServiceNames() {
+		if utils.StringContains(services, name) {
+			continue
+		}
+		service := project.Services[name]
+		for dependency, config := range service.DependsOn {
+			if config.Condition == types.ServiceConditionHealthy && utils.StringContains(services, dependency) {
+				errs = append(errs, fmt.Sprintf("service %q depends on %q to be healthy", name, dependency))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("%s, use --force to pause anyway", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
 func (s *composeService) UnPause(ctx context.Context, projectName string, options api.PauseOptions) error {
 	return progress.Run(ctx, func(ctx context.Context) error {
 		return s.unPause(ctx, strings.ToLower(projectName), options)
@@ -0,0 +1,41 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestCheckHealthyDependents(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			"db":     {Name: "db"},
+			"cache":  {Name: "cache"},
+			"web":    {Name: "web", DependsOn: types.DependsOnConfig{"db": {Condition: types.ServiceConditionHealthy}}},
+			"worker": {Name: "worker", DependsOn: types.DependsOnConfig{"cache": {Condition: types.ServiceConditionStarted}}},
+		},
+	}
+
+	assert.NilError(t, checkHealthyDependents(project, nil))
+	assert.NilError(t, checkHealthyDependents(project, []string{"cache"}))
+	assert.NilError(t, checkHealthyDependents(project, []string{"db", "web"}))
+	assert.Error(t, checkHealthyDependents(project, []string{"db"}),
+		`service "web" depends on "db" to be healthy, use --force to pause anyway`)
+}
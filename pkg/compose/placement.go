@@ -0,0 +1,75 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// Warnings reports deploy.placement attributes, which only make sense for a cluster and are ignored by the local
+// Docker Engine as all containers run on the same node
+func (s *composeService) Warnings(_ context.Context, project *types.Project) ([]api.Warning, error) {
+	var warnings []api.Warning
+	for _, name := range project.ServiceNames() {
+		service := project.Services[name]
+		if service.Deploy == nil {
+			continue
+		}
+		placement := service.Deploy.Placement
+		if len(placement.Constraints) > 0 {
+			warnings = append(warnings, api.Warning{
+				Code:      api.WarningIgnoredAttribute,
+				Service:   name,
+				Attribute: "deploy.placement.constraints",
+				Message: fmt.Sprintf("%s is ignored, containers run on the local Docker Engine",
+					strings.Join(placement.Constraints, ", ")),
+			})
+		}
+		if len(placement.Preferences) > 0 {
+			var spreads []string
+			for _, preference := range placement.Preferences {
+				spreads = append(spreads, preference.Spread)
+			}
+			warnings = append(warnings, api.Warning{
+				Code:      api.WarningIgnoredAttribute,
+				Service:   name,
+				Attribute: "deploy.placement.preferences",
+				Message: fmt.Sprintf("spread over %s is ignored, containers run on the local Docker Engine",
+					strings.Join(spreads, ", ")),
+			})
+		}
+		if placement.MaxReplicas > 0 {
+			message := fmt.Sprintf("%d is ignored, containers run on the local Docker Engine", placement.MaxReplicas)
+			if scale := service.GetScale(); uint64(scale) > placement.MaxReplicas {
+				message = fmt.Sprintf("%d is ignored, all %d replicas run on the local Docker Engine", placement.MaxReplicas, scale)
+			}
+			warnings = append(warnings, api.Warning{
+				Code:      api.WarningIgnoredAttribute,
+				Service:   name,
+				Attribute: "deploy.placement.max_replicas_per_node",
+				Message:   message,
+			})
+		}
+	}
+	return warnings, nil
+}
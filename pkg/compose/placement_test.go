@@ -0,0 +1,84 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+func TestPlacementWarnings(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			"web": {
+				Name: "web",
+				Deploy: &types.DeployConfig{
+					Replicas: intPtr(4),
+					Placement: types.Placement{
+						Constraints: []string{"node.role==worker"},
+						Preferences: []types.PlacementPreferences{{Spread: "node.labels.zone"}},
+						MaxReplicas: 2,
+					},
+				},
+			},
+			"db": {
+				Name: "db",
+				Deploy: &types.DeployConfig{
+					Placement: types.Placement{MaxReplicas: 1},
+				},
+			},
+			"cache": {Name: "cache"},
+		},
+	}
+
+	tested := composeService{}
+	warnings, err := tested.Warnings(context.Background(), project)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, warnings, []api.Warning{
+		{
+			Code:      api.WarningIgnoredAttribute,
+			Service:   "db",
+			Attribute: "deploy.placement.max_replicas_per_node",
+			Message:   "1 is ignored, containers run on the local Docker Engine",
+		},
+		{
+			Code:      api.WarningIgnoredAttribute,
+			Service:   "web",
+			Attribute: "deploy.placement.constraints",
+			Message:   "node.role==worker is ignored, containers run on the local Docker Engine",
+		},
+		{
+			Code:      api.WarningIgnoredAttribute,
+			Service:   "web",
+			Attribute: "deploy.placement.preferences",
+			Message:   "spread over node.labels.zone is ignored, containers run on the local Docker Engine",
+		},
+		{
+			Code:      api.WarningIgnoredAttribute,
+			Service:   "web",
+			Attribute: "deploy.placement.max_replicas_per_node",
+			Message:   "2 is ignored, all 4 replicas run on the local Docker Engine",
+		},
+	})
+	assert.Equal(t, warnings[0].String(),
+		`service "db": deploy.placement.max_replicas_per_node 1 is ignored, containers run on the local Docker Engine`)
+}
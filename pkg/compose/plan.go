@@ -0,0 +1,166 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/utils"
+)
+
+// Plan diffs project services against existing containers, the same way `up` does to converge, and reports
+// changes per container. Project is updated with local images digests and dependents lifecycle, as Create would
+func (s *composeService) Plan(ctx context.Context, project *types.Project, options api.CreateOptions) ([]api.PlannedChange, error) {
+	if len(options.Services) == 0 {
+		options.Services = project.ServiceNames()
+	}
+
+	if err := applyHealthcheckOverrides(project, options.HealthCheck); err != nil {
+		return nil, err
+	}
+
+	observedState, err := s.getContainers(ctx, project.Name, oneOffExclude, true)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.getLocalImagesDigests(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu      sync.Mutex
+		changes []api.PlannedChange
+	)
+	err = InDependencyOrder(ctx, project, func(ctx context.Context, name string) error {
+		service, err := project.GetService(name)
+		if err != nil {
+			return err
+		}
+		strategy := options.RecreateDependencies
+		if utils.StringContains(options.Services, name) {
+			strategy = options.Recreate
+		}
+		planned, err := planService(ctx, s.containerNamer(), project, service, observedState.filter(isService(name)), strategy)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, planned...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if options.RemoveOrphans {
+		allServiceNames := append(project.ServiceNames(), project.DisabledServiceNames()...)
+		for _, c := range observedState.filter(isNotService(allServiceNames...)) {
+			changes = append(changes, api.PlannedChange{
+				Service:   c.Labels[api.ServiceLabel],
+				Container: getCanonicalContainerName(c),
+				Action:    api.PlanRemove,
+				Reason:    "orphan container",
+			})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Service < changes[j].Service
+	})
+	return changes, nil
+}
+
+// planService computes changes for a single service, following ensureService logic
+func planService(ctx context.Context, namer api.ContainerNamer, project *types.Project, service types.ServiceConfig, containers Containers, policy string) ([]api.PlannedChange, error) {
+	expected, err := getScale(service)
+	if err != nil {
+		return nil, err
+	}
+
+	reasons := map[string]string{}
+	for _, c := range containers {
+		reason, err := recreateReason(service, c, policy)
+		if err != nil {
+			return nil, err
+		}
+		reasons[c.ID] = reason
+	}
+	sort.SliceStable(containers, func(i, j int) bool {
+		// obsolete containers first, as those get removed when scaling down
+		oi, oj := reasons[containers[i].ID] != "", reasons[containers[j].ID] != ""
+		if oi != oj {
+			return oi
+		}
+		ni, _ := strconv.Atoi(containers[i].Labels[api.ContainerNumberLabel])
+		nj, _ := strconv.Atoi(containers[j].Labels[api.ContainerNumberLabel])
+		return ni < nj
+	})
+
+	var changes []api.PlannedChange
+	recreated := false
+	for i, c := range containers {
+		change := api.PlannedChange{
+			Service:   service.Name,
+			Container: getCanonicalContainerName(c),
+		}
+		reason := reasons[c.ID]
+		switch {
+		case i >= expected:
+			change.Action = api.PlanRemove
+			change.Reason = fmt.Sprintf("scale down to %d", expected)
+		case reason != "":
+			change.Action = api.PlanRecreate
+			change.Reason = reason
+			recreated = true
+		case c.State == ContainerRunning:
+			change.Action = api.PlanNoop
+			change.Reason = "up-to-date"
+		default:
+			change.Action = api.PlanStart
+			change.Reason = fmt.Sprintf("container is %s", c.State)
+		}
+		changes = append(changes, change)
+	}
+
+	next := nextContainerNumber(ctx, containers)
+	for i := 0; i < expected-len(containers); i++ {
+		reason := fmt.Sprintf("scale up to %d", expected)
+		if len(containers) == 0 {
+			reason = "no container"
+		}
+		changes = append(changes, api.PlannedChange{
+			Service:   service.Name,
+			Container: getContainerName(namer, project.Name, service, next+i),
+			Action:    api.PlanCreate,
+			Reason:    reason,
+		})
+	}
+
+	if recreated {
+		setDependentLifecycle(project, service.Name, forceRecreate)
+	}
+	return changes, nil
+}
@@ -0,0 +1,90 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	moby "github.com/docker/docker/api/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+func TestPlanService(t *testing.T) {
+	replicas := 2
+	service := types.ServiceConfig{
+		Name:  "web",
+		Image: "nginx",
+		Scale: &replicas,
+		CustomLabels: types.Labels{
+			api.ImageDigestLabel: "sha256:1234",
+		},
+	}
+	hash, err := ServiceHash(service)
+	assert.NilError(t, err)
+
+	container := func(id string, number string, state string, hash string) moby.Container {
+		c := testContainer("web", id, false)
+		c.State = state
+		c.Labels[api.ContainerNumberLabel] = number
+		c.Labels[api.ConfigHashLabel] = hash
+		c.Labels[api.ImageDigestLabel] = "sha256:1234"
+		return c
+	}
+	project := &types.Project{Name: strings.ToLower(testProject), Services: types.Services{"web": service}}
+
+	t.Run("up-to-date", func(t *testing.T) {
+		changes, err := planService(context.Background(), api.DefaultContainerNamer, project, service, Containers{
+			container("web1", "1", ContainerRunning, hash),
+			container("web2", "2", ContainerExited, hash),
+		}, api.RecreateDiverged)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, changes, []api.PlannedChange{
+			{Service: "web", Container: "web1", Action: api.PlanNoop, Reason: "up-to-date"},
+			{Service: "web", Container: "web2", Action: api.PlanStart, Reason: "container is exited"},
+		})
+	})
+
+	t.Run("diverged and scale down", func(t *testing.T) {
+		changes, err := planService(context.Background(), api.DefaultContainerNamer, project, service, Containers{
+			container("web1", "1", ContainerRunning, hash),
+			container("web2", "2", ContainerRunning, hash),
+			container("web3", "3", ContainerRunning, "outdated"),
+		}, api.RecreateDiverged)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, changes, []api.PlannedChange{
+			{Service: "web", Container: "web3", Action: api.PlanRecreate, Reason: "configuration changed"},
+			{Service: "web", Container: "web1", Action: api.PlanNoop, Reason: "up-to-date"},
+			{Service: "web", Container: "web2", Action: api.PlanRemove, Reason: "scale down to 2"},
+		})
+	})
+
+	t.Run("never recreate and scale up", func(t *testing.T) {
+		changes, err := planService(context.Background(), api.DefaultContainerNamer, project, service, Containers{
+			container("web1", "1", ContainerRunning, "outdated"),
+		}, api.RecreateNever)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, changes, []api.PlannedChange{
+			{Service: "web", Container: "web1", Action: api.PlanNoop, Reason: "up-to-date"},
+			{Service: "web", Container: getContainerName(api.DefaultContainerNamer, project.Name, service, 2), Action: api.PlanCreate, Reason: "scale up to 2"},
+		})
+	})
+}
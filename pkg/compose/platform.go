@@ -0,0 +1,83 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/containerd/containerd/platforms"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// servicePlatform returns the platform service runs for, as set by `platform` or DOCKER_DEFAULT_PLATFORM, nil if none
+// is set and the Docker Engine selects the image variant matching its own platform
+func servicePlatform(project *types.Project, service types.ServiceConfig) (*specs.Platform, error) {
+	platform := service.Platform
+	if platform == "" {
+		platform = project.Environment["DOCKER_DEFAULT_PLATFORM"]
+	}
+	if platform == "" {
+		return nil, nil
+	}
+	p, err := platforms.Parse(platform)
+	if err != nil {
+		return nil, api.InvalidAttributeError{Service: service.Name, Attribute: "platform", Value: platform, Reason: err.Error()}
+	}
+	return &p, nil
+}
+
+// checkPlatforms validates services' platform before any container gets created, and warns about services which
+// platform doesn't match the Docker Engine one, so containers run with emulation, as arm64 images on an amd64 host
+func (s *composeService) checkPlatforms(ctx context.Context, project *types.Project, services []string) error {
+	var host *specs.Platform
+	for _, name := range services {
+		service, err := project.GetService(name)
+		if err != nil {
+			continue
+		}
+		platform, err := servicePlatform(project, service)
+		if err != nil {
+			return err
+		}
+		if platform == nil {
+			continue
+		}
+		if host == nil {
+			info, err := s.apiClient().Info(ctx)
+			if err != nil {
+				return err
+			}
+			host = &specs.Platform{OS: info.OSType, Architecture: normalizeArchitecture(info.Architecture)}
+			normalized := platforms.Normalize(*host)
+			host = &normalized
+		}
+		if !platforms.Only(*host).Match(*platform) {
+			api.Warn(ctx, api.Warning{
+				Code:      api.WarningEmulation,
+				Service:   service.Name,
+				Attribute: "platform",
+				Message: fmt.Sprintf("%s doesn't match the Docker Engine platform %s, containers run with emulation",
+					platforms.Format(*platform), platforms.Format(*host)),
+			})
+		}
+	}
+	return nil
+}
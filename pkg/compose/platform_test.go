@@ -0,0 +1,65 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/system"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+func TestCheckPlatforms(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	apiClient, cli := prepareMocks(mockCtrl)
+	tested := composeService{dockerCli: cli}
+	apiClient.EXPECT().Info(gomock.Any()).Return(system.Info{OSType: "linux", Architecture: "x86_64"}, nil).Times(1)
+
+	project := &types.Project{
+		Services: types.Services{
+			"web":    {Name: "web", Platform: "linux/arm64"},
+			"db":     {Name: "db", Platform: "linux/amd64"},
+			"legacy": {Name: "legacy", Platform: "linux/386"},
+			"cache":  {Name: "cache"},
+		},
+	}
+	collector := &api.WarningCollector{}
+	ctx := api.WithWarningCollector(context.Background(), collector)
+	err := tested.checkPlatforms(ctx, project, project.ServiceNames())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, collector.Warnings(), []api.Warning{{
+		Code:      api.WarningEmulation,
+		Service:   "web",
+		Attribute: "platform",
+		Message:   "linux/arm64 doesn't match the Docker Engine platform linux/amd64, containers run with emulation",
+	}})
+}
+
+func TestCheckPlatformsInvalid(t *testing.T) {
+	tested := composeService{}
+	project := &types.Project{
+		Services: types.Services{"web": {Name: "web", Platform: "linux/"}},
+	}
+	err := tested.checkPlatforms(context.Background(), project, project.ServiceNames())
+	assert.ErrorContains(t, err, `service "web": invalid platform "linux/"`)
+}
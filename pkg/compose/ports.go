@@ -0,0 +1,160 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/utils"
+	containerType "github.com/docker/docker/api/types/container"
+)
+
+// hostPort is a port bound on host by a service or container
+type hostPort struct {
+	ip       string
+	port     uint64
+	protocol string
+	owner    string
+}
+
+func (p hostPort) String() string {
+	return fmt.Sprintf("%d/%s", p.port, p.protocol)
+}
+
+// overlaps tells if both bindings can't be used simultaneously
+func (p hostPort) overlaps(other hostPort) bool {
+	if p.port != other.port || p.protocol != other.protocol {
+		return false
+	}
+	return isAnyAddress(p.ip) || isAnyAddress(other.ip) || p.ip == other.ip
+}
+
+func isAnyAddress(ip string) bool {
+	return ip == "" || ip == "0.0.0.0" || ip == "::"
+}
+
+// servicePublishedPorts lists host ports published by service, with port ranges expanded
+func servicePublishedPorts(service types.ServiceConfig) ([]hostPort, error) {
+	var ports []hostPort
+	for _, port := range service.Ports {
+		if port.Published == "" {
+			continue
+		}
+		start, end, err := parsePortRange(port.Published)
+		if err != nil {
+			return nil, fmt.Errorf("service %q: invalid published port %q: %w", service.Name, port.Published, err)
+		}
+		if start != end {
+			// engine picks one available port in range
+			continue
+		}
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = "tcp"
+		}
+		ports = append(ports, hostPort{
+			ip:       port.HostIP,
+			port:     start,
+			protocol: protocol,
+			owner:    "service " + service.Name,
+		})
+	}
+	return ports, nil
+}
+
+func parsePortRange(published string) (uint64, uint64, error) {
+	first, last, isRange := strings.Cut(published, "-")
+	start, err := strconv.ParseUint(first, 10, 16)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !isRange {
+		return start, start, nil
+	}
+	end, err := strconv.ParseUint(last, 10, 16)
+	return start, end, err
+}
+
+// validatePublishedPorts checks host ports published by services to be created don't conflict with each other
+// nor with running containers, so we don't fail after some containers have been created
+func (s *composeService) validatePublishedPorts(ctx context.Context, project *types.Project, services []string) error {
+	var wanted []hostPort
+	for _, name := range services {
+		service, ok := project.Services[name]
+		if !ok {
+			continue
+		}
+		ports, err := servicePublishedPorts(service)
+		if err != nil {
+			return err
+		}
+		wanted = append(wanted, ports...)
+	}
+	if len(wanted) == 0 {
+		return nil
+	}
+
+	var conflicts []string
+	for i, p := range wanted {
+		for _, other := range wanted[:i] {
+			if p.owner != other.owner && p.overlaps(other) {
+				conflicts = append(conflicts, fmt.Sprintf("host port %s is published by both %s and %s", p, other.owner, p.owner))
+			}
+		}
+	}
+
+	running, err := s.apiClient().ContainerList(ctx, containerType.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, c := range running {
+		if c.Labels[api.ProjectLabel] == project.Name && utils.StringContains(services, c.Labels[api.ServiceLabel]) {
+			// will be recreated or kept as is
+			continue
+		}
+		for _, port := range c.Ports {
+			if port.PublicPort == 0 {
+				continue
+			}
+			used := hostPort{
+				ip:       port.IP,
+				port:     uint64(port.PublicPort),
+				protocol: port.Type,
+				owner:    "container " + getCanonicalContainerName(c),
+			}
+			for _, p := range wanted {
+				if !p.overlaps(used) {
+					continue
+				}
+				// engine reports a binding per IP family
+				conflict := fmt.Sprintf("host port %s published by %s is already allocated by %s", p, p.owner, used.owner)
+				if !utils.StringContains(conflicts, conflict) {
+					conflicts = append(conflicts, conflict)
+				}
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("port conflicts:\n%s", strings.Join(conflicts, "\n"))
+	}
+	return nil
+}
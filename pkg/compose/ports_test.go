@@ -0,0 +1,89 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	moby "github.com/docker/docker/api/types"
+	containerType "github.com/docker/docker/api/types/container"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	compose "github.com/docker/compose/v2/pkg/api"
+)
+
+func TestValidatePublishedPorts(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	project := &types.Project{
+		Name: strings.ToLower(testProject),
+		Services: types.Services{
+			"web":   {Name: "web", Ports: []types.ServicePortConfig{{Target: 80, Published: "8080"}}},
+			"admin": {Name: "admin", Ports: []types.ServicePortConfig{{Target: 80, Published: "8080", HostIP: "127.0.0.1"}}},
+			"db":    {Name: "db", Ports: []types.ServicePortConfig{{Target: 5432, Published: "5432"}}},
+			"dns":   {Name: "dns", Ports: []types.ServicePortConfig{{Target: 53, Published: "5432", Protocol: "udp"}}},
+			"api":   {Name: "api", Ports: []types.ServicePortConfig{{Target: 80, Published: "9000-9010"}}},
+		},
+	}
+
+	db := testContainer("db", "123", false)
+	db.Ports = []moby.Port{{IP: "0.0.0.0", PublicPort: 5432, PrivatePort: 5432, Type: "tcp"}}
+	other := moby.Container{
+		ID:    "456",
+		Names: []string{"/other"},
+		Ports: []moby.Port{
+			{IP: "0.0.0.0", PublicPort: 5432, PrivatePort: 5432, Type: "tcp"},
+			{IP: "::", PublicPort: 5432, PrivatePort: 5432, Type: "tcp"},
+		},
+		Labels: map[string]string{compose.ProjectLabel: "other"},
+	}
+	api.EXPECT().ContainerList(gomock.Any(), containerType.ListOptions{}).Return([]moby.Container{db, other}, nil)
+
+	err := tested.validatePublishedPorts(context.Background(), project, []string{"admin", "api", "db", "dns", "web"})
+	assert.Error(t, err, `port conflicts:
+host port 8080/tcp is published by both service admin and service web
+host port 5432/tcp published by service db is already allocated by container other`)
+}
+
+func TestValidatePublishedPortsNone(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	_, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	project := &types.Project{
+		Name: strings.ToLower(testProject),
+		Services: types.Services{
+			"web": {Name: "web", Ports: []types.ServicePortConfig{{Target: 80}}},
+		},
+	}
+	err := tested.validatePublishedPorts(context.Background(), project, []string{"web"})
+	assert.NilError(t, err)
+}
@@ -0,0 +1,291 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/go-connections/nat"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
+)
+
+const (
+	extProbe = "x-probe"
+
+	defaultProbeInterval = time.Second
+	defaultProbeTimeout  = time.Minute
+)
+
+// dependencyProbe is the `x-probe` extension of a depends_on entry: a readiness check run by compose against
+// the dependency, for images without a HEALTHCHECK. Either a TCP port to be open, or an HTTP path to return 2xx.
+// With DNS set, the dependency aliases are first checked to be registered on the networks shared with the dependant
+type dependencyProbe struct {
+	Port     int
+	Path     string
+	DNS      bool
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+type rawDependencyProbe struct {
+	TCP      int    `mapstructure:"tcp"`
+	HTTP     string `mapstructure:"http"`
+	Port     int    `mapstructure:"port"`
+	DNS      bool   `mapstructure:"dns"`
+	Interval string `mapstructure:"interval"`
+	Timeout  string `mapstructure:"timeout"`
+}
+
+func hasDependencyProbe(config types.ServiceDependency) bool {
+	_, ok := config.Extensions[extProbe]
+	return ok
+}
+
+// getDependencyProbe parses depends_on `x-probe` extension, returns nil if dependency doesn't declare one
+func getDependencyProbe(dep string, config types.ServiceDependency) (*dependencyProbe, error) {
+	y, ok := config.Extensions[extProbe]
+	if !ok {
+		return nil, nil
+	}
+	var raw rawDependencyProbe
+	if err := mapstructure.WeakDecode(y, &raw); err != nil {
+		return nil, fmt.Errorf("dependency %q: invalid %s: %w", dep, extProbe, err)
+	}
+
+	probe := dependencyProbe{
+		Port:     raw.TCP,
+		DNS:      raw.DNS,
+		Interval: defaultProbeInterval,
+		Timeout:  defaultProbeTimeout,
+	}
+	if raw.HTTP != "" {
+		probe.Port = raw.Port
+		probe.Path = raw.HTTP
+	}
+	if probe.Port <= 0 && (!probe.DNS || raw.HTTP != "") {
+		return nil, fmt.Errorf("dependency %q: %s requires a tcp port, or an http path and port", dep, extProbe)
+	}
+
+	var err error
+	if raw.Interval != "" {
+		if probe.Interval, err = time.ParseDuration(raw.Interval); err != nil {
+			return nil, fmt.Errorf("dependency %q: invalid %s interval: %w", dep, extProbe, err)
+		}
+	}
+	if raw.Timeout != "" {
+		if probe.Timeout, err = time.ParseDuration(raw.Timeout); err != nil {
+			return nil, fmt.Errorf("dependency %q: invalid %s timeout: %w", dep, extProbe, err)
+		}
+	}
+	return &probe, nil
+}
+
+// waitDependencyProbe runs probe against all dependency containers until it succeeds or times out. dnsNames are the
+// names the dependency must be registered with, by network, for a DNS probe
+func (s *composeService) waitDependencyProbe(ctx context.Context, dep string, config types.ServiceDependency, probe dependencyProbe, dnsNames map[string][]string, containers Containers) error {
+	w := progress.ContextWriter(ctx)
+	ctx, cancel := context.WithTimeout(ctx, probe.Timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(probe.Interval)
+	defer ticker.Stop()
+	retry := func(check func() error) error {
+		for {
+			err := check()
+			if err == nil {
+				return nil
+			}
+			select {
+			case <-ctx.Done():
+				msg := fmt.Sprintf("dependency %q not ready after %s: %s", dep, probe.Timeout, err)
+				if !config.Required {
+					w.Events(containerReasonEvents(containers, progress.SkippedEvent, msg))
+					api.Warn(ctx, api.Warning{Code: api.WarningDependency, Message: "optional " + msg})
+					return errSkipProbe
+				}
+				w.Events(containerReasonEvents(containers, progress.ErrorMessageEvent, msg))
+				return fmt.Errorf("%s", msg)
+			case <-ticker.C:
+			}
+		}
+	}
+	for _, container := range containers {
+		if probe.DNS {
+			err := retry(func() error {
+				return s.checkDNSRegistration(ctx, container.ID, dnsNames)
+			})
+			if err != nil {
+				return ignoreSkippedProbe(err)
+			}
+		}
+		if probe.Port <= 0 {
+			continue
+		}
+		address, err := s.probeAddress(ctx, container.ID, probe.Port)
+		if err != nil {
+			return err
+		}
+		err = retry(func() error {
+			return runDependencyProbe(ctx, probe, address)
+		})
+		if err != nil {
+			return ignoreSkippedProbe(err)
+		}
+	}
+	w.Events(containerEvents(containers, progress.Healthy))
+	return nil
+}
+
+// errSkipProbe is used to stop probing an optional dependency which didn't get ready in time
+var errSkipProbe = errors.New("skipped")
+
+func ignoreSkippedProbe(err error) error {
+	if errors.Is(err, errSkipProbe) {
+		return nil
+	}
+	return err
+}
+
+// dependencyDNSNames returns the names dependency containers are expected to be resolved by, for each network the
+// dependency shares with the dependant: the service name and the aliases it declares on this network
+func dependencyDNSNames(project *types.Project, dependant string, dep string) map[string][]string {
+	service, err := project.GetService(dep)
+	if err != nil {
+		return nil
+	}
+	dependantService, err := project.GetService(dependant)
+	names := map[string][]string{}
+	for key, config := range service.Networks {
+		if err == nil {
+			if _, ok := dependantService.Networks[key]; !ok {
+				continue
+			}
+		}
+		network := key
+		if n, ok := project.Networks[key]; ok && n.Name != "" {
+			network = n.Name
+		}
+		aliases := []string{dep}
+		if config != nil {
+			aliases = append(aliases, config.Aliases...)
+		}
+		names[network] = aliases
+	}
+	return names
+}
+
+// checkDNSRegistration checks a container got registered by the engine's embedded DNS server with the expected names
+func (s *composeService) checkDNSRegistration(ctx context.Context, containerID string, names map[string][]string) error {
+	inspect, err := s.apiClient().ContainerInspect(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	if inspect.NetworkSettings == nil {
+		return fmt.Errorf("container %s has no network settings", containerID)
+	}
+	for network, expected := range names {
+		endpoint, ok := inspect.NetworkSettings.Networks[network]
+		if !ok || endpoint == nil || (endpoint.IPAddress == "" && endpoint.GlobalIPv6Address == "") {
+			return fmt.Errorf("container %s is not connected to network %s yet", containerID, network)
+		}
+		registered := endpoint.DNSNames
+		if len(registered) == 0 {
+			// engine older than API 1.44 doesn't report DNS names
+			registered = endpoint.Aliases
+		}
+		for _, name := range expected {
+			if !slices.Contains(registered, name) {
+				return fmt.Errorf("%s is not resolvable on network %s yet", name, network)
+			}
+		}
+	}
+	return nil
+}
+
+func runDependencyProbe(ctx context.Context, probe dependencyProbe, address string) error {
+	if probe.Path == "" {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, (&url.URL{Scheme: "http", Host: address, Path: probe.Path}).String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("GET %s returned %s", probe.Path, resp.Status)
+	}
+	return nil
+}
+
+// probeAddress resolves the address compose can reach a container port on: the published host port if any,
+// otherwise the container IP address, which is only reachable when engine runs on the local host
+func (s *composeService) probeAddress(ctx context.Context, containerID string, port int) (string, error) {
+	inspect, err := s.apiClient().ContainerInspect(ctx, containerID)
+	if err != nil {
+		return "", err
+	}
+	if inspect.NetworkSettings == nil {
+		return "", fmt.Errorf("container %s has no network settings", containerID)
+	}
+	for _, binding := range inspect.NetworkSettings.Ports[nat.Port(fmt.Sprintf("%d/tcp", port))] {
+		if binding.HostPort == "" {
+			continue
+		}
+		host := binding.HostIP
+		if host == "" || net.ParseIP(host).IsUnspecified() {
+			host = s.daemonHostname()
+		}
+		return net.JoinHostPort(host, binding.HostPort), nil
+	}
+	for _, network := range inspect.NetworkSettings.Networks {
+		if network.IPAddress != "" {
+			return net.JoinHostPort(network.IPAddress, strconv.Itoa(port)), nil
+		}
+	}
+	return "", fmt.Errorf("container %s port %d is neither published nor reachable", containerID, port)
+}
+
+// daemonHostname returns the host name of a remote docker engine, or loopback for a local socket
+func (s *composeService) daemonHostname() string {
+	u, err := url.Parse(s.apiClient().DaemonHost())
+	if err == nil && (u.Scheme == "tcp" || u.Scheme == "ssh") && u.Hostname() != "" {
+		return u.Hostname()
+	}
+	return "127.0.0.1"
+}
@@ -0,0 +1,150 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestGetDependencyProbe(t *testing.T) {
+	probe, err := getDependencyProbe("db", types.ServiceDependency{})
+	assert.NilError(t, err)
+	assert.Check(t, probe == nil)
+
+	probe, err = getDependencyProbe("db", types.ServiceDependency{
+		Extensions: types.Extensions{extProbe: map[string]any{"tcp": 5432}},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, *probe, dependencyProbe{Port: 5432, Interval: defaultProbeInterval, Timeout: defaultProbeTimeout})
+
+	probe, err = getDependencyProbe("api", types.ServiceDependency{
+		Extensions: types.Extensions{extProbe: map[string]any{"http": "/ready", "port": "8080", "timeout": "10s"}},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, *probe, dependencyProbe{Port: 8080, Path: "/ready", Interval: defaultProbeInterval, Timeout: 10 * time.Second})
+
+	probe, err = getDependencyProbe("api", types.ServiceDependency{
+		Extensions: types.Extensions{extProbe: map[string]any{"dns": true}},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, *probe, dependencyProbe{DNS: true, Interval: defaultProbeInterval, Timeout: defaultProbeTimeout})
+
+	_, err = getDependencyProbe("api", types.ServiceDependency{
+		Extensions: types.Extensions{extProbe: map[string]any{"http": "/ready"}},
+	})
+	assert.Error(t, err, `dependency "api": x-probe requires a tcp port, or an http path and port`)
+}
+
+func TestDependencyDNSNames(t *testing.T) {
+	project := &types.Project{
+		Name: "myproject",
+		Networks: types.Networks{
+			"front": {Name: "myproject_front"},
+			"back":  {Name: "myproject_back"},
+		},
+		Services: types.Services{
+			"web": {Name: "web", Networks: map[string]*types.ServiceNetworkConfig{"front": nil}},
+			"api": {Name: "api", Networks: map[string]*types.ServiceNetworkConfig{
+				"front": {Aliases: []string{"backend"}},
+				"back":  nil,
+			}},
+		},
+	}
+	assert.DeepEqual(t, dependencyDNSNames(project, "web", "api"), map[string][]string{
+		"myproject_front": {"api", "backend"},
+	})
+	// dependant is the project itself
+	assert.DeepEqual(t, dependencyDNSNames(project, "myproject", "api"), map[string][]string{
+		"myproject_front": {"api", "backend"},
+		"myproject_back":  {"api"},
+	})
+}
+
+func TestCheckDNSRegistration(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	apiClient, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+	names := map[string][]string{"myproject_front": {"api", "backend"}}
+	inspect := func(endpoint *network.EndpointSettings) moby.ContainerJSON {
+		return moby.ContainerJSON{NetworkSettings: &moby.NetworkSettings{
+			Networks: map[string]*network.EndpointSettings{"myproject_front": endpoint},
+		}}
+	}
+
+	ctx := context.Background()
+	apiClient.EXPECT().ContainerInspect(ctx, "123").Return(inspect(&network.EndpointSettings{}), nil)
+	err := tested.checkDNSRegistration(ctx, "123", names)
+	assert.Error(t, err, "container 123 is not connected to network myproject_front yet")
+
+	apiClient.EXPECT().ContainerInspect(ctx, "123").Return(inspect(&network.EndpointSettings{
+		IPAddress: "172.18.0.2",
+		DNSNames:  []string{"myproject-api-1", "api"},
+	}), nil)
+	err = tested.checkDNSRegistration(ctx, "123", names)
+	assert.Error(t, err, "backend is not resolvable on network myproject_front yet")
+
+	apiClient.EXPECT().ContainerInspect(ctx, "123").Return(inspect(&network.EndpointSettings{
+		IPAddress: "172.18.0.2",
+		DNSNames:  []string{"myproject-api-1", "api", "backend"},
+	}), nil)
+	assert.NilError(t, tested.checkDNSRegistration(ctx, "123", names))
+
+	// older engines only report aliases
+	apiClient.EXPECT().ContainerInspect(ctx, "123").Return(inspect(&network.EndpointSettings{
+		IPAddress: "172.18.0.2",
+		Aliases:   []string{"myproject-api-1", "api", "backend"},
+	}), nil)
+	assert.NilError(t, tested.checkDNSRegistration(ctx, "123", names))
+}
+
+func TestRunDependencyProbe(t *testing.T) {
+	var ready atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !ready.Load() || r.URL.Path != "/ready" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	address := strings.TrimPrefix(server.URL, "http://")
+
+	ctx := context.Background()
+	assert.NilError(t, runDependencyProbe(ctx, dependencyProbe{}, address))
+
+	err := runDependencyProbe(ctx, dependencyProbe{Path: "/ready"}, address)
+	assert.Error(t, err, "GET /ready returned 503 Service Unavailable")
+
+	ready.Store(true)
+	assert.NilError(t, runDependencyProbe(ctx, dependencyProbe{Path: "/ready"}, address))
+}
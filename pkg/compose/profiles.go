@@ -0,0 +1,51 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"sort"
+
+	"github.com/compose-spec/compose-go/v2/types"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// ActiveProfiles collects profiles of the disabled services which have containers, so a command can
+// extend a project running with other profiles than the ones currently selected
+func (s *composeService) ActiveProfiles(ctx context.Context, project *types.Project) ([]string, error) {
+	containers, err := s.getContainers(ctx, project.Name, oneOffExclude, true)
+	if err != nil {
+		return nil, err
+	}
+	set := map[string]struct{}{}
+	for _, c := range containers {
+		service, ok := project.DisabledServices[c.Labels[api.ServiceLabel]]
+		if !ok {
+			continue
+		}
+		for _, p := range service.Profiles {
+			set[p] = struct{}{}
+		}
+	}
+	profiles := make([]string, 0, len(set))
+	for p := range set {
+		profiles = append(profiles, p)
+	}
+	sort.Strings(profiles)
+	return profiles, nil
+}
@@ -0,0 +1,57 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	moby "github.com/docker/docker/api/types"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestActiveProfiles(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	project := &types.Project{
+		Name: strings.ToLower(testProject),
+		Services: types.Services{
+			"service1": {Name: "service1"},
+		},
+		DisabledServices: types.Services{
+			"debug":   {Name: "debug", Profiles: []string{"debug", "tools"}},
+			"monitor": {Name: "monitor", Profiles: []string{"monitoring"}},
+		},
+	}
+
+	ctx := context.Background()
+	api.EXPECT().ContainerList(ctx, projectFilterListOpt(false)).Return(
+		[]moby.Container{testContainer("service1", "123", false), testContainer("debug", "456", false)}, nil)
+
+	profiles, err := tested.ActiveProfiles(ctx, project)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, profiles, []string{"debug", "tools"})
+}
@@ -0,0 +1,239 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/mitchellh/mapstructure"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
+	"github.com/docker/compose/v2/pkg/utils"
+)
+
+// providerPluginPrefix is the prefix of provider plugin binaries looked up on PATH
+const providerPluginPrefix = "compose-provider-"
+
+// providerConfig is the typed form of the x-provider service extension
+type providerConfig struct {
+	// Type selects the plugin binary, as compose-provider-<type>
+	Type    string         `mapstructure:"type"`
+	Options map[string]any `mapstructure:"options"`
+}
+
+// providerRequest is written as JSON to the plugin standard input
+type providerRequest struct {
+	Command string         `json:"command"`
+	Project string         `json:"project"`
+	Service string         `json:"service"`
+	Options map[string]any `json:"options,omitempty"`
+}
+
+// providerMessage is a JSON line written by the plugin to its standard output
+type providerMessage struct {
+	// Type is one of info, debug, error or setenv
+	Type string `json:"type"`
+	// Message is a KEY=VALUE pair for setenv messages
+	Message string `json:"message"`
+}
+
+func getProviderConfig(service types.ServiceConfig) (*providerConfig, error) {
+	y, ok := service.Extensions[api.ProviderExtension]
+	if !ok {
+		return nil, nil
+	}
+	var config providerConfig
+	if err := mapstructure.Decode(y, &config); err != nil {
+		return nil, fmt.Errorf("service %s: invalid %s: %w", service.Name, api.ProviderExtension, err)
+	}
+	if config.Type == "" {
+		return nil, fmt.Errorf("service %s: %s requires a type", service.Name, api.ProviderExtension)
+	}
+	return &config, nil
+}
+
+// runProviders runs provider plugins for provider services, selected ones only if services is set. On up, outputs
+// are injected as environment variables, prefixed by provider service name, into dependent services. The returned
+// project doesn't declare provider services anymore, as those have no container to manage
+func (s *composeService) runProviders(ctx context.Context, project *types.Project, command string, services []string) (*types.Project, error) {
+	providers := map[string]*providerConfig{}
+	for name, service := range project.Services {
+		config, err := getProviderConfig(service)
+		if err != nil {
+			return nil, err
+		}
+		if config != nil {
+			providers[name] = config
+		}
+	}
+	if len(providers) == 0 {
+		return project, nil
+	}
+
+	// providers which don't depend on each other run concurrently
+	var mu sync.Mutex
+	outputs := map[string]map[string]string{}
+	run := func(ctx context.Context, name string) error {
+		config, ok := providers[name]
+		if !ok || (len(services) > 0 && !utils.StringContains(services, name)) {
+			return nil
+		}
+		env, err := s.runProvider(ctx, project.Name, name, config, command)
+		mu.Lock()
+		outputs[name] = env
+		mu.Unlock()
+		return err
+	}
+	var err error
+	if command == "down" {
+		err = InReverseDependencyOrder(ctx, project, run)
+	} else {
+		err = InDependencyOrder(ctx, project, run)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p := *project
+	p.Services = types.Services{}
+	for name, service := range project.Services {
+		if _, ok := providers[name]; ok {
+			continue
+		}
+		if len(service.DependsOn) > 0 {
+			dependsOn := types.DependsOnConfig{}
+			for dependency, config := range service.DependsOn {
+				if _, ok := providers[dependency]; !ok {
+					dependsOn[dependency] = config
+					continue
+				}
+				if service.Environment == nil {
+					service.Environment = types.MappingWithEquals{}
+				}
+				service.Environment = service.Environment.OverrideBy(providerEnvironment(dependency, outputs[dependency]))
+			}
+			service.DependsOn = dependsOn
+		}
+		p.Services[name] = service
+	}
+	return &p, nil
+}
+
+// providerEnvironment prefixes provider outputs with provider service name, as DATABASE_URL for url set by database
+func providerEnvironment(provider string, outputs map[string]string) types.MappingWithEquals {
+	prefix := strings.ToUpper(strings.ReplaceAll(provider, "-", "_")) + "_"
+	env := types.MappingWithEquals{}
+	for k, v := range outputs {
+		v := v
+		env[prefix+k] = &v
+	}
+	return env
+}
+
+// runProvider runs the plugin binary for a provider service, and returns the variables it set
+func (s *composeService) runProvider(ctx context.Context, projectName string, service string, config *providerConfig, command string) (map[string]string, error) {
+	plugin := providerPluginPrefix + config.Type
+	path, err := exec.LookPath(plugin)
+	if err != nil {
+		return nil, fmt.Errorf("service %s: provider %q not found, %s must be installed on PATH", service, config.Type, plugin)
+	}
+	request, err := json.Marshal(providerRequest{
+		Command: command,
+		Project: projectName,
+		Service: service,
+		Options: config.Options,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	w := progress.ContextWriter(ctx)
+	eventName := "Provider " + service
+	w.Event(progress.NewEvent(eventName, progress.Working, command))
+
+	if s.dryRun {
+		w.Event(progress.NewEvent(eventName, progress.Done, command))
+		return map[string]string{}, nil
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stdout = &stdout
+	cmd.Stderr = s.stderr()
+	err = cmd.Run()
+
+	env := map[string]string{}
+	var errs []string
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		var msg providerMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			logrus.Debugf("provider %s: ignoring invalid message %q", config.Type, scanner.Text())
+			continue
+		}
+		switch msg.Type {
+		case "info":
+			w.Event(progress.NewEvent(eventName, progress.Working, msg.Message))
+		case "debug":
+			logrus.Debugf("provider %s: %s", config.Type, msg.Message)
+		case "error":
+			errs = append(errs, msg.Message)
+		case "setenv":
+			k, v, ok := strings.Cut(msg.Message, "=")
+			if !ok {
+				errs = append(errs, fmt.Sprintf("invalid setenv message %q", msg.Message))
+				continue
+			}
+			env[k] = v
+		}
+	}
+	if len(errs) > 0 {
+		err = errors.New(strings.Join(errs, ", "))
+	}
+	if err != nil {
+		w.Event(progress.ErrorMessageEvent(eventName, err.Error()))
+		return nil, fmt.Errorf("service %s: provider %s failed: %w", service, config.Type, err)
+	}
+	w.Event(progress.NewEvent(eventName, progress.Done, command))
+	return env, nil
+}
+
+// withoutProviderServices filters out services removed from project by runProviders
+func withoutProviderServices(project *types.Project, services []string) []string {
+	if services == nil {
+		return nil
+	}
+	var filtered []string
+	for _, name := range services {
+		if _, ok := project.Services[name]; ok {
+			filtered = append(filtered, name)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,158 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	compose "github.com/docker/compose/v2/pkg/api"
+)
+
+func TestRunProviders(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake provider plugin is a shell script")
+	}
+	dir := t.TempDir()
+	request := filepath.Join(dir, "request.json")
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "compose-provider-fake"), []byte(`#!/bin/sh
+cat > `+request+`
+echo '{"type":"info","message":"creating database"}'
+echo '{"type":"setenv","message":"URL=postgres://db:5432"}'
+`), 0o700))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	project := &types.Project{
+		Name: testProject,
+		Services: types.Services{
+			"database": {
+				Name: "database",
+				Extensions: map[string]any{
+					compose.ProviderExtension: map[string]any{
+						"type":    "fake",
+						"options": map[string]any{"size": "small"},
+					},
+				},
+			},
+			"app": {
+				Name:      "app",
+				Image:     "app",
+				DependsOn: types.DependsOnConfig{"database": {Condition: types.ServiceConditionStarted}},
+			},
+		},
+	}
+	p, err := tested.runProviders(context.Background(), project, "up", nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, p.ServiceNames(), []string{"app"})
+	app := p.Services["app"]
+	assert.Equal(t, len(app.DependsOn), 0)
+	assert.Equal(t, *app.Environment["DATABASE_URL"], "postgres://db:5432")
+
+	b, err := os.ReadFile(request)
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), `{"command":"up","project":"testProject","service":"database","options":{"size":"small"}}`)
+}
+
+func TestRunProvidersConcurrently(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake provider plugin is a shell script")
+	}
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "compose-provider-fake"), []byte(`#!/bin/sh
+echo '{"type":"setenv","message":"URL=fake://"}'
+`), 0o700))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	project := &types.Project{
+		Name:     testProject,
+		Services: types.Services{},
+	}
+	app := types.ServiceConfig{Name: "app", Image: "app", DependsOn: types.DependsOnConfig{}}
+	for _, name := range []string{"database", "cache", "queue", "storage"} {
+		project.Services[name] = types.ServiceConfig{
+			Name:       name,
+			Extensions: map[string]any{compose.ProviderExtension: map[string]any{"type": "fake"}},
+		}
+		app.DependsOn[name] = types.ServiceDependency{Condition: types.ServiceConditionStarted}
+	}
+	project.Services["app"] = app
+
+	p, err := tested.runProviders(context.Background(), project, "up", nil)
+	assert.NilError(t, err)
+	environment := p.Services["app"].Environment
+	for _, name := range []string{"DATABASE_URL", "CACHE_URL", "QUEUE_URL", "STORAGE_URL"} {
+		assert.Equal(t, *environment[name], "fake://")
+	}
+}
+
+func TestRunProvidersError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake provider plugin is a shell script")
+	}
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "compose-provider-fake"), []byte(`#!/bin/sh
+echo '{"type":"error","message":"quota exceeded"}'
+`), 0o700))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	project := &types.Project{
+		Name: testProject,
+		Services: types.Services{
+			"database": {
+				Name:       "database",
+				Extensions: map[string]any{compose.ProviderExtension: map[string]any{"type": "fake"}},
+			},
+			"cache": {
+				Name:       "cache",
+				Extensions: map[string]any{compose.ProviderExtension: map[string]any{"type": "missing"}},
+			},
+		},
+	}
+	_, err := tested.runProviders(context.Background(), project, "up", []string{"database"})
+	assert.ErrorContains(t, err, "service database: provider fake failed: quota exceeded")
+
+	_, err = tested.runProviders(context.Background(), project, "up", []string{"cache"})
+	assert.ErrorContains(t, err, `service cache: provider "missing" not found, compose-provider-missing must be installed on PATH`)
+}
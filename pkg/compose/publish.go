@@ -18,7 +18,10 @@ package compose
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 
 	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/distribution/reference"
@@ -26,6 +29,7 @@ import (
 	"github.com/docker/compose/v2/internal/ocipush"
 	"github.com/docker/compose/v2/pkg/api"
 	"github.com/docker/compose/v2/pkg/progress"
+	"github.com/docker/compose/v2/pkg/utils"
 )
 
 func (s *composeService) Publish(ctx context.Context, project *types.Project, repository string, options api.PublishOptions) error {
@@ -63,6 +67,18 @@ func (s *composeService) publish(ctx context.Context, project *types.Project, re
 		})
 	}
 
+	files := publishedLocalFiles(ctx, project, options.WithEnvironment)
+	for _, file := range files {
+		f, err := os.ReadFile(filepath.Join(project.WorkingDir, file))
+		if err != nil {
+			return err
+		}
+		layers = append(layers, ocipush.Pushable{
+			Descriptor: ocipush.DescriptorForLocalFile(file, f),
+			Data:       f,
+		})
+	}
+
 	if options.ResolveImageDigests {
 		yaml, err := s.generateImageDigestsOverride(ctx, project)
 		if err != nil {
@@ -105,6 +121,60 @@ func (s *composeService) publish(ctx context.Context, project *types.Project, re
 	return nil
 }
 
+// publishedLocalFiles lists local files the project relies on, relative to project directory: configs and, if
+// withEnv is set, the .env file setting variables defaults and env files. Those often hold credentials, so are only
+// published on demand. Secrets are never published, nor files outside of project directory
+func publishedLocalFiles(ctx context.Context, project *types.Project, withEnv bool) []string {
+	var envPaths []string
+	if _, err := os.Stat(filepath.Join(project.WorkingDir, ".env")); err == nil {
+		envPaths = append(envPaths, filepath.Join(project.WorkingDir, ".env"))
+	}
+	for _, service := range project.Services {
+		for _, envFile := range service.EnvFiles {
+			if _, err := os.Stat(envFile.Path); err != nil && !envFile.Required {
+				continue
+			}
+			envPaths = append(envPaths, envFile.Path)
+		}
+	}
+	var paths []string
+	switch {
+	case withEnv && len(envPaths) > 0:
+		api.Warn(ctx, api.Warning{
+			Code:    api.WarningPublishedFile,
+			Message: "environment files are published, make sure they don't hold any credentials",
+		})
+		paths = append(paths, envPaths...)
+	case len(envPaths) > 0:
+		api.Warn(ctx, api.Warning{
+			Code:    api.WarningPublishedFile,
+			Message: "environment files are not published, use --with-env to include them",
+		})
+	}
+	for _, config := range project.Configs {
+		if config.File != "" {
+			paths = append(paths, config.File)
+		}
+	}
+
+	var files []string
+	for _, path := range paths {
+		rel, err := filepath.Rel(project.WorkingDir, path)
+		if err != nil || !filepath.IsLocal(rel) {
+			api.Warn(ctx, api.Warning{
+				Code:    api.WarningPublishedFile,
+				Message: fmt.Sprintf("%s is outside of project directory %s, and is not published", path, project.WorkingDir),
+			})
+			continue
+		}
+		if !utils.StringContains(files, rel) {
+			files = append(files, rel)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
 func (s *composeService) generateImageDigestsOverride(ctx context.Context, project *types.Project) ([]byte, error) {
 	project, err := project.WithProfiles([]string{"*"})
 	if err != nil {
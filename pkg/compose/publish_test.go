@@ -0,0 +1,76 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+func TestPublishedLocalFiles(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, ".env"), []byte("TAG=1.0\n"), 0o600))
+	assert.NilError(t, os.MkdirAll(filepath.Join(dir, "config"), 0o700))
+
+	project := &types.Project{
+		WorkingDir: dir,
+		Services: types.Services{
+			"app": {
+				Name: "app",
+				EnvFiles: []types.EnvFile{
+					{Path: filepath.Join(dir, "config", "app.env"), Required: true},
+					{Path: filepath.Join(dir, "missing.env"), Required: false},
+				},
+			},
+			"worker": {
+				Name:     "worker",
+				EnvFiles: []types.EnvFile{{Path: filepath.Join(dir, "config", "app.env"), Required: true}},
+			},
+		},
+		Configs: types.Configs{
+			"nginx": {File: filepath.Join(dir, "config", "nginx.conf")},
+		},
+		Secrets: types.Secrets{
+			"password": {File: filepath.Join(dir, "password.txt")},
+		},
+	}
+	collector := &api.WarningCollector{}
+	ctx := api.WithWarningCollector(context.Background(), collector)
+	files := publishedLocalFiles(ctx, project, true)
+	assert.DeepEqual(t, files, []string{".env", filepath.Join("config", "app.env"), filepath.Join("config", "nginx.conf")})
+	assert.Check(t, is.Len(collector.Warnings(), 1))
+
+	files = publishedLocalFiles(context.Background(), project, false)
+	assert.DeepEqual(t, files, []string{filepath.Join("config", "nginx.conf")})
+
+	collector = &api.WarningCollector{}
+	ctx = api.WithWarningCollector(context.Background(), collector)
+	project.Configs["outside"] = types.ConfigObjConfig{File: filepath.Join(filepath.Dir(dir), "outside.conf")}
+	files = publishedLocalFiles(ctx, project, false)
+	assert.DeepEqual(t, files, []string{filepath.Join("config", "nginx.conf")})
+	warnings := collector.Warnings()
+	assert.Assert(t, is.Len(warnings, 2))
+	assert.Check(t, is.Contains(warnings[1].Message, "is outside of project directory"))
+}
@@ -18,7 +18,6 @@ package compose
 
 import (
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -32,7 +31,6 @@ import (
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/jsonmessage"
-	"github.com/docker/docker/registry"
 	"github.com/hashicorp/go-multierror"
 	"github.com/opencontainers/go-digest"
 	"golang.org/x/sync/errgroup"
@@ -189,9 +187,9 @@ func (s *composeService) pullServiceImage(ctx context.Context, service types.Ser
 		return "", err
 	}
 
-	encodedAuth, err := encodedAuth(ref, configFile)
+	creds, err := resolveRegistryCredentials(ref, configFile)
 	if err != nil {
-		return "", err
+		return "", WrapCategorisedComposeError(creds.authError(service.Name, configFile, err), PullFailure)
 	}
 
 	platform := service.Platform
@@ -200,9 +198,12 @@ func (s *composeService) pullServiceImage(ctx context.Context, service types.Ser
 	}
 
 	stream, err := s.apiClient().ImagePull(ctx, service.Image, image.PullOptions{
-		RegistryAuth: encodedAuth,
+		RegistryAuth: creds.encoded,
 		Platform:     platform,
 	})
+	if err != nil {
+		err = registryAuthError(service.Name, creds, configFile, err)
+	}
 
 	// check if has error and the service has a build section
 	// then the status should be warning instead of error
@@ -236,7 +237,8 @@ func (s *composeService) pullServiceImage(ctx context.Context, service types.Ser
 			return "", WrapCategorisedComposeError(err, PullFailure)
 		}
 		if jm.Error != nil {
-			return "", WrapCategorisedComposeError(errors.New(jm.Error.Message), PullFailure)
+			err := registryAuthError(service.Name, creds, configFile, errors.New(jm.Error.Message))
+			return "", WrapCategorisedComposeError(err, PullFailure)
 		}
 		if !quietPull {
 			toPullProgressEvent(service.Name, jm, w)
@@ -271,22 +273,8 @@ func ImageDigestResolver(ctx context.Context, file *configfile.ConfigFile, apiCl
 }
 
 func encodedAuth(ref reference.Named, configFile driver.Auth) (string, error) {
-	repoInfo, err := registry.ParseRepositoryInfo(ref)
-	if err != nil {
-		return "", err
-	}
-
-	key := registry.GetAuthConfigKey(repoInfo.Index)
-	authConfig, err := configFile.GetAuthConfig(key)
-	if err != nil {
-		return "", err
-	}
-
-	buf, err := json.Marshal(authConfig)
-	if err != nil {
-		return "", err
-	}
-	return base64.URLEncoding.EncodeToString(buf), nil
+	creds, err := resolveRegistryCredentials(ref, configFile)
+	return creds.encoded, err
 }
 
 func (s *composeService) pullRequiredImages(ctx context.Context, project *types.Project, images map[string]string, quietPull bool) error {
@@ -300,7 +288,13 @@ func (s *composeService) pullRequiredImages(ctx context.Context, project *types.
 			if _, ok := images[service.Image]; ok {
 				continue
 			}
-		case types.PullPolicyNever, types.PullPolicyBuild:
+		case types.PullPolicyNever:
+			if _, ok := images[service.Image]; !ok && !isServiceImageToBuild(service, project.Services) {
+				return fmt.Errorf("service %q: image %s is not available locally and pull_policy is %q: %w",
+					service.Name, service.Image, service.PullPolicy, api.ErrNotFound)
+			}
+			continue
+		case types.PullPolicyBuild:
 			continue
 		case types.PullPolicyAlways:
 			// force pull
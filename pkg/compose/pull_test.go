@@ -0,0 +1,46 @@
+/*
+   Copyright 2023 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+func TestPullRequiredImagesPolicyNever(t *testing.T) {
+	tested := composeService{}
+	project := &types.Project{
+		Services: types.Services{
+			"local": {Name: "local", Image: "local:latest", PullPolicy: types.PullPolicyNever},
+			"built": {Name: "built", Image: "built:latest", PullPolicy: types.PullPolicyNever, Build: &types.BuildConfig{Context: "."}},
+		},
+	}
+	images := map[string]string{"local:latest": "sha256:1234"}
+	err := tested.pullRequiredImages(context.Background(), project, images, true)
+	assert.NilError(t, err)
+
+	delete(images, "local:latest")
+	err = tested.pullRequiredImages(context.Background(), project, images, true)
+	assert.Error(t, err, `service "local": image local:latest is not available locally and pull_policy is "never": not found`)
+	assert.Check(t, errors.Is(err, api.ErrNotFound))
+}
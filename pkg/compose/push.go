@@ -18,7 +18,6 @@ package compose
 
 import (
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -29,9 +28,7 @@ import (
 	"github.com/distribution/reference"
 	"github.com/docker/buildx/driver"
 	"github.com/docker/docker/api/types/image"
-	"github.com/docker/docker/api/types/system"
 	"github.com/docker/docker/pkg/jsonmessage"
-	"github.com/docker/docker/registry"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/docker/compose/v2/pkg/api"
@@ -51,14 +48,6 @@ func (s *composeService) push(ctx context.Context, project *types.Project, optio
 	eg, ctx := errgroup.WithContext(ctx)
 	eg.SetLimit(s.maxConcurrency)
 
-	info, err := s.apiClient().Info(ctx)
-	if err != nil {
-		return err
-	}
-	if info.IndexServerAddress == "" {
-		info.IndexServerAddress = registry.IndexServer
-	}
-
 	w := progress.ContextWriter(ctx)
 	for _, service := range project.Services {
 		if service.Build == nil || service.Image == "" {
@@ -78,7 +67,7 @@ func (s *composeService) push(ctx context.Context, project *types.Project, optio
 		for _, tag := range tags {
 			tag := tag
 			eg.Go(func() error {
-				err := s.pushServiceImage(ctx, tag, info, s.configFile(), w, options.Quiet)
+				err := s.pushServiceImage(ctx, service.Name, tag, s.configFile(), w, options.Quiet)
 				if err != nil {
 					if !options.IgnoreFailures {
 						return err
@@ -92,36 +81,22 @@ func (s *composeService) push(ctx context.Context, project *types.Project, optio
 	return eg.Wait()
 }
 
-func (s *composeService) pushServiceImage(ctx context.Context, tag string, info system.Info, configFile driver.Auth, w progress.Writer, quietPush bool) error {
+func (s *composeService) pushServiceImage(ctx context.Context, service string, tag string, configFile driver.Auth, w progress.Writer, quietPush bool) error {
 	ref, err := reference.ParseNormalizedNamed(tag)
 	if err != nil {
 		return err
 	}
 
-	repoInfo, err := registry.ParseRepositoryInfo(ref)
-	if err != nil {
-		return err
-	}
-
-	key := repoInfo.Index.Name
-	if repoInfo.Index.Official {
-		key = info.IndexServerAddress
-	}
-	authConfig, err := configFile.GetAuthConfig(key)
-	if err != nil {
-		return err
-	}
-
-	buf, err := json.Marshal(authConfig)
+	creds, err := resolveRegistryCredentials(ref, configFile)
 	if err != nil {
-		return err
+		return creds.authError(service, configFile, err)
 	}
 
 	stream, err := s.apiClient().ImagePush(ctx, tag, image.PushOptions{
-		RegistryAuth: base64.URLEncoding.EncodeToString(buf),
+		RegistryAuth: creds.encoded,
 	})
 	if err != nil {
-		return err
+		return registryAuthError(service, creds, configFile, err)
 	}
 	dec := json.NewDecoder(stream)
 	for {
@@ -133,7 +108,7 @@ func (s *composeService) pushServiceImage(ctx context.Context, tag string, info
 			return err
 		}
 		if jm.Error != nil {
-			return errors.New(jm.Error.Message)
+			return registryAuthError(service, creds, configFile, errors.New(jm.Error.Message))
 		}
 
 		if !quietPush {
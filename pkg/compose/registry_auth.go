@@ -0,0 +1,110 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/docker/buildx/driver"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/registry"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// registryCredentials are the credentials to access the registry hosting an image
+type registryCredentials struct {
+	// registry is the registry name, as docker.io
+	registry string
+	// key is the key credentials are stored by in docker config, and credential helpers are set for
+	key string
+	// encoded is the base64 encoded auth config to be passed to the engine
+	encoded string
+}
+
+// resolveRegistryCredentials retrieves credentials for the registry hosting ref, each registry being resolved
+// separately so a project can pull and push images from multiple registries. Credentials are looked up with the
+// credential helper configured for the registry, then the default credentials store, then the docker config file
+func resolveRegistryCredentials(ref reference.Named, configFile driver.Auth) (registryCredentials, error) {
+	repoInfo, err := registry.ParseRepositoryInfo(ref)
+	if err != nil {
+		return registryCredentials{}, err
+	}
+	creds := registryCredentials{
+		registry: repoInfo.Index.Name,
+		key:      registry.GetAuthConfigKey(repoInfo.Index),
+	}
+	authConfig, err := configFile.GetAuthConfig(creds.key)
+	if err != nil {
+		return creds, err
+	}
+	buf, err := json.Marshal(authConfig)
+	if err != nil {
+		return creds, err
+	}
+	creds.encoded = base64.URLEncoding.EncodeToString(buf)
+	return creds, nil
+}
+
+// credentialHelper returns the credential helper configured for a registry, or the default credentials store
+func credentialHelper(configFile driver.Auth, key string) string {
+	file, ok := configFile.(*configfile.ConfigFile)
+	if !ok {
+		return ""
+	}
+	if helper, ok := file.CredentialHelpers[key]; ok {
+		return helper
+	}
+	return file.CredentialsStore
+}
+
+// authError reports a failure to authenticate to the registry for a service
+func (c registryCredentials) authError(service string, configFile driver.Auth, err error) error {
+	return api.RegistryAuthError{
+		Service:  service,
+		Registry: c.registry,
+		Helper:   credentialHelper(configFile, c.key),
+		Err:      err,
+	}
+}
+
+// registryAuthError wraps err as a api.RegistryAuthError if it is caused by registry authentication
+func registryAuthError(service string, creds registryCredentials, configFile driver.Auth, err error) error {
+	if !isRegistryAuthFailure(err) {
+		return err
+	}
+	return creds.authError(service, configFile, err)
+}
+
+// isRegistryAuthFailure tells if err is a registry rejecting credentials. Errors reported by the engine's pull and
+// push progress stream are plain messages, so those are matched by content
+func isRegistryAuthFailure(err error) bool {
+	if errdefs.IsUnauthorized(err) || errdefs.IsForbidden(err) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, s := range []string{"unauthorized", "authentication required", "no basic auth credentials", "denied: requested access"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
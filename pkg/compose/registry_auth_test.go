@@ -0,0 +1,115 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/config/configfile"
+	clitypes "github.com/docker/cli/cli/config/types"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/errdefs"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
+)
+
+func TestResolveRegistryCredentials(t *testing.T) {
+	configFile := &configfile.ConfigFile{
+		AuthConfigs: map[string]clitypes.AuthConfig{
+			"https://index.docker.io/v1/": {Username: "hub-user", Password: "hub-password"},
+			"registry.example.com":        {Username: "example-user", Password: "example-password"},
+		},
+	}
+
+	for image, expected := range map[string]struct {
+		registry string
+		username string
+	}{
+		"alpine":                           {registry: "docker.io", username: "hub-user"},
+		"registry.example.com/team/web:v1": {registry: "registry.example.com", username: "example-user"},
+		"other.example.com/team/api":       {registry: "other.example.com"},
+	} {
+		ref, err := reference.ParseNormalizedNamed(image)
+		assert.NilError(t, err)
+		creds, err := resolveRegistryCredentials(ref, configFile)
+		assert.NilError(t, err)
+		assert.Equal(t, creds.registry, expected.registry)
+
+		buf, err := base64.URLEncoding.DecodeString(creds.encoded)
+		assert.NilError(t, err)
+		var authConfig clitypes.AuthConfig
+		assert.NilError(t, json.Unmarshal(buf, &authConfig))
+		assert.Equal(t, authConfig.Username, expected.username, image)
+	}
+}
+
+func TestRegistryAuthError(t *testing.T) {
+	configFile := &configfile.ConfigFile{
+		CredentialsStore:  "desktop",
+		CredentialHelpers: map[string]string{"registry.example.com": "ecr-login"},
+	}
+	creds := registryCredentials{registry: "registry.example.com", key: "registry.example.com"}
+
+	err := registryAuthError("web", creds, configFile, errors.New("unexpected EOF"))
+	assert.Error(t, err, "unexpected EOF")
+
+	err = registryAuthError("web", creds, configFile, errors.New("unauthorized: authentication required"))
+	var authErr api.RegistryAuthError
+	assert.Assert(t, errors.As(err, &authErr))
+	assert.Equal(t, authErr.Helper, "ecr-login")
+	assert.Assert(t, errors.Is(err, api.ErrLoginRequired))
+	assert.Error(t, err, `service "web": authentication to registry registry.example.com failed using credential `+
+		`helper docker-credential-ecr-login: unauthorized: authentication required`)
+
+	creds = registryCredentials{registry: "docker.io", key: "https://index.docker.io/v1/"}
+	err = registryAuthError("db", creds, &configfile.ConfigFile{}, errdefs.Unauthorized(errors.New("denied")))
+	assert.Error(t, err, "service \"db\": authentication to registry docker.io failed, run `docker login docker.io`: denied")
+}
+
+func TestPullRegistryAuthFailure(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	apiClient, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	apiClient.EXPECT().ImagePull(gomock.Any(), "registry.example.com/team/web", image.PullOptions{
+		RegistryAuth: "e30=",
+	}).Return(nil, errdefs.Unauthorized(errors.New("unauthorized")))
+
+	service := types.ServiceConfig{Name: "web", Image: "registry.example.com/team/web"}
+	w := progress.ContextWriter(context.Background())
+	_, err := tested.pullServiceImage(context.Background(), service, &configfile.ConfigFile{}, w, true, "")
+	var authErr api.RegistryAuthError
+	assert.Assert(t, errors.As(err, &authErr))
+	assert.Equal(t, authErr.Service, "web")
+	assert.Equal(t, authErr.Registry, "registry.example.com")
+	var composeErr Error
+	assert.Assert(t, errors.As(err, &composeErr))
+	assert.Equal(t, composeErr.GetMetricsFailureCategory(), PullFailure)
+}
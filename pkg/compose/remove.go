@@ -32,7 +32,12 @@ import (
 
 func (s *composeService) Remove(ctx context.Context, projectName string, options api.RemoveOptions) error {
 	projectName = strings.ToLower(projectName)
+	return s.withProjectLock(ctx, projectName, func() error {
+		return s.removeProject(ctx, projectName, options)
+	})
+}
 
+func (s *composeService) removeProject(ctx context.Context, projectName string, options api.RemoveOptions) error {
 	if options.Stop {
 		err := s.Stop(ctx, projectName, api.StopOptions{
 			Services: options.Services,
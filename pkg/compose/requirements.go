@@ -0,0 +1,256 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/mitchellh/mapstructure"
+	"github.com/sirupsen/logrus"
+)
+
+const extRequirements = "x-requirements"
+
+// serviceRequirements is the `x-requirements` service extension: host requirements checked before any container
+// gets created
+type serviceRequirements struct {
+	APIVersion    string   `mapstructure:"api_version"`
+	Architectures []string `mapstructure:"architectures"`
+	KernelModules []string `mapstructure:"kernel_modules"`
+	Ports         []string `mapstructure:"ports"`
+}
+
+// getServiceRequirements parses service `x-requirements` extension, returns nil if service doesn't declare one
+func getServiceRequirements(service types.ServiceConfig) (*serviceRequirements, error) {
+	y, ok := service.Extensions[extRequirements]
+	if !ok {
+		return nil, nil
+	}
+	var requirements serviceRequirements
+	if err := mapstructure.WeakDecode(y, &requirements); err != nil {
+		return nil, fmt.Errorf("service %q: invalid %s: %w", service.Name, extRequirements, err)
+	}
+	return &requirements, nil
+}
+
+// hostInfo describes the host running containers, as required by services
+type hostInfo struct {
+	apiVersion   string
+	architecture string
+	// modules are the loaded kernel modules, nil when those can't be inspected
+	modules map[string]bool
+	// portAvailable tells if a host port can be bound, nil when this can't be checked
+	portAvailable func(port uint64, protocol string) bool
+}
+
+// checkRequirements checks host requirements declared by services, and reports all the unmet ones at once
+func (s *composeService) checkRequirements(ctx context.Context, project *types.Project, services []string) error {
+	requirements := map[string]*serviceRequirements{}
+	for _, name := range services {
+		service, ok := project.Services[name]
+		if !ok {
+			continue
+		}
+		r, err := getServiceRequirements(service)
+		if err != nil {
+			return err
+		}
+		if r != nil {
+			requirements[name] = r
+		}
+	}
+	if len(requirements) == 0 {
+		return nil
+	}
+
+	host, err := s.hostInfo(ctx, project.Name)
+	if err != nil {
+		return err
+	}
+	var unmet []string
+	for _, name := range services {
+		if r, ok := requirements[name]; ok {
+			for _, problem := range r.check(host) {
+				unmet = append(unmet, fmt.Sprintf("service %q: %s", name, problem))
+			}
+		}
+	}
+	if len(unmet) == 0 {
+		return nil
+	}
+	return errors.New("host requirements are not met:\n" + strings.Join(unmet, "\n"))
+}
+
+func (s *composeService) hostInfo(ctx context.Context, projectName string) (hostInfo, error) {
+	info, err := s.apiClient().Info(ctx)
+	if err != nil {
+		return hostInfo{}, err
+	}
+	apiVersion, err := s.RuntimeVersion(ctx)
+	if err != nil {
+		return hostInfo{}, err
+	}
+	host := hostInfo{
+		apiVersion:   apiVersion,
+		architecture: normalizeArchitecture(info.Architecture),
+	}
+	if !s.isLocalEngine() {
+		logrus.Debugf("Docker Engine is not running on local host, kernel modules and ports %s are not checked", extRequirements)
+		return host, nil
+	}
+	host.modules, err = loadedKernelModules("/proc/modules", "/sys/module")
+	if err != nil {
+		logrus.Debugf("unable to list kernel modules: %v", err)
+	}
+	// ports published by the project containers are in use, but will be released when those get recreated
+	containers, err := s.getContainers(ctx, projectName, oneOffInclude, true)
+	if err != nil {
+		return hostInfo{}, err
+	}
+	owned := map[string]bool{}
+	for _, c := range containers {
+		for _, p := range c.Ports {
+			if p.PublicPort != 0 {
+				owned[fmt.Sprintf("%d/%s", p.PublicPort, p.Type)] = true
+			}
+		}
+	}
+	host.portAvailable = func(port uint64, protocol string) bool {
+		return owned[fmt.Sprintf("%d/%s", port, protocol)] || isHostPortAvailable(port, protocol)
+	}
+	return host, nil
+}
+
+// check returns the requirements host doesn't meet
+func (r serviceRequirements) check(host hostInfo) []string {
+	var unmet []string
+	if r.APIVersion != "" && versions.LessThan(host.apiVersion, r.APIVersion) {
+		unmet = append(unmet, fmt.Sprintf("requires Docker Engine API %s, but engine supports %s", r.APIVersion, host.apiVersion))
+	}
+	if len(r.Architectures) > 0 {
+		supported := false
+		for _, arch := range r.Architectures {
+			if normalizeArchitecture(arch) == host.architecture {
+				supported = true
+			}
+		}
+		if !supported {
+			unmet = append(unmet, fmt.Sprintf("requires architecture %s, but host is %s", strings.Join(r.Architectures, " or "), host.architecture))
+		}
+	}
+	if host.modules != nil {
+		for _, module := range r.KernelModules {
+			if !host.modules[strings.ReplaceAll(module, "-", "_")] {
+				unmet = append(unmet, fmt.Sprintf("requires kernel module %s, which is not loaded", module))
+			}
+		}
+	}
+	if host.portAvailable != nil {
+		for _, p := range r.Ports {
+			port, protocol, err := parseRequiredPort(p)
+			if err != nil {
+				unmet = append(unmet, err.Error())
+				continue
+			}
+			if !host.portAvailable(port, protocol) {
+				unmet = append(unmet, fmt.Sprintf("requires port %d/%s, which is already in use on host", port, protocol))
+			}
+		}
+	}
+	return unmet
+}
+
+// normalizeArchitecture converts architectures as reported by `uname -m` to the ones used by image platforms
+func normalizeArchitecture(arch string) string {
+	switch strings.ToLower(arch) {
+	case "x86_64", "x86-64":
+		return "amd64"
+	case "aarch64":
+		return "arm64"
+	case "armv7l", "armhf":
+		return "arm"
+	case "i386", "i686":
+		return "386"
+	default:
+		return strings.ToLower(arch)
+	}
+}
+
+func parseRequiredPort(p string) (uint64, string, error) {
+	port, protocol, _ := strings.Cut(p, "/")
+	if protocol == "" {
+		protocol = "tcp"
+	}
+	n, err := strconv.ParseUint(port, 10, 16)
+	if err != nil || (protocol != "tcp" && protocol != "udp") {
+		return 0, "", fmt.Errorf("invalid required port %q", p)
+	}
+	return n, protocol, nil
+}
+
+// loadedKernelModules lists kernel modules loaded, and those built into the kernel which are exposed in sysfs
+func loadedKernelModules(procModules string, sysModule string) (map[string]bool, error) {
+	f, err := os.Open(procModules)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() //nolint:errcheck
+
+	modules := map[string]bool{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) > 0 {
+			modules[fields[0]] = true
+		}
+	}
+	if entries, err := os.ReadDir(sysModule); err == nil {
+		for _, e := range entries {
+			modules[filepath.Base(e.Name())] = true
+		}
+	}
+	return modules, scanner.Err()
+}
+
+// isHostPortAvailable tells if port can be bound on host. A port the current user isn't allowed to bind, as privileged
+// ports for non-root users, can't be checked and is considered available, as the engine binds it
+func isHostPortAvailable(port uint64, protocol string) bool {
+	address := net.JoinHostPort("", strconv.FormatUint(port, 10))
+	if protocol == "udp" {
+		conn, err := net.ListenPacket("udp", address)
+		if err != nil {
+			return errors.Is(err, os.ErrPermission)
+		}
+		_ = conn.Close()
+		return true
+	}
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return errors.Is(err, os.ErrPermission)
+	}
+	_ = l.Close()
+	return true
+}
@@ -0,0 +1,101 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestGetServiceRequirements(t *testing.T) {
+	r, err := getServiceRequirements(types.ServiceConfig{Name: "web"})
+	assert.NilError(t, err)
+	assert.Check(t, r == nil)
+
+	r, err = getServiceRequirements(types.ServiceConfig{
+		Name: "web",
+		Extensions: types.Extensions{extRequirements: map[string]any{
+			"api_version":    "1.44",
+			"architectures":  []any{"amd64", "arm64"},
+			"kernel_modules": []any{"br_netfilter"},
+			"ports":          []any{"80", 53},
+		}},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, *r, serviceRequirements{
+		APIVersion:    "1.44",
+		Architectures: []string{"amd64", "arm64"},
+		KernelModules: []string{"br_netfilter"},
+		Ports:         []string{"80", "53"},
+	})
+}
+
+func TestServiceRequirementsCheck(t *testing.T) {
+	requirements := serviceRequirements{
+		APIVersion:    "1.45",
+		Architectures: []string{"arm64", "armv7l"},
+		KernelModules: []string{"br_netfilter", "ip-vs"},
+		Ports:         []string{"80", "53/udp", "http"},
+	}
+	host := hostInfo{
+		apiVersion:   "1.44",
+		architecture: normalizeArchitecture("x86_64"),
+		modules:      map[string]bool{"ip_vs": true},
+		portAvailable: func(port uint64, protocol string) bool {
+			return port != 80
+		},
+	}
+	assert.DeepEqual(t, requirements.check(host), []string{
+		"requires Docker Engine API 1.45, but engine supports 1.44",
+		"requires architecture arm64 or armv7l, but host is amd64",
+		"requires kernel module br_netfilter, which is not loaded",
+		"requires port 80/tcp, which is already in use on host",
+		`invalid required port "http"`,
+	})
+
+	// kernel modules and ports can't be checked on a remote engine
+	host = hostInfo{apiVersion: "1.46", architecture: normalizeArchitecture("aarch64")}
+	assert.Check(t, len(requirements.check(host)) == 0)
+}
+
+func TestLoadedKernelModules(t *testing.T) {
+	dir := t.TempDir()
+	procModules := filepath.Join(dir, "modules")
+	assert.NilError(t, os.WriteFile(procModules, []byte(
+		"br_netfilter 32768 0 - Live 0x0000000000000000\n"+
+			"overlay 151552 2 - Live 0x0000000000000000\n"), 0o644))
+	sysModule := filepath.Join(dir, "module")
+	assert.NilError(t, os.MkdirAll(filepath.Join(sysModule, "nf_conntrack"), 0o755))
+
+	modules, err := loadedKernelModules(procModules, sysModule)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, modules, map[string]bool{"br_netfilter": true, "overlay": true, "nf_conntrack": true})
+}
+
+func TestIsHostPortAvailable(t *testing.T) {
+	l, err := net.Listen("tcp", ":0")
+	assert.NilError(t, err)
+	port := uint64(l.Addr().(*net.TCPAddr).Port)
+	assert.Check(t, !isHostPortAvailable(port, "tcp"))
+	assert.NilError(t, l.Close())
+	assert.Check(t, isHostPortAvailable(port, "tcp"))
+}
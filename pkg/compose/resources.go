@@ -0,0 +1,104 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/go-units"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// checkResources compares memory and cpus required by services, times their scale, with the resources reported by the
+// Docker Engine, so a project which can't fit fails before some of its containers get OOM-killed. A service requires
+// the resources it reserves, or the ones it is limited to when it doesn't set a reservation
+func (s *composeService) checkResources(ctx context.Context, project *types.Project, services []string, ignore bool) error {
+	var memory, nanoCPUs int64
+	for _, name := range services {
+		service, ok := project.Services[name]
+		if !ok {
+			continue
+		}
+		m, c := serviceResourceNeeds(service)
+		scale := int64(service.GetScale())
+		memory += m * scale
+		nanoCPUs += c * scale
+	}
+	if memory == 0 && nanoCPUs == 0 {
+		return nil
+	}
+
+	info, err := s.apiClient().Info(ctx)
+	if err != nil {
+		return err
+	}
+	var exceeded []string
+	if info.MemTotal > 0 && memory > info.MemTotal {
+		exceeded = append(exceeded, fmt.Sprintf("services require %s of memory, but Docker Engine only has %s",
+			units.BytesSize(float64(memory)), units.BytesSize(float64(info.MemTotal))))
+	}
+	if info.NCPU > 0 && nanoCPUs > int64(info.NCPU)*1e9 {
+		exceeded = append(exceeded, fmt.Sprintf("services require %g cpus, but Docker Engine only has %d",
+			float64(nanoCPUs)/1e9, info.NCPU))
+	}
+	if len(exceeded) == 0 {
+		return nil
+	}
+	if ignore {
+		for _, e := range exceeded {
+			api.Warn(ctx, api.Warning{Code: api.WarningResources, Message: e})
+		}
+		return nil
+	}
+	return errors.New(strings.Join(exceeded, "\n") + "\nuse --ignore-resource-check to create containers anyway")
+}
+
+// serviceResourceNeeds returns the memory and nano cpus required by a service container, being its reservations or,
+// when not set, its limits
+func serviceResourceNeeds(service types.ServiceConfig) (memory int64, nanoCPUs int64) {
+	memory = int64(service.MemReservation)
+	if service.Deploy != nil && service.Deploy.Resources.Reservations != nil {
+		reservations := service.Deploy.Resources.Reservations
+		if reservations.MemoryBytes != 0 {
+			memory = int64(reservations.MemoryBytes)
+		}
+		nanoCPUs = int64(reservations.NanoCPUs * 1e9)
+	}
+
+	limitMemory, limitCPUs := int64(service.MemLimit), int64(service.CPUS*1e9)
+	if service.Deploy != nil && service.Deploy.Resources.Limits != nil {
+		limits := service.Deploy.Resources.Limits
+		if limits.MemoryBytes != 0 {
+			limitMemory = int64(limits.MemoryBytes)
+		}
+		if limits.NanoCPUs != 0 {
+			limitCPUs = int64(limits.NanoCPUs * 1e9)
+		}
+	}
+	if memory == 0 {
+		memory = limitMemory
+	}
+	if nanoCPUs == 0 {
+		nanoCPUs = limitCPUs
+	}
+	return memory, nanoCPUs
+}
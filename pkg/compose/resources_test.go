@@ -0,0 +1,84 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/system"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestCheckResources(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+	project := &types.Project{
+		Name: testProject,
+		Services: types.Services{
+			"web": {
+				Name:           "web",
+				MemReservation: 1 << 30,
+				Deploy: &types.DeployConfig{
+					Resources: types.Resources{Reservations: &types.Resource{NanoCPUs: 1}},
+				},
+				Scale: intPtr(3),
+			},
+			"db": {
+				Name: "db",
+				Deploy: &types.DeployConfig{
+					Resources: types.Resources{Reservations: &types.Resource{MemoryBytes: 2 << 30, NanoCPUs: 2}},
+				},
+			},
+			// reservation is used when set, limit otherwise
+			"cache": {Name: "cache", MemReservation: 512 << 20, MemLimit: 8 << 30, CPUS: 0.5},
+			"worker": {
+				Name: "worker",
+				Deploy: &types.DeployConfig{
+					Resources: types.Resources{Limits: &types.Resource{MemoryBytes: 8 << 30}},
+				},
+			},
+			"proxy": {Name: "proxy"},
+		},
+	}
+	api.EXPECT().Info(gomock.Any()).Return(system.Info{MemTotal: 4 << 30, NCPU: 4}, nil).Times(4)
+
+	err := tested.checkResources(context.Background(), project, []string{"web", "db", "proxy"}, false)
+	assert.Error(t, err, "services require 5GiB of memory, but Docker Engine only has 4GiB\n"+
+		"services require 5 cpus, but Docker Engine only has 4\n"+
+		"use --ignore-resource-check to create containers anyway")
+
+	err = tested.checkResources(context.Background(), project, []string{"web", "db", "proxy"}, true)
+	assert.NilError(t, err)
+
+	err = tested.checkResources(context.Background(), project, []string{"web", "cache"}, false)
+	assert.NilError(t, err)
+
+	err = tested.checkResources(context.Background(), project, []string{"worker"}, false)
+	assert.ErrorContains(t, err, "services require 8GiB of memory, but Docker Engine only has 4GiB")
+
+	// no requirement set, engine isn't queried
+	err = tested.checkResources(context.Background(), project, []string{"proxy"}, false)
+	assert.NilError(t, err)
+}
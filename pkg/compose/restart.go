@@ -29,8 +29,11 @@ import (
 )
 
 func (s *composeService) Restart(ctx context.Context, projectName string, options api.RestartOptions) error {
+	projectName = strings.ToLower(projectName)
 	return progress.RunWithTitle(ctx, func(ctx context.Context) error {
-		return s.restart(ctx, strings.ToLower(projectName), options)
+		return s.withProjectLock(ctx, projectName, func() error {
+			return s.restart(ctx, projectName, options)
+		})
 	}, s.stdinfo(), "Restarting")
 }
 
@@ -56,13 +59,15 @@ func (s *composeService) restart(ctx context.Context, projectName string, option
 	}
 
 	// ignore depends_on relations which are not impacted by restarting service or not required
-	for i, service := range project.Services {
-		for name, r := range service.DependsOn {
-			if !r.Restart {
-				delete(service.DependsOn, name)
+	if !options.WithDependents {
+		for i, service := range project.Services {
+			for name, r := range service.DependsOn {
+				if !r.Restart {
+					delete(service.DependsOn, name)
+				}
 			}
+			project.Services[i] = service
 		}
-		project.Services[i] = service
 	}
 
 	if len(options.Services) != 0 {
@@ -0,0 +1,118 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	containerType "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
+)
+
+type resourceKind string
+
+const (
+	containerResource resourceKind = "Container"
+	volumeResource    resourceKind = "Volume"
+	networkResource   resourceKind = "Network"
+)
+
+type createdResource struct {
+	kind resourceKind
+	id   string
+	name string
+}
+
+// rollbackRecorder collects resources created while converging a project, so they can be removed if user cancels
+type rollbackRecorder struct {
+	mu        sync.Mutex
+	resources []createdResource
+}
+
+type rollbackRecorderKey struct{}
+
+func withRollbackRecorder(ctx context.Context) (context.Context, *rollbackRecorder) {
+	recorder := &rollbackRecorder{}
+	return context.WithValue(ctx, rollbackRecorderKey{}, recorder), recorder
+}
+
+// recordCreated registers a resource created by compose to the rollback recorder, if one is set by ctx
+func recordCreated(ctx context.Context, kind resourceKind, id string, name string) {
+	recorder, ok := ctx.Value(rollbackRecorderKey{}).(*rollbackRecorder)
+	if !ok {
+		return
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.resources = append(recorder.resources, createdResource{kind: kind, id: id, name: name})
+}
+
+// withRollbackOnCancel runs fn and, if enabled and user cancels the command, removes the resources fn created in
+// reverse creation order. A recorder already set by ctx is reused, so the caller can roll back later steps as well
+func (s *composeService) withRollbackOnCancel(ctx context.Context, enabled bool, fn func(ctx context.Context) error) error {
+	if !enabled {
+		return fn(ctx)
+	}
+	recorder, ok := ctx.Value(rollbackRecorderKey{}).(*rollbackRecorder)
+	if !ok {
+		ctx, recorder = withRollbackRecorder(ctx)
+	}
+	err := fn(ctx)
+	if err == nil || !(errors.Is(ctx.Err(), context.Canceled) || api.IsErrCanceled(err)) {
+		return err
+	}
+	if rollbackErr := s.rollback(context.WithoutCancel(ctx), recorder); rollbackErr != nil {
+		return fmt.Errorf("%w, rollback failed: %w", api.ErrCanceled, rollbackErr)
+	}
+	return api.ErrRolledBack
+}
+
+func (s *composeService) rollback(ctx context.Context, recorder *rollbackRecorder) error {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	w := progress.ContextWriter(ctx)
+	var errs []error
+	for i := len(recorder.resources) - 1; i >= 0; i-- {
+		resource := recorder.resources[i]
+		eventName := fmt.Sprintf("%s %s", resource.kind, resource.name)
+		w.Event(progress.RemovingEvent(eventName))
+		var err error
+		switch resource.kind {
+		case containerResource:
+			// recorded containers never inherit mounts from a previous container, so their anonymous volumes can go
+			err = s.apiClient().ContainerRemove(ctx, resource.id, containerType.RemoveOptions{Force: true, RemoveVolumes: true})
+		case volumeResource:
+			err = s.apiClient().VolumeRemove(ctx, resource.id, false)
+		case networkResource:
+			err = s.apiClient().NetworkRemove(ctx, resource.id)
+		}
+		if err != nil && !errdefs.IsNotFound(err) {
+			w.Event(progress.ErrorEvent(eventName))
+			errs = append(errs, err)
+			continue
+		}
+		w.Event(progress.RemovedEvent(eventName))
+	}
+	recorder.resources = nil
+	return errors.Join(errs...)
+}
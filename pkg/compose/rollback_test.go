@@ -0,0 +1,95 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	containerType "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	compose "github.com/docker/compose/v2/pkg/api"
+)
+
+func TestRollbackOnCancel(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	gomock.InOrder(
+		api.EXPECT().ContainerRemove(gomock.Any(), "456", containerType.RemoveOptions{Force: true, RemoveVolumes: true}).Return(nil),
+		api.EXPECT().ContainerRemove(gomock.Any(), "123", containerType.RemoveOptions{Force: true, RemoveVolumes: true}).
+			Return(errdefs.NotFound(errors.New("gone"))),
+		api.EXPECT().VolumeRemove(gomock.Any(), "myProject_data", false).Return(nil),
+		api.EXPECT().NetworkRemove(gomock.Any(), "abc123").Return(nil),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := tested.withRollbackOnCancel(ctx, true, func(ctx context.Context) error {
+		recordCreated(ctx, networkResource, "abc123", "myProject_default")
+		recordCreated(ctx, volumeResource, "myProject_data", "myProject_data")
+		recordCreated(ctx, containerResource, "123", "myProject-service1-1")
+		recordCreated(ctx, containerResource, "456", "myProject-service2-1")
+		cancel()
+		return ctx.Err()
+	})
+	assert.Assert(t, errors.Is(err, compose.ErrRolledBack))
+	assert.Assert(t, compose.IsErrCanceled(err))
+}
+
+func TestNoRollbackOnFailure(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	_, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	failure := errors.New("failure")
+	err := tested.withRollbackOnCancel(context.Background(), true, func(ctx context.Context) error {
+		recordCreated(ctx, containerResource, "123", "myProject-service1-1")
+		return failure
+	})
+	assert.Equal(t, err, failure)
+}
+
+func TestRollbackRecorderReused(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	_, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	ctx, recorder := withRollbackRecorder(context.Background())
+	err := tested.withRollbackOnCancel(ctx, true, func(ctx context.Context) error {
+		recordCreated(ctx, containerResource, "123", "myProject-service1-1")
+		return nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(recorder.resources), 1)
+}
@@ -0,0 +1,254 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	moby "github.com/docker/docker/api/types"
+	containerType "github.com/docker/docker/api/types/container"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
+	"github.com/docker/compose/v2/pkg/utils"
+)
+
+const (
+	updateOrderStartFirst = "start-first"
+	updateOrderStopFirst  = "stop-first"
+)
+
+// rollingUpdate is the strategy used to recreate running replicas of a service declaring deploy.update_config
+type rollingUpdate struct {
+	parallelism int
+	delay       time.Duration
+	monitor     time.Duration
+	startFirst  bool
+}
+
+// getRollingUpdate returns the rolling update strategy for service, or nil if service doesn't declare
+// deploy.update_config, in which case all obsolete replicas are recreated at once
+func getRollingUpdate(service types.ServiceConfig) *rollingUpdate {
+	if service.Deploy == nil || service.Deploy.UpdateConfig == nil {
+		return nil
+	}
+	config := service.Deploy.UpdateConfig
+	update := rollingUpdate{
+		parallelism: 1,
+		delay:       time.Duration(config.Delay),
+		monitor:     time.Duration(config.Monitor),
+	}
+	if config.Parallelism != nil {
+		// parallelism 0 means all replicas are updated simultaneously
+		update.parallelism = int(*config.Parallelism)
+	}
+	switch config.Order {
+	case updateOrderStartFirst:
+		update.startFirst = true
+	case updateOrderStopFirst:
+	default:
+		// start-first keeps the service available during update, but can't be used when replacement would need to
+		// bind the same host port as the replaced container
+		update.startFirst = !publishesHostPorts(service)
+	}
+	return &update
+}
+
+// publishesHostPorts tells if service binds ports set in its definition on the host
+func publishesHostPorts(service types.ServiceConfig) bool {
+	for _, port := range service.Ports {
+		if port.Published != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// rollingUpdate recreates containers selected by indexes, by batches of `parallelism` replicas.
+// A batch only completes once new replicas are healthy, or running if service has no healthcheck, and didn't fail
+// during the monitor period
+func (c *convergence) rollingUpdate(ctx context.Context, project *types.Project, service types.ServiceConfig, update rollingUpdate,
+	containers Containers, indexes []int, updated Containers, inherit bool, timeout *time.Duration,
+) error {
+	batch := update.parallelism
+	if batch <= 0 {
+		batch = len(indexes)
+	}
+	for start := 0; start < len(indexes); start += batch {
+		if start > 0 && update.delay > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(update.delay):
+			}
+		}
+		end := start + batch
+		if end > len(indexes) {
+			end = len(indexes)
+		}
+		eg, ctx := errgroup.WithContext(ctx)
+		for _, i := range indexes[start:end] {
+			i := i
+			eg.Go(func() error {
+				recreated, err := c.service.rollContainer(ctx, project, service, containers[i], update, inherit, timeout)
+				updated[i] = recreated
+				return err
+			})
+		}
+		if err := eg.Wait(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollContainer replaces a running container by a new one and waits for it to be healthy.
+// With start-first order, the replaced container is only stopped once the new one is healthy. With stop-first order,
+// it is stopped before the new one starts. Either way, if the new container fails, it is removed and the replaced one
+// is left running
+func (s *composeService) rollContainer(ctx context.Context, project *types.Project, service types.ServiceConfig,
+	replaced moby.Container, update rollingUpdate, inherit bool, timeout *time.Duration,
+) (moby.Container, error) {
+	w := progress.ContextWriter(ctx)
+	eventName := getContainerProgressName(replaced)
+	w.Event(progress.NewEvent(eventName, progress.Working, "Rolling update"))
+
+	number, err := strconv.Atoi(replaced.Labels[api.ContainerNumberLabel])
+	if err != nil {
+		return replaced, err
+	}
+
+	var inherited *moby.Container
+	if inherit {
+		inherited = &replaced
+	}
+	name := getContainerName(s.containerNamer(), project.Name, service, number)
+	tmpName := fmt.Sprintf("%s_%s", replaced.ID[:12], name)
+	opts := createOptions{
+		AutoRemove:        false,
+		AttachStdin:       false,
+		UseNetworkAliases: true,
+		Labels:            mergeLabels(service.Labels, service.CustomLabels).Add(api.ContainerReplaceLabel, replaced.ID),
+	}
+	created, err := s.createMobyContainer(ctx, project, service, tmpName, number, inherited, opts, w)
+	if err != nil {
+		return replaced, err
+	}
+
+	timeoutInSecond := utils.DurationSecondToInt(timeout)
+	if !update.startFirst {
+		err = s.apiClient().ContainerStop(ctx, replaced.ID, containerType.StopOptions{Timeout: timeoutInSecond})
+		if err != nil {
+			return replaced, errors.Join(err, s.restoreContainer(ctx, created, replaced, false))
+		}
+	}
+
+	err = s.startAndWaitHealthy(ctx, created, update.monitor)
+	if err != nil {
+		w.Event(progress.ErrorMessageEvent(eventName, "Rolling update failed, keeping current container"))
+		err = fmt.Errorf("new replica of service %q failed to become healthy: %w", service.Name, err)
+		return replaced, errors.Join(err, s.restoreContainer(ctx, created, replaced, !update.startFirst))
+	}
+
+	if update.startFirst {
+		err = s.apiClient().ContainerStop(ctx, replaced.ID, containerType.StopOptions{Timeout: timeoutInSecond})
+		if err != nil {
+			return created, err
+		}
+	}
+	err = s.apiClient().ContainerRemove(ctx, replaced.ID, containerType.RemoveOptions{})
+	if err != nil {
+		return created, err
+	}
+	err = s.apiClient().ContainerRename(ctx, created.ID, name)
+	if err != nil {
+		return created, err
+	}
+
+	w.Event(progress.NewEvent(eventName, progress.Done, "Updated"))
+	setDependentLifecycle(project, service.Name, forceRecreate)
+	return created, nil
+}
+
+// restoreContainer removes a new replica which failed during a rolling update, and restarts the container it was
+// replacing when it got stopped
+func (s *composeService) restoreContainer(ctx context.Context, created moby.Container, replaced moby.Container, restart bool) error {
+	// restore even if update was canceled
+	ctx = context.WithoutCancel(ctx)
+	err := s.apiClient().ContainerRemove(ctx, created.ID, containerType.RemoveOptions{Force: true})
+	if err != nil {
+		return err
+	}
+	if !restart {
+		return nil
+	}
+	return s.apiClient().ContainerStart(ctx, replaced.ID, containerType.StartOptions{})
+}
+
+// startAndWaitHealthy starts container and waits for it to be healthy, or running if it has no healthcheck. Container
+// is then monitored for monitor duration, and the update fails if it exits or becomes unhealthy meanwhile
+func (s *composeService) startAndWaitHealthy(ctx context.Context, container moby.Container, monitor time.Duration) error {
+	err := s.apiClient().ContainerStart(ctx, container.ID, containerType.StartOptions{})
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		healthy, err := s.isServiceHealthy(ctx, Containers{container}, true)
+		if err != nil {
+			return err
+		}
+		if healthy {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("container %s not healthy: %w", getCanonicalContainerName(container), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+	if monitor <= 0 {
+		return nil
+	}
+
+	deadline := time.NewTimer(monitor)
+	defer deadline.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline.C:
+			return nil
+		case <-ticker.C:
+		}
+		healthy, err := s.isServiceHealthy(ctx, Containers{container}, true)
+		if err != nil {
+			return err
+		}
+		if !healthy {
+			return fmt.Errorf("container %s failed while monitored", getCanonicalContainerName(container))
+		}
+	}
+}
@@ -58,6 +58,18 @@ func (s *composeService) RunOneOffContainer(ctx context.Context, project *types.
 }
 
 func (s *composeService) prepareRun(ctx context.Context, project *types.Project, opts api.RunOptions) (string, error) {
+	if err := s.disableUnsupportedFeatures(ctx, project); err != nil {
+		return "", err
+	}
+
+	if err := s.resolveProjectNetworks(ctx, project); err != nil {
+		return "", err
+	}
+
+	if err := s.prepareSecrets(ctx, project); err != nil {
+		return "", err
+	}
+
 	service, err := project.GetService(opts.Service)
 	if err != nil {
 		return "", err
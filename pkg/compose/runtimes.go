@@ -0,0 +1,123 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/context/store"
+)
+
+// ContainerRuntimeField is the docker context metadata field to set the container runtime serving the Docker Engine API
+const ContainerRuntimeField = "compose.runtime"
+
+// ContainerRuntime describes a container runtime exposing a Docker Engine compatible API, and which of the
+// dockerd-specific features compose relies on it supports
+type ContainerRuntime struct {
+	Name string
+	// Buildkit is set when runtime supports BuildKit build sessions, otherwise the classic builder is used
+	Buildkit bool
+	// Swarm is set when runtime can run as a swarm node, otherwise swarm detection is skipped
+	Swarm bool
+}
+
+var (
+	containerRuntimesMu sync.Mutex
+	containerRuntimes   = map[string]ContainerRuntime{}
+)
+
+func init() {
+	RegisterContainerRuntime(dockerRuntime)
+	RegisterContainerRuntime(ContainerRuntime{Name: "podman"})
+}
+
+var dockerRuntime = ContainerRuntime{Name: "docker", Buildkit: true, Swarm: true}
+
+// unsupportedRuntimes are container runtimes which don't expose a Docker Engine compatible API, and would require a
+// dedicated api.Service implementation driving them directly
+var unsupportedRuntimes = []string{"containerd", "nerdctl"}
+
+// RegisterContainerRuntime declares a container runtime which can be selected by docker context metadata
+func RegisterContainerRuntime(runtime ContainerRuntime) {
+	containerRuntimesMu.Lock()
+	defer containerRuntimesMu.Unlock()
+	containerRuntimes[runtime.Name] = runtime
+}
+
+func getContainerRuntime(name string) (ContainerRuntime, error) {
+	containerRuntimesMu.Lock()
+	defer containerRuntimesMu.Unlock()
+	runtime, ok := containerRuntimes[name]
+	if !ok && slices.Contains(unsupportedRuntimes, name) {
+		return ContainerRuntime{}, fmt.Errorf("container runtime %q doesn't expose a Docker Engine compatible API and is not supported yet", name)
+	}
+	if !ok {
+		var names []string
+		for n := range containerRuntimes {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return ContainerRuntime{}, fmt.Errorf("unsupported container runtime %q, supported runtimes are: %s", name, strings.Join(names, ", "))
+	}
+	return runtime, nil
+}
+
+// ContainerRuntimeFromContext returns the container runtime set by docker context metadata, if any
+func ContainerRuntimeFromContext(st store.Store, name string) (string, error) {
+	meta, err := st.GetMetadata(name)
+	if err != nil {
+		return "", err
+	}
+
+	var runtime interface{}
+	switch m := meta.Metadata.(type) {
+	case command.DockerContext:
+		runtime = m.AdditionalFields[ContainerRuntimeField]
+	case map[string]interface{}:
+		runtime = m[ContainerRuntimeField]
+	}
+	switch r := runtime.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return r, nil
+	default:
+		return "", fmt.Errorf("unexpected type for field %q: %T (expected: string)", ContainerRuntimeField, runtime)
+	}
+}
+
+func (s *composeService) SetContainerRuntime(name string) error {
+	runtime, err := getContainerRuntime(name)
+	if err != nil {
+		return err
+	}
+	s.runtime = &runtime
+	return nil
+}
+
+// containerRuntime returns the container runtime serving the Docker Engine API, which is dockerd by default
+func (s *composeService) containerRuntime() ContainerRuntime {
+	if s.runtime == nil {
+		return dockerRuntime
+	}
+	return *s.runtime
+}
@@ -0,0 +1,49 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestSetContainerRuntime(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, cli := prepareMocks(mockCtrl)
+	tested := composeService{dockerCli: cli}
+
+	assert.DeepEqual(t, tested.containerRuntime(), dockerRuntime)
+
+	err := tested.SetContainerRuntime("lxd")
+	assert.Error(t, err, `unsupported container runtime "lxd", supported runtimes are: docker, podman`)
+
+	err = tested.SetContainerRuntime("containerd")
+	assert.Error(t, err, `container runtime "containerd" doesn't expose a Docker Engine compatible API and is not supported yet`)
+
+	assert.NilError(t, tested.SetContainerRuntime("podman"))
+	assert.Equal(t, tested.containerRuntime().Name, "podman")
+	assert.Check(t, !tested.containerRuntime().Buildkit)
+
+	// swarm mode is not queried from podman
+	enabled, err := tested.isSWarmEnabled(context.Background())
+	assert.NilError(t, err)
+	assert.Check(t, !enabled)
+}
@@ -26,11 +26,18 @@ import (
 
 func (s *composeService) Scale(ctx context.Context, project *types.Project, options api.ScaleOptions) error {
 	return progress.Run(ctx, tracing.SpanWrapFunc("project/scale", tracing.ProjectOptions(ctx, project), func(ctx context.Context) error {
-		err := s.create(ctx, project, api.CreateOptions{Services: options.Services})
-		if err != nil {
-			return err
-		}
-		return s.start(ctx, project.Name, api.StartOptions{Project: project, Services: options.Services}, nil)
+		return s.withProjectLock(ctx, project.Name, func() error {
+			err := s.create(ctx, project, api.CreateOptions{Services: options.Services})
+			if err != nil {
+				return err
+			}
+			return s.start(ctx, project.Name, api.StartOptions{
+				Project:     project,
+				Services:    options.Services,
+				Wait:        options.Wait,
+				WaitTimeout: options.WaitTimeout,
+			}, nil)
+		})
 
 	}), s.stdinfo())
 }
@@ -20,18 +20,185 @@ import (
 	"archive/tar"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/compose/v2/pkg/api"
 	moby "github.com/docker/docker/api/types"
+	"github.com/mattn/go-shellwords"
 )
 
+// extSecretExec sets a command to run, from project working directory, to produce the value of a secret declared
+// with `environment` when the variable is not set
+const extSecretExec = "x-exec"
+
+// secretCommand returns the command declared by x-exec for secret, if any
+func secretCommand(secret types.SecretConfig) ([]string, error) {
+	switch command := secret.Extensions[extSecretExec].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return shellwords.Parse(command)
+	case []interface{}:
+		args := make([]string, len(command))
+		for i, arg := range command {
+			args[i] = fmt.Sprint(arg)
+		}
+		return args, nil
+	default:
+		return nil, fmt.Errorf("secret %q: %s must be a string or a list", secret.Name, extSecretExec)
+	}
+}
+
+// SecretSource describes where the value of a secret comes from
+func SecretSource(secret types.SecretConfig) string {
+	switch {
+	case bool(secret.External):
+		return "external"
+	case secret.Environment != "" && secret.Extensions[extSecretExec] != nil:
+		command, _ := secretCommand(secret)
+		return fmt.Sprintf("environment:%s|exec:%s", secret.Environment, strings.Join(command, " "))
+	case secret.Environment != "":
+		return "environment:" + secret.Environment
+	default:
+		return "file:" + secret.File
+	}
+}
+
+// SetSecretsExec defines if secrets can run the host command set by x-exec
+func (s *composeService) SetSecretsExec(enabled bool) {
+	s.secretsExec = enabled
+}
+
+// resolveSecret returns the value of a secret defined by an environment variable, or the output of the x-exec command
+// if variable is not set and running commands is allowed. File based secrets are not resolved, as those are bind mounted
+func resolveSecret(ctx context.Context, project *types.Project, secret types.SecretConfig, allowExec bool) (string, bool, error) {
+	if secret.Environment == "" {
+		return "", false, nil
+	}
+	if env, ok := project.Environment[secret.Environment]; ok {
+		return env, true, nil
+	}
+
+	command, err := secretCommand(secret)
+	if err != nil {
+		return "", true, err
+	}
+	if len(command) == 0 {
+		return "", true, fmt.Errorf("environment variable %q required by file %q is not set", secret.Environment, secret.Name)
+	}
+	if !allowExec {
+		return "", true, fmt.Errorf("environment variable %q required by file %q is not set, and running %s commands requires COMPOSE_SECRETS_EXEC=1",
+			secret.Environment, secret.Name, extSecretExec)
+	}
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Dir = project.WorkingDir
+	cmd.Env = append(os.Environ(), project.Environment.Values()...)
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			err = fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", true, fmt.Errorf("failed to run command for secret %q: %w", secret.Name, err)
+	}
+	return string(out), true, nil
+}
+
+// resolveSecrets resolves secrets used by services and defined by environment variable or x-exec command once for the
+// whole operation, so commands don't run again for each container. Resolved value is kept as secret content
+func (s *composeService) resolveSecrets(ctx context.Context, project *types.Project) error {
+	for _, service := range project.Services {
+		for _, ref := range service.Secrets {
+			secret, ok := project.Secrets[ref.Source]
+			if !ok || secret.Environment == "" || secret.Content != "" {
+				continue
+			}
+			content, _, err := resolveSecret(ctx, project, secret, s.secretsExec)
+			if err != nil {
+				return err
+			}
+			secret.Content = content
+			project.Secrets[ref.Source] = secret
+		}
+	}
+	return nil
+}
+
+// secretsRuntimeDir returns the directory used to store resolved secrets on host, within the user runtime directory
+// which is a tmpfs, so secrets don't get persisted on disk nor in container filesystem
+func secretsRuntimeDir(projectName string) string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "docker-compose", projectName, "secrets")
+}
+
+// prepareSecrets resolves secrets and, when engine runs on this host, stores them in secretsRuntimeDir to be bind
+// mounted as file based secrets. Otherwise, secrets are copied into containers by injectSecrets
+func (s *composeService) prepareSecrets(ctx context.Context, project *types.Project) error {
+	if err := s.resolveSecrets(ctx, project); err != nil {
+		return err
+	}
+	dir := secretsRuntimeDir(project.Name)
+	local := dir != "" && !s.dryRun && s.isLocalEngine()
+	for _, name := range sortedKeys(project.Secrets) {
+		secret := project.Secrets[name]
+		if secret.Environment == "" {
+			continue
+		}
+		if !local {
+			api.Warn(ctx, api.Warning{
+				Code:      api.WarningUnsupportedOption,
+				Attribute: "secrets." + name,
+				Message:   "secret can't be mounted from a tmpfs on this engine, it is copied into container filesystem",
+			})
+			continue
+		}
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return err
+		}
+		path := filepath.Join(dir, name)
+		// secret file is read-only, so can't be overwritten
+		_ = os.Remove(path)
+		if err := os.WriteFile(path, []byte(secret.Content), 0o444); err != nil {
+			return err
+		}
+		secret.File = path
+		secret.Environment = ""
+		secret.Content = ""
+		project.Secrets[name] = secret
+	}
+	return nil
+}
+
+// removeSecretsRuntimeDir removes secrets stored on host by prepareSecrets for project
+func (s *composeService) removeSecretsRuntimeDir(ctx context.Context, projectName string) {
+	dir := secretsRuntimeDir(projectName)
+	if dir == "" || s.dryRun {
+		return
+	}
+	if err := os.RemoveAll(filepath.Dir(dir)); err != nil {
+		api.Warn(ctx, api.Warning{
+			Code:    api.WarningOperationFailed,
+			Message: fmt.Sprintf("failed to remove secrets for project %q: %v", projectName, err),
+		})
+	}
+}
+
+// injectSecrets copies secrets which could not be mounted from secretsRuntimeDir into container
 func (s *composeService) injectSecrets(ctx context.Context, project *types.Project, service types.ServiceConfig, id string) error {
 	for _, config := range service.Secrets {
-		file := project.Secrets[config.Source]
-		if file.Environment == "" {
+		secret := project.Secrets[config.Source]
+		if secret.Environment == "" {
 			continue
 		}
 
@@ -41,11 +208,7 @@ func (s *composeService) injectSecrets(ctx context.Context, project *types.Proje
 			config.Target = "/run/secrets/" + config.Target
 		}
 
-		env, ok := project.Environment[file.Environment]
-		if !ok {
-			return fmt.Errorf("environment variable %q required by file %q is not set", file.Environment, file.Name)
-		}
-		b, err := createTar(env, types.FileReferenceConfig(config))
+		b, err := createTar(secret.Content, types.FileReferenceConfig(config))
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,105 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestResolveSecret(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("relies on echo command")
+	}
+	project := &types.Project{
+		WorkingDir:  t.TempDir(),
+		Environment: types.Mapping{"TOKEN": "from-env"},
+	}
+
+	_, ok, err := resolveSecret(context.Background(), project, types.SecretConfig{Name: "file", File: "./secret.txt"}, true)
+	assert.NilError(t, err)
+	assert.Check(t, !ok)
+
+	value, ok, err := resolveSecret(context.Background(), project, types.SecretConfig{
+		Name:        "token",
+		Environment: "TOKEN",
+		Extensions:  types.Extensions{extSecretExec: "echo from-exec"},
+	}, true)
+	assert.NilError(t, err)
+	assert.Check(t, ok)
+	assert.Equal(t, value, "from-env")
+
+	value, _, err = resolveSecret(context.Background(), project, types.SecretConfig{
+		Name:        "token",
+		Environment: "UNSET",
+		Extensions:  types.Extensions{extSecretExec: []interface{}{"echo", "from exec"}},
+	}, true)
+	assert.NilError(t, err)
+	assert.Equal(t, value, "from exec\n")
+
+	_, _, err = resolveSecret(context.Background(), project, types.SecretConfig{
+		Name:        "token",
+		Environment: "UNSET",
+		Extensions:  types.Extensions{extSecretExec: "echo from-exec"},
+	}, false)
+	assert.ErrorContains(t, err, "requires COMPOSE_SECRETS_EXEC=1")
+
+	_, _, err = resolveSecret(context.Background(), project, types.SecretConfig{Name: "token", Environment: "UNSET"}, true)
+	assert.Error(t, err, `environment variable "UNSET" required by file "token" is not set`)
+
+	_, _, err = resolveSecret(context.Background(), project, types.SecretConfig{
+		Name:        "token",
+		Environment: "UNSET",
+		Extensions:  types.Extensions{extSecretExec: "false"},
+	}, true)
+	assert.ErrorContains(t, err, `failed to run command for secret "token"`)
+}
+
+func TestResolveSecretsOnce(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("relies on sh command")
+	}
+	dir := t.TempDir()
+	project := &types.Project{
+		WorkingDir: dir,
+		Services: types.Services{
+			"api":    {Name: "api", Secrets: []types.ServiceSecretConfig{{Source: "token"}}},
+			"worker": {Name: "worker", Secrets: []types.ServiceSecretConfig{{Source: "token"}}},
+		},
+		Secrets: types.Secrets{
+			"token": {
+				Name:        "token",
+				Environment: "UNSET",
+				Extensions:  types.Extensions{extSecretExec: []interface{}{"sh", "-c", "echo run >> calls; echo secret"}},
+			},
+		},
+	}
+
+	s := &composeService{secretsExec: true}
+	assert.NilError(t, s.resolveSecrets(context.Background(), project))
+	assert.Equal(t, project.Secrets["token"].Content, "secret\n")
+
+	calls, err := os.ReadFile(filepath.Join(dir, "calls"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(calls), "run\n")
+}
@@ -0,0 +1,582 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/distribution/reference"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/swarm"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
+)
+
+// stackNamespaceLabel is set by `docker stack deploy`, so stacks deployed by compose can be managed by `docker stack`
+const stackNamespaceLabel = "com.docker.stack.namespace"
+
+func (s *composeService) StackDeploy(ctx context.Context, project *types.Project, options api.StackDeployOptions) error {
+	return progress.RunWithTitle(ctx, func(ctx context.Context) error {
+		return s.stackDeploy(ctx, project, options)
+	}, s.stdinfo(), "Deploying")
+}
+
+func (s *composeService) stackDeploy(ctx context.Context, project *types.Project, options api.StackDeployOptions) error {
+	info, err := s.apiClient().Info(ctx)
+	if err != nil {
+		return err
+	}
+	if info.Swarm.LocalNodeState != swarm.LocalNodeStateActive || !info.Swarm.ControlAvailable {
+		return errors.New("this node is not a swarm manager, use `docker swarm init` or `docker swarm join` to connect this node to a swarm")
+	}
+
+	for name, network := range project.Networks {
+		if !network.External && network.Driver == "" {
+			network.Driver = "overlay"
+			network.Attachable = true
+			project.Networks[name] = network
+		}
+	}
+	prepareNetworks(project)
+	if err := s.ensureNetworks(ctx, project.Networks); err != nil {
+		return err
+	}
+	if err := s.resolveSecrets(ctx, project); err != nil {
+		return err
+	}
+	refs, err := s.ensureSwarmSecretsAndConfigs(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.apiClient().ServiceList(ctx, moby.ServiceListOptions{
+		Filters: filters.NewArgs(projectFilter(project.Name)),
+	})
+	if err != nil {
+		return err
+	}
+	deployed := map[string]swarm.Service{}
+	for _, service := range existing {
+		deployed[service.Spec.Name] = service
+	}
+
+	w := progress.ContextWriter(ctx)
+	for _, name := range project.ServiceNames() {
+		service := project.Services[name]
+		if service.Build != nil {
+			api.Warn(ctx, api.Warning{
+				Code:      api.WarningIgnoredAttribute,
+				Service:   name,
+				Attribute: "build",
+				Message:   fmt.Sprintf("is ignored, image %s must be pushed to a registry for swarm nodes to pull it", api.GetImageNameOrDefault(service, project.Name)),
+			})
+		}
+		spec, err := s.toSwarmServiceSpec(ctx, project, service, refs)
+		if err != nil {
+			return err
+		}
+		var auth string
+		if options.WithRegistryAuth {
+			ref, err := reference.ParseNormalizedNamed(spec.TaskTemplate.ContainerSpec.Image)
+			if err != nil {
+				return err
+			}
+			auth, err = encodedAuth(ref, s.configFile())
+			if err != nil {
+				return err
+			}
+		}
+
+		eventName := "Service " + spec.Name
+		if current, ok := deployed[spec.Name]; ok {
+			delete(deployed, spec.Name)
+			w.Event(progress.NewEvent(eventName, progress.Working, "Updating"))
+			response, err := s.apiClient().ServiceUpdate(ctx, current.ID, current.Version, spec, moby.ServiceUpdateOptions{
+				EncodedRegistryAuth: auth,
+				QueryRegistry:       options.WithRegistryAuth,
+			})
+			if err != nil {
+				w.Event(progress.ErrorMessageEvent(eventName, "Error while Updating"))
+				return err
+			}
+			for _, warning := range response.Warnings {
+				api.Warn(ctx, api.Warning{Code: api.WarningEngine, Service: name, Message: warning})
+			}
+			w.Event(progress.NewEvent(eventName, progress.Done, "Updated"))
+			continue
+		}
+		w.Event(progress.CreatingEvent(eventName))
+		response, err := s.apiClient().ServiceCreate(ctx, spec, moby.ServiceCreateOptions{
+			EncodedRegistryAuth: auth,
+			QueryRegistry:       options.WithRegistryAuth,
+		})
+		if err != nil {
+			w.Event(progress.ErrorEvent(eventName))
+			return err
+		}
+		for _, warning := range response.Warnings {
+			api.Warn(ctx, api.Warning{Code: api.WarningEngine, Service: name, Message: warning})
+		}
+		w.Event(progress.CreatedEvent(eventName))
+	}
+
+	remaining := make([]string, 0, len(deployed))
+	for name, service := range deployed {
+		// services not selected by the command line are left unchanged
+		if _, disabled := project.DisabledServices[service.Spec.Labels[api.ServiceLabel]]; !disabled {
+			remaining = append(remaining, name)
+		}
+	}
+	sort.Strings(remaining)
+	for _, name := range remaining {
+		eventName := "Service " + name
+		if !options.Prune {
+			api.Warn(ctx, api.Warning{
+				Code: api.WarningOrphanContainers,
+				Message: fmt.Sprintf("Found swarm service %s for this project which is not declared by the compose file. "+
+					"You can run this command with the --prune flag to remove it.", name),
+			})
+			continue
+		}
+		w.Event(progress.RemovingEvent(eventName))
+		if err := s.apiClient().ServiceRemove(ctx, deployed[name].ID); err != nil {
+			w.Event(progress.ErrorEvent(eventName))
+			return err
+		}
+		w.Event(progress.RemovedEvent(eventName))
+	}
+	return nil
+}
+
+// toSwarmServiceSpec maps a compose service onto a swarm service, with `deploy` section setting the replication
+// mode, placement and update strategy. Secrets and configs refer to the swarm objects set by refs
+func (s *composeService) toSwarmServiceSpec(ctx context.Context, project *types.Project, service types.ServiceConfig, refs swarmReferences) (swarm.ServiceSpec, error) {
+	labels := map[string]string{}
+	if service.Deploy != nil {
+		for k, v := range service.Deploy.Labels {
+			labels[k] = v
+		}
+	}
+	labels[api.ProjectLabel] = project.Name
+	labels[api.ServiceLabel] = service.Name
+	labels[stackNamespaceLabel] = project.Name
+
+	containerLabels := map[string]string{stackNamespaceLabel: project.Name}
+	for k, v := range service.Labels {
+		containerLabels[k] = v
+	}
+
+	env := ToMobyEnv(service.Environment)
+	sort.Strings(env)
+
+	healthcheck, err := s.ToMobyHealthCheck(ctx, service.HealthCheck)
+	if err != nil {
+		return swarm.ServiceSpec{}, err
+	}
+
+	var mounts []mount.Mount
+	for _, volume := range service.Volumes {
+		m, err := buildMount(ctx, *project, volume)
+		if err != nil {
+			return swarm.ServiceSpec{}, err
+		}
+		mounts = append(mounts, m)
+	}
+
+	var networks []swarm.NetworkAttachmentConfig
+	for _, key := range service.NetworksByPriority() {
+		attachment := swarm.NetworkAttachmentConfig{
+			Target:  project.Networks[key].Name,
+			Aliases: []string{service.Name},
+		}
+		if config := service.Networks[key]; config != nil {
+			attachment.Aliases = append(attachment.Aliases, config.Aliases...)
+		}
+		networks = append(networks, attachment)
+	}
+
+	secrets, err := refs.secretReferences(service)
+	if err != nil {
+		return swarm.ServiceSpec{}, err
+	}
+	configs, err := refs.configReferences(service)
+	if err != nil {
+		return swarm.ServiceSpec{}, err
+	}
+
+	spec := swarm.ServiceSpec{
+		Annotations: swarm.Annotations{
+			// named as `docker stack deploy` does
+			Name:   project.Name + "_" + service.Name,
+			Labels: labels,
+		},
+		TaskTemplate: swarm.TaskSpec{
+			ContainerSpec: &swarm.ContainerSpec{
+				Image:       api.GetImageNameOrDefault(service, project.Name),
+				Labels:      containerLabels,
+				Command:     service.Entrypoint,
+				Args:        service.Command,
+				Hostname:    service.Hostname,
+				Env:         env,
+				Dir:         service.WorkingDir,
+				User:        service.User,
+				Mounts:      mounts,
+				Healthcheck: healthcheck,
+				TTY:         service.Tty,
+				OpenStdin:   service.StdinOpen,
+				ReadOnly:    service.ReadOnly,
+				Init:        service.Init,
+				StopSignal:  service.StopSignal,
+				Secrets:     secrets,
+				Configs:     configs,
+			},
+			Networks: networks,
+		},
+		Mode:         toSwarmServiceMode(service),
+		EndpointSpec: toSwarmEndpointSpec(service),
+	}
+	if service.StopGracePeriod != nil {
+		grace := time.Duration(*service.StopGracePeriod)
+		spec.TaskTemplate.ContainerSpec.StopGracePeriod = &grace
+	}
+
+	if service.Deploy == nil {
+		return spec, nil
+	}
+	deploy := service.Deploy
+	spec.TaskTemplate.Resources = toSwarmResources(deploy.Resources)
+	spec.TaskTemplate.RestartPolicy = toSwarmRestartPolicy(deploy.RestartPolicy)
+	spec.TaskTemplate.Placement = toSwarmPlacement(deploy.Placement)
+	spec.UpdateConfig = toSwarmUpdateConfig(deploy.UpdateConfig)
+	spec.RollbackConfig = toSwarmUpdateConfig(deploy.RollbackConfig)
+	return spec, nil
+}
+
+// swarmReferences are the swarm secrets and configs, by project secret or config name
+type swarmReferences struct {
+	secrets map[string]swarm.Secret
+	configs map[string]swarm.Config
+}
+
+// ensureSwarmSecretsAndConfigs creates or updates the swarm secrets and configs used by project services, as
+// `docker stack deploy` does, and looks up external ones
+func (s *composeService) ensureSwarmSecretsAndConfigs(ctx context.Context, project *types.Project) (swarmReferences, error) {
+	refs := swarmReferences{secrets: map[string]swarm.Secret{}, configs: map[string]swarm.Config{}}
+	usedSecrets := map[string]bool{}
+	usedConfigs := map[string]bool{}
+	for _, service := range project.Services {
+		for _, secret := range service.Secrets {
+			usedSecrets[secret.Source] = true
+		}
+		for _, config := range service.Configs {
+			usedConfigs[config.Source] = true
+		}
+	}
+	labels := map[string]string{api.ProjectLabel: project.Name, stackNamespaceLabel: project.Name}
+
+	for _, key := range sortedKeys(usedSecrets) {
+		secret, ok := project.Secrets[key]
+		if !ok {
+			return refs, fmt.Errorf("secret %q is not declared", key)
+		}
+		name := swarmObjectName(project, key, secret.Name)
+		existing, err := s.apiClient().SecretList(ctx, moby.SecretListOptions{Filters: filters.NewArgs(filters.Arg("name", name))})
+		if err != nil {
+			return refs, err
+		}
+		current, found := findSwarmSecret(existing, name)
+		if bool(secret.External) {
+			if !found {
+				return refs, fmt.Errorf("external secret %q not found", name)
+			}
+			refs.secrets[key] = current
+			continue
+		}
+		var data []byte
+		if secret.File != "" {
+			data, err = os.ReadFile(secret.File)
+		} else {
+			data = []byte(secret.Content)
+		}
+		if err != nil {
+			return refs, err
+		}
+		spec := swarm.SecretSpec{Annotations: swarm.Annotations{Name: name, Labels: labels}, Data: data}
+		if found {
+			// swarm only allows labels to be updated, so this fails when secret content changed
+			if err := s.apiClient().SecretUpdate(ctx, current.ID, current.Version, spec); err != nil {
+				return refs, fmt.Errorf("failed to update secret %q: %w", name, err)
+			}
+		} else {
+			response, err := s.apiClient().SecretCreate(ctx, spec)
+			if err != nil {
+				return refs, err
+			}
+			current = swarm.Secret{ID: response.ID}
+		}
+		current.Spec.Name = name
+		refs.secrets[key] = current
+	}
+
+	for _, key := range sortedKeys(usedConfigs) {
+		config, ok := project.Configs[key]
+		if !ok {
+			return refs, fmt.Errorf("config %q is not declared", key)
+		}
+		name := swarmObjectName(project, key, config.Name)
+		existing, err := s.apiClient().ConfigList(ctx, moby.ConfigListOptions{Filters: filters.NewArgs(filters.Arg("name", name))})
+		if err != nil {
+			return refs, err
+		}
+		current, found := findSwarmConfig(existing, name)
+		if bool(config.External) {
+			if !found {
+				return refs, fmt.Errorf("external config %q not found", name)
+			}
+			refs.configs[key] = current
+			continue
+		}
+		var data []byte
+		switch {
+		case config.File != "":
+			data, err = os.ReadFile(config.File)
+			if err != nil {
+				return refs, err
+			}
+		case config.Environment != "":
+			env, ok := project.Environment[config.Environment]
+			if !ok {
+				return refs, fmt.Errorf("environment variable %q required by file %q is not set", config.Environment, key)
+			}
+			data = []byte(env)
+		default:
+			data = []byte(config.Content)
+		}
+		spec := swarm.ConfigSpec{Annotations: swarm.Annotations{Name: name, Labels: labels}, Data: data}
+		if found {
+			if err := s.apiClient().ConfigUpdate(ctx, current.ID, current.Version, spec); err != nil {
+				return refs, fmt.Errorf("failed to update config %q: %w", name, err)
+			}
+		} else {
+			response, err := s.apiClient().ConfigCreate(ctx, spec)
+			if err != nil {
+				return refs, err
+			}
+			current = swarm.Config{ID: response.ID}
+		}
+		current.Spec.Name = name
+		refs.configs[key] = current
+	}
+	return refs, nil
+}
+
+// swarmObjectName returns the name of a swarm secret or config, as set by compose-go loader or named after project
+func swarmObjectName(project *types.Project, key string, name string) string {
+	if name != "" {
+		return name
+	}
+	return project.Name + "_" + key
+}
+
+// findSwarmSecret selects the secret with name, as the engine filters secrets by name prefix
+func findSwarmSecret(secrets []swarm.Secret, name string) (swarm.Secret, bool) {
+	for _, secret := range secrets {
+		if secret.Spec.Name == name {
+			return secret, true
+		}
+	}
+	return swarm.Secret{}, false
+}
+
+// findSwarmConfig selects the config with name, as the engine filters configs by name prefix
+func findSwarmConfig(configs []swarm.Config, name string) (swarm.Config, bool) {
+	for _, config := range configs {
+		if config.Spec.Name == name {
+			return config, true
+		}
+	}
+	return swarm.Config{}, false
+}
+
+func (r swarmReferences) secretReferences(service types.ServiceConfig) ([]*swarm.SecretReference, error) {
+	var references []*swarm.SecretReference
+	for _, secret := range service.Secrets {
+		object, ok := r.secrets[secret.Source]
+		if !ok {
+			return nil, fmt.Errorf("service %q: secret %q is not available on swarm", service.Name, secret.Source)
+		}
+		target := secret.Target
+		if target == "" {
+			target = secret.Source
+		}
+		references = append(references, &swarm.SecretReference{
+			File:       swarmFileTarget(target, secret.UID, secret.GID, secret.Mode),
+			SecretID:   object.ID,
+			SecretName: object.Spec.Name,
+		})
+	}
+	return references, nil
+}
+
+func (r swarmReferences) configReferences(service types.ServiceConfig) ([]*swarm.ConfigReference, error) {
+	var references []*swarm.ConfigReference
+	for _, config := range service.Configs {
+		object, ok := r.configs[config.Source]
+		if !ok {
+			return nil, fmt.Errorf("service %q: config %q is not available on swarm", service.Name, config.Source)
+		}
+		target := config.Target
+		if target == "" {
+			target = "/" + config.Source
+		}
+		file := swarmFileTarget(target, config.UID, config.GID, config.Mode)
+		references = append(references, &swarm.ConfigReference{
+			File:       (*swarm.ConfigReferenceFileTarget)(file),
+			ConfigID:   object.ID,
+			ConfigName: object.Spec.Name,
+		})
+	}
+	return references, nil
+}
+
+func swarmFileTarget(target string, uid string, gid string, mode *uint32) *swarm.SecretReferenceFileTarget {
+	file := &swarm.SecretReferenceFileTarget{Name: target, UID: "0", GID: "0", Mode: 0o444}
+	if uid != "" {
+		file.UID = uid
+	}
+	if gid != "" {
+		file.GID = gid
+	}
+	if mode != nil {
+		file.Mode = os.FileMode(*mode)
+	}
+	return file
+}
+
+func toSwarmServiceMode(service types.ServiceConfig) swarm.ServiceMode {
+	if service.Deploy != nil && service.Deploy.Mode == "global" {
+		return swarm.ServiceMode{Global: &swarm.GlobalService{}}
+	}
+	replicas := uint64(service.GetScale())
+	return swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}}
+}
+
+func toSwarmEndpointSpec(service types.ServiceConfig) *swarm.EndpointSpec {
+	endpoint := &swarm.EndpointSpec{}
+	if service.Deploy != nil && service.Deploy.EndpointMode != "" {
+		endpoint.Mode = swarm.ResolutionMode(service.Deploy.EndpointMode)
+	}
+	for _, port := range service.Ports {
+		var published uint64
+		if port.Published != "" {
+			// port ranges have been expanded by compose-go loader
+			_, _ = fmt.Sscanf(port.Published, "%d", &published)
+		}
+		mode := swarm.PortConfigPublishModeIngress
+		if port.Mode == "host" {
+			mode = swarm.PortConfigPublishModeHost
+		}
+		protocol := swarm.PortConfigProtocolTCP
+		if port.Protocol != "" {
+			protocol = swarm.PortConfigProtocol(port.Protocol)
+		}
+		endpoint.Ports = append(endpoint.Ports, swarm.PortConfig{
+			Name:          port.Name,
+			Protocol:      protocol,
+			TargetPort:    port.Target,
+			PublishedPort: uint32(published),
+			PublishMode:   mode,
+		})
+	}
+	return endpoint
+}
+
+func toSwarmResources(resources types.Resources) *swarm.ResourceRequirements {
+	requirements := &swarm.ResourceRequirements{}
+	if limits := resources.Limits; limits != nil {
+		requirements.Limits = &swarm.Limit{
+			NanoCPUs:    int64(limits.NanoCPUs * 1e9),
+			MemoryBytes: int64(limits.MemoryBytes),
+			Pids:        limits.Pids,
+		}
+	}
+	if reservations := resources.Reservations; reservations != nil {
+		requirements.Reservations = &swarm.Resources{
+			NanoCPUs:    int64(reservations.NanoCPUs * 1e9),
+			MemoryBytes: int64(reservations.MemoryBytes),
+		}
+	}
+	return requirements
+}
+
+func toSwarmRestartPolicy(policy *types.RestartPolicy) *swarm.RestartPolicy {
+	if policy == nil {
+		return nil
+	}
+	restart := &swarm.RestartPolicy{
+		Condition:   swarm.RestartPolicyCondition(policy.Condition),
+		MaxAttempts: policy.MaxAttempts,
+	}
+	if policy.Delay != nil {
+		delay := time.Duration(*policy.Delay)
+		restart.Delay = &delay
+	}
+	if policy.Window != nil {
+		window := time.Duration(*policy.Window)
+		restart.Window = &window
+	}
+	return restart
+}
+
+func toSwarmPlacement(placement types.Placement) *swarm.Placement {
+	p := &swarm.Placement{
+		Constraints: placement.Constraints,
+		MaxReplicas: placement.MaxReplicas,
+	}
+	for _, preference := range placement.Preferences {
+		p.Preferences = append(p.Preferences, swarm.PlacementPreference{
+			Spread: &swarm.SpreadOver{SpreadDescriptor: preference.Spread},
+		})
+	}
+	return p
+}
+
+func toSwarmUpdateConfig(config *types.UpdateConfig) *swarm.UpdateConfig {
+	if config == nil {
+		return nil
+	}
+	update := &swarm.UpdateConfig{
+		Delay:           time.Duration(config.Delay),
+		FailureAction:   config.FailureAction,
+		Monitor:         time.Duration(config.Monitor),
+		MaxFailureRatio: config.MaxFailureRatio,
+		Order:           config.Order,
+	}
+	if config.Parallelism != nil {
+		update.Parallelism = *config.Parallelism
+	}
+	return update
+}
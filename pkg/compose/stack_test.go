@@ -0,0 +1,207 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/api/types/system"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	compose "github.com/docker/compose/v2/pkg/api"
+)
+
+func TestToSwarmServiceSpec(t *testing.T) {
+	parallelism := uint64(2)
+	project := &types.Project{
+		Name: "demo",
+		Networks: types.Networks{
+			"back": {Name: "demo_back"},
+		},
+		Services: types.Services{
+			"web": {
+				Name:     "web",
+				Image:    "nginx",
+				Networks: map[string]*types.ServiceNetworkConfig{"back": nil},
+				Ports:    []types.ServicePortConfig{{Target: 80, Published: "8080", Protocol: "tcp"}},
+				Deploy: &types.DeployConfig{
+					Replicas: intPtr(3),
+					Placement: types.Placement{
+						Constraints: []string{"node.role==worker"},
+						Preferences: []types.PlacementPreferences{{Spread: "node.labels.zone"}},
+					},
+					UpdateConfig: &types.UpdateConfig{
+						Parallelism: &parallelism,
+						Delay:       types.Duration(10 * time.Second),
+						Order:       "start-first",
+					},
+				},
+			},
+		},
+	}
+	tested := composeService{}
+	spec, err := tested.toSwarmServiceSpec(context.Background(), project, project.Services["web"], swarmReferences{})
+	assert.NilError(t, err)
+
+	replicas := uint64(3)
+	assert.Equal(t, spec.Name, "demo_web")
+	assert.DeepEqual(t, spec.Labels, map[string]string{
+		compose.ProjectLabel: "demo",
+		compose.ServiceLabel: "web",
+		stackNamespaceLabel:  "demo",
+	})
+	assert.Equal(t, spec.TaskTemplate.ContainerSpec.Image, "nginx")
+	assert.DeepEqual(t, spec.TaskTemplate.Networks, []swarm.NetworkAttachmentConfig{{Target: "demo_back", Aliases: []string{"web"}}})
+	assert.DeepEqual(t, spec.Mode, swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}})
+	assert.DeepEqual(t, spec.EndpointSpec.Ports, []swarm.PortConfig{{
+		Protocol:      swarm.PortConfigProtocolTCP,
+		TargetPort:    80,
+		PublishedPort: 8080,
+		PublishMode:   swarm.PortConfigPublishModeIngress,
+	}})
+	assert.DeepEqual(t, spec.TaskTemplate.Placement, &swarm.Placement{
+		Constraints: []string{"node.role==worker"},
+		Preferences: []swarm.PlacementPreference{{Spread: &swarm.SpreadOver{SpreadDescriptor: "node.labels.zone"}}},
+	})
+	assert.DeepEqual(t, spec.UpdateConfig, &swarm.UpdateConfig{Parallelism: 2, Delay: 10 * time.Second, Order: "start-first"})
+}
+
+func TestSwarmSecretsAndConfigs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+	file := filepath.Join(t.TempDir(), "password.txt")
+	assert.NilError(t, os.WriteFile(file, []byte("secret"), 0o600))
+	mode := uint32(0o400)
+	project := &types.Project{
+		Name: "demo",
+		Services: types.Services{
+			"web": {
+				Name:    "web",
+				Image:   "nginx",
+				Secrets: []types.ServiceSecretConfig{{Source: "password", Mode: &mode}},
+				Configs: []types.ServiceConfigObjConfig{{Source: "nginx", Target: "/etc/nginx/nginx.conf"}},
+			},
+		},
+		Secrets: types.Secrets{
+			"password": {Name: "demo_password", File: file},
+			"unused":   {Name: "demo_unused", File: file},
+		},
+		Configs: types.Configs{
+			"nginx": {Name: "shared_nginx", External: true},
+		},
+	}
+
+	api.EXPECT().SecretList(gomock.Any(), moby.SecretListOptions{Filters: filters.NewArgs(filters.Arg("name", "demo_password"))}).
+		Return(nil, nil)
+	api.EXPECT().SecretCreate(gomock.Any(), swarm.SecretSpec{
+		Annotations: swarm.Annotations{Name: "demo_password", Labels: map[string]string{compose.ProjectLabel: "demo", stackNamespaceLabel: "demo"}},
+		Data:        []byte("secret"),
+	}).Return(moby.SecretCreateResponse{ID: "s1"}, nil)
+	api.EXPECT().ConfigList(gomock.Any(), moby.ConfigListOptions{Filters: filters.NewArgs(filters.Arg("name", "shared_nginx"))}).
+		Return([]swarm.Config{
+			{ID: "c0", Spec: swarm.ConfigSpec{Annotations: swarm.Annotations{Name: "shared_nginx_old"}}},
+			{ID: "c1", Spec: swarm.ConfigSpec{Annotations: swarm.Annotations{Name: "shared_nginx"}}},
+		}, nil)
+
+	refs, err := tested.ensureSwarmSecretsAndConfigs(context.Background(), project)
+	assert.NilError(t, err)
+	spec, err := tested.toSwarmServiceSpec(context.Background(), project, project.Services["web"], refs)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, spec.TaskTemplate.ContainerSpec.Secrets, []*swarm.SecretReference{{
+		File:       &swarm.SecretReferenceFileTarget{Name: "password", UID: "0", GID: "0", Mode: 0o400},
+		SecretID:   "s1",
+		SecretName: "demo_password",
+	}})
+	assert.DeepEqual(t, spec.TaskTemplate.ContainerSpec.Configs, []*swarm.ConfigReference{{
+		File:       &swarm.ConfigReferenceFileTarget{Name: "/etc/nginx/nginx.conf", UID: "0", GID: "0", Mode: 0o444},
+		ConfigID:   "c1",
+		ConfigName: "shared_nginx",
+	}})
+}
+
+func TestStackDeploy(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+	project := &types.Project{
+		Name: strings.ToLower(testProject),
+		Services: types.Services{
+			"web": {Name: "web", Image: "nginx"},
+			"db":  {Name: "db", Image: "postgres"},
+		},
+		DisabledServices: types.Services{
+			"cache": {Name: "cache", Image: "redis"},
+		},
+	}
+
+	api.EXPECT().Info(gomock.Any()).Return(system.Info{
+		Swarm: swarm.Info{LocalNodeState: swarm.LocalNodeStateActive, ControlAvailable: true},
+	}, nil)
+	api.EXPECT().ServiceList(gomock.Any(), moby.ServiceListOptions{
+		Filters: filters.NewArgs(projectFilter(strings.ToLower(testProject))),
+	}).Return([]swarm.Service{
+		{ID: "s1", Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "testproject_web", Labels: map[string]string{compose.ServiceLabel: "web"}}}},
+		{ID: "s2", Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "testproject_cache", Labels: map[string]string{compose.ServiceLabel: "cache"}}}},
+		{ID: "s3", Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "testproject_old", Labels: map[string]string{compose.ServiceLabel: "old"}}}},
+	}, nil)
+	api.EXPECT().ServiceCreate(gomock.Any(), gomock.Any(), moby.ServiceCreateOptions{}).
+		DoAndReturn(func(_ context.Context, spec swarm.ServiceSpec, _ moby.ServiceCreateOptions) (swarm.ServiceCreateResponse, error) {
+			assert.Equal(t, spec.Name, "testproject_db")
+			return swarm.ServiceCreateResponse{ID: "s4"}, nil
+		})
+	api.EXPECT().ServiceUpdate(gomock.Any(), "s1", gomock.Any(), gomock.Any(), moby.ServiceUpdateOptions{}).
+		Return(swarm.ServiceUpdateResponse{}, nil)
+	api.EXPECT().ServiceRemove(gomock.Any(), "s3").Return(nil)
+
+	err := tested.stackDeploy(context.Background(), project, compose.StackDeployOptions{Prune: true})
+	assert.NilError(t, err)
+}
+
+func TestStackDeployRequiresSwarmManager(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+	api.EXPECT().Info(gomock.Any()).Return(system.Info{
+		Swarm: swarm.Info{LocalNodeState: swarm.LocalNodeStateInactive},
+	}, nil)
+
+	err := tested.stackDeploy(context.Background(), &types.Project{Name: "demo"}, compose.StackDeployOptions{})
+	assert.ErrorContains(t, err, "this node is not a swarm manager")
+}
@@ -37,8 +37,11 @@ import (
 )
 
 func (s *composeService) Start(ctx context.Context, projectName string, options api.StartOptions) error {
+	projectName = strings.ToLower(projectName)
 	return progress.Run(ctx, func(ctx context.Context) error {
-		return s.start(ctx, strings.ToLower(projectName), options, nil)
+		return s.withProjectLock(ctx, projectName, func() error {
+			return s.start(ctx, projectName, options, nil)
+		})
 	}, s.stdinfo())
 }
 
@@ -63,6 +66,16 @@ func (s *composeService) start(ctx context.Context, projectName string, options
 	// but an attach failing won't interfere with the rest of the start
 	eg, attachCtx := errgroup.WithContext(ctx)
 	if listener != nil {
+		// init containers exit once completed, which must not be reported as the end of the application
+		initContainers := utils.NewSet[string]()
+		for _, service := range project.Services {
+			if _, ok := service.Labels[api.InitContainerLabel]; ok {
+				initContainers.Add(service.Name)
+			}
+		}
+		options.AttachTo = utils.NewSet[string](options.AttachTo...).Diff(initContainers).Elements()
+		options.Services = utils.NewSet[string](options.Services...).Diff(initContainers).Elements()
+
 		_, err := s.attach(attachCtx, project, listener, options.AttachTo)
 		if err != nil {
 			return err
@@ -75,16 +88,24 @@ func (s *composeService) start(ctx context.Context, projectName string, options
 			// required containers have exited, even if their output is not being shown
 			attachTo := utils.NewSet[string](options.AttachTo...)
 			required := utils.NewSet[string](options.Services...)
-			toWatch := attachTo.Union(required).Elements()
+			toWatch := attachTo.Union(required)
+			if options.ExitCodeFrom != "" {
+				// the service to return the exit code of is watched even if not attached, or only started as a
+				// dependency of the required ones
+				toWatch.Add(options.ExitCodeFrom)
+				if len(required) > 0 {
+					required.Add(options.ExitCodeFrom)
+				}
+			}
 
-			containers, err := s.getContainers(ctx, projectName, oneOffExclude, true, toWatch...)
+			containers, err := s.getContainers(ctx, projectName, oneOffExclude, true, toWatch.Elements()...)
 			if err != nil {
 				return err
 			}
 
 			// N.B. this uses the parent context (instead of attachCtx) so that the watch itself can
 			// continue even if one of the log streams fails
-			return s.watchContainers(ctx, project.Name, toWatch, required.Elements(), listener, containers,
+			return s.watchContainers(ctx, project.Name, toWatch.Elements(), required.Elements(), listener, containers,
 				func(container moby.Container, _ time.Time) error {
 					svc := container.Labels[api.ServiceLabel]
 					if attachTo.Has(svc) {
@@ -94,7 +115,7 @@ func (s *composeService) start(ctx context.Context, projectName string, options
 					// HACK: simulate an "attach" event
 					listener(api.ContainerEvent{
 						Type:      api.ContainerEventAttach,
-						Container: getContainerNameWithoutProject(container),
+						Container: s.getContainerNameWithoutProject(container),
 						ID:        container.ID,
 						Service:   svc,
 					})
@@ -158,6 +179,7 @@ func (s *composeService) start(ctx context.Context, projectName string, options
 		}
 	}
 
+	runUpHook(ctx)
 	return eg.Wait()
 }
 
@@ -252,7 +274,7 @@ func (s *composeService) watchContainers(ctx context.Context, //nolint:gocyclo
 				Names:  []string{inspected.Name},
 				Labels: inspected.Config.Labels,
 			}
-			name := getContainerNameWithoutProject(container)
+			name := s.getContainerNameWithoutProject(container)
 
 			service := container.Labels[api.ServiceLabel]
 			switch event.Status {
@@ -0,0 +1,97 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	moby "github.com/docker/docker/api/types"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+func (s *composeService) Stats(ctx context.Context, projectName string, options api.StatsOptions) ([]api.ContainerStats, error) {
+	projectName = strings.ToLower(projectName)
+	containers, err := s.getContainers(ctx, projectName, oneOffExclude, options.All, options.Services...)
+	if err != nil {
+		return nil, err
+	}
+	if len(options.Services) != 0 {
+		containers = containers.filter(isService(options.Services...))
+	}
+	stats := make([]api.ContainerStats, len(containers))
+	eg, ctx := errgroup.WithContext(ctx)
+	for i, container := range containers {
+		i, container := i, container
+		eg.Go(func() error {
+			inspect, err := s.apiClient().ContainerInspect(ctx, container.ID)
+			if err != nil {
+				return err
+			}
+			number, _ := strconv.Atoi(container.Labels[api.ContainerNumberLabel])
+			stat := api.ContainerStats{
+				ID:           container.ID,
+				Name:         getCanonicalContainerName(container),
+				Service:      container.Labels[api.ServiceLabel],
+				Number:       number,
+				State:        container.State,
+				RestartCount: inspect.RestartCount,
+			}
+			if inspect.State != nil && inspect.State.Health != nil {
+				stat.Health = inspect.State.Health.Status
+			}
+			if container.State == ContainerRunning {
+				usage, err := s.containerUsage(ctx, container.ID)
+				if err != nil {
+					return err
+				}
+				stat.CPUUsage = time.Duration(usage.CPUStats.CPUUsage.TotalUsage)
+				stat.MemoryUsage = usage.MemoryStats.Usage
+				stat.MemoryLimit = usage.MemoryStats.Limit
+			}
+			stats[i] = stat
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Service == stats[j].Service {
+			return stats[i].Number < stats[j].Number
+		}
+		return stats[i].Service < stats[j].Service
+	})
+	return stats, nil
+}
+
+func (s *composeService) containerUsage(ctx context.Context, id string) (moby.StatsJSON, error) {
+	var usage moby.StatsJSON
+	response, err := s.apiClient().ContainerStatsOneShot(ctx, id)
+	if err != nil {
+		return usage, err
+	}
+	defer response.Body.Close() //nolint:errcheck
+	err = json.NewDecoder(response.Body).Decode(&usage)
+	return usage, err
+}
@@ -0,0 +1,73 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	moby "github.com/docker/docker/api/types"
+	containerType "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	compose "github.com/docker/compose/v2/pkg/api"
+)
+
+func TestStats(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	web := testContainer("web", "123", false)
+	web.State = ContainerRunning
+	web.Labels[compose.ContainerNumberLabel] = "1"
+	db := testContainer("db", "456", false)
+	db.State = ContainerExited
+	db.Labels[compose.ContainerNumberLabel] = "1"
+	api.EXPECT().ContainerList(gomock.Any(), containerType.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(getDefaultFilters(strings.ToLower(testProject), oneOffExclude)...),
+	}).Return([]moby.Container{web, db}, nil)
+
+	webInspect := inspected("running", 0, "healthy")
+	webInspect.RestartCount = 2
+	api.EXPECT().ContainerInspect(gomock.Any(), "123").Return(webInspect, nil)
+	api.EXPECT().ContainerInspect(gomock.Any(), "456").Return(inspected("exited", 1, ""), nil)
+	api.EXPECT().ContainerStatsOneShot(gomock.Any(), "123").Return(moby.ContainerStats{
+		Body: io.NopCloser(strings.NewReader(`{"cpu_stats":{"cpu_usage":{"total_usage":1500000000}},"memory_stats":{"usage":1024,"limit":4096}}`)),
+	}, nil)
+
+	stats, err := tested.Stats(context.Background(), testProject, compose.StatsOptions{All: true})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, stats, []compose.ContainerStats{
+		{ID: "456", Name: "456", Service: "db", Number: 1, State: ContainerExited},
+		{
+			ID: "123", Name: "123", Service: "web", Number: 1, State: ContainerRunning,
+			Health: "healthy", RestartCount: 2,
+			CPUUsage: 1500 * time.Millisecond, MemoryUsage: 1024, MemoryLimit: 4096,
+		},
+	})
+}
@@ -20,14 +20,18 @@ import (
 	"context"
 	"strings"
 
+	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/docker/compose/v2/pkg/api"
 	"github.com/docker/compose/v2/pkg/progress"
 	"github.com/docker/compose/v2/pkg/utils"
 )
 
 func (s *composeService) Stop(ctx context.Context, projectName string, options api.StopOptions) error {
+	projectName = strings.ToLower(projectName)
 	return progress.RunWithTitle(ctx, func(ctx context.Context) error {
-		return s.stop(ctx, strings.ToLower(projectName), options)
+		return s.withProjectLock(ctx, projectName, func() error {
+			return s.stop(ctx, projectName, options)
+		})
 	}, s.stdinfo(), "Stopping")
 }
 
@@ -54,6 +58,29 @@ func (s *composeService) stop(ctx context.Context, projectName string, options a
 		if !utils.StringContains(options.Services, service) {
 			return nil
 		}
-		return s.stopContainers(ctx, w, containers.filter(isService(service)).filter(isNotOneOff), options.Timeout)
+		serviceContainers := containers.filter(isService(service)).filter(isNotOneOff)
+		if options.WaitForDrain {
+			s.drainService(ctx, w, project, service, serviceContainers)
+		}
+		return s.stopContainers(ctx, w, serviceContainers, options.Timeout)
 	})
 }
+
+// drainService waits for service containers to be drained, if service declares x-drain. Failures are only reported as
+// warnings, so containers get stopped anyway
+func (s *composeService) drainService(ctx context.Context, w progress.Writer, project *types.Project, name string, containers Containers) {
+	service, err := project.GetService(name)
+	if err != nil {
+		// project was rebuilt from containers, service has no x-drain declared
+		return
+	}
+	config, err := getDrainConfig(service)
+	if err != nil {
+		api.Warn(ctx, api.Warning{Code: api.WarningOperationFailed, Service: name, Attribute: extDrain, Message: err.Error()})
+		return
+	}
+	if config == nil {
+		return
+	}
+	s.drainContainers(ctx, w, name, *config, containers)
+}
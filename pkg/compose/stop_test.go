@@ -22,6 +22,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/docker/compose/v2/pkg/utils"
 
 	compose "github.com/docker/compose/v2/pkg/api"
@@ -70,3 +71,37 @@ func TestStopTimeout(t *testing.T) {
 	})
 	assert.NilError(t, err)
 }
+
+func TestStopReverseDependencyOrder(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	project := &types.Project{
+		Name: strings.ToLower(testProject),
+		Services: types.Services{
+			"service1": {Name: "service1"},
+			"service2": {Name: "service2", DependsOn: types.DependsOnConfig{"service1": {Condition: types.ServiceConditionStarted}}},
+		},
+	}
+
+	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt(false)).Return(
+		[]moby.Container{
+			testContainer("service1", "123", false),
+			testContainer("service2", "789", false),
+		}, nil)
+	// without --timeout, engine applies the stop_grace_period and stop_signal set on container
+	gomock.InOrder(
+		api.EXPECT().ContainerStop(gomock.Any(), "789", containerType.StopOptions{}).Return(nil),
+		api.EXPECT().ContainerStop(gomock.Any(), "123", containerType.StopOptions{}).Return(nil),
+	)
+
+	err := tested.Stop(context.Background(), strings.ToLower(testProject), compose.StopOptions{
+		Project: project,
+	})
+	assert.NilError(t, err)
+}
@@ -46,6 +46,7 @@ func (s *composeService) Top(ctx context.Context, projectName string, services [
 			summary[i] = api.ContainerProcSummary{
 				ID:        container.ID,
 				Name:      getCanonicalContainerName(container),
+				Service:   container.Labels[api.ServiceLabel],
 				Processes: topContent.Processes,
 				Titles:    topContent.Titles,
 			}
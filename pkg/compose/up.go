@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"sync"
 	"sync/atomic"
 	"syscall"
 
@@ -33,25 +34,42 @@ import (
 	"github.com/docker/docker/errdefs"
 	"github.com/eiannone/keyboard"
 	"github.com/hashicorp/go-multierror"
-	"github.com/sirupsen/logrus"
 )
 
 func (s *composeService) Up(ctx context.Context, project *types.Project, options api.UpOptions) error { //nolint:gocyclo
+	// the recorder outlives the create step, so a cancellation while attached up is starting containers rolls back too
+	var recorder *rollbackRecorder
+	if options.Create.RollbackOnCancel {
+		ctx, recorder = withRollbackRecorder(ctx)
+	}
 	err := progress.Run(ctx, tracing.SpanWrapFunc("project/up", tracing.ProjectOptions(ctx, project), func(ctx context.Context) error {
-		err := s.create(ctx, project, options.Create)
-		if err != nil {
-			return err
-		}
-		if options.Start.Attach == nil {
-			return s.start(ctx, project.Name, options.Start, nil)
-		}
-		return nil
+		// attached mode releases the lock once containers are created, so other commands can manage the project
+		return s.withProjectLock(ctx, project.Name, func() error {
+			var err error
+			project, err = s.runProviders(ctx, project, "up", nil)
+			if err != nil {
+				return err
+			}
+			options.Create.Services = withoutProviderServices(project, options.Create.Services)
+			options.Start.Services = withoutProviderServices(project, options.Start.Services)
+			options.Start.AttachTo = withoutProviderServices(project, options.Start.AttachTo)
+			if options.Start.Project != nil {
+				options.Start.Project = project
+			}
+			return s.withRollbackOnCancel(ctx, options.Create.RollbackOnCancel, func(ctx context.Context) error {
+				err := s.create(ctx, project, options.Create)
+				if err != nil {
+					return err
+				}
+				if options.Start.Attach == nil {
+					return s.start(ctx, project.Name, options.Start, nil)
+				}
+				return nil
+			})
+		})
 	}), s.stdinfo())
-	if err != nil {
-		return err
-	}
-
-	if options.Start.Attach == nil {
+	if err != nil || options.Start.Attach == nil {
+		s.runHook(ctx, project, hookOnUp, "up", options.Create.Services, err)
 		return err
 	}
 	if s.dryRun {
@@ -61,6 +79,14 @@ func (s *composeService) Up(ctx context.Context, project *types.Project, options
 
 	var eg multierror.Group
 
+	// on_up hook runs once all services are started, or if start fails before
+	var hookOnce sync.Once
+	runHookOnce := func(err error) {
+		hookOnce.Do(func() {
+			s.runHook(ctx, project, hookOnUp, "up", options.Create.Services, err)
+		})
+	}
+
 	// if we get a second signal during shutdown, we kill the services
 	// immediately, so the channel needs to have sufficient capacity or
 	// we might miss a signal while setting up the second channel read
@@ -69,13 +95,28 @@ func (s *composeService) Up(ctx context.Context, project *types.Project, options
 	defer close(signalChan)
 	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
 	defer signal.Stop(signalChan)
-	var isTerminated atomic.Bool
+	var isTerminated, isStarting, rollbackNeeded atomic.Bool
 	printer := newLogPrinter(options.Start.Attach)
 
+	var dashboard *formatter.Dashboard
+	eventListener := printer.HandleEvent
+	if options.Start.NavigationMenu {
+		dashboard = formatter.NewDashboard(project.ServiceNames())
+		eventListener = func(event api.ContainerEvent) {
+			dashboard.HandleContainerEvent(event)
+			printer.HandleEvent(event)
+		}
+	}
+	eventsCtx, cancelEvents := context.WithCancel(ctx)
+	defer cancelEvents()
+
 	doneCh := make(chan bool)
 	eg.Go(func() error {
 		first := true
 		gracefulTeardown := func() {
+			if recorder != nil && isStarting.Load() {
+				rollbackNeeded.Store(true)
+			}
 			printer.Cancel()
 			fmt.Fprintln(s.stdinfo(), "Gracefully stopping... (press Ctrl+C again to force)")
 			eg.Go(func() error {
@@ -93,16 +134,19 @@ func (s *composeService) Up(ctx context.Context, project *types.Project, options
 		if options.Start.NavigationMenu {
 			kEvents, err = keyboard.GetKeys(100)
 			if err != nil {
-				logrus.Warn("could not start menu, an error occurred while starting.")
+				api.Warn(ctx, api.Warning{Code: api.WarningOperationFailed, Message: "could not start menu, an error occurred while starting."})
 			} else {
 				isWatchConfigured := s.shouldWatch(project)
 				isDockerDesktopActive := s.isDesktopIntegrationActive()
 				tracing.KeyboardMetrics(ctx, options.Start.NavigationMenu, isDockerDesktopActive, isWatchConfigured)
 
-				formatter.NewKeyboardManager(ctx, isDockerDesktopActive, isWatchConfigured, signalChan, s.Watch)
+				formatter.NewKeyboardManager(ctx, isDockerDesktopActive, isWatchConfigured, signalChan, dashboard, s.Watch)
 				if options.Start.Watch {
 					formatter.KeyboardManager.StartWatch(ctx, project, options)
 				}
+				eg.Go(func() error {
+					return s.watchDashboardEvents(eventsCtx, project.Name, dashboard)
+				})
 			}
 		}
 
@@ -145,7 +189,7 @@ func (s *composeService) Up(ctx context.Context, project *types.Project, options
 			fmt.Fprintln(s.stdinfo(), "Aborting on container exit...")
 			return progress.Run(ctx, func(ctx context.Context) error {
 				return s.Stop(ctx, project.Name, api.StopOptions{
-					Services: options.Create.Services,
+					Services: abortedServices(options),
 					Project:  project,
 				})
 			}, s.stdinfo())
@@ -166,17 +210,33 @@ func (s *composeService) Up(ctx context.Context, project *types.Project, options
 	}
 
 	// We use the parent context without cancelation as we manage sigterm to stop the stack
-	err = s.start(context.WithoutCancel(ctx), project.Name, options.Start, printer.HandleEvent)
+	startCtx := withUpHook(context.WithoutCancel(ctx), func() {
+		runHookOnce(nil)
+	})
+	isStarting.Store(true)
+	err = s.start(startCtx, project.Name, options.Start, eventListener)
+	isStarting.Store(false)
 	if err != nil && !isTerminated.Load() { // Ignore error if the process is terminated
+		runHookOnce(err)
 		return err
 	}
 
 	// Signal for the signal-handler goroutines to stop
 	close(doneCh)
+	cancelEvents()
 
 	printer.Stop()
 
 	err = eg.Wait().ErrorOrNil()
+	if rollbackNeeded.Load() {
+		err = progress.Run(context.WithoutCancel(ctx), func(ctx context.Context) error {
+			return s.rollback(ctx, recorder)
+		}, s.stdinfo())
+		if err != nil {
+			return fmt.Errorf("%w, rollback failed: %w", api.ErrCanceled, err)
+		}
+		return api.ErrRolledBack
+	}
 	if exitCode != 0 {
 		errMsg := ""
 		if err != nil {
@@ -186,3 +246,28 @@ func (s *composeService) Up(ctx context.Context, project *types.Project, options
 	}
 	return err
 }
+
+// watchDashboardEvents feeds dashboard with engine events until ctx is done, and refreshes navigation menu
+func (s *composeService) watchDashboardEvents(ctx context.Context, projectName string, dashboard *formatter.Dashboard) error {
+	err := s.Events(ctx, projectName, api.EventsOptions{
+		Consumer: func(event api.Event) error {
+			dashboard.HandleEvent(event)
+			formatter.KeyboardManager.PrintKeyboardInfo()
+			return nil
+		},
+	})
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// abortedServices returns the services to stop when a container exit aborts up. With ExitCodeFrom, the dependencies
+// started for the selected services are stopped as well, in reverse dependency order, so a test harness doesn't leave
+// them running
+func abortedServices(options api.UpOptions) []string {
+	if options.Start.ExitCodeFrom != "" {
+		return nil
+	}
+	return options.Create.Services
+}
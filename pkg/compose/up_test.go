@@ -0,0 +1,37 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+func TestAbortedServices(t *testing.T) {
+	options := api.UpOptions{
+		Create: api.CreateOptions{Services: []string{"test"}},
+		Start:  api.StartOptions{OnExit: api.CascadeStop},
+	}
+	assert.DeepEqual(t, abortedServices(options), []string{"test"})
+
+	// dependencies started for the test service are stopped as well
+	options.Start.ExitCodeFrom = "test"
+	assert.Check(t, abortedServices(options) == nil)
+}
@@ -0,0 +1,193 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
+)
+
+func (s *composeService) VolumeBackup(ctx context.Context, project *types.Project, options api.VolumeBackupOptions) error {
+	return progress.RunWithTitle(ctx, func(ctx context.Context) error {
+		return s.volumeBackup(ctx, project, options)
+	}, s.stdinfo(), "Backing up")
+}
+
+func (s *composeService) volumeBackup(ctx context.Context, project *types.Project, options api.VolumeBackupOptions) error {
+	key, volume, err := projectVolume(project, options.Volume)
+	if err != nil {
+		return err
+	}
+	_, err = s.apiClient().VolumeInspect(ctx, volume.Name)
+	if errdefs.IsNotFound(err) {
+		return fmt.Errorf("volume %q has not been created", volume.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.warnVolumeInUse(ctx, project, volume.Name, "backup may be inconsistent"); err != nil {
+		return err
+	}
+
+	f, err := os.Create(options.File)
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck
+
+	w := progress.ContextWriter(ctx)
+	eventName := fmt.Sprintf("Volume %q", volume.Name)
+	w.Event(progress.NewEvent(eventName, progress.Working, "Backing up"))
+	err = s.withVolumeHelper(ctx, volumeHelperImage(project, key), volume.Name, true, func(id string) error {
+		content, _, err := s.apiClient().CopyFromContainer(ctx, id, exportVolumeMountPoint)
+		if err != nil {
+			return err
+		}
+		defer content.Close() //nolint:errcheck
+		gz := gzip.NewWriter(f)
+		if _, err := io.Copy(gz, content); err != nil {
+			return err
+		}
+		if err := gz.Close(); err != nil {
+			return err
+		}
+		return f.Close()
+	})
+	if err != nil {
+		w.Event(progress.ErrorMessageEvent(eventName, "Error while Backing up"))
+		// don't leave a truncated archive which could later be mistaken for a valid backup
+		_ = f.Close()
+		_ = os.Remove(options.File)
+		return err
+	}
+	w.Event(progress.NewEvent(eventName, progress.Done, "Backed up"))
+	return nil
+}
+
+func (s *composeService) VolumeRestore(ctx context.Context, project *types.Project, options api.VolumeBackupOptions) error {
+	return progress.RunWithTitle(ctx, func(ctx context.Context) error {
+		return s.volumeRestore(ctx, project, options)
+	}, s.stdinfo(), "Restoring")
+}
+
+func (s *composeService) volumeRestore(ctx context.Context, project *types.Project, options api.VolumeBackupOptions) error {
+	key, volume, err := projectVolume(project, options.Volume)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(options.File)
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck
+	content, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("invalid backup %s: %w", options.File, err)
+	}
+
+	if err := s.warnVolumeInUse(ctx, project, volume.Name, "running containers may not see a consistent state"); err != nil {
+		return err
+	}
+	if options.Clean {
+		if err := s.removeVolumeForRestore(ctx, volume); err != nil {
+			return err
+		}
+	}
+	volume.Labels = volume.Labels.Add(api.VolumeLabel, key)
+	volume.Labels = volume.Labels.Add(api.ProjectLabel, project.Name)
+	volume.Labels = volume.Labels.Add(api.VersionLabel, api.ComposeVersion)
+	if err := s.ensureVolume(ctx, volume, project.Name); err != nil {
+		return err
+	}
+
+	w := progress.ContextWriter(ctx)
+	eventName := fmt.Sprintf("Volume %q", volume.Name)
+	w.Event(progress.NewEvent(eventName, progress.Working, "Restoring"))
+	err = s.withVolumeHelper(ctx, volumeHelperImage(project, key), volume.Name, false, func(id string) error {
+		// backup content is rooted on the mount point directory
+		return s.apiClient().CopyToContainer(ctx, id, path.Dir(exportVolumeMountPoint), content, moby.CopyToContainerOptions{})
+	})
+	if err != nil {
+		w.Event(progress.ErrorMessageEvent(eventName, "Error while Restoring"))
+		return err
+	}
+	w.Event(progress.NewEvent(eventName, progress.Done, "Restored"))
+	return nil
+}
+
+// removeVolumeForRestore removes volume so it gets re-created empty
+func (s *composeService) removeVolumeForRestore(ctx context.Context, volume types.VolumeConfig) error {
+	if volume.External {
+		return fmt.Errorf("external volume %q can't be cleaned before restore", volume.Name)
+	}
+	err := s.apiClient().VolumeRemove(ctx, volume.Name, false)
+	switch {
+	case err == nil, errdefs.IsNotFound(err):
+		return nil
+	case errdefs.IsConflict(err):
+		return fmt.Errorf("volume %q is in use, remove containers using it to restore with --clean: %w", volume.Name, err)
+	default:
+		return err
+	}
+}
+
+// projectVolume selects a project volume by key or name
+func projectVolume(project *types.Project, name string) (string, types.VolumeConfig, error) {
+	if volume, ok := project.Volumes[name]; ok {
+		return name, volume, nil
+	}
+	for key, volume := range project.Volumes {
+		if volume.Name == name {
+			return key, volume, nil
+		}
+	}
+	return "", types.VolumeConfig{}, fmt.Errorf("no such volume %q in project %q", name, project.Name)
+}
+
+// warnVolumeInUse warns when running project containers have volume mounted, as its content could change while
+// being copied
+func (s *composeService) warnVolumeInUse(ctx context.Context, project *types.Project, volume string, consequence string) error {
+	containers, err := s.getContainers(ctx, project.Name, oneOffInclude, false)
+	if err != nil {
+		return err
+	}
+	for _, c := range containers {
+		for _, m := range c.Mounts {
+			if m.Name == volume {
+				api.Warn(ctx, api.Warning{
+					Code:    api.WarningResourceInUse,
+					Service: c.Labels[api.ServiceLabel],
+					Message: fmt.Sprintf("volume %q is in use by running container %s, %s. Stop service %q first to prevent this",
+						volume, getCanonicalContainerName(c), consequence, c.Labels[api.ServiceLabel]),
+				})
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	moby "github.com/docker/docker/api/types"
+	containerType "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+
+	compose "github.com/docker/compose/v2/pkg/api"
+)
+
+func backupProject() *types.Project {
+	return &types.Project{
+		Name: "myproject",
+		Services: types.Services{
+			"db": {
+				Name:  "db",
+				Image: "postgres",
+				Volumes: []types.ServiceVolumeConfig{
+					{Type: types.VolumeTypeVolume, Source: "data", Target: "/var/lib/postgresql/data"},
+				},
+			},
+		},
+		Volumes: types.Volumes{
+			"data": {Name: "myproject_data"},
+		},
+	}
+}
+
+func TestVolumeBackupRestore(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "data.tgz")
+	content := volumeContent(t)
+
+	running := testContainer("db", "123", false)
+	running.Mounts = []moby.MountPoint{{Name: "myproject_data", Destination: "/var/lib/postgresql/data"}}
+	api.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return([]moby.Container{running}, nil).Times(2)
+	api.EXPECT().VolumeInspect(gomock.Any(), "myproject_data").Return(volume.Volume{Name: "myproject_data"}, nil)
+	api.EXPECT().ContainerCreate(gomock.Any(), gomock.Any(), gomock.Any(), nil, nil, "").
+		DoAndReturn(func(_ context.Context, config *containerType.Config, hostConfig *containerType.HostConfig, _, _ any, _ string) (containerType.CreateResponse, error) {
+			assert.Equal(t, config.Image, "postgres")
+			assert.Equal(t, hostConfig.Mounts[0].Source, "myproject_data")
+			assert.Check(t, hostConfig.Mounts[0].ReadOnly)
+			return containerType.CreateResponse{ID: "helper"}, nil
+		})
+	api.EXPECT().CopyFromContainer(gomock.Any(), "helper", "/volume").Return(io.NopCloser(bytes.NewReader(content)), moby.ContainerPathStat{}, nil)
+	api.EXPECT().ContainerRemove(gomock.Any(), "helper", containerType.RemoveOptions{Force: true}).Return(nil)
+
+	err := tested.volumeBackup(ctx, backupProject(), compose.VolumeBackupOptions{Volume: "data", File: file})
+	assert.NilError(t, err)
+
+	api.EXPECT().VolumeInspect(gomock.Any(), "myproject_data").Return(volume.Volume{}, errdefs.NotFound(errors.New("not found")))
+	api.EXPECT().VolumeCreate(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, options volume.CreateOptions) (volume.Volume, error) {
+			assert.Equal(t, options.Name, "myproject_data")
+			assert.Equal(t, options.Labels[compose.VolumeLabel], "data")
+			return volume.Volume{Name: options.Name}, nil
+		})
+	api.EXPECT().ContainerCreate(gomock.Any(), gomock.Any(), gomock.Any(), nil, nil, "").
+		DoAndReturn(func(_ context.Context, _ *containerType.Config, hostConfig *containerType.HostConfig, _, _ any, _ string) (containerType.CreateResponse, error) {
+			assert.Check(t, !hostConfig.Mounts[0].ReadOnly)
+			return containerType.CreateResponse{ID: "helper"}, nil
+		})
+	api.EXPECT().CopyToContainer(gomock.Any(), "helper", "/", gomock.Any(), moby.CopyToContainerOptions{}).
+		DoAndReturn(func(_ context.Context, _, _ string, input io.Reader, _ moby.CopyToContainerOptions) error {
+			b, err := io.ReadAll(input)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, b, content)
+			return nil
+		})
+	api.EXPECT().ContainerRemove(gomock.Any(), "helper", containerType.RemoveOptions{Force: true}).Return(nil)
+
+	// volume can also be selected by name
+	err = tested.volumeRestore(ctx, backupProject(), compose.VolumeBackupOptions{Volume: "myproject_data", File: file})
+	assert.NilError(t, err)
+}
+
+func TestVolumeBackupUnknownVolume(t *testing.T) {
+	tested := composeService{}
+	err := tested.volumeBackup(context.Background(), backupProject(), compose.VolumeBackupOptions{Volume: "unknown"})
+	assert.Check(t, is.ErrorContains(err, `no such volume "unknown" in project "myproject"`))
+}
+
+func TestVolumeBackupFailureRemovesArchive(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+	file := filepath.Join(t.TempDir(), "data.tgz")
+
+	api.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return(nil, nil)
+	api.EXPECT().VolumeInspect(gomock.Any(), "myproject_data").Return(volume.Volume{Name: "myproject_data"}, nil)
+	api.EXPECT().ContainerCreate(gomock.Any(), gomock.Any(), gomock.Any(), nil, nil, "").Return(containerType.CreateResponse{ID: "helper"}, nil)
+	api.EXPECT().CopyFromContainer(gomock.Any(), "helper", "/volume").Return(nil, moby.ContainerPathStat{}, errors.New("copy failed"))
+	api.EXPECT().ContainerRemove(gomock.Any(), "helper", containerType.RemoveOptions{Force: true}).Return(nil)
+
+	err := tested.volumeBackup(context.Background(), backupProject(), compose.VolumeBackupOptions{Volume: "data", File: file})
+	assert.Check(t, is.ErrorContains(err, "copy failed"))
+	_, err = os.Stat(file)
+	assert.Check(t, os.IsNotExist(err))
+}
+
+func TestVolumeRestoreClean(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+	file := filepath.Join(t.TempDir(), "data.tgz")
+	f, err := os.Create(file)
+	assert.NilError(t, err)
+	gz := gzip.NewWriter(f)
+	_, err = gz.Write(volumeContent(t))
+	assert.NilError(t, err)
+	assert.NilError(t, gz.Close())
+	assert.NilError(t, f.Close())
+
+	api.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return(nil, nil)
+	api.EXPECT().VolumeRemove(gomock.Any(), "myproject_data", false).Return(errdefs.Conflict(errors.New("volume is in use")))
+
+	err = tested.volumeRestore(context.Background(), backupProject(), compose.VolumeBackupOptions{Volume: "data", File: file, Clean: true})
+	assert.Check(t, is.ErrorContains(err, `volume "myproject_data" is in use, remove containers using it to restore with --clean`))
+}
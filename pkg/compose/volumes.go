@@ -0,0 +1,136 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/go-units"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
+	"github.com/docker/compose/v2/pkg/prompt"
+	"github.com/docker/compose/v2/pkg/utils"
+)
+
+func (s *composeService) Volumes(ctx context.Context, projectName string, options api.VolumesOptions) ([]api.VolumeSummary, error) {
+	projectName = strings.ToLower(projectName)
+	volumes, err := s.apiClient().VolumeList(ctx, volume.ListOptions{
+		Filters: filters.NewArgs(projectFilter(projectName)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	du, err := s.apiClient().DiskUsage(ctx, moby.DiskUsageOptions{
+		Types: []moby.DiskUsageObject{moby.VolumeObject},
+	})
+	if err != nil {
+		return nil, err
+	}
+	usage := map[string]*volume.UsageData{}
+	for _, v := range du.Volumes {
+		usage[v.Name] = v.UsageData
+	}
+
+	var summary []api.VolumeSummary
+	for _, v := range volumes.Volumes {
+		key := v.Labels[api.VolumeLabel]
+		if len(options.Volumes) > 0 && !utils.StringContains(options.Volumes, key) && !utils.StringContains(options.Volumes, v.Name) {
+			continue
+		}
+		vol := api.VolumeSummary{
+			Name:       v.Name,
+			Volume:     key,
+			Driver:     v.Driver,
+			Mountpoint: v.Mountpoint,
+			Scope:      v.Scope,
+			CreatedAt:  v.CreatedAt,
+			Labels:     v.Labels,
+			Options:    v.Options,
+			Size:       -1,
+			RefCount:   -1,
+		}
+		if u := usage[v.Name]; u != nil {
+			vol.Size = u.Size
+			vol.RefCount = u.RefCount
+		}
+		summary = append(summary, vol)
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		return summary[i].Name < summary[j].Name
+	})
+	return summary, nil
+}
+
+func (s *composeService) VolumesPrune(ctx context.Context, projectName string, options api.VolumesPruneOptions) ([]api.VolumeSummary, error) {
+	volumes, err := s.Volumes(ctx, projectName, api.VolumesOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var unused []api.VolumeSummary
+	var names []string
+	var size int64
+	for _, v := range volumes {
+		if v.RefCount == 0 {
+			unused = append(unused, v)
+			names = append(names, v.Name)
+			size += v.Size
+		}
+	}
+	if len(unused) == 0 {
+		fmt.Fprintln(s.stdinfo(), "No unused volumes")
+		return nil, nil
+	}
+
+	msg := fmt.Sprintf("Going to remove %s (%s)", strings.Join(names, ", "), units.HumanSize(float64(size)))
+	if options.Force {
+		fmt.Fprintln(s.stdout(), msg)
+	} else {
+		confirm, err := prompt.NewPrompt(s.stdin(), s.stdout()).Confirm(msg, false)
+		if err != nil {
+			return nil, err
+		}
+		if !confirm {
+			return nil, nil
+		}
+	}
+
+	err = progress.RunWithTitle(ctx, func(ctx context.Context) error {
+		w := progress.ContextWriter(ctx)
+		eg, ctx := errgroup.WithContext(ctx)
+		for _, v := range unused {
+			name := v.Name
+			eg.Go(func() error {
+				return s.removeVolume(ctx, name, w)
+			})
+		}
+		return eg.Wait()
+	}, s.stdinfo(), "Removing")
+	if err != nil {
+		return nil, err
+	}
+	return unused, nil
+}
@@ -0,0 +1,100 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	compose "github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/mocks"
+)
+
+func expectProjectVolumes(api *mocks.MockAPIClient) {
+	api.EXPECT().VolumeList(gomock.Any(), volume.ListOptions{
+		Filters: filters.NewArgs(projectFilter(strings.ToLower(testProject))),
+	}).Return(volume.ListResponse{
+		Volumes: []*volume.Volume{
+			{Name: "testproject_data", Driver: "local", Labels: map[string]string{compose.VolumeLabel: "data"}},
+			{Name: "testproject_cache", Driver: "local", Labels: map[string]string{compose.VolumeLabel: "cache"}},
+		},
+	}, nil)
+	api.EXPECT().DiskUsage(gomock.Any(), moby.DiskUsageOptions{Types: []moby.DiskUsageObject{moby.VolumeObject}}).
+		Return(moby.DiskUsage{
+			Volumes: []*volume.Volume{
+				{Name: "testproject_data", UsageData: &volume.UsageData{Size: 1024, RefCount: 1}},
+				{Name: "testproject_cache", UsageData: &volume.UsageData{Size: 2048, RefCount: 0}},
+				{Name: "other_volume", UsageData: &volume.UsageData{Size: 4096, RefCount: 0}},
+			},
+		}, nil)
+}
+
+func TestVolumes(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+	expectProjectVolumes(api)
+
+	volumes, err := tested.Volumes(context.Background(), testProject, compose.VolumesOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, volumes, []compose.VolumeSummary{
+		{
+			Name:     "testproject_cache",
+			Volume:   "cache",
+			Driver:   "local",
+			Labels:   map[string]string{compose.VolumeLabel: "cache"},
+			Size:     2048,
+			RefCount: 0,
+		},
+		{
+			Name:     "testproject_data",
+			Volume:   "data",
+			Driver:   "local",
+			Labels:   map[string]string{compose.VolumeLabel: "data"},
+			Size:     1024,
+			RefCount: 1,
+		},
+	})
+}
+
+func TestVolumesPrune(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+	expectProjectVolumes(api)
+	api.EXPECT().VolumeRemove(gomock.Any(), "testproject_cache", true).Return(nil)
+
+	removed, err := tested.VolumesPrune(context.Background(), testProject, compose.VolumesPruneOptions{Force: true})
+	assert.NilError(t, err)
+	assert.Equal(t, len(removed), 1)
+	assert.Equal(t, removed[0].Name, "testproject_cache")
+}
@@ -18,9 +18,13 @@ package compose
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/docker/compose/v2/pkg/api"
+	moby "github.com/docker/docker/api/types"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -33,6 +37,27 @@ func (s *composeService) Wait(ctx context.Context, projectName string, options a
 		return 0, fmt.Errorf("no containers for project %q", projectName)
 	}
 
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	switch options.Condition {
+	case "", api.WaitConditionExited:
+		return s.waitExited(ctx, projectName, containers, options)
+	case api.WaitConditionRunning, api.WaitConditionHealthy:
+		statusCode, err := s.waitCondition(ctx, containers, options.Condition)
+		if errors.Is(err, context.DeadlineExceeded) {
+			return 0, fmt.Errorf("timeout waiting for services to be %s", options.Condition)
+		}
+		return statusCode, err
+	default:
+		return 0, fmt.Errorf("unsupported wait condition %q", options.Condition)
+	}
+}
+
+func (s *composeService) waitExited(ctx context.Context, projectName string, containers Containers, options api.WaitOptions) (int64, error) {
 	eg, waitCtx := errgroup.WithContext(ctx)
 	var statusCode int64
 	for _, c := range containers {
@@ -52,7 +77,10 @@ func (s *composeService) Wait(ctx context.Context, projectName string, options a
 		})
 	}
 
-	err = eg.Wait()
+	err := eg.Wait()
+	if errors.Is(err, context.DeadlineExceeded) {
+		return 0, fmt.Errorf("timeout waiting for services to exit")
+	}
 	if err != nil {
 		return 42, err // Ignore abort flag in case of error in wait
 	}
@@ -65,3 +93,61 @@ func (s *composeService) Wait(ctx context.Context, projectName string, options a
 
 	return statusCode, err
 }
+
+// waitCondition polls containers until all of them reached condition, and returns the exit code of the first
+// container found to have stopped instead
+func (s *composeService) waitCondition(ctx context.Context, containers Containers, condition string) (int64, error) {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		ready := true
+		for _, c := range containers {
+			inspect, err := s.apiClient().ContainerInspect(ctx, c.ID)
+			if err != nil {
+				return 0, err
+			}
+			ok, err := reachedCondition(inspect, condition)
+			if err != nil {
+				return int64(inspect.State.ExitCode), err
+			}
+			ready = ready && ok
+		}
+		if ready {
+			return 0, nil
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func reachedCondition(container moby.ContainerJSON, condition string) (bool, error) {
+	name := strings.TrimPrefix(container.Name, "/")
+	state := container.State
+	if state == nil {
+		return false, nil
+	}
+	switch state.Status {
+	case ContainerExited, ContainerDead:
+		return false, fmt.Errorf("container %s exited (%d)", name, state.ExitCode)
+	case ContainerRunning:
+	default:
+		return false, nil
+	}
+	if condition != api.WaitConditionHealthy || container.Config == nil || container.Config.Healthcheck == nil {
+		return true, nil
+	}
+	if state.Health == nil {
+		return false, nil
+	}
+	switch state.Health.Status {
+	case moby.Healthy:
+		return true, nil
+	case moby.Unhealthy:
+		return false, fmt.Errorf("container %s is unhealthy", name)
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,117 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	moby "github.com/docker/docker/api/types"
+	containerType "github.com/docker/docker/api/types/container"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	compose "github.com/docker/compose/v2/pkg/api"
+)
+
+func inspected(status string, exitCode int, health string) moby.ContainerJSON {
+	c := moby.ContainerJSON{
+		ContainerJSONBase: &moby.ContainerJSONBase{
+			Name:  "/testproject-service1-1",
+			State: &moby.ContainerState{Status: status, ExitCode: exitCode},
+		},
+		Config: &containerType.Config{},
+	}
+	if health != "" {
+		c.Config.Healthcheck = &containerType.HealthConfig{Test: []string{"CMD", "true"}}
+		c.State.Health = &moby.Health{Status: health}
+	}
+	return c
+}
+
+func TestReachedCondition(t *testing.T) {
+	tests := []struct {
+		name      string
+		container moby.ContainerJSON
+		condition string
+		ready     bool
+		err       string
+	}{
+		{name: "created", container: inspected("created", 0, ""), condition: compose.WaitConditionRunning},
+		{name: "running", container: inspected("running", 0, ""), condition: compose.WaitConditionRunning, ready: true},
+		{name: "running without healthcheck", container: inspected("running", 0, ""), condition: compose.WaitConditionHealthy, ready: true},
+		{name: "starting", container: inspected("running", 0, moby.Starting), condition: compose.WaitConditionHealthy},
+		{name: "healthy", container: inspected("running", 0, moby.Healthy), condition: compose.WaitConditionHealthy, ready: true},
+		{name: "unhealthy", container: inspected("running", 0, moby.Unhealthy), condition: compose.WaitConditionHealthy, err: "container testproject-service1-1 is unhealthy"},
+		{name: "exited", container: inspected("exited", 3, ""), condition: compose.WaitConditionRunning, err: "container testproject-service1-1 exited (3)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ready, err := reachedCondition(tt.container, tt.condition)
+			if tt.err != "" {
+				assert.Error(t, err, tt.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, ready, tt.ready)
+		})
+	}
+}
+
+func TestWaitHealthyReturnsExitCode(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	api.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return(
+		[]moby.Container{testContainer("service1", "123", false)}, nil)
+	api.EXPECT().ContainerInspect(gomock.Any(), "123").Return(inspected("running", 0, moby.Starting), nil)
+	api.EXPECT().ContainerInspect(gomock.Any(), "123").Return(inspected("exited", 2, moby.Unhealthy), nil)
+
+	code, err := tested.Wait(context.Background(), strings.ToLower(testProject), compose.WaitOptions{
+		Services:  []string{"service1"},
+		Condition: compose.WaitConditionHealthy,
+	})
+	assert.Error(t, err, "container testproject-service1-1 exited (2)")
+	assert.Equal(t, code, int64(2))
+}
+
+func TestWaitTimeout(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: cli,
+	}
+
+	api.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return(
+		[]moby.Container{testContainer("service1", "123", false)}, nil)
+	api.EXPECT().ContainerInspect(gomock.Any(), "123").Return(inspected("created", 0, ""), nil).AnyTimes()
+
+	_, err := tested.Wait(context.Background(), strings.ToLower(testProject), compose.WaitOptions{
+		Condition: compose.WaitConditionRunning,
+		Timeout:   100 * time.Millisecond,
+	})
+	assert.Error(t, err, "timeout waiting for services to be running")
+}
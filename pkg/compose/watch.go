@@ -34,7 +34,6 @@ import (
 	"github.com/docker/compose/v2/pkg/watch"
 	moby "github.com/docker/docker/api/types"
 	"github.com/jonboulle/clockwork"
-	"github.com/mitchellh/mapstructure"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 )
@@ -91,13 +90,12 @@ func (s *composeService) Watch(ctx context.Context, project *types.Project, serv
 	options.LogTo.Register(api.WatchLogger)
 	for i := range project.Services {
 		service := project.Services[i]
-		config, err := loadDevelopmentConfig(service, project)
+		config, err := DevelopmentConfig(ctx, service, project)
 		if err != nil {
 			return err
 		}
-
-		if service.Develop != nil {
-			config = service.Develop
+		if _, err := DevelopmentReload(config); err != nil {
+			return fmt.Errorf("service %s: %w", service.Name, err)
 		}
 
 		if config == nil {
@@ -149,7 +147,12 @@ func (s *composeService) Watch(ctx context.Context, project *types.Project, serv
 		var paths, pathLogs []string
 		for _, trigger := range config.Watch {
 			if checkIfPathAlreadyBindMounted(trigger.Path, service.Volumes) {
-				logrus.Warnf("path '%s' also declared by a bind mount volume, this path won't be monitored!\n", trigger.Path)
+				api.Warn(ctx, api.Warning{
+					Code:      api.WarningIgnoredAttribute,
+					Service:   service.Name,
+					Attribute: "develop.watch",
+					Message:   fmt.Sprintf("path '%s' also declared by a bind mount volume, this path won't be monitored!", trigger.Path),
+				})
 				continue
 			}
 			paths = append(paths, trigger.Path)
@@ -209,7 +212,11 @@ func (s *composeService) watch(ctx context.Context, project *types.Project, name
 				start := time.Now()
 				logrus.Debugf("batch start: service[%s] count[%d]", name, len(batch))
 				if err := s.handleWatchBatch(ctx, project, name, options, batch, syncer); err != nil {
-					logrus.Warnf("Error handling changed files for service %s: %v", name, err)
+					api.Warn(ctx, api.Warning{
+						Code:    api.WarningOperationFailed,
+						Service: name,
+						Message: fmt.Sprintf("Error handling changed files: %v", err),
+					})
 				}
 				logrus.Debugf("batch complete: service[%s] duration[%s] count[%d]",
 					name, time.Since(start), len(batch))
@@ -229,7 +236,7 @@ func (s *composeService) watch(ctx context.Context, project *types.Project, name
 			hostPath := event.Path()
 			for i, trigger := range triggers {
 				logrus.Debugf("change for %s - comparing with %s", hostPath, trigger.Path)
-				if fileEvent := maybeFileEvent(trigger, hostPath, ignores[i]); fileEvent != nil {
+				if fileEvent := maybeFileEvent(ctx, trigger, hostPath, ignores[i]); fileEvent != nil {
 					events <- *fileEvent
 				}
 			}
@@ -241,13 +248,16 @@ func (s *composeService) watch(ctx context.Context, project *types.Project, name
 // rules.
 //
 // Any errors are logged as warnings and nil (no file event) is returned.
-func maybeFileEvent(trigger types.Trigger, hostPath string, ignore watch.PathMatcher) *fileEvent {
+func maybeFileEvent(ctx context.Context, trigger types.Trigger, hostPath string, ignore watch.PathMatcher) *fileEvent {
 	if !pathutil.IsChild(trigger.Path, hostPath) {
 		return nil
 	}
 	isIgnored, err := ignore.Matches(hostPath)
 	if err != nil {
-		logrus.Warnf("error ignore matching %q: %v", hostPath, err)
+		api.Warn(ctx, api.Warning{
+			Code:    api.WarningOperationFailed,
+			Message: fmt.Sprintf("error ignore matching %q: %v", hostPath, err),
+		})
 		return nil
 	}
 
@@ -260,7 +270,10 @@ func maybeFileEvent(trigger types.Trigger, hostPath string, ignore watch.PathMat
 	if trigger.Target != "" {
 		rel, err := filepath.Rel(trigger.Path, hostPath)
 		if err != nil {
-			logrus.Warnf("error making %s relative to %s: %v", hostPath, trigger.Path, err)
+			api.Warn(ctx, api.Warning{
+				Code:    api.WarningOperationFailed,
+				Message: fmt.Sprintf("error making %s relative to %s: %v", hostPath, trigger.Path, err),
+			})
 			return nil
 		}
 		// always use Unix-style paths for inside the container
@@ -276,44 +289,6 @@ func maybeFileEvent(trigger types.Trigger, hostPath string, ignore watch.PathMat
 	}
 }
 
-func loadDevelopmentConfig(service types.ServiceConfig, project *types.Project) (*types.DevelopConfig, error) {
-	var config types.DevelopConfig
-	y, ok := service.Extensions["x-develop"]
-	if !ok {
-		return nil, nil
-	}
-	logrus.Warnf("x-develop is DEPRECATED, please use the official `develop` attribute")
-	err := mapstructure.Decode(y, &config)
-	if err != nil {
-		return nil, err
-	}
-	baseDir, err := filepath.EvalSymlinks(project.WorkingDir)
-	if err != nil {
-		return nil, fmt.Errorf("resolving symlink for %q: %w", project.WorkingDir, err)
-	}
-
-	for i, trigger := range config.Watch {
-		if !filepath.IsAbs(trigger.Path) {
-			trigger.Path = filepath.Join(baseDir, trigger.Path)
-		}
-		if p, err := filepath.EvalSymlinks(trigger.Path); err == nil {
-			// this might fail because the path doesn't exist, etc.
-			trigger.Path = p
-		}
-		trigger.Path = filepath.Clean(trigger.Path)
-		if trigger.Path == "" {
-			return nil, errors.New("watch rules MUST define a path")
-		}
-
-		if trigger.Action == types.WatchActionRebuild && service.Build == nil {
-			return nil, fmt.Errorf("service %s doesn't have a build section, can't apply 'rebuild' on watch", service.Name)
-		}
-
-		config.Watch[i] = trigger
-	}
-	return &config, nil
-}
-
 // batchDebounceEvents groups identical file events within a sliding time window and writes the results to the returned
 // channel.
 //
@@ -507,7 +482,7 @@ func (s *composeService) handleWatchBatch(ctx context.Context, project *types.Pr
 			NoDeps:   false,
 		})
 	}
-	return nil
+	return s.reloadService(ctx, project, service, options.LogTo)
 }
 
 // writeWatchSyncMessage prints out a message about the sync for the changed paths.
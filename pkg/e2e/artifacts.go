@@ -0,0 +1,138 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package e2e
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/icmd"
+)
+
+// ArtifactsDirEnvVar is the environment variable setting the directory where diagnostics are saved when a test fails.
+// When not set, diagnostics are written to the test log.
+const ArtifactsDirEnvVar = "E2E_ARTIFACTS_DIR"
+
+// composeGlobalFlags are the compose global flags taking a value, set to true for those selecting the project
+var composeGlobalFlags = map[string]bool{
+	"-f": true, "--file": true,
+	"-p": true, "--project-name": true,
+	"--project-directory": true,
+	"--env-file":          true,
+	"--profile":           true,
+	"--ansi":              false,
+	"--progress":          false,
+	"--parallel":          false,
+}
+
+// splitComposeArgs returns the leading flags of a compose command line selecting the target project, so other
+// commands can be run against the same project, and the compose subcommand
+func splitComposeArgs(args []string) (project []string, command string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			return project, arg
+		}
+		name, _, hasValue := strings.Cut(arg, "=")
+		selectsProject, takesValue := composeGlobalFlags[name]
+		if selectsProject {
+			project = append(project, arg)
+		}
+		if takesValue && !hasValue && i+1 < len(args) {
+			i++
+			if selectsProject {
+				project = append(project, args[i])
+			}
+		}
+	}
+	return project, ""
+}
+
+// recordProject keeps track of projects a test ran compose commands against, to capture their state on failure
+func (c *CLI) recordProject(args []string) {
+	project, _ := splitComposeArgs(args)
+	key := strings.Join(project, " ")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, p := range c.projects {
+		if strings.Join(p, " ") == key {
+			return
+		}
+	}
+	c.projects = append(c.projects, project)
+}
+
+// captureArtifacts saves the `compose ps` output and container logs of the projects used by a failed test, with daemon
+// events since CLI was created. Artifacts are only captured once per test
+func (c *CLI) captureArtifacts(t testing.TB) {
+	c.mu.Lock()
+	if c.captured[t.Name()] {
+		c.mu.Unlock()
+		return
+	}
+	c.captured[t.Name()] = true
+	projects := append([][]string(nil), c.projects...)
+	c.mu.Unlock()
+
+	dir := os.Getenv(ArtifactsDirEnvVar)
+	if dir != "" {
+		dir = filepath.Join(dir, artifactName(t.Name()))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Logf("failed to create artifacts directory: %v", err)
+			dir = ""
+		}
+	}
+	save := func(name string, cmd icmd.Cmd) {
+		res := icmd.RunCmd(cmd)
+		content := fmt.Sprintf("$ %s\n%s", strings.Join(cmd.Command, " "), res.Combined())
+		if dir == "" {
+			t.Logf("%s:\n%s", name, content)
+			return
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Logf("failed to save %s: %v", name, err)
+		}
+	}
+
+	for i, project := range projects {
+		suffix := ""
+		if len(projects) > 1 {
+			suffix = "-" + strconv.Itoa(i+1)
+		}
+		save("ps"+suffix+".txt", c.newComposeCmd(append(project, "ps", "--all", "--no-trunc")...))
+		save("logs"+suffix+".txt", c.newComposeCmd(append(project, "logs", "--no-color", "--timestamps")...))
+	}
+	save("events.jsonl", c.NewCmd(DockerExecutableName, "events",
+		"--since", strconv.FormatInt(c.startedAt.Unix(), 10),
+		"--until", strconv.FormatInt(time.Now().Unix(), 10),
+		"--format", "{{json .}}"))
+	if dir != "" {
+		t.Logf("diagnostics saved to %s", dir)
+	}
+}
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func artifactName(testName string) string {
+	return unsafeChars.ReplaceAllString(testName, "_")
+}
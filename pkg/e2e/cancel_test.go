@@ -1,6 +1,3 @@
-//go:build !windows
-// +build !windows
-
 /*
    Copyright 2020 Docker Compose CLI authors
 
@@ -22,15 +19,12 @@ package e2e
 import (
 	"context"
 	"fmt"
-	"os/exec"
 	"strings"
-	"syscall"
 	"testing"
 	"time"
 
 	"github.com/docker/compose/v2/pkg/utils"
 	"gotest.tools/v3/assert"
-	"gotest.tools/v3/icmd"
 )
 
 func TestComposeCancel(t *testing.T) {
@@ -67,7 +61,7 @@ func TestComposeCancel(t *testing.T) {
 		}, 30*time.Second, 1*time.Second)
 
 		// simulate Ctrl-C : send signal to processGroup, children will have same groupId by default
-		err = syscall.Kill(-cmd.Process.Pid, syscall.SIGINT)
+		err = InterruptProcessGroup(cmd)
 		assert.NilError(t, err)
 
 		select {
@@ -83,17 +77,3 @@ func TestComposeCancel(t *testing.T) {
 		}
 	})
 }
-
-func StartWithNewGroupID(ctx context.Context, command icmd.Cmd, stdout *utils.SafeBuffer, stderr *utils.SafeBuffer) (*exec.Cmd, error) {
-	cmd := exec.CommandContext(ctx, command.Command[0], command.Command[1:]...)
-	cmd.Env = command.Env
-	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
-	if stdout != nil {
-		cmd.Stdout = stdout
-	}
-	if stderr != nil {
-		cmd.Stderr = stderr
-	}
-	err := cmd.Start()
-	return cmd, err
-}
@@ -110,6 +110,24 @@ func TestLocalComposeRun(t *testing.T) {
 		assert.Assert(t, strings.Contains(res.Stdout(), "8082->80/tcp"), res.Stdout())
 	})
 
+	t.Run("compose run --detach prints container ID", func(t *testing.T) {
+		res := c.RunDockerComposeCmd(t, "-f", "./fixtures/run-test/ports.yaml", "run", "-d", "back",
+			"/bin/sh", "-c", "sleep 1")
+		id := strings.TrimSpace(res.Stdout())
+		assert.Assert(t, id != "", res.Combined())
+		res = c.RunDockerCmd(t, "inspect", "--format", "{{ index .Config.Labels \"com.docker.compose.oneoff\" }}", id)
+		assert.Equal(t, strings.TrimSpace(res.Stdout()), "True")
+	})
+
+	t.Run("compose run --build", func(t *testing.T) {
+		t.Cleanup(func() {
+			c.RunDockerComposeCmd(t, "-f", "./fixtures/run-test/build.yaml", "down", "--rmi", "all")
+		})
+		res := c.RunDockerComposeCmd(t, "-f", "./fixtures/run-test/build.yaml", "run", "--build", "--rm", "build")
+		assert.Assert(t, strings.Contains(res.Stdout(), "built by compose run"), res.Combined())
+		c.RunDockerCmd(t, "image", "inspect", "run-test-build")
+	})
+
 	t.Run("compose run orphan", func(t *testing.T) {
 		// Use different compose files to get an orphan container
 		c.RunDockerComposeCmd(t, "-f", "./fixtures/run-test/orphan.yaml", "run", "simple")
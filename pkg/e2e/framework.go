@@ -27,6 +27,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -77,6 +78,12 @@ type CLI struct {
 	//
 	// To populate, use WithEnv when creating a CLI instance.
 	env []string
+
+	startedAt time.Time
+	mu        sync.Mutex
+	// projects are the compose project flags used by commands, to capture diagnostics on failure
+	projects [][]string
+	captured map[string]bool
 }
 
 // CLIOption to customize behavior for all commands for a CLI instance.
@@ -101,7 +108,14 @@ func NewCLI(t testing.TB, opts ...CLIOption) *CLI {
 	c := &CLI{
 		ConfigDir: configDir,
 		HomeDir:   t.TempDir(),
+		startedAt: time.Now(),
+		captured:  map[string]bool{},
 	}
+	t.Cleanup(func() {
+		if t.Failed() {
+			c.captureArtifacts(t)
+		}
+	})
 
 	for _, opt := range opts {
 		opt(c)
@@ -338,6 +352,10 @@ func (c *CLI) RunDockerComposeCmd(t testing.TB, args ...string) *icmd.Result {
 // RunDockerComposeCmdNoCheck runs a docker compose command, don't presume of any expectation and returns a result
 func (c *CLI) RunDockerComposeCmdNoCheck(t testing.TB, args ...string) *icmd.Result {
 	t.Helper()
+	if _, command := splitComposeArgs(args); command == "down" && t.Failed() {
+		// test cleanup is about to remove containers, capture their state first
+		c.captureArtifacts(t)
+	}
 	cmd := c.NewDockerComposeCmd(t, args...)
 	cmd.Stdout = os.Stdout
 	t.Logf("Running command: %s", strings.Join(cmd.Command, " "))
@@ -348,9 +366,19 @@ func (c *CLI) RunDockerComposeCmdNoCheck(t testing.TB, args ...string) *icmd.Res
 // or standalone mode (based on build tags).
 func (c *CLI) NewDockerComposeCmd(t testing.TB, args ...string) icmd.Cmd {
 	t.Helper()
+	c.recordProject(args)
 	if composeStandaloneMode {
 		return c.NewCmd(ComposeStandalonePath(t), args...)
 	}
+	return c.newComposeCmd(args...)
+}
+
+func (c *CLI) newComposeCmd(args ...string) icmd.Cmd {
+	if composeStandaloneMode {
+		if composeBinary, err := findExecutable(DockerComposeExecutableName); err == nil {
+			return c.NewCmd(composeBinary, args...)
+		}
+	}
 	args = append([]string{"compose"}, args...)
 	return c.NewCmd(DockerExecutableName, args...)
 }
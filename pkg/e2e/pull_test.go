@@ -28,21 +28,23 @@ func TestComposePull(t *testing.T) {
 	c := NewParallelCLI(t)
 
 	t.Run("Verify image pulled", func(t *testing.T) {
-		// cleanup existing images
-		c.RunDockerComposeCmd(t, "--project-directory", "fixtures/compose-pull/simple", "down", "--rmi", "all")
+		RetryFlaky(t, 3, func(t testing.TB) {
+			// cleanup existing images
+			c.RunDockerComposeCmd(t, "--project-directory", "fixtures/compose-pull/simple", "down", "--rmi", "all")
 
-		res := c.RunDockerComposeCmd(t, "--project-directory", "fixtures/compose-pull/simple", "pull")
-		output := res.Combined()
+			res := c.RunDockerComposeCmd(t, "--project-directory", "fixtures/compose-pull/simple", "pull")
+			output := res.Combined()
 
-		assert.Assert(t, strings.Contains(output, "simple Pulled"))
-		assert.Assert(t, strings.Contains(output, "another Pulled"))
+			assert.Assert(t, strings.Contains(output, "simple Pulled"))
+			assert.Assert(t, strings.Contains(output, "another Pulled"))
 
-		// verify default policy is 'always' for pull command
-		res = c.RunDockerComposeCmd(t, "--project-directory", "fixtures/compose-pull/simple", "pull")
-		output = res.Combined()
+			// verify default policy is 'always' for pull command
+			res = c.RunDockerComposeCmd(t, "--project-directory", "fixtures/compose-pull/simple", "pull")
+			output = res.Combined()
 
-		assert.Assert(t, strings.Contains(output, "simple Pulled"))
-		assert.Assert(t, strings.Contains(output, "another Pulled"))
+			assert.Assert(t, strings.Contains(output, "simple Pulled"))
+			assert.Assert(t, strings.Contains(output, "another Pulled"))
+		})
 	})
 
 	t.Run("Verify a image is pulled once", func(t *testing.T) {
@@ -0,0 +1,137 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package e2e
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// RetryFlaky runs fn up to attempts times, until it succeeds. This is opt-in for tests known to be flaky for reasons
+// out of our control, typically because they depend on a remote registry. Failures of the first attempts are logged,
+// only the last attempt can fail the test.
+func RetryFlaky(t *testing.T, attempts int, fn func(t testing.TB)) {
+	t.Helper()
+	for i := 1; i < attempts; i++ {
+		a := &attempt{TB: t, name: fmt.Sprintf("%s#attempt-%d", t.Name(), i)}
+		a.run(fn)
+		if a.skipped {
+			t.SkipNow()
+		}
+		if !a.Failed() {
+			return
+		}
+		t.Logf("attempt %d/%d failed, retrying", i, attempts)
+	}
+	fn(t)
+}
+
+// attempt is a testing.TB which records failures instead of failing the test
+type attempt struct {
+	testing.TB
+	name string
+
+	mu       sync.Mutex
+	failed   bool
+	skipped  bool
+	cleanups []func()
+}
+
+func (a *attempt) run(fn func(t testing.TB)) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer a.runCleanups()
+		fn(a)
+	}()
+	<-done
+}
+
+func (a *attempt) runCleanups() {
+	for len(a.cleanups) > 0 {
+		last := len(a.cleanups) - 1
+		cleanup := a.cleanups[last]
+		a.cleanups = a.cleanups[:last]
+		cleanup()
+	}
+}
+
+func (a *attempt) Name() string {
+	return a.name
+}
+
+func (a *attempt) Cleanup(f func()) {
+	a.cleanups = append(a.cleanups, f)
+}
+
+func (a *attempt) Fail() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.failed = true
+}
+
+func (a *attempt) Failed() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.failed
+}
+
+func (a *attempt) FailNow() {
+	a.Fail()
+	runtime.Goexit()
+}
+
+func (a *attempt) Error(args ...any) {
+	a.Log(args...)
+	a.Fail()
+}
+
+func (a *attempt) Errorf(format string, args ...any) {
+	a.Logf(format, args...)
+	a.Fail()
+}
+
+func (a *attempt) Fatal(args ...any) {
+	a.Log(args...)
+	a.FailNow()
+}
+
+func (a *attempt) Fatalf(format string, args ...any) {
+	a.Logf(format, args...)
+	a.FailNow()
+}
+
+func (a *attempt) SkipNow() {
+	a.skipped = true
+	runtime.Goexit()
+}
+
+func (a *attempt) Skip(args ...any) {
+	a.Log(args...)
+	a.SkipNow()
+}
+
+func (a *attempt) Skipf(format string, args ...any) {
+	a.Logf(format, args...)
+	a.SkipNow()
+}
+
+func (a *attempt) Skipped() bool {
+	return a.skipped
+}
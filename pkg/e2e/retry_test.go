@@ -0,0 +1,49 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package e2e
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRetryFlaky(t *testing.T) {
+	var attempts []string
+	cleanups := 0
+	RetryFlaky(t, 3, func(t testing.TB) {
+		attempts = append(attempts, t.Name())
+		t.Cleanup(func() {
+			cleanups++
+		})
+		if len(attempts) < 3 {
+			t.Fatal("flaky")
+		}
+	})
+	assert.DeepEqual(t, attempts, []string{
+		"TestRetryFlaky#attempt-1",
+		"TestRetryFlaky#attempt-2",
+		"TestRetryFlaky",
+	})
+	assert.Equal(t, cleanups, 2, "cleanups of failed attempts are expected to run before retrying")
+}
+
+func TestSplitComposeArgs(t *testing.T) {
+	project, command := splitComposeArgs([]string{"--ansi", "never", "-f", "compose.yaml", "--project-name=test", "up", "-d"})
+	assert.DeepEqual(t, project, []string{"-f", "compose.yaml", "--project-name=test"})
+	assert.Equal(t, command, "up")
+}
@@ -25,7 +25,6 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
-	"syscall"
 	"testing"
 	"time"
 
@@ -86,7 +85,7 @@ func TestUpDependenciesNotStopped(t *testing.T) {
 	RequireServiceState(t, c, "dependency", "running")
 
 	t.Log("Simulating Ctrl-C")
-	require.NoError(t, syscall.Kill(-cmd.Process.Pid, syscall.SIGINT),
+	require.NoError(t, InterruptProcessGroup(cmd),
 		"Failed to send SIGINT to compose up process")
 
 	t.Log("Waiting for `compose up` to exit")
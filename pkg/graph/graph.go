@@ -0,0 +1,254 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package graph
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/utils"
+)
+
+// Status indicates the status of a vertex, as updated while traversing the graph
+type Status int
+
+// Vertex status flags
+const (
+	Stopped Status = iota
+	Started
+)
+
+// Graph represents dependencies between resources, an edge goes from a vertex to the one it depends on
+type Graph[T any] struct {
+	Vertices map[string]*Vertex[T]
+	lock     sync.RWMutex
+}
+
+// Vertex represents a resource in the dependencies structure, carrying a payload of type T
+type Vertex[T any] struct {
+	Key      string
+	Payload  T
+	Status   Status
+	Children map[string]*Vertex[T]
+	Parents  map[string]*Vertex[T]
+}
+
+// New is the constructor function for an empty Graph
+func New[T any]() *Graph[T] {
+	return &Graph[T]{
+		Vertices: map[string]*Vertex[T]{},
+	}
+}
+
+// NewVertex is the constructor function for the Vertex
+func NewVertex[T any](key string, payload T, initialStatus Status) *Vertex[T] {
+	return &Vertex[T]{
+		Key:      key,
+		Payload:  payload,
+		Status:   initialStatus,
+		Parents:  map[string]*Vertex[T]{},
+		Children: map[string]*Vertex[T]{},
+	}
+}
+
+// GetParents returns a slice with the parent vertices of the a Vertex
+func (v *Vertex[T]) GetParents() []*Vertex[T] {
+	var res []*Vertex[T]
+	for _, p := range v.Parents {
+		res = append(res, p)
+	}
+	return res
+}
+
+// GetChildren returns a slice with the child vertices of the a Vertex
+func (v *Vertex[T]) GetChildren() []*Vertex[T] {
+	var res []*Vertex[T]
+	for _, p := range v.Children {
+		res = append(res, p)
+	}
+	return res
+}
+
+// getAncestors return all descendents for a vertex, might contain duplicates
+func getAncestors[T any](v *Vertex[T]) []*Vertex[T] {
+	var descendents []*Vertex[T]
+	for _, parent := range v.GetParents() {
+		descendents = append(descendents, parent)
+		descendents = append(descendents, getAncestors(parent)...)
+	}
+	return descendents
+}
+
+// AddVertex adds a vertex to the Graph
+func (g *Graph[T]) AddVertex(key string, payload T, initialStatus Status) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	v := NewVertex(key, payload, initialStatus)
+	g.Vertices[key] = v
+}
+
+// AddEdge adds a relationship of dependency between vertices `source` and `destination`
+func (g *Graph[T]) AddEdge(source string, destination string) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	sourceVertex := g.Vertices[source]
+	destinationVertex := g.Vertices[destination]
+
+	if sourceVertex == nil {
+		return fmt.Errorf("could not find %s: %w", source, api.ErrNotFound)
+	}
+	if destinationVertex == nil {
+		return fmt.Errorf("could not find %s: %w", destination, api.ErrNotFound)
+	}
+
+	// If they are already connected
+	if _, ok := sourceVertex.Children[destination]; ok {
+		return nil
+	}
+
+	sourceVertex.Children[destination] = destinationVertex
+	destinationVertex.Parents[source] = sourceVertex
+
+	return nil
+}
+
+// Leaves returns the slice of leaves of the graph
+func (g *Graph[T]) Leaves() []*Vertex[T] {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	var res []*Vertex[T]
+	for _, v := range g.Vertices {
+		if len(v.Children) == 0 {
+			res = append(res, v)
+		}
+	}
+
+	return res
+}
+
+// Roots returns the slice of "Roots" of the graph
+func (g *Graph[T]) Roots() []*Vertex[T] {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	var res []*Vertex[T]
+	for _, v := range g.Vertices {
+		if len(v.Parents) == 0 {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// UpdateStatus updates the status of a certain vertex
+func (g *Graph[T]) UpdateStatus(key string, status Status) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.Vertices[key].Status = status
+}
+
+// FilterChildren returns children of a certain vertex that are in a certain status
+func (g *Graph[T]) FilterChildren(key string, status Status) []*Vertex[T] {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	var res []*Vertex[T]
+	vertex := g.Vertices[key]
+
+	for _, child := range vertex.Children {
+		if child.Status == status {
+			res = append(res, child)
+		}
+	}
+
+	return res
+}
+
+// FilterParents returns the parents of a certain vertex that are in a certain status
+func (g *Graph[T]) FilterParents(key string, status Status) []*Vertex[T] {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	var res []*Vertex[T]
+	vertex := g.Vertices[key]
+
+	for _, parent := range vertex.Parents {
+		if parent.Status == status {
+			res = append(res, parent)
+		}
+	}
+
+	return res
+}
+
+// HasCycles detects cycles in the graph
+func (g *Graph[T]) HasCycles() (bool, error) {
+	discovered := []string{}
+	finished := []string{}
+
+	for _, vertex := range g.Vertices {
+		path := []string{
+			vertex.Key,
+		}
+		if !utils.StringContains(discovered, vertex.Key) && !utils.StringContains(finished, vertex.Key) {
+			var err error
+			discovered, finished, err = g.visit(vertex.Key, path, discovered, finished)
+
+			if err != nil {
+				return true, err
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func (g *Graph[T]) visit(key string, path []string, discovered []string, finished []string) ([]string, []string, error) {
+	discovered = append(discovered, key)
+
+	for _, v := range g.Vertices[key].Children {
+		path := append(path, v.Key)
+		if utils.StringContains(discovered, v.Key) {
+			return nil, nil, fmt.Errorf("cycle found: %s", strings.Join(path, " -> "))
+		}
+
+		if !utils.StringContains(finished, v.Key) {
+			if _, _, err := g.visit(v.Key, path, discovered, finished); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	discovered = remove(discovered, key)
+	finished = append(finished, key)
+	return discovered, finished, nil
+}
+
+func remove(slice []string, item string) []string {
+	var s []string
+	for _, i := range slice {
+		if i != item {
+			s = append(s, i)
+		}
+	}
+	return s
+}
@@ -0,0 +1,292 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/compose/v2/pkg/utils"
+)
+
+// Traversal visits the vertices of a Graph concurrently, a vertex being visited once all vertices it depends on
+// (or which depend on it, when traversing in reverse order) have been
+type Traversal[T any] struct {
+	mu      sync.Mutex
+	seen    map[string]struct{}
+	ignored map[string]struct{}
+
+	extremityNodesFn         func(*Graph[T]) []*Vertex[T]                 // leaves or roots
+	adjacentNodesFn          func(*Vertex[T]) []*Vertex[T]                // getParents or getChildren
+	filterAdjacentByStatusFn func(*Graph[T], string, Status) []*Vertex[T] // filterChildren or filterParents
+	targetStatus             Status
+	adjacentStatusToSkip     Status
+
+	visitorFn      func(context.Context, T) error
+	maxConcurrency int
+
+	continueOnError bool
+	errors          *multierror.Error
+
+	observersMu sync.Mutex
+	observers   []func(VertexEvent[T])
+}
+
+// VertexState is the state of a vertex while traversing the graph
+type VertexState string
+
+// Vertex states, a vertex goes from pending to started then done or failed, or from pending to skipped
+const (
+	VertexPending VertexState = "pending"
+	VertexStarted VertexState = "started"
+	VertexDone    VertexState = "done"
+	VertexFailed  VertexState = "failed"
+	VertexSkipped VertexState = "skipped"
+)
+
+// VertexEvent reports a vertex state transition to traversal observers
+type VertexEvent[T any] struct {
+	Key     string
+	Payload T
+	State   VertexState
+	Time    time.Time
+	// Duration is the time spent visiting the vertex, set once done or failed
+	Duration time.Duration
+	// Err is the error returned by visitor, set once failed
+	Err error
+}
+
+// TraversalOption customizes a Traversal
+type TraversalOption[T any] func(*Traversal[T])
+
+// UpDirectionTraversal creates a Traversal visiting leaves first, then vertices which depend on them
+func UpDirectionTraversal[T any](visitorFn func(context.Context, T) error, options ...TraversalOption[T]) *Traversal[T] {
+	t := &Traversal[T]{
+		extremityNodesFn:         (*Graph[T]).Leaves,
+		adjacentNodesFn:          (*Vertex[T]).GetParents,
+		filterAdjacentByStatusFn: (*Graph[T]).FilterChildren,
+		adjacentStatusToSkip:     Stopped,
+		targetStatus:             Started,
+		visitorFn:                visitorFn,
+	}
+	for _, option := range options {
+		option(t)
+	}
+	return t
+}
+
+// DownDirectionTraversal creates a Traversal visiting roots first, then vertices they depend on
+func DownDirectionTraversal[T any](visitorFn func(context.Context, T) error, options ...TraversalOption[T]) *Traversal[T] {
+	t := &Traversal[T]{
+		extremityNodesFn:         (*Graph[T]).Roots,
+		adjacentNodesFn:          (*Vertex[T]).GetChildren,
+		filterAdjacentByStatusFn: (*Graph[T]).FilterParents,
+		adjacentStatusToSkip:     Started,
+		targetStatus:             Stopped,
+		visitorFn:                visitorFn,
+	}
+	for _, option := range options {
+		option(t)
+	}
+	return t
+}
+
+// WithRootNodesAndDown restricts traversal to the vertices set by key, and those which depend on them
+func WithRootNodesAndDown[T any](nodes []string) TraversalOption[T] {
+	return func(t *Traversal[T]) {
+		if len(nodes) == 0 {
+			return
+		}
+		originalFn := t.extremityNodesFn
+		t.extremityNodesFn = func(g *Graph[T]) []*Vertex[T] {
+			var want []string
+			for _, node := range nodes {
+				vertex := g.Vertices[node]
+				want = append(want, vertex.Key)
+				for _, v := range getAncestors(vertex) {
+					want = append(want, v.Key)
+				}
+			}
+
+			t.ignored = map[string]struct{}{}
+			for k := range g.Vertices {
+				if !utils.Contains(want, k) {
+					t.ignored[k] = struct{}{}
+				}
+			}
+
+			return originalFn(g)
+		}
+	}
+}
+
+// WithContinueOnError makes traversal go on with independent vertices when visitor fails on one. Vertices depending
+// on the failed one (or which the failed one depends on, when traversing in reverse order) are skipped, and all errors
+// are collected and returned once traversal completes
+func WithContinueOnError[T any]() TraversalOption[T] {
+	return func(t *Traversal[T]) {
+		t.continueOnError = true
+	}
+}
+
+// WithMaxConcurrency limits the number of vertices visited concurrently, a negative value meaning no limit
+func WithMaxConcurrency[T any](maxConcurrency int) TraversalOption[T] {
+	return func(t *Traversal[T]) {
+		t.maxConcurrency = maxConcurrency
+	}
+}
+
+// WithObserver registers a function to be notified of vertices state transitions. Observers are called one at a time,
+// and must not block as this would hold back traversal
+func WithObserver[T any](observer func(VertexEvent[T])) TraversalOption[T] {
+	return func(t *Traversal[T]) {
+		t.observers = append(t.observers, observer)
+	}
+}
+
+func (t *Traversal[T]) notify(node *Vertex[T], state VertexState, started time.Time, err error) {
+	if len(t.observers) == 0 {
+		return
+	}
+	event := VertexEvent[T]{
+		Key:     node.Key,
+		Payload: node.Payload,
+		State:   state,
+		Time:    time.Now(),
+		Err:     err,
+	}
+	if !started.IsZero() {
+		event.Duration = event.Time.Sub(started)
+	}
+	t.observersMu.Lock()
+	defer t.observersMu.Unlock()
+	for _, observer := range t.observers {
+		observer(event)
+	}
+}
+
+// Visit traverses the graph, calling visitor with the payload of each vertex
+func (t *Traversal[T]) Visit(ctx context.Context, g *Graph[T]) error {
+	expect := len(g.Vertices)
+	if expect == 0 {
+		return nil
+	}
+
+	eg, ctx := errgroup.WithContext(ctx)
+	if t.maxConcurrency > 0 {
+		eg.SetLimit(t.maxConcurrency + 1)
+	}
+	nodeCh := make(chan *Vertex[T], expect)
+	defer close(nodeCh)
+	// nodeCh need to allow n=expect writers while reader goroutine could have returner after ctx.Done
+	eg.Go(func() error {
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case node := <-nodeCh:
+				expect--
+				if expect == 0 {
+					return nil
+				}
+				t.run(ctx, g, eg, t.adjacentNodesFn(node), nodeCh)
+			}
+		}
+	})
+
+	nodes := t.extremityNodesFn(g)
+	for _, node := range g.Vertices {
+		t.notify(node, VertexPending, time.Time{}, nil)
+	}
+	t.run(ctx, g, eg, nodes, nodeCh)
+
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+	return t.errors.ErrorOrNil()
+}
+
+func (t *Traversal[T]) run(ctx context.Context, g *Graph[T], eg *errgroup.Group, nodes []*Vertex[T], nodeCh chan *Vertex[T]) {
+	for _, node := range nodes {
+		// Don't visit this vertex yet if all of its children have
+		// not been visited yet.
+		if len(t.filterAdjacentByStatusFn(g, node.Key, t.adjacentStatusToSkip)) != 0 {
+			continue
+		}
+
+		node := node
+		if !t.consume(node.Key) {
+			// another worker already visited this node
+			continue
+		}
+
+		eg.Go(func() error {
+			var err error
+			if _, ignore := t.ignored[node.Key]; ignore {
+				t.notify(node, VertexSkipped, time.Time{}, nil)
+			} else {
+				started := time.Now()
+				t.notify(node, VertexStarted, time.Time{}, nil)
+				err = t.visitorFn(ctx, node.Payload)
+				if err != nil {
+					t.notify(node, VertexFailed, started, err)
+				} else {
+					t.notify(node, VertexDone, started, nil)
+				}
+			}
+			if err == nil {
+				g.UpdateStatus(node.Key, t.targetStatus)
+			} else if t.continueOnError {
+				t.mu.Lock()
+				t.errors = multierror.Append(t.errors, err)
+				t.mu.Unlock()
+				t.skip(node, nodeCh)
+				err = nil
+			}
+			nodeCh <- node
+			return err
+		})
+	}
+}
+
+// skip marks all nodes reachable from a failed node as visited, so they won't run but still count for traversal completion
+func (t *Traversal[T]) skip(node *Vertex[T], nodeCh chan *Vertex[T]) {
+	for _, adjacent := range t.adjacentNodesFn(node) {
+		if t.consume(adjacent.Key) {
+			t.notify(adjacent, VertexSkipped, time.Time{}, nil)
+			nodeCh <- adjacent
+			t.skip(adjacent, nodeCh)
+		}
+	}
+}
+
+func (t *Traversal[T]) consume(nodeKey string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.seen == nil {
+		t.seen = make(map[string]struct{})
+	}
+	if _, ok := t.seen[nodeKey]; ok {
+		return false
+	}
+	t.seen[nodeKey] = struct{}{}
+	return true
+}
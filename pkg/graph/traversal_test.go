@@ -0,0 +1,182 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package graph
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/utils"
+)
+
+func TestWith_RootNodesAndUp(t *testing.T) {
+	graph := New[string]()
+
+	/** graph topology:
+	           A   B
+		      / \ / \
+		     G   C   E
+		          \ /
+		           D
+		           |
+		           F
+	*/
+
+	graph.AddVertex("A", "A", 0)
+	graph.AddVertex("B", "B", 0)
+	graph.AddVertex("C", "C", 0)
+	graph.AddVertex("D", "D", 0)
+	graph.AddVertex("E", "E", 0)
+	graph.AddVertex("F", "F", 0)
+	graph.AddVertex("G", "G", 0)
+
+	_ = graph.AddEdge("C", "A")
+	_ = graph.AddEdge("C", "B")
+	_ = graph.AddEdge("E", "B")
+	_ = graph.AddEdge("D", "C")
+	_ = graph.AddEdge("D", "E")
+	_ = graph.AddEdge("F", "D")
+	_ = graph.AddEdge("G", "A")
+
+	tests := []struct {
+		name  string
+		nodes []string
+		want  []string
+	}{
+		{
+			name:  "whole graph",
+			nodes: []string{"A", "B"},
+			want:  []string{"A", "B", "C", "D", "E", "F", "G"},
+		},
+		{
+			name:  "only leaves",
+			nodes: []string{"F", "G"},
+			want:  []string{"F", "G"},
+		},
+		{
+			name:  "simple dependent",
+			nodes: []string{"D"},
+			want:  []string{"D", "F"},
+		},
+		{
+			name:  "diamond dependents",
+			nodes: []string{"B"},
+			want:  []string{"B", "C", "D", "E", "F"},
+		},
+		{
+			name:  "partial graph",
+			nodes: []string{"A"},
+			want:  []string{"A", "C", "D", "F", "G"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mx := sync.Mutex{}
+			expected := utils.Set[string]{}
+			expected.AddAll("C", "G", "D", "F")
+			var visited []string
+
+			gt := DownDirectionTraversal(func(ctx context.Context, s string) error {
+				mx.Lock()
+				defer mx.Unlock()
+				visited = append(visited, s)
+				return nil
+			}, WithRootNodesAndDown[string](tt.nodes))
+			err := gt.Visit(context.TODO(), graph)
+			assert.NilError(t, err)
+			sort.Strings(visited)
+			assert.DeepEqual(t, tt.want, visited)
+		})
+	}
+}
+
+type network struct {
+	name   string
+	driver string
+}
+
+func TestUpDirectionTraversalPayload(t *testing.T) {
+	g := New[network]()
+	g.AddVertex("front", network{name: "front", driver: "bridge"}, Stopped)
+	g.AddVertex("back", network{name: "back", driver: "overlay"}, Stopped)
+	assert.NilError(t, g.AddEdge("front", "back"))
+
+	var visited []network
+	err := UpDirectionTraversal(func(ctx context.Context, n network) error {
+		visited = append(visited, n)
+		return nil
+	}, WithMaxConcurrency[network](1)).Visit(context.TODO(), g)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, visited, []network{{name: "back", driver: "overlay"}, {name: "front", driver: "bridge"}},
+		cmp.AllowUnexported(network{}))
+	assert.Equal(t, g.Vertices["front"].Status, Started)
+}
+
+func TestHasCycles(t *testing.T) {
+	g := New[int]()
+	g.AddVertex("a", 1, Stopped)
+	g.AddVertex("b", 2, Stopped)
+	assert.NilError(t, g.AddEdge("a", "b"))
+	cycle, err := g.HasCycles()
+	assert.NilError(t, err)
+	assert.Check(t, !cycle)
+
+	assert.NilError(t, g.AddEdge("b", "a"))
+	cycle, err = g.HasCycles()
+	assert.Check(t, cycle)
+	assert.ErrorContains(t, err, "cycle found")
+}
+
+func TestTraversalObserver(t *testing.T) {
+	g := New[string]()
+	g.AddVertex("db", "db", Stopped)
+	g.AddVertex("cache", "cache", Stopped)
+	g.AddVertex("app", "app", Stopped)
+	assert.NilError(t, g.AddEdge("app", "db"))
+	assert.NilError(t, g.AddEdge("app", "cache"))
+
+	failure := errors.New("failure")
+	states := map[string][]VertexState{}
+	var failed VertexEvent[string]
+	err := UpDirectionTraversal(func(ctx context.Context, s string) error {
+		if s == "db" {
+			return failure
+		}
+		return nil
+	}, WithContinueOnError[string](), WithObserver(func(event VertexEvent[string]) {
+		states[event.Key] = append(states[event.Key], event.State)
+		if event.State == VertexFailed {
+			failed = event
+		}
+	})).Visit(context.TODO(), g)
+	assert.Assert(t, errors.Is(err, failure))
+
+	assert.DeepEqual(t, states, map[string][]VertexState{
+		"db":    {VertexPending, VertexStarted, VertexFailed},
+		"cache": {VertexPending, VertexStarted, VertexDone},
+		"app":   {VertexPending, VertexSkipped},
+	})
+	assert.Equal(t, failed.Payload, "db")
+	assert.Equal(t, failed.Err, failure)
+	assert.Check(t, failed.Duration >= 0)
+}
@@ -0,0 +1,418 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package kube
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// waitImage is the image used by init containers to wait for service dependencies
+const waitImage = "busybox:1.36"
+
+// defaultVolumeSize is the storage requested by PersistentVolumeClaims created for project volumes
+const defaultVolumeSize = "1Gi"
+
+// Convert maps a compose project to Kubernetes manifests: a Deployment per service, a Service for those exposing
+// ports, a ConfigMap per config and a PersistentVolumeClaim per volume
+func Convert(ctx context.Context, project *types.Project) ([]interface{}, error) {
+	var objects []interface{}
+
+	for _, name := range sortedKeys(project.Configs) {
+		configMap, err := toConfigMap(project, name, project.Configs[name])
+		if err != nil {
+			return nil, err
+		}
+		if configMap != nil {
+			objects = append(objects, configMap)
+		}
+	}
+
+	for _, name := range sortedKeys(project.Volumes) {
+		if project.Volumes[name].External {
+			continue
+		}
+		objects = append(objects, toPersistentVolumeClaim(project, name))
+	}
+
+	for _, name := range project.ServiceNames() {
+		service := project.Services[name]
+		objects = append(objects, toDeployment(ctx, project, service), toService(project, service))
+	}
+	return objects, nil
+}
+
+// Marshal renders Kubernetes manifests for project as a multi-document YAML stream
+func Marshal(ctx context.Context, project *types.Project) ([]byte, error) {
+	objects, err := Convert(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	for i, o := range objects {
+		if i > 0 {
+			b.WriteString("---\n")
+		}
+		manifest, err := yaml.Marshal(o)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(manifest)
+	}
+	return b.Bytes(), nil
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// resourceName converts a compose name into a valid Kubernetes resource name (RFC 1123)
+func resourceName(name string) string {
+	return strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func objectMeta(project *types.Project, name string, labels map[string]string) metav1.ObjectMeta {
+	l := map[string]string{api.ProjectLabel: project.Name}
+	for k, v := range labels {
+		l[k] = v
+	}
+	return metav1.ObjectMeta{Name: resourceName(name), Labels: l}
+}
+
+func selector(project *types.Project, service string) map[string]string {
+	return map[string]string{
+		api.ProjectLabel: project.Name,
+		api.ServiceLabel: service,
+	}
+}
+
+func toConfigMap(project *types.Project, name string, config types.ConfigObjConfig) (*corev1.ConfigMap, error) {
+	if config.External {
+		return nil, nil
+	}
+	content := config.Content
+	switch {
+	case config.Environment != "":
+		content = project.Environment[config.Environment]
+	case config.File != "":
+		b, err := os.ReadFile(config.File)
+		if err != nil {
+			return nil, fmt.Errorf("config %q: %w", name, err)
+		}
+		content = string(b)
+	}
+	return &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: objectMeta(project, name, nil),
+		Data:       map[string]string{name: content},
+	}, nil
+}
+
+func toPersistentVolumeClaim(project *types.Project, name string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "PersistentVolumeClaim"},
+		ObjectMeta: objectMeta(project, name, map[string]string{api.VolumeLabel: name}),
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse(defaultVolumeSize),
+				},
+			},
+		},
+	}
+}
+
+// toService creates the Service making service reachable by name. Services without ports get a headless Service, which
+// only registers ready pods in DNS, so dependents can still wait for them
+func toService(project *types.Project, service types.ServiceConfig) *corev1.Service {
+	var ports []corev1.ServicePort
+	for _, p := range service.Ports {
+		protocol := corev1.ProtocolTCP
+		if strings.EqualFold(p.Protocol, "udp") {
+			protocol = corev1.ProtocolUDP
+		}
+		ports = append(ports, corev1.ServicePort{
+			Name:       fmt.Sprintf("%d-%s", p.Target, strings.ToLower(string(protocol))),
+			Port:       int32(p.Target),
+			TargetPort: intstr.FromInt32(int32(p.Target)),
+			Protocol:   protocol,
+		})
+	}
+	spec := corev1.ServiceSpec{
+		Selector: selector(project, service.Name),
+		Ports:    ports,
+	}
+	if len(ports) == 0 {
+		spec.ClusterIP = corev1.ClusterIPNone
+	}
+	return &corev1.Service{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: objectMeta(project, service.Name, map[string]string{api.ServiceLabel: service.Name}),
+		Spec:       spec,
+	}
+}
+
+func toDeployment(ctx context.Context, project *types.Project, service types.ServiceConfig) *appsv1.Deployment {
+	replicas := int32(service.GetScale())
+	container := corev1.Container{
+		Name:       resourceName(service.Name),
+		Image:      api.GetImageNameOrDefault(service, project.Name),
+		Command:    service.Entrypoint,
+		Args:       service.Command,
+		WorkingDir: service.WorkingDir,
+		TTY:        service.Tty,
+		Stdin:      service.StdinOpen,
+	}
+	for _, name := range sortedKeys(service.Environment) {
+		value := service.Environment[name]
+		if value == nil {
+			continue
+		}
+		container.Env = append(container.Env, corev1.EnvVar{Name: name, Value: *value})
+	}
+	for _, p := range service.Ports {
+		protocol := corev1.ProtocolTCP
+		if strings.EqualFold(p.Protocol, "udp") {
+			protocol = corev1.ProtocolUDP
+		}
+		container.Ports = append(container.Ports, corev1.ContainerPort{ContainerPort: int32(p.Target), Protocol: protocol})
+	}
+	container.Resources = toResources(service)
+	if probe := toProbe(service.HealthCheck); probe != nil {
+		container.ReadinessProbe = probe
+		container.LivenessProbe = probe
+	}
+
+	volumes, mounts := toVolumes(ctx, project, service)
+	container.VolumeMounts = mounts
+
+	spec := corev1.PodSpec{
+		Hostname:       service.Hostname,
+		Containers:     []corev1.Container{container},
+		InitContainers: toInitContainers(ctx, project, service),
+		Volumes:        volumes,
+	}
+	switch service.Restart {
+	case types.RestartPolicyNo, types.RestartPolicyOnFailure:
+		api.Warn(ctx, api.Warning{
+			Code:      api.WarningUnsupportedOption,
+			Service:   service.Name,
+			Attribute: "restart",
+			Message:   fmt.Sprintf("restart policy %q is not supported by Deployments, containers will always be restarted", service.Restart),
+		})
+	}
+
+	return &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: objectMeta(project, service.Name, map[string]string{api.ServiceLabel: service.Name}),
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: selector(project, service.Name)},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: selector(project, service.Name)},
+				Spec:       spec,
+			},
+		},
+	}
+}
+
+func toResources(service types.ServiceConfig) corev1.ResourceRequirements {
+	var requirements corev1.ResourceRequirements
+	if service.Deploy == nil {
+		return requirements
+	}
+	toList := func(r *types.Resource) corev1.ResourceList {
+		if r == nil {
+			return nil
+		}
+		list := corev1.ResourceList{}
+		if r.NanoCPUs > 0 {
+			list[corev1.ResourceCPU] = *resource.NewMilliQuantity(int64(r.NanoCPUs*1000), resource.DecimalSI)
+		}
+		if r.MemoryBytes > 0 {
+			list[corev1.ResourceMemory] = *resource.NewQuantity(int64(r.MemoryBytes), resource.BinarySI)
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		return list
+	}
+	requirements.Limits = toList(service.Deploy.Resources.Limits)
+	requirements.Requests = toList(service.Deploy.Resources.Reservations)
+	return requirements
+}
+
+// toProbe converts a healthcheck into an exec probe
+func toProbe(check *types.HealthCheckConfig) *corev1.Probe {
+	if check == nil || check.Disable || len(check.Test) == 0 {
+		return nil
+	}
+	var command []string
+	switch check.Test[0] {
+	case "NONE":
+		return nil
+	case "CMD":
+		command = check.Test[1:]
+	case "CMD-SHELL":
+		command = []string{"/bin/sh", "-c", strings.Join(check.Test[1:], " ")}
+	default:
+		command = check.Test
+	}
+	seconds := func(d *types.Duration) int32 {
+		if d == nil {
+			return 0
+		}
+		return int32(time.Duration(*d).Seconds())
+	}
+	probe := &corev1.Probe{
+		ProbeHandler:        corev1.ProbeHandler{Exec: &corev1.ExecAction{Command: command}},
+		PeriodSeconds:       seconds(check.Interval),
+		TimeoutSeconds:      seconds(check.Timeout),
+		InitialDelaySeconds: seconds(check.StartPeriod),
+	}
+	if check.Retries != nil {
+		probe.FailureThreshold = int32(*check.Retries)
+	}
+	return probe
+}
+
+// toInitContainers creates an init container for each dependency, waiting for the dependency service to be
+// reachable. As a Kubernetes Service only routes traffic to ready pods, this also waits for healthchecks to pass
+func toInitContainers(ctx context.Context, project *types.Project, service types.ServiceConfig) []corev1.Container {
+	var containers []corev1.Container
+	for _, name := range sortedKeys(service.DependsOn) {
+		dependency, ok := project.Services[name]
+		if !ok {
+			continue
+		}
+		if service.DependsOn[name].Condition == types.ServiceConditionCompletedSuccessfully {
+			api.Warn(ctx, api.Warning{
+				Code:      api.WarningUnsupportedOption,
+				Service:   service.Name,
+				Attribute: "depends_on",
+				Message:   fmt.Sprintf("condition %q for %q is not supported and will be ignored", types.ServiceConditionCompletedSuccessfully, name),
+			})
+			continue
+		}
+		host := resourceName(name)
+		script := fmt.Sprintf("until nslookup %s; do echo waiting for %s; sleep 1; done", host, name)
+		if len(dependency.Ports) > 0 {
+			port := dependency.Ports[0].Target
+			script = fmt.Sprintf("until nc -z %s %d; do echo waiting for %s; sleep 1; done", host, port, name)
+		}
+		containers = append(containers, corev1.Container{
+			Name:    "wait-for-" + host,
+			Image:   waitImage,
+			Command: []string{"/bin/sh", "-c", script},
+		})
+	}
+	return containers
+}
+
+func toVolumes(ctx context.Context, project *types.Project, service types.ServiceConfig) ([]corev1.Volume, []corev1.VolumeMount) {
+	var volumes []corev1.Volume
+	var mounts []corev1.VolumeMount
+	for i, v := range service.Volumes {
+		switch v.Type {
+		case types.VolumeTypeVolume:
+			if v.Source == "" {
+				name := fmt.Sprintf("anonymous-%d", i)
+				volumes = append(volumes, corev1.Volume{
+					Name:         name,
+					VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+				})
+				mounts = append(mounts, corev1.VolumeMount{Name: name, MountPath: v.Target, ReadOnly: v.ReadOnly})
+				continue
+			}
+			claim := v.Source
+			if vol, ok := project.Volumes[v.Source]; ok && bool(vol.External) {
+				claim = vol.Name
+			}
+			name := resourceName(v.Source)
+			volumes = append(volumes, corev1.Volume{
+				Name: name,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: resourceName(claim)},
+				},
+			})
+			mounts = append(mounts, corev1.VolumeMount{Name: name, MountPath: v.Target, ReadOnly: v.ReadOnly})
+		case types.VolumeTypeTmpfs:
+			name := fmt.Sprintf("tmpfs-%d", i)
+			volumes = append(volumes, corev1.Volume{
+				Name:         name,
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory}},
+			})
+			mounts = append(mounts, corev1.VolumeMount{Name: name, MountPath: v.Target})
+		default:
+			api.Warn(ctx, api.Warning{
+				Code:      api.WarningUnsupportedOption,
+				Service:   service.Name,
+				Attribute: "volumes",
+				Message:   fmt.Sprintf("%s mount %s is not supported and will be ignored", v.Type, v.Target),
+			})
+		}
+	}
+	for _, config := range service.Configs {
+		if c, ok := project.Configs[config.Source]; !ok || bool(c.External) {
+			continue
+		}
+		target := config.Target
+		if target == "" {
+			target = "/" + config.Source
+		}
+		name := "config-" + resourceName(config.Source)
+		volumes = append(volumes, corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: resourceName(config.Source)},
+				},
+			},
+		})
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      name,
+			MountPath: target,
+			SubPath:   config.Source,
+			ReadOnly:  true,
+		})
+	}
+	return volumes, mounts
+}
@@ -0,0 +1,112 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestConvert(t *testing.T) {
+	interval := types.Duration(10 * time.Second)
+	retries := uint64(3)
+	replicas := 2
+	project := &types.Project{
+		Name: "myapp",
+		Services: types.Services{
+			"web": {
+				Name:  "web",
+				Image: "nginx",
+				Ports: []types.ServicePortConfig{{Target: 80, Published: "8080"}},
+				DependsOn: types.DependsOnConfig{
+					"db": {Condition: types.ServiceConditionHealthy},
+				},
+				Deploy: &types.DeployConfig{Replicas: &replicas},
+			},
+			"db": {
+				Name:    "db",
+				Image:   "postgres",
+				Ports:   []types.ServicePortConfig{{Target: 5432}},
+				Volumes: []types.ServiceVolumeConfig{{Type: types.VolumeTypeVolume, Source: "db_data", Target: "/data"}},
+				HealthCheck: &types.HealthCheckConfig{
+					Test:     types.HealthCheckTest{"CMD-SHELL", "pg_isready"},
+					Interval: &interval,
+					Retries:  &retries,
+				},
+			},
+		},
+		Volumes: types.Volumes{
+			"db_data": {Name: "myapp_db_data"},
+		},
+	}
+
+	objects, err := Convert(context.Background(), project)
+	assert.NilError(t, err)
+	assert.Equal(t, len(objects), 5)
+	assert.Equal(t, objects[2].(*corev1.Service).Spec.ClusterIP, "")
+
+	pvc := objects[0].(*corev1.PersistentVolumeClaim)
+	assert.Equal(t, pvc.Name, "db-data")
+
+	db := objects[1].(*appsv1.Deployment)
+	assert.Equal(t, db.Name, "db")
+	assert.Equal(t, *db.Spec.Replicas, int32(1))
+	container := db.Spec.Template.Spec.Containers[0]
+	assert.DeepEqual(t, container.ReadinessProbe.Exec.Command, []string{"/bin/sh", "-c", "pg_isready"})
+	assert.Equal(t, container.ReadinessProbe.PeriodSeconds, int32(10))
+	assert.Equal(t, container.ReadinessProbe.FailureThreshold, int32(3))
+	assert.Equal(t, db.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName, "db-data")
+
+	dbService := objects[2].(*corev1.Service)
+	assert.Equal(t, dbService.Spec.Ports[0].Port, int32(5432))
+	assert.DeepEqual(t, dbService.Spec.Selector, db.Spec.Selector.MatchLabels)
+
+	web := objects[3].(*appsv1.Deployment)
+	assert.Equal(t, *web.Spec.Replicas, int32(2))
+	initContainers := web.Spec.Template.Spec.InitContainers
+	assert.Equal(t, len(initContainers), 1)
+	assert.DeepEqual(t, initContainers[0].Command, []string{"/bin/sh", "-c", "until nc -z db 5432; do echo waiting for db; sleep 1; done"})
+
+	_, ok := objects[4].(*corev1.Service)
+	assert.Check(t, ok)
+}
+
+func TestConvertHeadlessService(t *testing.T) {
+	project := &types.Project{
+		Name: "myapp",
+		Services: types.Services{
+			"worker": {Name: "worker", Image: "worker"},
+		},
+	}
+	objects, err := Convert(context.Background(), project)
+	assert.NilError(t, err)
+	assert.Equal(t, len(objects), 2)
+	service := objects[1].(*corev1.Service)
+	assert.Equal(t, service.Spec.ClusterIP, corev1.ClusterIPNone)
+	assert.Equal(t, len(service.Spec.Ports), 0)
+}
+
+func TestResourceName(t *testing.T) {
+	assert.Equal(t, resourceName("My_Service"), "my-service")
+	assert.Equal(t, resourceName("_data."), "data")
+}
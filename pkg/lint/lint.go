@@ -0,0 +1,119 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/compose-spec/compose-go/v2/types"
+)
+
+// Level is the severity of a finding, using SARIF levels
+type Level string
+
+const (
+	// LevelWarning is for practices which make a project fragile or hard to operate
+	LevelWarning Level = "warning"
+	// LevelError is for configurations which are a security risk
+	LevelError Level = "error"
+)
+
+// Finding is a problem reported by a rule for a service
+type Finding struct {
+	Rule    string `json:"rule"`
+	Level   Level  `json:"level"`
+	Service string `json:"service"`
+	Message string `json:"message"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: service %q: %s (%s)", f.Level, f.Service, f.Message, f.Rule)
+}
+
+// Rule checks services of a compose project for a bad practice
+type Rule interface {
+	// ID identifies the rule, so it can be disabled
+	ID() string
+	// Description explains what the rule checks
+	Description() string
+	// Level is the severity of findings reported by the rule
+	Level() Level
+	// Check returns messages describing the problems found for a service, if any
+	Check(project *types.Project, service types.ServiceConfig) []string
+}
+
+var rules []Rule
+
+// Register adds a rule to the default rule set
+func Register(rule Rule) {
+	rules = append(rules, rule)
+}
+
+// Rules returns the default rule set, sorted by ID
+func Rules() []Rule {
+	sorted := append([]Rule{}, rules...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ID() < sorted[j].ID()
+	})
+	return sorted
+}
+
+// Lint runs rules against all services of project. Findings are sorted by service, then by rule
+func Lint(project *types.Project, rules []Rule) []Finding {
+	findings := []Finding{}
+	for _, name := range project.ServiceNames() {
+		service := project.Services[name]
+		for _, rule := range rules {
+			for _, msg := range rule.Check(project, service) {
+				findings = append(findings, Finding{
+					Rule:    rule.ID(),
+					Level:   rule.Level(),
+					Service: name,
+					Message: msg,
+				})
+			}
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Service != findings[j].Service {
+			return findings[i].Service < findings[j].Service
+		}
+		return findings[i].Rule < findings[j].Rule
+	})
+	return findings
+}
+
+// Without filters out rules from the rule set by ID, and reports unknown IDs
+func Without(rules []Rule, ids ...string) ([]Rule, error) {
+	disabled := map[string]bool{}
+	for _, id := range ids {
+		disabled[id] = true
+	}
+	var filtered []Rule
+	for _, rule := range rules {
+		if disabled[rule.ID()] {
+			delete(disabled, rule.ID())
+			continue
+		}
+		filtered = append(filtered, rule)
+	}
+	for id := range disabled {
+		return nil, fmt.Errorf("unknown lint rule %q", id)
+	}
+	return filtered, nil
+}
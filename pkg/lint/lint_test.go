@@ -0,0 +1,112 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package lint
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestLint(t *testing.T) {
+	dir := t.TempDir()
+	shared := filepath.Join(dir, "shared")
+	assert.NilError(t, os.Mkdir(shared, 0o755))
+	assert.NilError(t, os.Chmod(shared, 0o777))
+
+	project := &types.Project{
+		Name: "myproject",
+		Services: types.Services{
+			"web": {
+				Name:        "web",
+				Image:       "nginx",
+				HealthCheck: &types.HealthCheckConfig{Disable: true},
+				MemLimit:    types.UnitBytes(64 * 1024 * 1024),
+				Volumes: []types.ServiceVolumeConfig{
+					{Type: types.VolumeTypeBind, Source: shared, Target: "/shared"},
+					{Type: types.VolumeTypeBind, Source: dir, Target: "/src"},
+				},
+			},
+			"db": {
+				Name:  "db",
+				Image: "postgres:latest",
+			},
+			"api": {
+				Name:        "api",
+				Image:       "registry.example.com/api:1.2@sha256:8be990ef2aeb16dbcb9271ddfe2610fa6658d13f6dfb8bc72074cc1ca36966a7",
+				HealthCheck: &types.HealthCheckConfig{Test: []string{"CMD", "true"}},
+				Deploy: &types.DeployConfig{Resources: types.Resources{
+					Limits: &types.Resource{NanoCPUs: 0.5},
+				}},
+			},
+			"app": {
+				Name:        "app",
+				Image:       "myapp",
+				Build:       &types.BuildConfig{Context: "."},
+				HealthCheck: &types.HealthCheckConfig{Test: []string{"CMD", "true"}},
+				CPUS:        1,
+			},
+		},
+	}
+
+	findings := Lint(project, Rules())
+	assert.DeepEqual(t, findings, []Finding{
+		{Rule: "healthcheck-missing", Level: LevelWarning, Service: "db", Message: "no healthcheck declared, unless the image sets one"},
+		{Rule: "image-latest", Level: LevelWarning, Service: "db", Message: "image postgres:latest uses the latest tag"},
+		{Rule: "resources-missing", Level: LevelWarning, Service: "db", Message: "no memory nor cpus limit set"},
+		{Rule: "bind-world-writable", Level: LevelError, Service: "web", Message: "bind mount source " + shared + " is world-writable (-rwxrwxrwx)"},
+		{Rule: "image-unpinned", Level: LevelWarning, Service: "web", Message: "image nginx has no tag nor digest"},
+	})
+
+	rules, err := Without(Rules(), "healthcheck-missing", "resources-missing")
+	assert.NilError(t, err)
+	assert.Equal(t, len(Lint(project, rules)), 3)
+
+	_, err = Without(Rules(), "unknown")
+	assert.Error(t, err, `unknown lint rule "unknown"`)
+}
+
+func TestSARIF(t *testing.T) {
+	rules := []Rule{imageLatest{}}
+	content, err := SARIF([]Finding{
+		{Rule: "image-latest", Level: LevelWarning, Service: "db", Message: "image postgres:latest uses the latest tag"},
+	}, rules, "compose.yaml")
+	assert.NilError(t, err)
+
+	var log sarifLog
+	assert.NilError(t, json.Unmarshal(content, &log))
+	assert.Equal(t, log.Version, "2.1.0")
+	assert.Equal(t, len(log.Runs), 1)
+	assert.DeepEqual(t, log.Runs[0].Tool.Driver.Rules, []sarifRule{{
+		ID:                   "image-latest",
+		ShortDescription:     sarifMessage{Text: "Image uses the latest tag, which changes over time"},
+		DefaultConfiguration: sarifConfiguration{Level: LevelWarning},
+	}})
+	assert.DeepEqual(t, log.Runs[0].Results, []sarifResult{{
+		RuleID:  "image-latest",
+		Level:   LevelWarning,
+		Message: sarifMessage{Text: "image postgres:latest uses the latest tag"},
+		Locations: []sarifLocation{{
+			PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: "compose.yaml"}},
+			LogicalLocations: []sarifLogicalLocation{{Name: "services.db", Kind: "object"}},
+		}},
+	}})
+}
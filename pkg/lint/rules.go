@@ -0,0 +1,155 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package lint
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/distribution/reference"
+)
+
+func init() {
+	Register(imageUnpinned{})
+	Register(imageLatest{})
+	Register(healthcheckMissing{})
+	Register(bindWorldWritable{})
+	Register(resourcesMissing{})
+}
+
+// imageTag returns the tag set by a service image, and if image is pinned to a digest. Services built by compose are
+// skipped, as their image is not pulled
+func imageTag(service types.ServiceConfig) (tag string, digest bool, ok bool) {
+	if service.Image == "" || service.Build != nil {
+		return "", false, false
+	}
+	ref, err := reference.ParseNormalizedNamed(service.Image)
+	if err != nil {
+		return "", false, false
+	}
+	if _, ok := ref.(reference.Canonical); ok {
+		return "", true, true
+	}
+	if tagged, ok := ref.(reference.Tagged); ok {
+		return tagged.Tag(), false, true
+	}
+	return "", false, true
+}
+
+type imageUnpinned struct{}
+
+func (imageUnpinned) ID() string { return "image-unpinned" }
+
+func (imageUnpinned) Description() string {
+	return "Image is set without a tag nor a digest, so the latest version gets pulled implicitly"
+}
+
+func (imageUnpinned) Level() Level { return LevelWarning }
+
+func (imageUnpinned) Check(_ *types.Project, service types.ServiceConfig) []string {
+	if tag, digest, ok := imageTag(service); ok && tag == "" && !digest {
+		return []string{fmt.Sprintf("image %s has no tag nor digest", service.Image)}
+	}
+	return nil
+}
+
+type imageLatest struct{}
+
+func (imageLatest) ID() string { return "image-latest" }
+
+func (imageLatest) Description() string {
+	return "Image uses the latest tag, which changes over time"
+}
+
+func (imageLatest) Level() Level { return LevelWarning }
+
+func (imageLatest) Check(_ *types.Project, service types.ServiceConfig) []string {
+	if tag, _, ok := imageTag(service); ok && tag == "latest" {
+		return []string{fmt.Sprintf("image %s uses the latest tag", service.Image)}
+	}
+	return nil
+}
+
+type healthcheckMissing struct{}
+
+func (healthcheckMissing) ID() string { return "healthcheck-missing" }
+
+func (healthcheckMissing) Description() string {
+	return "Service doesn't declare a healthcheck, so dependents can't wait for it to be ready"
+}
+
+func (healthcheckMissing) Level() Level { return LevelWarning }
+
+func (healthcheckMissing) Check(_ *types.Project, service types.ServiceConfig) []string {
+	// a healthcheck explicitly disabled is a deliberate choice
+	if service.HealthCheck == nil {
+		return []string{"no healthcheck declared, unless the image sets one"}
+	}
+	return nil
+}
+
+type bindWorldWritable struct{}
+
+func (bindWorldWritable) ID() string { return "bind-world-writable" }
+
+func (bindWorldWritable) Description() string {
+	return "Bind mount source is writable by any user on the host"
+}
+
+func (bindWorldWritable) Level() Level { return LevelError }
+
+func (bindWorldWritable) Check(_ *types.Project, service types.ServiceConfig) []string {
+	var messages []string
+	for _, volume := range service.Volumes {
+		if volume.Type != types.VolumeTypeBind {
+			continue
+		}
+		info, err := os.Stat(volume.Source)
+		if err != nil {
+			// source is created by the engine, or lives on a remote host
+			continue
+		}
+		if info.Mode().Perm()&0o002 != 0 {
+			messages = append(messages, fmt.Sprintf("bind mount source %s is world-writable (%s)", volume.Source, info.Mode().Perm()))
+		}
+	}
+	return messages
+}
+
+type resourcesMissing struct{}
+
+func (resourcesMissing) ID() string { return "resources-missing" }
+
+func (resourcesMissing) Description() string {
+	return "Service doesn't set a memory nor a cpus limit, so it can starve other services"
+}
+
+func (resourcesMissing) Level() Level { return LevelWarning }
+
+func (resourcesMissing) Check(_ *types.Project, service types.ServiceConfig) []string {
+	if service.MemLimit > 0 || service.CPUS > 0 {
+		return nil
+	}
+	if service.Deploy != nil && service.Deploy.Resources.Limits != nil {
+		limits := service.Deploy.Resources.Limits
+		if limits.MemoryBytes > 0 || limits.NanoCPUs > 0 {
+			return nil
+		}
+	}
+	return []string{"no memory nor cpus limit set"}
+}
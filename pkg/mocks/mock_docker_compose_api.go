@@ -40,6 +40,21 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// ActiveProfiles mocks base method.
+func (m *MockService) ActiveProfiles(ctx context.Context, project *types.Project) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActiveProfiles", ctx, project)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActiveProfiles indicates an expected call of ActiveProfiles.
+func (mr *MockServiceMockRecorder) ActiveProfiles(ctx, project any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActiveProfiles", reflect.TypeOf((*MockService)(nil).ActiveProfiles), ctx, project)
+}
+
 // Attach mocks base method.
 func (m *MockService) Attach(ctx context.Context, projectName string, options api.AttachOptions) error {
 	m.ctrl.T.Helper()
@@ -68,6 +83,20 @@ func (mr *MockServiceMockRecorder) Build(ctx, project, options any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Build", reflect.TypeOf((*MockService)(nil).Build), ctx, project, options)
 }
 
+// Checkpoint mocks base method.
+func (m *MockService) Checkpoint(ctx context.Context, project *types.Project, options api.CheckpointOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Checkpoint", ctx, project, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Checkpoint indicates an expected call of Checkpoint.
+func (mr *MockServiceMockRecorder) Checkpoint(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Checkpoint", reflect.TypeOf((*MockService)(nil).Checkpoint), ctx, project, options)
+}
+
 // Copy mocks base method.
 func (m *MockService) Copy(ctx context.Context, projectName string, options api.CopyOptions) error {
 	m.ctrl.T.Helper()
@@ -154,6 +183,20 @@ func (mr *MockServiceMockRecorder) Exec(ctx, projectName, options any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exec", reflect.TypeOf((*MockService)(nil).Exec), ctx, projectName, options)
 }
 
+// Export mocks base method.
+func (m *MockService) Export(ctx context.Context, project *types.Project, options api.ExportOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, project, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockServiceMockRecorder) Export(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockService)(nil).Export), ctx, project, options)
+}
+
 // Images mocks base method.
 func (m *MockService) Images(ctx context.Context, projectName string, options api.ImagesOptions) ([]api.ImageSummary, error) {
 	m.ctrl.T.Helper()
@@ -169,6 +212,35 @@ func (mr *MockServiceMockRecorder) Images(ctx, projectName, options any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Images", reflect.TypeOf((*MockService)(nil).Images), ctx, projectName, options)
 }
 
+// ImagesPrune mocks base method.
+func (m *MockService) ImagesPrune(ctx context.Context, project *types.Project, options api.ImagesPruneOptions) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImagesPrune", ctx, project, options)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImagesPrune indicates an expected call of ImagesPrune.
+func (mr *MockServiceMockRecorder) ImagesPrune(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImagesPrune", reflect.TypeOf((*MockService)(nil).ImagesPrune), ctx, project, options)
+}
+
+// Import mocks base method.
+func (m *MockService) Import(ctx context.Context, options api.ImportOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockServiceMockRecorder) Import(ctx, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockService)(nil).Import), ctx, options)
+}
+
 // Kill mocks base method.
 func (m *MockService) Kill(ctx context.Context, projectName string, options api.KillOptions) error {
 	m.ctrl.T.Helper()
@@ -224,6 +296,36 @@ func (mr *MockServiceMockRecorder) MaxConcurrency(parallel any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaxConcurrency", reflect.TypeOf((*MockService)(nil).MaxConcurrency), parallel)
 }
 
+// Networks mocks base method.
+func (m *MockService) Networks(ctx context.Context, projectName string) ([]api.NetworkSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Networks", ctx, projectName)
+	ret0, _ := ret[0].([]api.NetworkSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Networks indicates an expected call of Networks.
+func (mr *MockServiceMockRecorder) Networks(ctx, projectName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Networks", reflect.TypeOf((*MockService)(nil).Networks), ctx, projectName)
+}
+
+// Orphans mocks base method.
+func (m *MockService) Orphans(ctx context.Context, project *types.Project) ([]api.ContainerSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Orphans", ctx, project)
+	ret0, _ := ret[0].([]api.ContainerSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Orphans indicates an expected call of Orphans.
+func (mr *MockServiceMockRecorder) Orphans(ctx, project any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Orphans", reflect.TypeOf((*MockService)(nil).Orphans), ctx, project)
+}
+
 // Pause mocks base method.
 func (m *MockService) Pause(ctx context.Context, projectName string, options api.PauseOptions) error {
 	m.ctrl.T.Helper()
@@ -238,6 +340,21 @@ func (mr *MockServiceMockRecorder) Pause(ctx, projectName, options any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pause", reflect.TypeOf((*MockService)(nil).Pause), ctx, projectName, options)
 }
 
+// Plan mocks base method.
+func (m *MockService) Plan(ctx context.Context, project *types.Project, options api.CreateOptions) ([]api.PlannedChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Plan", ctx, project, options)
+	ret0, _ := ret[0].([]api.PlannedChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Plan indicates an expected call of Plan.
+func (mr *MockServiceMockRecorder) Plan(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Plan", reflect.TypeOf((*MockService)(nil).Plan), ctx, project, options)
+}
+
 // Port mocks base method.
 func (m *MockService) Port(ctx context.Context, projectName, service string, port uint16, options api.PortOptions) (string, int, error) {
 	m.ctrl.T.Helper()
@@ -254,6 +371,21 @@ func (mr *MockServiceMockRecorder) Port(ctx, projectName, service, port, options
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Port", reflect.TypeOf((*MockService)(nil).Port), ctx, projectName, service, port, options)
 }
 
+// Projects mocks base method.
+func (m *MockService) Projects(ctx context.Context, options api.ListOptions) ([]api.ProjectSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Projects", ctx, options)
+	ret0, _ := ret[0].([]api.ProjectSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Projects indicates an expected call of Projects.
+func (mr *MockServiceMockRecorder) Projects(ctx, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Projects", reflect.TypeOf((*MockService)(nil).Projects), ctx, options)
+}
+
 // Ps mocks base method.
 func (m *MockService) Ps(ctx context.Context, projectName string, options api.PsOptions) ([]api.ContainerSummary, error) {
 	m.ctrl.T.Helper()
@@ -339,6 +471,20 @@ func (mr *MockServiceMockRecorder) Restart(ctx, projectName, options any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restart", reflect.TypeOf((*MockService)(nil).Restart), ctx, projectName, options)
 }
 
+// Restore mocks base method.
+func (m *MockService) Restore(ctx context.Context, project *types.Project, options api.CheckpointOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, project, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockServiceMockRecorder) Restore(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockService)(nil).Restore), ctx, project, options)
+}
+
 // RunOneOffContainer mocks base method.
 func (m *MockService) RunOneOffContainer(ctx context.Context, project *types.Project, opts api.RunOptions) (int, error) {
 	m.ctrl.T.Helper()
@@ -368,6 +514,35 @@ func (mr *MockServiceMockRecorder) Scale(ctx, project, options any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scale", reflect.TypeOf((*MockService)(nil).Scale), ctx, project, options)
 }
 
+// StackDeploy mocks base method.
+func (m *MockService) StackDeploy(ctx context.Context, project *types.Project, options api.StackDeployOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StackDeploy", ctx, project, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StackDeploy indicates an expected call of StackDeploy.
+func (mr *MockServiceMockRecorder) StackDeploy(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StackDeploy", reflect.TypeOf((*MockService)(nil).StackDeploy), ctx, project, options)
+}
+
+// Warnings mocks base method.
+func (m *MockService) Warnings(ctx context.Context, project *types.Project) ([]api.Warning, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Warnings", ctx, project)
+	ret0, _ := ret[0].([]api.Warning)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Warnings indicates an expected call of Warnings.
+func (mr *MockServiceMockRecorder) Warnings(ctx, project any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Warnings", reflect.TypeOf((*MockService)(nil).Warnings), ctx, project)
+}
+
 // Start mocks base method.
 func (m *MockService) Start(ctx context.Context, projectName string, options api.StartOptions) error {
 	m.ctrl.T.Helper()
@@ -382,6 +557,21 @@ func (mr *MockServiceMockRecorder) Start(ctx, projectName, options any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockService)(nil).Start), ctx, projectName, options)
 }
 
+// Stats mocks base method.
+func (m *MockService) Stats(ctx context.Context, projectName string, options api.StatsOptions) ([]api.ContainerStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats", ctx, projectName, options)
+	ret0, _ := ret[0].([]api.ContainerStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockServiceMockRecorder) Stats(ctx, projectName, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockService)(nil).Stats), ctx, projectName, options)
+}
+
 // Stop mocks base method.
 func (m *MockService) Stop(ctx context.Context, projectName string, options api.StopOptions) error {
 	m.ctrl.T.Helper()
@@ -454,6 +644,64 @@ func (mr *MockServiceMockRecorder) Viz(ctx, project, options any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Viz", reflect.TypeOf((*MockService)(nil).Viz), ctx, project, options)
 }
 
+// VolumeBackup mocks base method.
+func (m *MockService) VolumeBackup(ctx context.Context, project *types.Project, options api.VolumeBackupOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VolumeBackup", ctx, project, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VolumeBackup indicates an expected call of VolumeBackup.
+func (mr *MockServiceMockRecorder) VolumeBackup(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VolumeBackup", reflect.TypeOf((*MockService)(nil).VolumeBackup), ctx, project, options)
+}
+
+// VolumeRestore mocks base method.
+func (m *MockService) VolumeRestore(ctx context.Context, project *types.Project, options api.VolumeBackupOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VolumeRestore", ctx, project, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VolumeRestore indicates an expected call of VolumeRestore.
+func (mr *MockServiceMockRecorder) VolumeRestore(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VolumeRestore", reflect.TypeOf((*MockService)(nil).VolumeRestore), ctx, project, options)
+}
+
+// Volumes mocks base method.
+func (m *MockService) Volumes(ctx context.Context, projectName string, options api.VolumesOptions) ([]api.VolumeSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Volumes", ctx, projectName, options)
+	ret0, _ := ret[0].([]api.VolumeSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Volumes indicates an expected call of Volumes.
+func (mr *MockServiceMockRecorder) Volumes(ctx, projectName, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Volumes", reflect.TypeOf((*MockService)(nil).Volumes), ctx, projectName, options)
+}
+
+// VolumesPrune mocks base method.
+func (m *MockService) VolumesPrune(ctx context.Context, projectName string, options api.VolumesPruneOptions) ([]api.VolumeSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VolumesPrune", ctx, projectName, options)
+	ret0, _ := ret[0].([]api.VolumeSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VolumesPrune indicates an expected call of VolumesPrune.
+func (mr *MockServiceMockRecorder) VolumesPrune(ctx, projectName, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VolumesPrune", reflect.TypeOf((*MockService)(nil).VolumesPrune), ctx, projectName, options)
+}
+
 // Wait mocks base method.
 func (m *MockService) Wait(ctx context.Context, projectName string, options api.WaitOptions) (int64, error) {
 	m.ctrl.T.Helper()
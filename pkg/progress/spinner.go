@@ -29,11 +29,16 @@ type spinner struct {
 	done  string
 }
 
-func newSpinner() *spinner {
-	chars := []string{
+var (
+	spinnerChars = []string{
 		"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏",
 	}
-	done := "⠿"
+	spinnerStopped = "⠿"
+)
+
+func newSpinner() *spinner {
+	chars := spinnerChars
+	done := spinnerStopped
 
 	if runtime.GOOS == "windows" {
 		chars = []string{"-"}
@@ -0,0 +1,45 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package progress
+
+import (
+	"strings"
+
+	"github.com/morikuni/aec"
+)
+
+// ASCII replaces unicode symbols used to render progress by plain ASCII characters,
+// for terminals, screen readers and CI logs which don't render them well
+func ASCII() {
+	spinnerChars = []string{"-", "\\", "|", "/"}
+	spinnerStopped = "-"
+	spinnerDone = "+"
+	spinnerWarning = "!"
+	spinnerError = "x"
+	percentChars = strings.Split(" .:-=+*#@", "")
+}
+
+// HighContrast only uses bold, bright colors, so progress remains readable on any terminal background
+func HighContrast() {
+	DoneColor = aec.GreenF.With(aec.Bold).Apply
+	TimerColor = aec.WhiteF.With(aec.Bold).Apply
+	CountColor = aec.YellowF.With(aec.Bold).Apply
+	WarningColor = aec.YellowF.With(aec.Bold).Apply
+	SuccessColor = aec.GreenF.With(aec.Bold).Apply
+	ErrorColor = aec.RedF.With(aec.Bold).Apply
+	PrefixColor = aec.CyanF.With(aec.Bold).Apply
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/docker/buildx/store/storeutil"
 	"github.com/docker/buildx/util/imagetools"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/compose/v2/internal/ocipush"
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
@@ -138,11 +139,13 @@ func (g ociRemoteLoader) pullComposeFiles(ctx context.Context, local string, com
 	}
 	defer f.Close() //nolint:errcheck
 
-	if manifest.ArtifactType != "application/vnd.docker.compose.project" {
+	// OCI 1.0 artifacts have no artifact type, but are recognized by their config media type
+	if manifest.ArtifactType != ocipush.ComposeProjectArtifactType && manifest.Config.MediaType != ocipush.ComposeEmptyConfigMediaType {
 		return fmt.Errorf("%s is not a compose project OCI artifact, but %s", ref.String(), manifest.ArtifactType)
 	}
 
-	for i, layer := range manifest.Layers {
+	var composeFiles int
+	for _, layer := range manifest.Layers {
 		digested, err := reference.WithDigest(ref, layer.Digest)
 		if err != nil {
 			return err
@@ -151,7 +154,14 @@ func (g ociRemoteLoader) pullComposeFiles(ctx context.Context, local string, com
 		if err != nil {
 			return err
 		}
-		if i > 0 {
+		if layer.MediaType == ocipush.ComposeLocalFileMediaType {
+			if err := writeLocalFile(local, layer.Annotations[ocipush.ComposeLocalFilePathAnnotation], content); err != nil {
+				return err
+			}
+			continue
+		}
+		composeFiles++
+		if composeFiles > 1 {
 			_, err = f.Write([]byte("\n---\n"))
 			if err != nil {
 				return err
@@ -165,4 +175,17 @@ func (g ociRemoteLoader) pullComposeFiles(ctx context.Context, local string, com
 	return nil
 }
 
+// writeLocalFile restores a local file published along with compose files, relative to the project directory
+func writeLocalFile(local string, path string, content []byte) error {
+	path = filepath.FromSlash(path)
+	if !filepath.IsLocal(path) {
+		return fmt.Errorf("invalid local file path %q in compose project OCI artifact", path)
+	}
+	path = filepath.Join(local, path)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0o600)
+}
+
 var _ loader.ResourceLoader = ociRemoteLoader{}